@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/forward-mcp/internal/config"
 	"github.com/forward-mcp/internal/logger"
 	"github.com/forward-mcp/internal/service"
 	mcp "github.com/metoro-io/mcp-golang"
-	"github.com/metoro-io/mcp-golang/transport/stdio"
 )
 
 func main() {
@@ -16,6 +20,9 @@ func main() {
 
 	// Load configuration
 	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		logger.Fatalf("%v\nSet these in the environment, a .env file, or the config file named by FORWARD_MCP_CONFIG (see env.example).", err)
+	}
 
 	// Create logger
 	logger.Info("Forward MCP Server starting...")
@@ -48,31 +55,21 @@ func main() {
 	logger.Debug("Creating Forward MCP service...")
 	forwardService := service.NewForwardMCPService(cfg, logger)
 
-	// Create MCP server with stdio transport for Claude Desktop compatibility
-	logger.Debug("Creating MCP server with stdio transport...")
-	transport := stdio.NewStdioServerTransport()
-	server := mcp.NewServer(transport)
-
-	// Register all Forward Networks tools
-	logger.Debug("Registering Forward Networks tools...")
-	if err := forwardService.RegisterTools(server); err != nil {
-		logger.Fatalf("Failed to register tools: %v", err)
+	// Optionally expose Prometheus metrics over HTTP (stdout is reserved for MCP)
+	if cfg.Server.MetricsEnabled {
+		addr := fmt.Sprintf("%s:%d", cfg.Server.MetricsHost, cfg.Server.Port)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", forwardService.MetricsHandler())
+		go func() {
+			logger.Info("Metrics listener started on http://%s/metrics", addr)
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				logger.Error("Metrics listener stopped: %v", err)
+			}
+		}()
 	}
-	logger.Debug("Tools registered successfully!")
 
-	// Register prompt workflows following MCP best practices
-	logger.Debug("Registering prompt workflows...")
-	if err := forwardService.RegisterPrompts(server); err != nil {
-		logger.Fatalf("Failed to register prompts: %v", err)
-	}
-	logger.Debug("Prompt workflows registered successfully!")
-
-	// Register contextual resources following MCP best practices
-	logger.Debug("Registering contextual resources...")
-	if err := forwardService.RegisterResources(server); err != nil {
-		logger.Fatalf("Failed to register resources: %v", err)
-	}
-	logger.Debug("Contextual resources registered successfully!")
+	// Warn about (and optionally regenerate) embeddings that predate the spec file
+	forwardService.StartEmbeddingsFreshnessCheck()
 
 	// Check if we're in a TTY (interactive mode) or pipe mode
 	if fileInfo, _ := os.Stdin.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
@@ -83,15 +80,39 @@ func main() {
 		logger.Debug("Running in pipe mode (stdin redirected)")
 	}
 
-	// Start the server
-	logger.Debug("Starting Forward Networks MCP server...")
-	if err := server.Serve(); err != nil {
-		logger.Fatalf("Server error: %v", err)
-	}
+	// Serve over stdio until interrupted or the client closes stdin
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	logger.Debug("MCP server is now running and waiting for connections...")
+	// Refresh the most-accessed queries from the previous run without delaying startup
+	forwardService.StartCacheWarmup(ctx)
 
-	// Block forever to keep the server running (for Claude Desktop compatibility)
-	done := make(chan struct{})
-	<-done
+	logger.Debug("Starting Forward Networks MCP server...")
+	err := serve(ctx, os.Stdin, os.Stdout, func(server *mcp.Server) error {
+		// Register all Forward Networks tools
+		logger.Debug("Registering Forward Networks tools...")
+		if err := forwardService.RegisterTools(server); err != nil {
+			return fmt.Errorf("failed to register tools: %w", err)
+		}
+
+		// Register prompt workflows following MCP best practices
+		logger.Debug("Registering prompt workflows...")
+		if err := forwardService.RegisterPrompts(server); err != nil {
+			return fmt.Errorf("failed to register prompts: %w", err)
+		}
+
+		// Register contextual resources following MCP best practices
+		logger.Debug("Registering contextual resources...")
+		if err := forwardService.RegisterResources(server); err != nil {
+			return fmt.Errorf("failed to register resources: %w", err)
+		}
+		logger.Debug("Tools, prompts, and resources registered successfully!")
+		return nil
+	})
+	// Keep the final access counts for the next start's warm-up
+	forwardService.SaveCacheWarmup()
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	logger.Info("Forward MCP Server stopped")
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport/stdio"
+)
+
+// closeNotifyingReader reports when the MCP client closes its end of the input
+type closeNotifyingReader struct {
+	io.Reader
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (r *closeNotifyingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil {
+		r.once.Do(func() { close(r.closed) })
+	}
+	return n, err
+}
+
+// serve runs an MCP server over in and out until ctx is cancelled or the client closes
+// the input. It then closes the transport and, when in can be closed, the input itself
+// so the transport's read loop is not left blocked.
+func serve(ctx context.Context, in io.Reader, out io.Writer, register func(*mcp.Server) error) error {
+	input := &closeNotifyingReader{Reader: in, closed: make(chan struct{})}
+	transport := stdio.NewStdioServerTransportWithIO(input, out)
+	server := mcp.NewServer(transport)
+	if err := register(server); err != nil {
+		return err
+	}
+
+	if err := server.Serve(); err != nil {
+		return fmt.Errorf("server error: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-input.closed:
+	}
+
+	transport.Close()
+	if closer, ok := in.(io.Closer); ok {
+		closer.Close()
+	}
+	return nil
+}
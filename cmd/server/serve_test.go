@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+func noRegistration(*mcp.Server) error { return nil }
+
+// serveInBackground starts serve on a pipe and returns the write end and the serve result
+func serveInBackground(ctx context.Context) (*io.PipeWriter, <-chan error) {
+	in, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- serve(ctx, in, io.Discard, noRegistration) }()
+	return writer, done
+}
+
+func TestServeStopsOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	writer, done := serveInBackground(ctx)
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected a clean shutdown, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected serve to return after cancellation")
+	}
+
+	// The input is closed so the transport's read loop is not left blocked
+	if _, err := writer.Write([]byte("{}\n")); err != io.ErrClosedPipe {
+		t.Errorf("Expected the input to be closed, got write error %v", err)
+	}
+}
+
+func TestServeStopsWhenClientClosesInput(t *testing.T) {
+	writer, done := serveInBackground(context.Background())
+
+	writer.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected a clean shutdown, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected serve to return when the input is closed")
+	}
+}
@@ -3,12 +3,15 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/forward-mcp/internal/logger"
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds all configuration for the application
@@ -20,8 +23,13 @@ type Config struct {
 
 // ServerConfig holds server-specific configuration
 type ServerConfig struct {
-	Port int
-	Host string
+	Port int    `json:"port" env:"SERVER_PORT"`
+	Host string `json:"host" env:"SERVER_HOST"`
+
+	// MetricsEnabled serves Prometheus metrics over HTTP at MetricsHost:Port/metrics
+	MetricsEnabled bool `json:"metricsEnabled" env:"FORWARD_MCP_METRICS_ENABLED"`
+	// MetricsHost is the address the metrics listener binds to (default loopback only)
+	MetricsHost string `json:"metricsHost" env:"FORWARD_MCP_METRICS_HOST"`
 }
 
 // ForwardConfig holds Forward Networks API configuration
@@ -32,6 +40,43 @@ type ForwardConfig struct {
 	DefaultNetworkID  string `json:"defaultNetworkId" env:"FORWARD_DEFAULT_NETWORK_ID"`
 	DefaultSnapshotID string `json:"defaultSnapshotId" env:"FORWARD_DEFAULT_SNAPSHOT_ID"`
 	DefaultQueryLimit int    `json:"defaultQueryLimit" env:"FORWARD_DEFAULT_QUERY_LIMIT"`
+	// MaxQueryLimit is the largest row limit an NQE call may request (0 = no cap)
+	MaxQueryLimit int `json:"maxQueryLimit" env:"FORWARD_MAX_QUERY_LIMIT"`
+	// DefaultNQEFormat is the output format of NQE calls that do not set one (empty = API default)
+	DefaultNQEFormat string `json:"defaultNqeFormat" env:"FORWARD_DEFAULT_NQE_FORMAT"`
+	// NQEResponseBudgetBytes is the predicted NQE response size above which a smaller limit
+	// is suggested (0 = no budget); NQEAutoLimit applies the smaller limit to calls without one
+	NQEResponseBudgetBytes int  `json:"nqeResponseBudgetBytes" env:"FORWARD_NQE_RESPONSE_BUDGET_BYTES"`
+	NQEAutoLimit           bool `json:"nqeAutoLimit" env:"FORWARD_NQE_AUTO_LIMIT"`
+	// ProfilesDir is where save_profile keeps named session profiles, one file per instance
+	// (empty = the user config directory)
+	ProfilesDir string `json:"profilesDir" env:"FORWARD_MCP_PROFILES_DIR"`
+
+	// Path search limits applied when a search_paths call does not set them (0 uses the built-in default)
+	PathMaxCandidates        int `json:"pathMaxCandidates" env:"FORWARD_PATH_MAX_CANDIDATES"`
+	PathMaxResults           int `json:"pathMaxResults" env:"FORWARD_PATH_MAX_RESULTS"`
+	PathMaxReturnPathResults int `json:"pathMaxReturnPathResults" env:"FORWARD_PATH_MAX_RETURN_PATH_RESULTS"`
+	PathMaxSeconds           int `json:"pathMaxSeconds" env:"FORWARD_PATH_MAX_SECONDS"`
+
+	// LatestSnapshotTTL is how many seconds a resolved latest snapshot is reused (0 disables caching)
+	LatestSnapshotTTL int `json:"latestSnapshotTtl" env:"FORWARD_LATEST_SNAPSHOT_TTL"`
+
+	// ListCacheTTL is how many seconds list_networks and list_locations results are reused (0 disables caching)
+	ListCacheTTL int `json:"listCacheTtl" env:"FORWARD_LIST_CACHE_TTL"`
+
+	// NQEPageCacheTTL is how many seconds the rows of paged NQE results are kept so paging
+	// back is served from memory (0 disables the page cache). The page cache only runs
+	// while the semantic cache is enabled and shares its row and byte limits.
+	NQEPageCacheTTL int `json:"nqePageCacheTtl" env:"FORWARD_NQE_PAGE_CACHE_TTL"`
+
+	// CoalesceLookups shares one API call between concurrent identical latest-snapshot and
+	// network list lookups
+	CoalesceLookups bool `json:"coalesceLookups" env:"FORWARD_COALESCE_LOOKUPS"`
+
+	// NQE directory policy: queries under a denied directory prefix are hidden and refused,
+	// and when allowed prefixes are set only queries under them are available
+	NQEAllowDirectories []string `json:"nqeAllowDirectories" env:"FORWARD_NQE_ALLOW_DIRECTORIES"`
+	NQEDenyDirectories  []string `json:"nqeDenyDirectories" env:"FORWARD_NQE_DENY_DIRECTORIES"`
 
 	// TLS Configuration
 	InsecureSkipVerify bool   `json:"insecureSkipVerify" env:"FORWARD_INSECURE_SKIP_VERIFY"`
@@ -40,10 +85,32 @@ type ForwardConfig struct {
 	ClientKeyPath      string `json:"clientKeyPath" env:"FORWARD_CLIENT_KEY_PATH"`
 	Timeout            int    `json:"timeout" env:"FORWARD_TIMEOUT"`
 
+	// ProxyURL sends Forward API requests through this proxy instead of the one named by
+	// HTTP_PROXY / HTTPS_PROXY; hosts matching NoProxy are reached directly
+	ProxyURL string   `json:"proxyUrl" env:"FORWARD_PROXY_URL"`
+	NoProxy  []string `json:"noProxy" env:"FORWARD_NO_PROXY"`
+
+	// UserAgent overrides the User-Agent sent to the Forward API (default: forward-mcp/<version>)
+	UserAgent string `json:"userAgent" env:"FORWARD_USER_AGENT"`
+
+	// Instances are additional named Forward instances set_active_instance can switch to;
+	// they are read from the config file only
+	Instances []ForwardInstance `json:"instances"`
+
 	// Semantic Cache Configuration
 	SemanticCache SemanticCacheConfig `json:"semanticCache"`
 }
 
+// ForwardInstance is a named Forward instance. Credentials are not stored in the config
+// file: APIKeyEnv and APISecretEnv name the environment variables that hold them.
+type ForwardInstance struct {
+	Name             string `json:"name"`
+	APIBaseURL       string `json:"apiBaseUrl"`
+	APIKeyEnv        string `json:"apiKeyEnv"`
+	APISecretEnv     string `json:"apiSecretEnv"`
+	DefaultNetworkID string `json:"defaultNetworkId"`
+}
+
 // SemanticCacheConfig holds semantic cache configuration
 type SemanticCacheConfig struct {
 	Enabled             bool    `json:"enabled" env:"FORWARD_SEMANTIC_CACHE_ENABLED"`
@@ -51,57 +118,233 @@ type SemanticCacheConfig struct {
 	TTLHours            int     `json:"ttlHours" env:"FORWARD_SEMANTIC_CACHE_TTL_HOURS"`
 	SimilarityThreshold float64 `json:"similarityThreshold" env:"FORWARD_SEMANTIC_CACHE_SIMILARITY_THRESHOLD"`
 	EmbeddingProvider   string  `json:"embeddingProvider" env:"FORWARD_EMBEDDING_PROVIDER"`
+	// EmbeddingFallbacks are providers tried in order when EmbeddingProvider fails
+	EmbeddingFallbacks []string `json:"embeddingFallbacks" env:"FORWARD_EMBEDDING_FALLBACKS"`
+	// SuggestionFloor is the minimum similarity for suggest_similar_queries results
+	SuggestionFloor float64 `json:"suggestionFloor" env:"FORWARD_SEMANTIC_CACHE_SUGGESTION_FLOOR"`
+	// KeywordVocabularyFile is a JSON file of synonyms and stopwords for the keyword embedding provider
+	KeywordVocabularyFile string `json:"keywordVocabularyFile" env:"FORWARD_KEYWORD_VOCABULARY_FILE"`
+	AutoTuneTTL           bool   `json:"autoTuneTtl" env:"FORWARD_SEMANTIC_CACHE_AUTO_TTL"`
+
+	// Result size limits (0 = unlimited): a result with more rows or JSON bytes than an
+	// entry limit is not cached, and the oldest entries are evicted to stay within MaxTotalBytes
+	MaxEntryRows  int `json:"maxEntryRows" env:"FORWARD_SEMANTIC_CACHE_MAX_ENTRY_ROWS"`
+	MaxEntryBytes int `json:"maxEntryBytes" env:"FORWARD_SEMANTIC_CACHE_MAX_ENTRY_BYTES"`
+	MaxTotalBytes int `json:"maxTotalBytes" env:"FORWARD_SEMANTIC_CACHE_MAX_TOTAL_BYTES"`
+
+	// MaxResidentEmbeddings caps NQE query embeddings held in memory; the rest are read
+	// from the embeddings cache file on demand (0 = keep all in memory)
+	MaxResidentEmbeddings int `json:"maxResidentEmbeddings" env:"FORWARD_NQE_MAX_RESIDENT_EMBEDDINGS"`
+
+	// SpecDir holds NQELibrary.json and the embeddings cache (empty = search the default
+	// locations relative to the working directory and executable)
+	SpecDir string `json:"specDir" env:"FORWARD_MCP_SPEC_DIR"`
+
+	// AutoRegenerateEmbeddings fills in embeddings in the background at startup when the
+	// spec file changed since the embeddings cache was written
+	AutoRegenerateEmbeddings bool `json:"autoRegenerateEmbeddings" env:"FORWARD_MCP_AUTO_REGENERATE_EMBEDDINGS"`
+
+	// Warm-up replays the most-accessed NQE queries from the previous run at startup
+	Warmup      bool   `json:"warmup" env:"FORWARD_MCP_CACHE_WARMUP"`
+	WarmupCount int    `json:"warmupCount" env:"FORWARD_MCP_CACHE_WARMUP_COUNT"`
+	WarmupFile  string `json:"warmupFile" env:"FORWARD_MCP_CACHE_WARMUP_FILE"`
 }
 
 // MCPConfig holds MCP-specific configuration
 type MCPConfig struct {
-	Version    string
-	MaxRetries int
+	Version    string `json:"version" env:"MCP_VERSION"`
+	MaxRetries int    `json:"maxRetries" env:"MCP_MAX_RETRIES"`
+
+	// Redact scrubs secrets (passwords, keys, community strings) from tool responses
+	Redact bool `json:"redact" env:"FORWARD_MCP_REDACT"`
+	// RedactPatterns are extra regular expressions to redact, on top of the built-in ones
+	RedactPatterns []string `json:"redactPatterns" env:"FORWARD_MCP_REDACT_PATTERNS"`
+	// MaskAssets partially masks management IPs and serial numbers in tool responses
+	MaskAssets bool `json:"maskAssets" env:"FORWARD_MCP_MASK_ASSETS"`
+
+	// MaxConcurrentToolCalls caps simultaneous tool executions; 0 means unlimited
+	MaxConcurrentToolCalls int `json:"maxConcurrentToolCalls" env:"FORWARD_MCP_MAX_CONCURRENT_TOOL_CALLS"`
+	// ConcurrencyPolicy is "queue" to wait for a free slot or "reject" to fail fast when busy
+	ConcurrencyPolicy string `json:"concurrencyPolicy" env:"FORWARD_MCP_CONCURRENCY_POLICY"`
+
+	// JSONFormat is the default rendering of tool JSON output: "formatted", "compact", or "auto"
+	JSONFormat string `json:"jsonFormat" env:"FORWARD_MCP_JSON_FORMAT"`
 }
 
-// LoadConfig loads configuration from environment variables and .env file
+// configFileEnv names the environment variable holding an explicit config file path
+const configFileEnv = "FORWARD_MCP_CONFIG"
+
+// defaultConfigPaths are searched in order when FORWARD_MCP_CONFIG is not set
+var defaultConfigPaths = []string{
+	"forward-mcp.yaml",
+	"forward-mcp.yml",
+	"forward-mcp.json",
+	"config.json",
+	"examples/config.json",
+	"/etc/forward-mcp/config.json",
+}
+
+// LoadConfig loads configuration from an optional config file, the .env file, and
+// environment variables. Environment variables override config file values, which
+// override the built-in defaults.
 func LoadConfig() *Config {
 	// Try to load .env file (fail silently if not found)
 	loadEnvFile()
 
+	// Start from defaults and overlay the config file, if any
+	base := defaultConfig()
+	if err := loadConfigFile(base); err != nil {
+		debugLogger := logger.New()
+		if _, explicit := os.LookupEnv(configFileEnv); explicit {
+			debugLogger.Warn("Could not load config file: %v", err)
+		} else {
+			debugLogger.Debug("Could not load config file: %v", err)
+		}
+	}
+
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnvAsInt("SERVER_PORT", 8080),
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+			Port: getEnvAsInt("SERVER_PORT", base.Server.Port),
+			Host: getEnv("SERVER_HOST", base.Server.Host),
+
+			MetricsEnabled: getEnvAsBool("FORWARD_MCP_METRICS_ENABLED", base.Server.MetricsEnabled),
+			MetricsHost:    getEnv("FORWARD_MCP_METRICS_HOST", base.Server.MetricsHost),
 		},
 		Forward: ForwardConfig{
-			APIKey:             getEnv("FORWARD_API_KEY", ""),
-			APISecret:          getEnv("FORWARD_API_SECRET", ""),
-			APIBaseURL:         getEnv("FORWARD_API_BASE_URL", ""),
-			Timeout:            getEnvAsInt("FORWARD_TIMEOUT", 30),
-			InsecureSkipVerify: getEnvAsBool("FORWARD_INSECURE_SKIP_VERIFY", false),
-			CACertPath:         getEnv("FORWARD_CA_CERT_PATH", ""),
-			ClientCertPath:     getEnv("FORWARD_CLIENT_CERT_PATH", ""),
-			ClientKeyPath:      getEnv("FORWARD_CLIENT_KEY_PATH", ""),
-			DefaultNetworkID:   getEnv("FORWARD_DEFAULT_NETWORK_ID", ""),
-			DefaultSnapshotID:  getEnv("FORWARD_DEFAULT_SNAPSHOT_ID", ""),
-			DefaultQueryLimit:  getEnvAsInt("FORWARD_DEFAULT_QUERY_LIMIT", 10000),
+			APIKey:             getEnv("FORWARD_API_KEY", base.Forward.APIKey),
+			APISecret:          getEnv("FORWARD_API_SECRET", base.Forward.APISecret),
+			APIBaseURL:         getEnv("FORWARD_API_BASE_URL", base.Forward.APIBaseURL),
+			Timeout:            getEnvAsInt("FORWARD_TIMEOUT", base.Forward.Timeout),
+			UserAgent:          getEnv("FORWARD_USER_AGENT", base.Forward.UserAgent),
+			ProxyURL:           getEnv("FORWARD_PROXY_URL", base.Forward.ProxyURL),
+			NoProxy:            getEnvAsList("FORWARD_NO_PROXY", ",", base.Forward.NoProxy),
+			InsecureSkipVerify: getEnvAsBool("FORWARD_INSECURE_SKIP_VERIFY", base.Forward.InsecureSkipVerify),
+			CACertPath:         getEnv("FORWARD_CA_CERT_PATH", base.Forward.CACertPath),
+			ClientCertPath:     getEnv("FORWARD_CLIENT_CERT_PATH", base.Forward.ClientCertPath),
+			ClientKeyPath:      getEnv("FORWARD_CLIENT_KEY_PATH", base.Forward.ClientKeyPath),
+			DefaultNetworkID:   getEnv("FORWARD_DEFAULT_NETWORK_ID", base.Forward.DefaultNetworkID),
+			DefaultSnapshotID:  getEnv("FORWARD_DEFAULT_SNAPSHOT_ID", base.Forward.DefaultSnapshotID),
+			DefaultQueryLimit:  getEnvAsInt("FORWARD_DEFAULT_QUERY_LIMIT", base.Forward.DefaultQueryLimit),
+			MaxQueryLimit:      getEnvAsInt("FORWARD_MAX_QUERY_LIMIT", base.Forward.MaxQueryLimit),
+			DefaultNQEFormat:   getEnv("FORWARD_DEFAULT_NQE_FORMAT", base.Forward.DefaultNQEFormat),
+
+			NQEResponseBudgetBytes: getEnvAsInt("FORWARD_NQE_RESPONSE_BUDGET_BYTES", base.Forward.NQEResponseBudgetBytes),
+			NQEAutoLimit:           getEnvAsBool("FORWARD_NQE_AUTO_LIMIT", base.Forward.NQEAutoLimit),
+			ProfilesDir:            getEnv("FORWARD_MCP_PROFILES_DIR", base.Forward.ProfilesDir),
+
+			PathMaxCandidates:        getEnvAsInt("FORWARD_PATH_MAX_CANDIDATES", base.Forward.PathMaxCandidates),
+			PathMaxResults:           getEnvAsInt("FORWARD_PATH_MAX_RESULTS", base.Forward.PathMaxResults),
+			PathMaxReturnPathResults: getEnvAsInt("FORWARD_PATH_MAX_RETURN_PATH_RESULTS", base.Forward.PathMaxReturnPathResults),
+			PathMaxSeconds:           getEnvAsInt("FORWARD_PATH_MAX_SECONDS", base.Forward.PathMaxSeconds),
+			LatestSnapshotTTL:        getEnvAsInt("FORWARD_LATEST_SNAPSHOT_TTL", base.Forward.LatestSnapshotTTL),
+			ListCacheTTL:             getEnvAsInt("FORWARD_LIST_CACHE_TTL", base.Forward.ListCacheTTL),
+			NQEPageCacheTTL:          getEnvAsInt("FORWARD_NQE_PAGE_CACHE_TTL", base.Forward.NQEPageCacheTTL),
+			CoalesceLookups:          getEnvAsBool("FORWARD_COALESCE_LOOKUPS", base.Forward.CoalesceLookups),
+			NQEAllowDirectories:      getEnvAsList("FORWARD_NQE_ALLOW_DIRECTORIES", ",", base.Forward.NQEAllowDirectories),
+			NQEDenyDirectories:       getEnvAsList("FORWARD_NQE_DENY_DIRECTORIES", ",", base.Forward.NQEDenyDirectories),
+			Instances:                base.Forward.Instances,
 			SemanticCache: SemanticCacheConfig{
-				Enabled:             getEnvAsBool("FORWARD_SEMANTIC_CACHE_ENABLED", true),
-				MaxEntries:          getEnvAsInt("FORWARD_SEMANTIC_CACHE_MAX_ENTRIES", 1000),
-				TTLHours:            getEnvAsInt("FORWARD_SEMANTIC_CACHE_TTL_HOURS", 24),
-				SimilarityThreshold: getEnvAsFloat("FORWARD_SEMANTIC_CACHE_SIMILARITY_THRESHOLD", 0.85),
-				EmbeddingProvider:   getEnv("FORWARD_EMBEDDING_PROVIDER", "openai"),
+				Enabled:                  getEnvAsBool("FORWARD_SEMANTIC_CACHE_ENABLED", base.Forward.SemanticCache.Enabled),
+				MaxEntries:               getEnvAsInt("FORWARD_SEMANTIC_CACHE_MAX_ENTRIES", base.Forward.SemanticCache.MaxEntries),
+				MaxEntryRows:             getEnvAsInt("FORWARD_SEMANTIC_CACHE_MAX_ENTRY_ROWS", base.Forward.SemanticCache.MaxEntryRows),
+				MaxEntryBytes:            getEnvAsInt("FORWARD_SEMANTIC_CACHE_MAX_ENTRY_BYTES", base.Forward.SemanticCache.MaxEntryBytes),
+				MaxTotalBytes:            getEnvAsInt("FORWARD_SEMANTIC_CACHE_MAX_TOTAL_BYTES", base.Forward.SemanticCache.MaxTotalBytes),
+				TTLHours:                 getEnvAsInt("FORWARD_SEMANTIC_CACHE_TTL_HOURS", base.Forward.SemanticCache.TTLHours),
+				SimilarityThreshold:      getEnvAsFloat("FORWARD_SEMANTIC_CACHE_SIMILARITY_THRESHOLD", base.Forward.SemanticCache.SimilarityThreshold),
+				EmbeddingProvider:        getEnv("FORWARD_EMBEDDING_PROVIDER", base.Forward.SemanticCache.EmbeddingProvider),
+				EmbeddingFallbacks:       getEnvAsList("FORWARD_EMBEDDING_FALLBACKS", ",", base.Forward.SemanticCache.EmbeddingFallbacks),
+				SuggestionFloor:          getEnvAsFloat("FORWARD_SEMANTIC_CACHE_SUGGESTION_FLOOR", base.Forward.SemanticCache.SuggestionFloor),
+				KeywordVocabularyFile:    getEnv("FORWARD_KEYWORD_VOCABULARY_FILE", base.Forward.SemanticCache.KeywordVocabularyFile),
+				AutoTuneTTL:              getEnvAsBool("FORWARD_SEMANTIC_CACHE_AUTO_TTL", base.Forward.SemanticCache.AutoTuneTTL),
+				MaxResidentEmbeddings:    getEnvAsInt("FORWARD_NQE_MAX_RESIDENT_EMBEDDINGS", base.Forward.SemanticCache.MaxResidentEmbeddings),
+				SpecDir:                  getEnv("FORWARD_MCP_SPEC_DIR", base.Forward.SemanticCache.SpecDir),
+				Warmup:                   getEnvAsBool("FORWARD_MCP_CACHE_WARMUP", base.Forward.SemanticCache.Warmup),
+				WarmupCount:              getEnvAsInt("FORWARD_MCP_CACHE_WARMUP_COUNT", base.Forward.SemanticCache.WarmupCount),
+				WarmupFile:               getEnv("FORWARD_MCP_CACHE_WARMUP_FILE", base.Forward.SemanticCache.WarmupFile),
+				AutoRegenerateEmbeddings: getEnvAsBool("FORWARD_MCP_AUTO_REGENERATE_EMBEDDINGS", base.Forward.SemanticCache.AutoRegenerateEmbeddings),
 			},
 		},
 		MCP: MCPConfig{
-			Version:    getEnv("MCP_VERSION", "v1"),
-			MaxRetries: getEnvAsInt("MCP_MAX_RETRIES", 3),
+			Version:    getEnv("MCP_VERSION", base.MCP.Version),
+			MaxRetries: getEnvAsInt("MCP_MAX_RETRIES", base.MCP.MaxRetries),
+
+			Redact:         getEnvAsBool("FORWARD_MCP_REDACT", base.MCP.Redact),
+			RedactPatterns: getEnvAsList("FORWARD_MCP_REDACT_PATTERNS", ";", base.MCP.RedactPatterns),
+			MaskAssets:     getEnvAsBool("FORWARD_MCP_MASK_ASSETS", base.MCP.MaskAssets),
+
+			MaxConcurrentToolCalls: getEnvAsInt("FORWARD_MCP_MAX_CONCURRENT_TOOL_CALLS", base.MCP.MaxConcurrentToolCalls),
+			ConcurrencyPolicy:      getEnv("FORWARD_MCP_CONCURRENCY_POLICY", base.MCP.ConcurrencyPolicy),
+
+			JSONFormat: getEnv("FORWARD_MCP_JSON_FORMAT", base.MCP.JSONFormat),
 		},
 	}
 
-	// Try to load JSON config file
-	if err := loadJSONConfig(config); err != nil {
-		debugLogger := logger.New()
-		debugLogger.Debug("Could not load JSON config file: %v", err)
+	return config
+}
+
+// Validate checks that required settings are present and well-formed, returning one
+// error that lists every problem so they can all be fixed at once
+func (c *Config) Validate() error {
+	var problems []string
+
+	if strings.TrimSpace(c.Forward.APIKey) == "" {
+		problems = append(problems, "FORWARD_API_KEY is required")
+	}
+	if strings.TrimSpace(c.Forward.APISecret) == "" {
+		problems = append(problems, "FORWARD_API_SECRET is required")
+	}
+	if strings.TrimSpace(c.Forward.APIBaseURL) == "" {
+		problems = append(problems, "FORWARD_API_BASE_URL is required")
+	} else if parsed, err := url.Parse(c.Forward.APIBaseURL); err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		problems = append(problems, fmt.Sprintf("FORWARD_API_BASE_URL %q must be an absolute URL such as https://fwd.app", c.Forward.APIBaseURL))
 	}
 
-	return config
+	if c.Forward.ProxyURL != "" {
+		if parsed, err := url.Parse(c.Forward.ProxyURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("FORWARD_PROXY_URL %q must be an absolute URL such as http://proxy.example.com:3128", c.Forward.ProxyURL))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// defaultConfig returns the built-in configuration defaults
+func defaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:        8080,
+			Host:        "0.0.0.0",
+			MetricsHost: "127.0.0.1",
+		},
+		Forward: ForwardConfig{
+			Timeout:                30,
+			DefaultQueryLimit:      10000,
+			MaxQueryLimit:          10000,
+			NQEResponseBudgetBytes: 262144,
+			LatestSnapshotTTL:      60,
+			ListCacheTTL:           30,
+			NQEPageCacheTTL:        300,
+			CoalesceLookups:        true,
+			SemanticCache: SemanticCacheConfig{
+				Enabled:             true,
+				MaxEntries:          1000,
+				TTLHours:            24,
+				SimilarityThreshold: 0.85,
+				EmbeddingProvider:   "openai",
+				SuggestionFloor:     0.5,
+				WarmupCount:         10,
+			},
+		},
+		MCP: MCPConfig{
+			Version:           "v1",
+			MaxRetries:        3,
+			ConcurrencyPolicy: "queue",
+			JSONFormat:        "formatted",
+		},
+	}
 }
 
 // loadEnvFile loads environment variables from .env file
@@ -112,20 +355,21 @@ func loadEnvFile() {
 	}
 }
 
-// loadJSONConfig loads configuration from a JSON file
-func loadJSONConfig(config *Config) error {
-	// Try to find config file in common locations
-	configPaths := []string{
-		"config.json",
-		"examples/config.json",
-		"/etc/forward-mcp/config.json",
+// loadConfigFile overlays values from the config file named by FORWARD_MCP_CONFIG, or
+// the first file found in defaultConfigPaths, onto config
+func loadConfigFile(config *Config) error {
+	configPaths := defaultConfigPaths
+	if path := getEnv(configFileEnv, ""); path != "" {
+		configPaths = []string{path}
 	}
 
 	var configFile []byte
+	var configPath string
 	var err error
 	for _, path := range configPaths {
 		configFile, err = os.ReadFile(path)
 		if err == nil {
+			configPath = path
 			break
 		}
 	}
@@ -133,35 +377,39 @@ func loadJSONConfig(config *Config) error {
 		return fmt.Errorf("could not find config file in any location: %w", err)
 	}
 
-	// Parse JSON config
-	var jsonConfig struct {
-		Forward ForwardConfig `json:"forward"`
-	}
-	if err := json.Unmarshal(configFile, &jsonConfig); err != nil {
-		return fmt.Errorf("failed to parse JSON config: %w", err)
+	if err := parseConfigFile(configPath, configFile, config); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", configPath, err)
 	}
+	return nil
+}
 
-	// Update config with JSON values if they are not empty
-	if jsonConfig.Forward.APIKey != "" {
-		config.Forward.APIKey = jsonConfig.Forward.APIKey
-	}
-	if jsonConfig.Forward.APISecret != "" {
-		config.Forward.APISecret = jsonConfig.Forward.APISecret
-	}
-	if jsonConfig.Forward.APIBaseURL != "" {
-		config.Forward.APIBaseURL = jsonConfig.Forward.APIBaseURL
-	}
-	if jsonConfig.Forward.DefaultNetworkID != "" {
-		config.Forward.DefaultNetworkID = jsonConfig.Forward.DefaultNetworkID
-	}
-	if jsonConfig.Forward.DefaultSnapshotID != "" {
-		config.Forward.DefaultSnapshotID = jsonConfig.Forward.DefaultSnapshotID
-	}
-	if jsonConfig.Forward.DefaultQueryLimit > 0 {
-		config.Forward.DefaultQueryLimit = jsonConfig.Forward.DefaultQueryLimit
+// parseConfigFile decodes a YAML or JSON config file onto config. Only keys present in
+// the file are changed. YAML is converted to JSON first so both formats share the
+// struct's json tags.
+func parseConfigFile(path string, data []byte, config *Config) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		var values map[string]interface{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		converted, err := json.Marshal(values)
+		if err != nil {
+			return err
+		}
+		data = converted
 	}
 
-	return nil
+	fileConfig := struct {
+		Server  *ServerConfig  `json:"server"`
+		Forward *ForwardConfig `json:"forward"`
+		MCP     *MCPConfig     `json:"mcp"`
+	}{
+		Server:  &config.Server,
+		Forward: &config.Forward,
+		MCP:     &config.MCP,
+	}
+	return json.Unmarshal(data, &fileConfig)
 }
 
 // Helper function to get environment variable with default
@@ -200,3 +448,19 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 	}
 	return defaultValue
 }
+
+// Helper function to get environment variable as a list split on sep, skipping empty items
+func getEnvAsList(key, sep string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig_YAMLFileValues(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(configFileEnv, writeConfigFile(t, "forward-mcp.yaml", `
+server:
+  port: 9090
+forward:
+  apiBaseUrl: https://fwd.example.com
+  timeout: 45
+  semanticCache:
+    ttlHours: 6
+mcp:
+  redact: true
+  redactPatterns:
+    - "token=\\S+"
+`))
+
+	cfg := LoadConfig()
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Expected port 9090 from file, got %d", cfg.Server.Port)
+	}
+	if cfg.Forward.APIBaseURL != "https://fwd.example.com" {
+		t.Errorf("Expected base URL from file, got %q", cfg.Forward.APIBaseURL)
+	}
+	if cfg.Forward.Timeout != 45 || cfg.Forward.SemanticCache.TTLHours != 6 {
+		t.Errorf("Expected timeout 45 and TTL 6 from file, got %d and %d", cfg.Forward.Timeout, cfg.Forward.SemanticCache.TTLHours)
+	}
+	if !cfg.MCP.Redact || len(cfg.MCP.RedactPatterns) != 1 {
+		t.Errorf("Expected redaction settings from file, got %+v", cfg.MCP)
+	}
+	// Keys absent from the file keep their defaults
+	if cfg.Forward.SemanticCache.MaxEntries != 1000 || cfg.Server.Host != "0.0.0.0" || cfg.Server.MetricsHost != "127.0.0.1" {
+		t.Errorf("Expected defaults for unset keys, got maxEntries=%d host=%q metricsHost=%q", cfg.Forward.SemanticCache.MaxEntries, cfg.Server.Host, cfg.Server.MetricsHost)
+	}
+}
+
+func TestLoadConfig_EnvOverridesJSONFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(configFileEnv, writeConfigFile(t, "forward-mcp.json", `{
+  "forward": {"apiBaseUrl": "https://file.example.com", "timeout": 45}
+}`))
+	t.Setenv("FORWARD_TIMEOUT", "90")
+
+	cfg := LoadConfig()
+
+	if cfg.Forward.Timeout != 90 {
+		t.Errorf("Expected env timeout 90 to override file, got %d", cfg.Forward.Timeout)
+	}
+	if cfg.Forward.APIBaseURL != "https://file.example.com" {
+		t.Errorf("Expected base URL from file, got %q", cfg.Forward.APIBaseURL)
+	}
+}
+
+func TestLoadConfig_NoFileUsesDefaults(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(configFileEnv, "")
+
+	cfg := LoadConfig()
+
+	if cfg.Forward.Timeout != 30 || cfg.Server.Port != 8080 || cfg.MCP.Version != "v1" {
+		t.Errorf("Expected built-in defaults without a config file, got %+v", cfg)
+	}
+}
+
+func validConfig() *Config {
+	cfg := defaultConfig()
+	cfg.Forward.APIKey = "key"
+	cfg.Forward.APISecret = "secret"
+	cfg.Forward.APIBaseURL = "https://fwd.app"
+	return cfg
+}
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		modify      func(cfg *Config)
+		expectedErr []string
+	}{
+		{name: "valid", modify: func(cfg *Config) {}},
+		{
+			name:        "missing key",
+			modify:      func(cfg *Config) { cfg.Forward.APIKey = "" },
+			expectedErr: []string{"FORWARD_API_KEY is required"},
+		},
+		{
+			name:        "missing URL",
+			modify:      func(cfg *Config) { cfg.Forward.APIBaseURL = "" },
+			expectedErr: []string{"FORWARD_API_BASE_URL is required"},
+		},
+		{
+			name:        "malformed URL",
+			modify:      func(cfg *Config) { cfg.Forward.APIBaseURL = "fwd.app/api" },
+			expectedErr: []string{"must be an absolute URL"},
+		},
+		{
+			name:        "malformed proxy URL",
+			modify:      func(cfg *Config) { cfg.Forward.ProxyURL = "proxy.example.com:3128" },
+			expectedErr: []string{"FORWARD_PROXY_URL"},
+		},
+		{
+			name: "every problem is listed",
+			modify: func(cfg *Config) {
+				cfg.Forward.APIKey = ""
+				cfg.Forward.APISecret = " "
+				cfg.Forward.APIBaseURL = "://bad"
+			},
+			expectedErr: []string{"FORWARD_API_KEY is required", "FORWARD_API_SECRET is required", "must be an absolute URL"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := validConfig()
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tc.expectedErr) == 0 {
+				if err != nil {
+					t.Fatalf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected validation error, got nil")
+			}
+			for _, expected := range tc.expectedErr {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected error to contain %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package cursor encodes list positions as opaque tokens, so paging survives across calls
+// without exposing raw offsets and stale cursors can be rejected instead of silently
+// returning the wrong page.
+package cursor
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidCursor is returned for tokens that are not cursors produced by Encode
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrStaleCursor is returned when a cursor was issued for a different network,
+	// snapshot, or set of filters than the current request
+	ErrStaleCursor = errors.New("stale cursor")
+)
+
+// Cursor is the position of the next page of a list request
+type Cursor struct {
+	NetworkID  string `json:"n"`
+	SnapshotID string `json:"s,omitempty"`
+	Offset     int    `json:"o"`
+	FilterHash string `json:"f,omitempty"`
+}
+
+// HashFilters returns a stable hash of a request's filters. Empty values are ignored,
+// so omitting a filter and passing it empty hash the same.
+func HashFilters(filters map[string]string) string {
+	keys := make([]string, 0, len(filters))
+	for key, value := range filters {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", key, filters[key])
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Encode renders c as a URL-safe base64 token
+func Encode(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a token produced by Encode
+func Decode(token string) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if c.NetworkID == "" || c.Offset < 0 {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Validate checks that c was issued for the same network, snapshot, and filters as the
+// current request
+func (c Cursor) Validate(networkID, snapshotID, filterHash string) error {
+	if c.NetworkID != networkID || c.SnapshotID != snapshotID {
+		return fmt.Errorf("%w: issued for network %s snapshot %q, not network %s snapshot %q", ErrStaleCursor, c.NetworkID, c.SnapshotID, networkID, snapshotID)
+	}
+	if c.FilterHash != filterHash {
+		return fmt.Errorf("%w: filters changed since the cursor was issued; restart paging without a cursor", ErrStaleCursor)
+	}
+	return nil
+}
+
+// Parse decodes token and validates it against the current request in one step
+func Parse(token, networkID, snapshotID, filterHash string) (Cursor, error) {
+	c, err := Decode(token)
+	if err != nil {
+		return c, err
+	}
+	return c, c.Validate(networkID, snapshotID, filterHash)
+}
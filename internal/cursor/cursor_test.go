@@ -0,0 +1,39 @@
+package cursor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	filters := HashFilters(map[string]string{"platform": "ios", "vendor": "cisco"})
+	original := Cursor{NetworkID: "162112", SnapshotID: "snapshot-123", Offset: 200, FilterHash: filters}
+
+	token := Encode(original)
+	decoded, err := Parse(token, "162112", "snapshot-123", filters)
+	if err != nil {
+		t.Fatalf("Expected cursor to round-trip, got: %v", err)
+	}
+	if decoded != original {
+		t.Errorf("Expected %+v, got %+v", original, decoded)
+	}
+
+	if HashFilters(map[string]string{"vendor": "cisco", "platform": "ios", "model": ""}) != filters {
+		t.Error("Expected filter hash to ignore key order and empty values")
+	}
+}
+
+func TestCursorRejectsMismatchedFilters(t *testing.T) {
+	token := Encode(Cursor{NetworkID: "162112", Offset: 100, FilterHash: HashFilters(map[string]string{"vendor": "cisco"})})
+
+	_, err := Parse(token, "162112", "", HashFilters(map[string]string{"vendor": "juniper"}))
+	if !errors.Is(err, ErrStaleCursor) {
+		t.Errorf("Expected stale cursor error for changed filters, got: %v", err)
+	}
+	if _, err := Parse(token, "999", "", HashFilters(map[string]string{"vendor": "cisco"})); !errors.Is(err, ErrStaleCursor) {
+		t.Errorf("Expected stale cursor error for another network, got: %v", err)
+	}
+	if _, err := Decode("not a cursor!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected invalid cursor error, got: %v", err)
+	}
+}
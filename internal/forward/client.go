@@ -2,20 +2,215 @@ package forward
 
 import (
 	"bytes"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/forward-mcp/internal/config"
 	"github.com/forward-mcp/internal/logger"
 )
 
+// Version identifies this build in the User-Agent sent to the Forward API; set at build
+// time with -ldflags "-X github.com/forward-mcp/internal/forward.Version=<version>"
+var Version = "dev"
+
+// ErrNoProcessedSnapshot is returned by GetLatestSnapshot when a network has never been
+// processed, so there is no latest snapshot to resolve
+var ErrNoProcessedSnapshot = errors.New("network has no processed snapshots")
+
+// Common Forward API conditions; an APIError wraps the one matching its status code so
+// callers can branch with errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServerError  = errors.New("server error")
+)
+
+// ErrNQEQueryNotFound is returned by RunNQEQueryByID when the API does not know the query ID
+var ErrNQEQueryNotFound = errors.New("NQE query not found")
+
+// NQEExecutionError is an NQE query that was found but failed to run, such as a runtime
+// error in its code or a bad parameter. It unwraps to the underlying *APIError.
+type NQEExecutionError struct {
+	QueryID string
+	Detail  string
+	Err     error
+}
+
+func (e *NQEExecutionError) Error() string {
+	return fmt.Sprintf("NQE query %s failed to execute: %s", e.QueryID, e.Detail)
+}
+
+func (e *NQEExecutionError) Unwrap() error {
+	return e.Err
+}
+
+// classifyNQERunError tells an unknown query ID apart from a query that failed to run.
+// A 404 whose body says the query was not found means the query ID is unknown; a 400 or
+// 422 carries the execution failure, as does a 500 whose body has the NQE error shape.
+// Other errors, such as a 404 for another resource or a 500 from an outage, are returned
+// unchanged.
+func classifyNQERunError(queryID string, err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound:
+		if isQueryNotFoundBody(apiErr.Body) {
+			return fmt.Errorf("%w: %s", ErrNQEQueryNotFound, queryID)
+		}
+		return err
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+	case http.StatusInternalServerError:
+		var info nqeErrorInfo
+		if json.Unmarshal([]byte(apiErr.Body), &info) != nil || (info.CompletionType == "" && len(info.Errors) == 0) {
+			return err
+		}
+	default:
+		return err
+	}
+
+	detail := strings.TrimSpace(apiErr.Body)
+	var info nqeErrorInfo
+	if json.Unmarshal([]byte(apiErr.Body), &info) == nil && len(info.Errors) > 0 {
+		messages := make([]string, 0, len(info.Errors))
+		for _, queryErr := range info.Errors {
+			messages = append(messages, queryErr.Message)
+		}
+		detail = strings.Join(messages, "; ")
+	} else {
+		var body struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal([]byte(apiErr.Body), &body) == nil && body.Message != "" {
+			detail = body.Message
+		}
+	}
+	if detail == "" {
+		detail = fmt.Sprintf("status %d", apiErr.StatusCode)
+	}
+	return &NQEExecutionError{QueryID: queryID, Detail: detail, Err: err}
+}
+
+// isQueryNotFoundBody reports whether a 404 body says the NQE query was not found, e.g.
+// {"message": "Query not found"}, rather than another resource such as the network
+func isQueryNotFoundBody(body string) bool {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal([]byte(body), &payload) != nil {
+		return false
+	}
+	message := strings.ToLower(payload.Message)
+	return strings.Contains(message, "query") && strings.Contains(message, "not found")
+}
+
+// APIError is a non-2xx response from the Forward API
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	if e.Body != "" {
+		msg += fmt.Sprintf(", response: %s", e.Body)
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error for the status code, or nil when none applies
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	}
+	return nil
+}
+
+// decodeSnippetLength is how much of an undecodable body is quoted in DecodeError
+const decodeSnippetLength = 200
+
+// DecodeError is a 2xx response whose body could not be decoded as the expected JSON,
+// such as an HTML error page from a proxy in front of the API
+type DecodeError struct {
+	ContentType string
+	Snippet     string
+	Err         error
+}
+
+func (e *DecodeError) Error() string {
+	msg := fmt.Sprintf("failed to decode response: %v", e.Err)
+	if e.nonJSON() {
+		msg += " (response was not JSON"
+		if e.ContentType != "" {
+			msg += fmt.Sprintf("; content type %s", e.ContentType)
+		}
+		msg += ")"
+	} else if e.ContentType != "" {
+		msg += fmt.Sprintf(" (content type %s)", e.ContentType)
+	}
+	if e.Snippet != "" {
+		msg += fmt.Sprintf(", body starts: %q", e.Snippet)
+	}
+	return msg
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// nonJSON reports whether the content type or body shows the response was not JSON at all
+func (e *DecodeError) nonJSON() bool {
+	if e.ContentType != "" && !strings.Contains(strings.ToLower(e.ContentType), "json") {
+		return true
+	}
+	return strings.HasPrefix(e.Snippet, "<")
+}
+
+// newDecodeError describes a body that failed to decode, quoting its start
+func newDecodeError(resp *http.Response, body []byte, err error) *DecodeError {
+	snippet := strings.TrimSpace(string(body))
+	if len(snippet) > decodeSnippetLength {
+		snippet = snippet[:decodeSnippetLength] + "..."
+	}
+	return &DecodeError{ContentType: resp.Header.Get("Content-Type"), Snippet: snippet, Err: err}
+}
+
+// decodeResponse decodes a JSON response body into v. Failures return a *DecodeError
+// with the content type and the start of the body; an empty body wraps io.EOF.
+func decodeResponse(resp *http.Response, v interface{}) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return newDecodeError(resp, body, io.EOF)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return newDecodeError(resp, body, err)
+	}
+	return nil
+}
+
 // ClientInterface defines the interface for Forward platform client operations
 type ClientInterface interface {
 	// Legacy chat operations (keeping for backward compatibility)
@@ -37,6 +232,7 @@ type ClientInterface interface {
 	RunNQEQueryByID(params *NQEQueryParams) (*NQERunResult, error)
 	GetNQEQueries(dir string) ([]NQEQuery, error)
 	DiffNQEQuery(before, after string, request *NQEDiffRequest) (*NQEDiffResult, error)
+	ValidateNQEQuery(networkID, query string) (*NQEValidationResult, error)
 
 	// Device operations
 	GetDevices(networkID string, params *DeviceQueryParams) (*DeviceResponse, error)
@@ -59,10 +255,63 @@ type ClientInterface interface {
 type Client struct {
 	httpClient *http.Client
 	config     *config.ForwardConfig
+	logger     *logger.Logger
+	observer   RequestObserver
+}
+
+// RequestObserver is told about every Forward API request once it completes: the HTTP
+// method, the endpoint with IDs replaced by {id}, the response status (0 when no response
+// arrived), and how long the request took
+type RequestObserver func(method, endpoint string, status int, duration time.Duration)
+
+// idCollections are the endpoint path segments that are followed by an ID
+var idCollections = map[string]bool{"networks": true, "snapshots": true, "locations": true}
+
+// endpointLabel reduces an endpoint to its route, dropping the query string and replacing
+// network, snapshot, and location IDs with {id}, so requests group by route in metrics
+func endpointLabel(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	segments := strings.Split(path, "/")
+	label := make([]string, len(segments))
+	for i, segment := range segments {
+		label[i] = segment
+		if i == 0 {
+			continue
+		}
+		previous := segments[i-1]
+		inDiff := previous == "nqe-diffs" || (i >= 2 && segments[i-2] == "nqe-diffs")
+		if inDiff || (idCollections[previous] && segment != "latestProcessed") {
+			label[i] = "{id}"
+		}
+	}
+	return strings.Join(label, "/")
+}
+
+// userAgent is the configured User-Agent, or forward-mcp/<Version>
+func (c *Client) userAgent() string {
+	if c.config.UserAgent != "" {
+		return c.config.UserAgent
+	}
+	return "forward-mcp/" + Version
+}
+
+// newRequestID returns a random (version 4) UUID to correlate a request with Forward's logs
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:]) // crypto/rand.Read never fails
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 }
 
 // NewClient creates a new Forward platform client
 func NewClient(config *config.ForwardConfig) ClientInterface {
+	return NewObservedClient(config, nil)
+}
+
+// NewObservedClient creates a Forward platform client that reports every API request to
+// observer (nil observes nothing)
+func NewObservedClient(config *config.ForwardConfig, observer RequestObserver) ClientInterface {
 	// Create TLS configuration
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: config.InsecureSkipVerify,
@@ -87,9 +336,19 @@ func NewClient(config *config.ForwardConfig) ClientInterface {
 		}
 	}
 
+	clientLogger := logger.New()
+
+	// Route through the configured proxy, or the one named by the environment
+	proxy, err := proxyFunc(config)
+	if err != nil {
+		clientLogger.Warn("Ignoring proxy settings: %v", err)
+		proxy = http.ProxyFromEnvironment
+	}
+
 	// Create custom transport with TLS configuration
 	transport := &http.Transport{
 		TLSClientConfig: tlsConfig,
+		Proxy:           proxy,
 	}
 
 	return &Client{
@@ -97,7 +356,9 @@ func NewClient(config *config.ForwardConfig) ClientInterface {
 			Timeout:   time.Duration(config.Timeout) * time.Second,
 			Transport: transport,
 		},
-		config: config,
+		config:   config,
+		logger:   clientLogger,
+		observer: observer,
 	}
 }
 
@@ -152,6 +413,8 @@ type PathSearchResponse struct {
 	SnapshotID         string                 `json:"snapshotId"`
 	SearchTimeMs       int                    `json:"searchTimeMs"`
 	NumCandidatesFound int                    `json:"numCandidatesFound"`
+	// Error is set on a paths-bulk entry whose individual search failed
+	Error string `json:"error,omitempty"`
 }
 
 type Path struct {
@@ -220,6 +483,34 @@ type NQEDiffResult struct {
 	Rows           []map[string]interface{} `json:"rows"`
 }
 
+// NQEValidationError is one compile error in an NQE query; Line and Column are 1-based
+// and zero when the API did not report a location
+type NQEValidationError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// NQEValidationResult reports whether an NQE query compiles
+type NQEValidationResult struct {
+	Valid  bool                 `json:"valid"`
+	Errors []NQEValidationError `json:"errors,omitempty"`
+}
+
+// nqeErrorInfo is the body of a 400 response from /api/nqe. Positions are 0-based.
+type nqeErrorInfo struct {
+	CompletionType string `json:"completionType"`
+	Errors         []struct {
+		Message  string `json:"message"`
+		Location *struct {
+			Start struct {
+				Line      int `json:"line"`
+				Character int `json:"character"`
+			} `json:"start"`
+		} `json:"location"`
+	} `json:"errors"`
+}
+
 // Device types
 type DeviceQueryParams struct {
 	SnapshotID string `json:"snapshotId,omitempty"`
@@ -329,10 +620,22 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.R
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent())
+	req.Header.Set("X-Request-Id", newRequestID())
 	auth := base64.StdEncoding.EncodeToString([]byte(c.config.APIKey + ":" + c.config.APISecret))
 	req.Header.Set("Authorization", "Basic "+auth)
 
+	c.logger.Debug("API Request - Method: %s, URL: %s, Headers: %s", method, req.URL.String(), formatHeadersForLog(req.Header))
+
+	started := time.Now()
 	resp, err := c.httpClient.Do(req)
+	if c.observer != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		c.observer(method, endpointLabel(endpoint), status, time.Since(started))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -342,24 +645,50 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.R
 		errorBody, readErr := io.ReadAll(resp.Body)
 		resp.Body.Close()
 
-		errorMsg := fmt.Sprintf("unexpected status code: %d", resp.StatusCode)
-		if readErr == nil && len(errorBody) > 0 {
-			errorMsg += fmt.Sprintf(", response: %s", string(errorBody))
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if readErr == nil {
+			apiErr.Body = string(errorBody)
 		}
 
 		// Log additional debugging information for 400 errors
 		if resp.StatusCode == 400 {
-			debugLogger := logger.New()
-			debugLogger.Debug("400 Bad Request - URL: %s%s, Method: %s, Request Body: %s",
-				c.config.APIBaseURL, endpoint, method, string(reqBody))
+			c.logger.Debug("400 Bad Request - URL: %s%s, Method: %s, Headers: %s, Request Body: %s",
+				c.config.APIBaseURL, endpoint, method, formatHeadersForLog(req.Header), string(reqBody))
 		}
 
-		return nil, fmt.Errorf("%s", errorMsg)
+		return nil, apiErr
 	}
 
 	return resp, nil
 }
 
+// formatHeadersForLog renders request headers for logging with credentials masked
+func formatHeadersForLog(headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(headers.Values(name), ",")
+		if strings.EqualFold(name, "Authorization") {
+			value = maskAuthorization(value)
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", name, value))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// maskAuthorization keeps the auth scheme but hides the credentials
+func maskAuthorization(value string) string {
+	if scheme, _, found := strings.Cut(value, " "); found {
+		return scheme + " ****"
+	}
+	return "****"
+}
+
 // Legacy methods for backward compatibility
 func (c *Client) SendChatRequest(req *ChatRequest) (*ChatResponse, error) {
 	resp, err := c.makeRequest("POST", "/chat", req)
@@ -369,8 +698,8 @@ func (c *Client) SendChatRequest(req *ChatRequest) (*ChatResponse, error) {
 	defer resp.Body.Close()
 
 	var chatResp ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &chatResp); err != nil {
+		return nil, err
 	}
 
 	return &chatResp, nil
@@ -384,8 +713,8 @@ func (c *Client) GetAvailableModels() ([]string, error) {
 	defer resp.Body.Close()
 
 	var models []string
-	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &models); err != nil {
+		return nil, err
 	}
 
 	return models, nil
@@ -400,8 +729,8 @@ func (c *Client) GetNetworks() ([]Network, error) {
 	defer resp.Body.Close()
 
 	var networks []Network
-	if err := json.NewDecoder(resp.Body).Decode(&networks); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &networks); err != nil {
+		return nil, err
 	}
 
 	return networks, nil
@@ -415,8 +744,8 @@ func (c *Client) CreateNetwork(name string) (*Network, error) {
 	defer resp.Body.Close()
 
 	var network Network
-	if err := json.NewDecoder(resp.Body).Decode(&network); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &network); err != nil {
+		return nil, err
 	}
 
 	return &network, nil
@@ -430,8 +759,8 @@ func (c *Client) DeleteNetwork(networkID string) (*Network, error) {
 	defer resp.Body.Close()
 
 	var network Network
-	if err := json.NewDecoder(resp.Body).Decode(&network); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &network); err != nil {
+		return nil, err
 	}
 
 	return &network, nil
@@ -445,8 +774,8 @@ func (c *Client) UpdateNetwork(networkID string, update *NetworkUpdate) (*Networ
 	defer resp.Body.Close()
 
 	var network Network
-	if err := json.NewDecoder(resp.Body).Decode(&network); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &network); err != nil {
+		return nil, err
 	}
 
 	return &network, nil
@@ -502,8 +831,8 @@ func (c *Client) SearchPaths(networkID string, params *PathSearchParams) (*PathS
 	defer resp.Body.Close()
 
 	var pathResp PathSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&pathResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &pathResp); err != nil {
+		return nil, err
 	}
 
 	return &pathResp, nil
@@ -519,8 +848,8 @@ func (c *Client) SearchPathsBulk(networkID string, requests []PathSearchParams)
 	defer resp.Body.Close()
 
 	var responses []PathSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &responses); err != nil {
+		return nil, err
 	}
 
 	return responses, nil
@@ -568,8 +897,8 @@ func (c *Client) RunNQEQueryByString(params *NQEQueryParams) (*NQERunResult, err
 	defer resp.Body.Close()
 
 	var result NQERunResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -605,13 +934,13 @@ func (c *Client) RunNQEQueryByID(params *NQEQueryParams) (*NQERunResult, error)
 
 	resp, err := c.makeRequest("POST", endpoint+query, requestBody)
 	if err != nil {
-		return nil, err
+		return nil, classifyNQERunError(params.QueryID, err)
 	}
 	defer resp.Body.Close()
 
 	var result NQERunResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -651,7 +980,7 @@ func (c *Client) GetNQEQueries(dir string) ([]NQEQuery, error) {
 		// If the first attempt fails, try to parse as a single object
 		var singleQuery NQEQuery
 		if err := json.Unmarshal(body, &singleQuery); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %w", err)
+			return nil, newDecodeError(resp, body, err)
 		}
 		queries = []NQEQuery{singleQuery}
 	}
@@ -690,13 +1019,50 @@ func (c *Client) DiffNQEQuery(before, after string, request *NQEDiffRequest) (*N
 	defer resp.Body.Close()
 
 	var result NQEDiffResult
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	return &result, nil
 }
 
+// ValidateNQEQuery compiles an NQE query against a network. The API has no compile-only
+// endpoint, so a valid query is executed for a single row (a limit of 0 would be dropped as
+// unset and run the full query); compile errors are read from the 400 response and other
+// failures are returned as errors.
+func (c *Client) ValidateNQEQuery(networkID, query string) (*NQEValidationResult, error) {
+	endpoint := fmt.Sprintf("/api/nqe?networkId=%s", networkID)
+	requestBody := map[string]interface{}{
+		"query":        query,
+		"queryOptions": map[string]interface{}{"limit": 1},
+	}
+
+	resp, err := c.makeRequest("POST", endpoint, requestBody)
+	if err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			return nil, err
+		}
+		var info nqeErrorInfo
+		if json.Unmarshal([]byte(apiErr.Body), &info) != nil || len(info.Errors) == 0 {
+			return nil, err
+		}
+		result := &NQEValidationResult{}
+		for _, queryErr := range info.Errors {
+			validationErr := NQEValidationError{Message: queryErr.Message}
+			if queryErr.Location != nil {
+				validationErr.Line = queryErr.Location.Start.Line + 1
+				validationErr.Column = queryErr.Location.Start.Character + 1
+			}
+			result.Errors = append(result.Errors, validationErr)
+		}
+		return result, nil
+	}
+	resp.Body.Close()
+
+	return &NQEValidationResult{Valid: true}, nil
+}
+
 // Device operations
 func (c *Client) GetDevices(networkID string, params *DeviceQueryParams) (*DeviceResponse, error) {
 	endpoint := fmt.Sprintf("/api/networks/%s/devices", networkID)
@@ -731,8 +1097,8 @@ func (c *Client) GetDevices(networkID string, params *DeviceQueryParams) (*Devic
 
 	// The API returns a direct array of devices, not wrapped in a response object
 	var devices []Device
-	if err := json.NewDecoder(resp.Body).Decode(&devices); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &devices); err != nil {
+		return nil, err
 	}
 
 	// Wrap in our response structure for consistency
@@ -754,8 +1120,8 @@ func (c *Client) GetDeviceLocations(networkID string) (map[string]string, error)
 	defer resp.Body.Close()
 
 	var locations map[string]string
-	if err := json.NewDecoder(resp.Body).Decode(&locations); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &locations); err != nil {
+		return nil, err
 	}
 
 	return locations, nil
@@ -785,8 +1151,8 @@ func (c *Client) GetSnapshots(networkID string) ([]Snapshot, error) {
 
 	// The API returns an object with a snapshots array property
 	var snapshotsResp SnapshotsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&snapshotsResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &snapshotsResp); err != nil {
+		return nil, err
 	}
 
 	return snapshotsResp.Snapshots, nil
@@ -797,18 +1163,37 @@ func (c *Client) GetLatestSnapshot(networkID string) (*Snapshot, error) {
 
 	resp, err := c.makeRequest("GET", endpoint, nil)
 	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, c.noProcessedSnapshotError(networkID, err)
+		}
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	var snapshot Snapshot
-	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &snapshot); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, c.noProcessedSnapshotError(networkID, err)
+		}
+		return nil, err
 	}
 
 	return &snapshot, nil
 }
 
+// noProcessedSnapshotError explains a 404 or empty latestProcessed response. The endpoint
+// answers the same way for an unknown network, so the network is looked up before the
+// failure is reported as ErrNoProcessedSnapshot.
+func (c *Client) noProcessedSnapshotError(networkID string, cause error) error {
+	if _, err := c.GetSnapshots(networkID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("network %s: %w", networkID, err)
+		}
+		return fmt.Errorf("latest snapshot of network %s: %w", networkID, cause)
+	}
+	return fmt.Errorf("%w (network %s)", ErrNoProcessedSnapshot, networkID)
+}
+
 func (c *Client) DeleteSnapshot(snapshotID string) error {
 	endpoint := fmt.Sprintf("/api/snapshots/%s", snapshotID)
 
@@ -832,8 +1217,8 @@ func (c *Client) GetLocations(networkID string) ([]Location, error) {
 	defer resp.Body.Close()
 
 	var locations []Location
-	if err := json.NewDecoder(resp.Body).Decode(&locations); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &locations); err != nil {
+		return nil, err
 	}
 
 	return locations, nil
@@ -849,8 +1234,8 @@ func (c *Client) CreateLocation(networkID string, location *LocationCreate) (*Lo
 	defer resp.Body.Close()
 
 	var newLocation Location
-	if err := json.NewDecoder(resp.Body).Decode(&newLocation); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &newLocation); err != nil {
+		return nil, err
 	}
 
 	return &newLocation, nil
@@ -866,8 +1251,8 @@ func (c *Client) UpdateLocation(networkID string, locationID string, update *Loc
 	defer resp.Body.Close()
 
 	var location Location
-	if err := json.NewDecoder(resp.Body).Decode(&location); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &location); err != nil {
+		return nil, err
 	}
 
 	return &location, nil
@@ -883,8 +1268,8 @@ func (c *Client) DeleteLocation(networkID string, locationID string) (*Location,
 	defer resp.Body.Close()
 
 	var location Location
-	if err := json.NewDecoder(resp.Body).Decode(&location); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeResponse(resp, &location); err != nil {
+		return nil, err
 	}
 
 	return &location, nil
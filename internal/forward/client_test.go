@@ -1,13 +1,17 @@
 package forward
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/forward-mcp/internal/config"
+	"github.com/forward-mcp/internal/logger"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -157,3 +161,324 @@ func TestClient_GetAvailableModels(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_LogsMaskAuthorizationHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "bad request"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := NewClient(&config.ForwardConfig{
+		APIKey:     "test-api-key",
+		APISecret:  "test-api-secret",
+		APIBaseURL: server.URL,
+		Timeout:    5,
+	}).(*Client)
+	client.logger = logger.NewWithWriter(&logs)
+	client.logger.SetDebugMode(true)
+
+	_, err := client.GetNetworks()
+	assert.Error(t, err)
+
+	credentials := base64.StdEncoding.EncodeToString([]byte("test-api-key:test-api-secret"))
+	output := logs.String()
+	assert.Contains(t, output, "Authorization=Basic ****")
+	assert.Contains(t, output, "400 Bad Request")
+	assert.NotContains(t, output, credentials)
+	assert.NotContains(t, output, "test-api-secret")
+}
+
+func TestClient_GetLatestSnapshotWithoutProcessedSnapshots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/networks/net-empty/snapshots" {
+			w.Write([]byte(`{"snapshots": []}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "No processed snapshot"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.ForwardConfig{
+		APIKey:     "test-api-key",
+		APISecret:  "test-api-secret",
+		APIBaseURL: server.URL,
+		Timeout:    5,
+	})
+
+	snapshot, err := client.GetLatestSnapshot("net-empty")
+	assert.Nil(t, snapshot)
+	assert.ErrorIs(t, err, ErrNoProcessedSnapshot)
+
+	// Other endpoints keep reporting the raw status
+	_, err = client.GetNetworks()
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestClient_GetLatestSnapshotOfUnknownNetwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Network not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.ForwardConfig{
+		APIKey:     "test-api-key",
+		APISecret:  "test-api-secret",
+		APIBaseURL: server.URL,
+		Timeout:    5,
+	})
+
+	snapshot, err := client.GetLatestSnapshot("net-missing")
+	assert.Nil(t, snapshot)
+	assert.NotErrorIs(t, err, ErrNoProcessedSnapshot)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, err.Error(), "network net-missing")
+}
+
+func TestClient_DecodeErrorReportsNonJSONBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body><h1>502 Bad Gateway</h1></body></html>"))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.ForwardConfig{
+		APIKey:     "test-api-key",
+		APISecret:  "test-api-secret",
+		APIBaseURL: server.URL,
+		Timeout:    5,
+	})
+
+	_, err := client.GetNetworks()
+	var decodeErr *DecodeError
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Contains(t, err.Error(), "response was not JSON")
+	assert.Contains(t, err.Error(), "text/html")
+	assert.Contains(t, err.Error(), "502 Bad Gateway")
+}
+
+func TestClient_SendsUserAgentAndRequestID(t *testing.T) {
+	var requestIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "forward-mcp/"+Version, r.Header.Get("User-Agent"))
+		requestIDs = append(requestIDs, r.Header.Get("X-Request-Id"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.ForwardConfig{
+		APIKey:     "test-api-key",
+		APISecret:  "test-api-secret",
+		APIBaseURL: server.URL,
+		Timeout:    5,
+	})
+	_, err := client.GetNetworks()
+	assert.NoError(t, err)
+	_, err = client.GetNetworks()
+	assert.NoError(t, err)
+
+	assert.Len(t, requestIDs, 2)
+	for _, id := range requestIDs {
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+	}
+	assert.NotEqual(t, requestIDs[0], requestIDs[1])
+}
+
+func TestClient_ValidateNQEQueryReportsCompileErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]interface{}{"limit": float64(1)}, body["queryOptions"])
+		if strings.Contains(body["query"].(string), "nmae") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"completionType": "FINISHED", "errors": [{"message": "Unknown field nmae", "location": {"start": {"line": 1, "character": 22}}}]}`))
+			return
+		}
+		w.Write([]byte(`{"snapshotId": "101", "items": []}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.ForwardConfig{APIBaseURL: server.URL, Timeout: 5})
+
+	result, err := client.ValidateNQEQuery("162112", "foreach device in network.devices\nselect {name: device.name}")
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	result, err = client.ValidateNQEQuery("162112", "foreach device in network.devices\nselect {name: device.nmae}")
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, []NQEValidationError{{Message: "Unknown field nmae", Line: 2, Column: 23}}, result.Errors)
+}
+
+func TestClient_APIErrorsMatchSentinels(t *testing.T) {
+	tests := []struct {
+		status   int
+		sentinel error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusServiceUnavailable, ErrServerError},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewClient(&config.ForwardConfig{
+				APIKey:     "test-api-key",
+				APISecret:  "test-api-secret",
+				APIBaseURL: server.URL,
+				Timeout:    5,
+			})
+
+			_, err := client.GetNetworks()
+			assert.ErrorIs(t, err, tt.sentinel)
+			var apiErr *APIError
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+		})
+	}
+}
+
+func TestAPIErrorWithoutSentinel(t *testing.T) {
+	err := error(&APIError{StatusCode: http.StatusBadRequest})
+	for _, sentinel := range []error{ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrServerError} {
+		assert.NotErrorIs(t, err, sentinel)
+	}
+}
+
+func TestClient_UsesConfiguredProxy(t *testing.T) {
+	var proxiedHosts []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHosts = append(proxiedHosts, r.URL.Host)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	client := NewClient(&config.ForwardConfig{
+		APIKey:     "test-api-key",
+		APISecret:  "test-api-secret",
+		APIBaseURL: "http://forward.example.invalid",
+		Timeout:    5,
+		ProxyURL:   proxy.URL,
+	})
+	_, err := client.GetNetworks()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"forward.example.invalid"}, proxiedHosts)
+}
+
+func TestClient_NoProxyBypassesConfiguredProxy(t *testing.T) {
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.ForwardConfig{
+		APIKey:     "test-api-key",
+		APISecret:  "test-api-secret",
+		APIBaseURL: server.URL,
+		Timeout:    5,
+		ProxyURL:   proxy.URL,
+		NoProxy:    []string{"127.0.0.0/8", "localhost"},
+	})
+	_, err := client.GetNetworks()
+	assert.NoError(t, err)
+	assert.False(t, proxied, "Expected a no-proxy host to be reached directly")
+}
+
+func TestBypassProxy(t *testing.T) {
+	noProxy := []string{".internal.example.com", "fwd.app", "10.0.0.0/8"}
+	assert.True(t, bypassProxy("api.internal.example.com", noProxy))
+	assert.True(t, bypassProxy("FWD.app", noProxy))
+	assert.True(t, bypassProxy("10.1.2.3", noProxy))
+	assert.False(t, bypassProxy("notfwd.app", noProxy))
+	assert.False(t, bypassProxy("192.168.1.1", noProxy))
+	assert.True(t, bypassProxy("anything", []string{"*"}))
+}
+
+func TestEndpointLabel(t *testing.T) {
+	assert.Equal(t, "/api/networks", endpointLabel("/api/networks?name=lab"))
+	assert.Equal(t, "/api/networks/{id}/snapshots", endpointLabel("/api/networks/101/snapshots"))
+	assert.Equal(t, "/api/networks/{id}/snapshots/latestProcessed", endpointLabel("/api/networks/101/snapshots/latestProcessed"))
+	assert.Equal(t, "/api/networks/{id}/locations/{id}", endpointLabel("/api/networks/101/locations/loc-1"))
+	assert.Equal(t, "/api/nqe-diffs/{id}/{id}", endpointLabel("/api/nqe-diffs/100/200"))
+	assert.Equal(t, "/api/nqe", endpointLabel("/api/nqe?networkId=101"))
+}
+
+func TestClient_RunNQEQueryByIDClassifiesErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch body["queryId"] {
+		case "FQ_missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Query not found"}`))
+		case "FQ_broken":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"completionType": "FAILED", "errors": [{"message": "Division by zero"}, {"message": "Parameter mtu must be a number"}]}`))
+		case "FQ_crashed":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"completionType": "FAILED", "errors": [{"message": "Query timed out"}]}`))
+		case "FQ_wrong_network":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Network 999 not found"}`))
+		case "FQ_outage":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`<html>Internal Server Error</html>`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.ForwardConfig{APIBaseURL: server.URL, Timeout: 5})
+
+	_, err := client.RunNQEQueryByID(&NQEQueryParams{NetworkID: "162112", QueryID: "FQ_missing"})
+	assert.ErrorIs(t, err, ErrNQEQueryNotFound)
+	assert.Contains(t, err.Error(), "FQ_missing")
+
+	_, err = client.RunNQEQueryByID(&NQEQueryParams{NetworkID: "162112", QueryID: "FQ_broken"})
+	var execErr *NQEExecutionError
+	if assert.ErrorAs(t, err, &execErr) {
+		assert.Equal(t, "FQ_broken", execErr.QueryID)
+		assert.Equal(t, "Division by zero; Parameter mtu must be a number", execErr.Detail)
+	}
+	assert.NotErrorIs(t, err, ErrNQEQueryNotFound)
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+
+	_, err = client.RunNQEQueryByID(&NQEQueryParams{NetworkID: "162112", QueryID: "FQ_crashed"})
+	if assert.ErrorAs(t, err, &execErr) {
+		assert.Equal(t, "Query timed out", execErr.Detail)
+	}
+
+	_, err = client.RunNQEQueryByID(&NQEQueryParams{NetworkID: "162112", QueryID: "FQ_wrong_network"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrNQEQueryNotFound, "Expected a 404 for another resource not to report the query as unknown")
+
+	_, err = client.RunNQEQueryByID(&NQEQueryParams{NetworkID: "162112", QueryID: "FQ_outage"})
+	assert.ErrorIs(t, err, ErrServerError)
+	assert.False(t, errors.As(err, &execErr), "Expected a 500 without an NQE error body not to be reported as an execution error")
+
+	_, err = client.RunNQEQueryByID(&NQEQueryParams{NetworkID: "162112", QueryID: "FQ_other"})
+	assert.ErrorIs(t, err, ErrServerError)
+	assert.False(t, errors.As(err, &execErr), "Expected an outage not to be reported as an execution error")
+}
@@ -0,0 +1,57 @@
+package forward
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/forward-mcp/internal/config"
+)
+
+// proxyFunc chooses the proxy for Forward API requests. Without a configured proxy URL the
+// standard HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables apply; with one,
+// every request goes through it except hosts matched by the configured no-proxy list.
+func proxyFunc(cfg *config.ForwardConfig) (func(*http.Request) (*url.URL, error), error) {
+	if cfg.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	proxyURL, err := url.Parse(cfg.ProxyURL)
+	if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: must be absolute, e.g. http://proxy.example.com:3128", cfg.ProxyURL)
+	}
+	noProxy := cfg.NoProxy
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// bypassProxy reports whether host matches a no-proxy entry: "*", an exact host, a domain
+// (with or without a leading dot) covering its subdomains, or an IP range in CIDR form
+func bypassProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		case strings.Contains(entry, "/"):
+			if _, network, err := net.ParseCIDR(entry); err == nil && ip != nil && network.Contains(ip) {
+				return true
+			}
+		default:
+			domain := strings.TrimPrefix(entry, ".")
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
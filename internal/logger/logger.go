@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -11,16 +12,22 @@ type Logger struct {
 	infoLogger  *log.Logger
 	debugLogger *log.Logger
 	debugMode   bool
+	prefix      string // prepended to every message, e.g. a correlation ID
 }
 
 // New creates a new logger instance
 func New() *Logger {
+	return NewWithWriter(os.Stderr)
+}
+
+// NewWithWriter creates a logger that writes to w instead of stderr
+func NewWithWriter(w io.Writer) *Logger {
 	// Check for debug mode from environment
 	debugMode := isDebugEnabled()
 
 	// Create loggers with appropriate prefixes
-	infoLogger := log.New(os.Stderr, "[INFO] ", log.LstdFlags)
-	debugLogger := log.New(os.Stderr, "[DEBUG] ", log.LstdFlags|log.Lshortfile)
+	infoLogger := log.New(w, "[INFO] ", log.LstdFlags)
+	debugLogger := log.New(w, "[DEBUG] ", log.LstdFlags|log.Lshortfile)
 
 	return &Logger{
 		infoLogger:  infoLogger,
@@ -29,6 +36,17 @@ func New() *Logger {
 	}
 }
 
+// WithCorrelationID returns a logger that tags every message with id, sharing this
+// logger's output and debug mode
+func (l *Logger) WithCorrelationID(id string) *Logger {
+	if l == nil {
+		return nil
+	}
+	scoped := *l
+	scoped.prefix = l.prefix + "[" + id + "] "
+	return &scoped
+}
+
 // isDebugEnabled checks environment variables for debug mode
 func isDebugEnabled() bool {
 	debug := os.Getenv("DEBUG")
@@ -47,30 +65,30 @@ func isDebugEnabled() bool {
 
 // Info logs informational messages (always shown)
 func (l *Logger) Info(format string, args ...interface{}) {
-	l.infoLogger.Printf(format, args...)
+	l.infoLogger.Printf(l.prefix+format, args...)
 }
 
 // Debug logs debug messages (only shown if debug mode is enabled)
 func (l *Logger) Debug(format string, args ...interface{}) {
 	if l.debugMode {
-		l.debugLogger.Printf(format, args...)
+		l.debugLogger.Printf(l.prefix+format, args...)
 	}
 }
 
 // Error logs error messages (always shown)
 func (l *Logger) Error(format string, args ...interface{}) {
-	l.infoLogger.Printf("[ERROR] "+format, args...)
+	l.infoLogger.Printf(l.prefix+"[ERROR] "+format, args...)
 }
 
 // Fatalf logs an error message and exits the program
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	l.infoLogger.Printf("[FATAL] "+format, args...)
+	l.infoLogger.Printf(l.prefix+"[FATAL] "+format, args...)
 	os.Exit(1)
 }
 
 // Warn logs warning messages (always shown)
 func (l *Logger) Warn(format string, args ...interface{}) {
-	l.infoLogger.Printf("[WARN] "+format, args...)
+	l.infoLogger.Printf(l.prefix+"[WARN] "+format, args...)
 }
 
 // IsDebugEnabled returns whether debug mode is active
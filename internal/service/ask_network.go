@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// defaultAskNetworkConfidence is the mapping confidence ask_network needs before it runs a
+// query on its own; it is stricter than find_executable_query because nobody reviews the pick
+const defaultAskNetworkConfidence = 0.75
+
+// askNetworkCandidates is how many candidates are listed when ask_network is not confident
+const askNetworkCandidates = 5
+
+// askNetwork answers a natural-language question in one step: it finds the best
+// executable query and, when the mapping is confident enough, runs it against the
+// default network. Otherwise it lists the candidates instead of guessing.
+func (s *ForwardMCPService) askNetwork(args AskNetworkArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("ask_network", args, nil)
+
+	question := strings.TrimSpace(args.Question)
+	if question == "" {
+		return nil, fmt.Errorf("a question is required, e.g. 'which devices are in the network?'")
+	}
+	minConfidence := args.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = defaultAskNetworkConfidence
+	}
+	if minConfidence > 1 {
+		return nil, fmt.Errorf("invalid min_confidence %v: must be between 0 and 1", args.MinConfidence)
+	}
+
+	results, err := s.queryIndex.SearchQueries(question, 20)
+	if err != nil && strings.Contains(err.Error(), "query index is empty") {
+		s.logger.Info("Query index empty in ask_network, auto-initializing...")
+		if loadErr := s.queryIndex.LoadFromSpec(); loadErr != nil {
+			return nil, fmt.Errorf("query index is not initialized and loading it failed: %w", loadErr)
+		}
+		results, err = s.queryIndex.SearchQueries(question, 20)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search queries: %w", err)
+	}
+
+	// A tie at the top is as ambiguous as a weak match, so neither runs anything
+	mappings := MapSemanticToExecutable(results)
+	if len(mappings) == 0 || mappings[0].MappingConfidence < minConfidence ||
+		(len(mappings) > 1 && mappings[1].MappingConfidence == mappings[0].MappingConfidence) {
+		return mcp.NewToolResponse(mcp.NewTextContent(formatAskNetworkCandidates(question, mappings, minConfidence))), nil
+	}
+
+	best := mappings[0]
+	query := best.ExecutableQuery
+	response := fmt.Sprintf("🤖 Interpreted %q as **%s** (`%s`, %.1f%% confidence)\n", question, query.Name, query.QueryID, best.MappingConfidence*100)
+	response += fmt.Sprintf("Why: %s\n\n", best.MappingReason)
+
+	if query.NeedsInput != "" {
+		response += fmt.Sprintf("This query needs %s. It was not run.", query.NeedsInput)
+		return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+	}
+
+	networkID := s.getNetworkID(args.NetworkID)
+	if networkID == "" {
+		return nil, fmt.Errorf("no network_id given and no default network set; use set_default_network or pass network_id")
+	}
+	var options *NQEQueryOptions
+	if args.Limit > 0 {
+		options = &NQEQueryOptions{Limit: args.Limit}
+	}
+	result, err := s.runNQEQueryByID(RunNQEQueryByIDArgs{
+		NetworkID:  networkID,
+		QueryID:    query.QueryID,
+		SnapshotID: args.SnapshotID,
+		Options:    options,
+		Pretty:     args.Pretty,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, content := range result.Content {
+		if content.TextContent != nil {
+			response += content.TextContent.Text
+		}
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
+
+// formatAskNetworkCandidates lists the best executable mappings for a question that no
+// single mapping answers confidently
+func formatAskNetworkCandidates(question string, mappings []QueryMappingResult, minConfidence float64) string {
+	if len(mappings) == 0 {
+		return fmt.Sprintf("No executable query matches %q, so nothing was run. Try find_executable_query or search_nqe_queries with different wording.", question)
+	}
+
+	reason := fmt.Sprintf("the best match is %.1f%%, below the %.0f%% threshold", mappings[0].MappingConfidence*100, minConfidence*100)
+	if mappings[0].MappingConfidence >= minConfidence {
+		reason = fmt.Sprintf("several queries tie at %.1f%%", mappings[0].MappingConfidence*100)
+	}
+	response := fmt.Sprintf("Not confident enough to run a query for %q: %s. Nothing was run.\n\nCandidates:\n", question, reason)
+	for i, mapping := range mappings {
+		if i >= askNetworkCandidates {
+			break
+		}
+		response += fmt.Sprintf("%d. **%s** (`%s`, %.1f%% confidence): %s\n", i+1, mapping.ExecutableQuery.Name, mapping.ExecutableQuery.QueryID, mapping.MappingConfidence*100, mapping.ExecutableQuery.Description)
+	}
+	response += "\nRephrase the question, lower min_confidence, or run one of these with run_nqe_query_by_id."
+	return response
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestAskNetworkRunsConfidentMatch(t *testing.T) {
+	service := setupSmartSearchTestService()
+	service.defaults.NetworkID = "162112"
+	seedQueryIndex(service.queryIndex, "/Devices/Inventory/Device Basic Info")
+	client := &recordingNQEClient{MockForwardClient: NewMockForwardClient()}
+	client.nqeResult = &forward.NQERunResult{SnapshotID: "snap-1", Items: []map[string]interface{}{{"name": "edge-1", "platform": "ios"}}}
+	service.active().client = client
+
+	response, err := service.askNetwork(AskNetworkArgs{Question: "device basic info"})
+	if err != nil {
+		t.Fatalf("askNetwork failed: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	for _, want := range []string{`Interpreted "device basic info" as **Device Basic Info** (` + "`FQ_ac651cb2901b067fe7dbfb511613ab44776d8029`", "edge-1"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the answer, got:\n%s", want, text)
+		}
+	}
+	if len(client.queryIDs) != 1 || client.queryIDs[0] != "FQ_ac651cb2901b067fe7dbfb511613ab44776d8029" {
+		t.Errorf("Expected the chosen query to run once, got %v", client.queryIDs)
+	}
+}
+
+func TestAskNetworkListsCandidatesWhenNotConfident(t *testing.T) {
+	service := setupSmartSearchTestService()
+	service.defaults.NetworkID = "162112"
+	seedQueryIndex(service.queryIndex, "/Devices/Inventory/Device Basic Info", "/Misc/Hardware Notes")
+	client := &recordingNQEClient{MockForwardClient: NewMockForwardClient()}
+	service.active().client = client
+
+	response, err := service.askNetwork(AskNetworkArgs{Question: "hardware notes"})
+	if err != nil {
+		t.Fatalf("askNetwork failed: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "Not confident enough") || !strings.Contains(text, "Candidates:\n1. **") {
+		t.Errorf("Expected the candidates instead of a run, got:\n%s", text)
+	}
+	if len(client.queryIDs) != 0 {
+		t.Errorf("Expected no query to run, got %v", client.queryIDs)
+	}
+
+	if _, err := service.askNetwork(AskNetworkArgs{Question: " "}); err == nil {
+		t.Error("Expected an empty question to be rejected")
+	}
+}
+
+func TestAskNetworkDoesNotPickBetweenTiedMatches(t *testing.T) {
+	service := setupSmartSearchTestService()
+	service.defaults.NetworkID = "162112"
+	seedQueryIndex(service.queryIndex, "/Devices/Inventory/Device Basic Info", "/Misc/Hardware Notes")
+	client := &recordingNQEClient{MockForwardClient: NewMockForwardClient()}
+	service.active().client = client
+
+	// Device Hardware and Hardware Support both map at 70%
+	response, err := service.askNetwork(AskNetworkArgs{Question: "hardware notes", MinConfidence: 0.5})
+	if err != nil {
+		t.Fatalf("askNetwork failed: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; !strings.Contains(text, "several queries tie at 70.0%") || len(client.queryIDs) != 0 {
+		t.Errorf("Expected a tie to return candidates without running anything, got %v:\n%s", client.queryIDs, text)
+	}
+}
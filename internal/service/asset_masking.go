@@ -0,0 +1,74 @@
+package service
+
+import (
+	"net/netip"
+	"regexp"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+var (
+	// managementIPField matches a JSON management IP field and its string or list value
+	managementIPField = regexp.MustCompile(`(?i)("(?:managementIps?|management_ips?|mgmtIps?)"\s*:\s*)(\[[^\]]*\]|"[^"]*")`)
+	// serialNumberField matches a JSON serial number field and its value
+	serialNumberField = regexp.MustCompile(`(?i)("(?:serialNumber|serial_number|serialNo|serial)"\s*:\s*")([^"]*)`)
+	// quotedValue matches one JSON string inside a field value
+	quotedValue = regexp.MustCompile(`"([^"]*)"`)
+)
+
+// AssetMasker partially masks asset identifiers (management IPs and serial numbers) in
+// tool output so transcripts can be shared. Device names are left intact.
+type AssetMasker struct{}
+
+// maskIP keeps the first octet of an IPv4 address, or the first group of an IPv6 address
+func maskIP(value string) string {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return value
+	}
+	if addr.Is4() {
+		first, _, _ := strings.Cut(addr.String(), ".")
+		return first + ".x.x.x"
+	}
+	first, _, _ := strings.Cut(addr.String(), ":")
+	return first + ":x:x:x"
+}
+
+// maskSerial hides all but the last two characters of a serial number
+func maskSerial(serial string) string {
+	if len(serial) <= 2 {
+		return strings.Repeat("*", len(serial))
+	}
+	return strings.Repeat("*", len(serial)-2) + serial[len(serial)-2:]
+}
+
+// Mask returns text with management IPs and serial numbers partially masked. A nil masker
+// returns text unchanged.
+func (m *AssetMasker) Mask(text string) string {
+	if m == nil {
+		return text
+	}
+	text = managementIPField.ReplaceAllStringFunc(text, func(field string) string {
+		parts := managementIPField.FindStringSubmatch(field)
+		return parts[1] + quotedValue.ReplaceAllStringFunc(parts[2], func(quoted string) string {
+			return `"` + maskIP(quoted[1:len(quoted)-1]) + `"`
+		})
+	})
+	return serialNumberField.ReplaceAllStringFunc(text, func(field string) string {
+		parts := serialNumberField.FindStringSubmatch(field)
+		return parts[1] + maskSerial(parts[2])
+	})
+}
+
+// MaskResponse masks asset identifiers in every text item of a tool response
+func (m *AssetMasker) MaskResponse(response *mcp.ToolResponse) {
+	if m == nil || response == nil {
+		return
+	}
+	for _, content := range response.Content {
+		if content != nil && content.TextContent != nil {
+			content.TextContent.Text = m.Mask(content.TextContent.Text)
+		}
+	}
+}
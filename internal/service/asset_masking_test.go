@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssetMaskingInDeviceOutput(t *testing.T) {
+	service := createTestService()
+	mock := service.client().(*MockForwardClient)
+	mock.devices[0].SerialNumber = "FTX1234ABCD"
+	mock.devices[0].ManagementIPs = []string{"192.168.1.1", "2001:db8::1"}
+	listDevices := withToolMiddleware(service, "list_devices", (*ForwardMCPService).listDevices)
+
+	response, err := listDevices(ListDevicesArgs{NetworkID: "162112"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "FTX1234ABCD") || !strings.Contains(text, "192.168.1.1") {
+		t.Errorf("Expected values intact with masking off, got:\n%s", text)
+	}
+
+	service.assetMasker = &AssetMasker{}
+	response, err = listDevices(ListDevicesArgs{NetworkID: "162112"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text = response.Content[0].TextContent.Text
+	if strings.Contains(text, "FTX1234ABCD") || !strings.Contains(text, `"*********CD"`) {
+		t.Errorf("Expected the serial number to be obscured, got:\n%s", text)
+	}
+	for _, hidden := range []string{"192.168.1.1", "192.168.1.2", "2001:db8::1"} {
+		if strings.Contains(text, hidden) {
+			t.Errorf("Expected %s to be masked, got:\n%s", hidden, text)
+		}
+	}
+	if !strings.Contains(text, `"192.x.x.x"`) || !strings.Contains(text, `"2001:x:x:x"`) {
+		t.Errorf("Expected management IPs to keep their first octet, got:\n%s", text)
+	}
+	if !strings.Contains(text, "router-1") || !strings.Contains(text, "switch-1") {
+		t.Errorf("Expected device names to stay intact, got:\n%s", text)
+	}
+}
+
+func TestAssetMaskerMasksNQEColumns(t *testing.T) {
+	masker := &AssetMasker{}
+	input := `[{"name":"edge-1","serial":"9K2X","mgmtIp":"10.20.30.40"}]`
+	expected := `[{"name":"edge-1","serial":"**2X","mgmtIp":"10.x.x.x"}]`
+	if got := masker.Mask(input); got != expected {
+		t.Errorf("Mask(%s) = %s, expected %s", input, got, expected)
+	}
+	var off *AssetMasker
+	if got := off.Mask(input); got != input {
+		t.Errorf("Expected a nil masker to leave text unchanged, got %s", got)
+	}
+}
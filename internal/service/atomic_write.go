@@ -0,0 +1,47 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// renameFile replaces a file with another; a variable so tests can simulate a crash
+// between writing and renaming
+var renameFile = os.Rename
+
+// writeFileAtomic writes data to path through a temporary file in the same directory
+// that is synced and then renamed over path, so readers see either the previous file or
+// the complete new one, never a partial write
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+	renamed := false
+	defer func() {
+		if !renamed {
+			os.Remove(tempPath)
+		}
+	}()
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tempPath, perm); err != nil {
+		return err
+	}
+	if err := renameFile(tempPath, path); err != nil {
+		return err
+	}
+	renamed = true
+	return nil
+}
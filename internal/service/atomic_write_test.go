@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomicKeepsPreviousFileOnCrash(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nqe-embeddings.json")
+	if err := writeFileAtomic(path, []byte(`{"good": [1]}`), 0644); err != nil {
+		t.Fatalf("Expected the first write to succeed, got: %v", err)
+	}
+
+	// Simulate the process dying after the temporary file is written but before the rename
+	renameFile = func(string, string) error { return errors.New("killed") }
+	defer func() { renameFile = os.Rename }()
+
+	if err := writeFileAtomic(path, []byte(`{"new": [`), 0644); err == nil {
+		t.Fatal("Expected the interrupted write to fail")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != `{"good": [1]}` {
+		t.Errorf("Expected the previous file to be intact, got %q (err: %v)", data, err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected the temporary file to be cleaned up, found %d files", len(entries))
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// defaultCacheWarmupCount is how many entries are warmed when no count is configured
+const defaultCacheWarmupCount = 10
+
+// cacheWarmupSaveInterval is how often the warm-up list is saved while the server runs
+const cacheWarmupSaveInterval = 5 * time.Minute
+
+// cacheWarmupSaveMu serializes writes of the warm-up list from the periodic and final saves
+var cacheWarmupSaveMu sync.Mutex
+
+// cacheWarmupEntry is one NQE execution remembered for warm-up on the next start
+type cacheWarmupEntry struct {
+	QueryID     string                   `json:"query_id"`
+	NetworkID   string                   `json:"network_id"`
+	Parameters  map[string]interface{}   `json:"parameters,omitempty"`
+	Options     *forward.NQEQueryOptions `json:"options,omitempty"`
+	AccessCount int                      `json:"access_count"`
+}
+
+// cacheWarmupEnabled reports whether the most-accessed queries are persisted and replayed
+func (s *ForwardMCPService) cacheWarmupEnabled() bool {
+	return s.config != nil && s.config.Forward.SemanticCache.Enabled && s.config.Forward.SemanticCache.Warmup
+}
+
+// cacheWarmupCount returns the maximum number of entries to warm
+func (s *ForwardMCPService) cacheWarmupCount() int {
+	if s.config != nil && s.config.Forward.SemanticCache.WarmupCount > 0 {
+		return s.config.Forward.SemanticCache.WarmupCount
+	}
+	return defaultCacheWarmupCount
+}
+
+// cacheWarmupPath returns where the warm-up list is kept between runs
+func (s *ForwardMCPService) cacheWarmupPath() string {
+	if s.config != nil && s.config.Forward.SemanticCache.WarmupFile != "" {
+		return s.config.Forward.SemanticCache.WarmupFile
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "forward-mcp", "cache-warmup.json")
+}
+
+// saveCacheWarmupList records the current most-accessed NQE entries for the next start
+func (s *ForwardMCPService) saveCacheWarmupList() error {
+	var entries []cacheWarmupEntry
+	for _, entry := range s.semanticCache.TopNQEEntries(s.cacheWarmupCount()) {
+		entries = append(entries, cacheWarmupEntry{
+			QueryID:     entry.QueryID,
+			NetworkID:   entry.NetworkID,
+			Parameters:  entry.Parameters,
+			Options:     entry.Options,
+			AccessCount: entry.AccessCount,
+		})
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache warm-up list: %w", err)
+	}
+
+	cacheWarmupSaveMu.Lock()
+	defer cacheWarmupSaveMu.Unlock()
+
+	path := s.cacheWarmupPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache warm-up directory: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache warm-up list: %w", err)
+	}
+	return nil
+}
+
+// loadCacheWarmupList reads the warm-up list saved by a previous run
+func (s *ForwardMCPService) loadCacheWarmupList() ([]CacheEntry, error) {
+	data, err := os.ReadFile(s.cacheWarmupPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache warm-up list: %w", err)
+	}
+
+	var saved []cacheWarmupEntry
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse cache warm-up list: %w", err)
+	}
+
+	entries := make([]CacheEntry, 0, len(saved))
+	for _, entry := range saved {
+		entries = append(entries, CacheEntry{
+			QueryID:     entry.QueryID,
+			NetworkID:   entry.NetworkID,
+			Parameters:  entry.Parameters,
+			Options:     entry.Options,
+			AccessCount: entry.AccessCount,
+		})
+	}
+	return entries, nil
+}
+
+// warmUpCache replays entries against the latest snapshot of their network and stores
+// the fresh results, keeping each entry's access count. It returns how many succeeded.
+func (s *ForwardMCPService) warmUpCache(entries []CacheEntry) int {
+	refreshed := 0
+	for _, entry := range entries {
+		if err := s.checkNQEPolicy(entry.QueryID); err != nil {
+			s.logger.Warn("Skipping cache warm-up: %v", err)
+			continue
+		}
+		result, err := s.client().RunNQEQueryByID(&forward.NQEQueryParams{
+			NetworkID:  entry.NetworkID,
+			QueryID:    entry.QueryID,
+			Parameters: entry.Parameters,
+			Options:    entry.Options,
+		})
+		if err != nil {
+			s.logger.Warn("Cache warm-up failed for query %s on network %s: %v", entry.QueryID, entry.NetworkID, err)
+			continue
+		}
+
+		// Key the fresh result by the latest snapshot ID, as fetchNQEResult looks it up
+		entry.SnapshotID = s.latestSnapshotCacheID(entry.NetworkID)
+		if entry.SnapshotID == "" {
+			continue
+		}
+		entry.Result = result
+		s.semanticCache.storeNQEEntry(entry)
+		refreshed++
+	}
+	return refreshed
+}
+
+// StartCacheWarmup replays the most-accessed queries from the previous run in the
+// background when FORWARD_MCP_CACHE_WARMUP is set, and saves the warm-up list every
+// cacheWarmupSaveInterval until ctx is done. It returns immediately so warm-up never
+// delays server readiness; call SaveCacheWarmup at shutdown to keep the final counts.
+func (s *ForwardMCPService) StartCacheWarmup(ctx context.Context) {
+	if !s.cacheWarmupEnabled() {
+		return
+	}
+
+	go func() {
+		entries, err := s.loadCacheWarmupList()
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				s.logger.Warn("Skipping cache warm-up: %v", err)
+			}
+			return
+		}
+		if limit := s.cacheWarmupCount(); len(entries) > limit {
+			entries = entries[:limit]
+		}
+
+		refreshed := s.warmUpCache(entries)
+		s.logger.Info("Cache warm-up refreshed %d/%d queries", refreshed, len(entries))
+	}()
+
+	go func() {
+		ticker := time.NewTicker(cacheWarmupSaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.SaveCacheWarmup()
+			}
+		}
+	}()
+}
+
+// SaveCacheWarmup saves the warm-up list when warm-up is enabled, logging any failure
+func (s *ForwardMCPService) SaveCacheWarmup() {
+	if !s.cacheWarmupEnabled() || s.semanticCache == nil {
+		return
+	}
+	if err := s.saveCacheWarmupList(); err != nil {
+		s.logger.Warn("Failed to save cache warm-up list: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestCacheWarmupRefreshesTopEntriesByAccessCount(t *testing.T) {
+	service := createTestService()
+	client := &recordingNQEClient{MockForwardClient: NewMockForwardClient()}
+	service.active().client = client
+
+	// Seed entries with access counts q1=1, q2=4, q3=3, q4=2
+	accesses := map[string]int{"q1": 1, "q2": 4, "q3": 3, "q4": 2}
+	for queryID, count := range accesses {
+		service.semanticCache.PutNQEResult(queryID, nil, nil, "162112", "", &forward.NQERunResult{})
+		for i := 1; i < count; i++ {
+			service.semanticCache.GetNQEResult(queryID, nil, nil, "162112", "")
+		}
+	}
+
+	refreshed := service.warmUpCache(service.semanticCache.TopNQEEntries(2))
+	if refreshed != 2 {
+		t.Fatalf("Expected 2 refreshed entries, got %d", refreshed)
+	}
+
+	sort.Strings(client.queryIDs)
+	if want := []string{"q2", "q3"}; !reflect.DeepEqual(client.queryIDs, want) {
+		t.Errorf("Expected refresh calls for %v, got %v", want, client.queryIDs)
+	}
+
+	// Refreshing must not reset popularity
+	top := service.semanticCache.TopNQEEntries(1)
+	if len(top) != 1 || top[0].QueryID != "q2" || top[0].AccessCount != 4 {
+		t.Errorf("Expected q2 to stay most-accessed with count 4, got %+v", top)
+	}
+}
+
+func TestCacheWarmupListRoundTrip(t *testing.T) {
+	service := createTestService()
+	service.config.Forward.SemanticCache.Warmup = true
+	service.config.Forward.SemanticCache.WarmupCount = 1
+	service.config.Forward.SemanticCache.WarmupFile = filepath.Join(t.TempDir(), "warmup.json")
+
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "q1"}); err != nil {
+		t.Fatalf("Failed to run query: %v", err)
+	}
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "q2"}); err != nil {
+		t.Fatalf("Failed to run query: %v", err)
+	}
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "q2"}); err != nil {
+		t.Fatalf("Failed to run query: %v", err)
+	}
+
+	if _, err := service.loadCacheWarmupList(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected queries not to write the warm-up list, got %v", err)
+	}
+
+	service.SaveCacheWarmup()
+	entries, err := service.loadCacheWarmupList()
+	if err != nil {
+		t.Fatalf("Failed to load warm-up list: %v", err)
+	}
+	if len(entries) != 1 || entries[0].QueryID != "q2" || entries[0].AccessCount != 2 {
+		t.Errorf("Expected only q2 with access count 2, got %+v", entries)
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/forward-mcp/internal/config"
+	"github.com/forward-mcp/internal/logger"
+)
+
+// EmbeddingProvider is one named provider in an embedding fallback chain
+type EmbeddingProvider struct {
+	Name    string
+	Service EmbeddingService
+}
+
+// EmbeddingTag identifies the vector space of an embedding: the provider that generated
+// it and its dimension. Cosine similarity is only meaningful within one space, so an
+// embedding served by a fallback provider is never compared with the primary's.
+type EmbeddingTag struct {
+	Provider   string `json:"provider"`
+	Dimensions int    `json:"dimensions"`
+}
+
+// String formats the tag as provider/dimensions, e.g. "openai/1536"
+func (t EmbeddingTag) String() string {
+	return t.Provider + "/" + strconv.Itoa(t.Dimensions)
+}
+
+// matches reports whether embeddings tagged t and other can be compared. An empty provider
+// or zero dimension is unknown (e.g. embeddings cached before tags were recorded) and
+// matches any value.
+func (t EmbeddingTag) matches(other EmbeddingTag) bool {
+	return (t.Provider == "" || other.Provider == "" || t.Provider == other.Provider) &&
+		(t.Dimensions == 0 || other.Dimensions == 0 || t.Dimensions == other.Dimensions)
+}
+
+// parseEmbeddingTag parses a tag formatted by EmbeddingTag.String
+func parseEmbeddingTag(text string) (EmbeddingTag, error) {
+	text = strings.TrimSpace(text)
+	slash := strings.LastIndex(text, "/")
+	if slash <= 0 {
+		return EmbeddingTag{}, fmt.Errorf("invalid embedding tag %q", text)
+	}
+	dimensions, err := strconv.Atoi(text[slash+1:])
+	if err != nil || dimensions <= 0 {
+		return EmbeddingTag{}, fmt.Errorf("invalid embedding tag %q", text)
+	}
+	return EmbeddingTag{Provider: text[:slash], Dimensions: dimensions}, nil
+}
+
+// realEmbeddingProvider is implemented by embedding services that can report whether they
+// produce real embeddings
+type realEmbeddingProvider interface {
+	IsRealProvider() bool
+}
+
+// isRealEmbeddingProvider reports whether service produces embeddings worth generating
+// and caching for the query index. Services that do not say otherwise are real.
+func isRealEmbeddingProvider(service EmbeddingService) bool {
+	if provider, ok := service.(realEmbeddingProvider); ok {
+		return provider.IsRealProvider()
+	}
+	return service != nil
+}
+
+// embeddingProviderName names an embedding service; a fallback chain is described by its
+// providers and the one that served last
+func embeddingProviderName(service EmbeddingService) string {
+	switch service := service.(type) {
+	case *OpenAIEmbeddingService:
+		return "openai"
+	case *KeywordEmbeddingService:
+		return "keyword"
+	case *LocalEmbeddingService:
+		return "local"
+	case *MockEmbeddingService:
+		return "mock"
+	case *CompositeEmbeddingService:
+		return service.describe()
+	default:
+		return "custom"
+	}
+}
+
+// primaryEmbeddingProvider names the provider whose embeddings service is expected to
+// produce: the first provider of a fallback chain, or the service itself
+func primaryEmbeddingProvider(service EmbeddingService) string {
+	if composite, ok := service.(*CompositeEmbeddingService); ok {
+		return composite.Primary()
+	}
+	return embeddingProviderName(service)
+}
+
+// generateTaggedEmbedding generates an embedding and tags it with the provider that served
+// it. fallback reports whether a fallback chain served it from a provider other than its
+// primary.
+func generateTaggedEmbedding(service EmbeddingService, text string) ([]float64, EmbeddingTag, bool, error) {
+	if composite, ok := service.(*CompositeEmbeddingService); ok {
+		embedding, provider, err := composite.GenerateEmbeddingWithProvider(text)
+		if err != nil {
+			return nil, EmbeddingTag{}, false, err
+		}
+		return embedding, EmbeddingTag{Provider: provider, Dimensions: len(embedding)}, provider != composite.Primary(), nil
+	}
+
+	embedding, err := service.GenerateEmbedding(text)
+	return embedding, EmbeddingTag{Provider: embeddingProviderName(service), Dimensions: len(embedding)}, false, err
+}
+
+// CompositeEmbeddingService tries an ordered chain of embedding providers and falls through
+// to the next one when a provider fails, recording which provider served each request.
+// Providers produce vectors in different spaces, so callers that store embeddings use
+// generateTaggedEmbedding and only compare embeddings with matching tags.
+type CompositeEmbeddingService struct {
+	providers []EmbeddingProvider
+	logger    *logger.Logger
+
+	mutex      sync.Mutex
+	lastServed string
+	served     map[string]int
+}
+
+// NewCompositeEmbeddingService creates a service that tries providers in order
+func NewCompositeEmbeddingService(providers []EmbeddingProvider, logger *logger.Logger) *CompositeEmbeddingService {
+	return &CompositeEmbeddingService{providers: providers, logger: logger, served: make(map[string]int)}
+}
+
+// GenerateEmbedding returns the first embedding a provider produces
+func (c *CompositeEmbeddingService) GenerateEmbedding(text string) ([]float64, error) {
+	embedding, _, err := c.GenerateEmbeddingWithProvider(text)
+	return embedding, err
+}
+
+// GenerateEmbeddingWithProvider returns the first embedding a provider produces along with
+// that provider's name. The error joins every provider's failure when all of them fail.
+func (c *CompositeEmbeddingService) GenerateEmbeddingWithProvider(text string) ([]float64, string, error) {
+	var failures []error
+	for _, provider := range c.providers {
+		embedding, err := provider.Service.GenerateEmbedding(text)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", provider.Name, err))
+			if c.logger != nil {
+				c.logger.Warn("Embedding provider %s failed, trying the next provider: %v", provider.Name, err)
+			}
+			continue
+		}
+
+		c.mutex.Lock()
+		c.lastServed = provider.Name
+		c.served[provider.Name]++
+		c.mutex.Unlock()
+		return embedding, provider.Name, nil
+	}
+	if len(failures) == 0 {
+		return nil, "", fmt.Errorf("no embedding providers configured")
+	}
+	return nil, "", fmt.Errorf("all embedding providers failed: %w", errors.Join(failures...))
+}
+
+// Primary returns the name of the first provider in the chain
+func (c *CompositeEmbeddingService) Primary() string {
+	if len(c.providers) == 0 {
+		return ""
+	}
+	return c.providers[0].Name
+}
+
+// IsRealProvider reports whether any provider in the chain produces real embeddings
+func (c *CompositeEmbeddingService) IsRealProvider() bool {
+	for _, provider := range c.providers {
+		if isRealEmbeddingProvider(provider.Service) {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the provider names in fallback order
+func (c *CompositeEmbeddingService) Names() []string {
+	names := make([]string, len(c.providers))
+	for i, provider := range c.providers {
+		names[i] = provider.Name
+	}
+	return names
+}
+
+// LastServed returns the provider that served the most recent request, or "" before any
+func (c *CompositeEmbeddingService) LastServed() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lastServed
+}
+
+// ServedCounts returns how many requests each provider has served
+func (c *CompositeEmbeddingService) ServedCounts() map[string]int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	counts := make(map[string]int, len(c.served))
+	for name, count := range c.served {
+		counts[name] = count
+	}
+	return counts
+}
+
+// describe names the chain and the provider that served last, e.g.
+// "openai>local>keyword (last served by local)"
+func (c *CompositeEmbeddingService) describe() string {
+	chain := strings.Join(c.Names(), ">")
+	if last := c.LastServed(); last != "" {
+		return fmt.Sprintf("%s (last served by %s)", chain, last)
+	}
+	return chain
+}
+
+// newEmbeddingProvider creates the named embedding provider
+func newEmbeddingProvider(name string, cfg config.SemanticCacheConfig, logger *logger.Logger) (EmbeddingService, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "openai":
+		openaiKey := os.Getenv("OPENAI_API_KEY")
+		if openaiKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY not set")
+		}
+		return NewOpenAIEmbeddingService(openaiKey), nil
+	case "local", "tfidf":
+		return NewLocalEmbeddingService(), nil
+	case "keyword":
+		return newKeywordEmbeddingServiceFromConfig(cfg.KeywordVocabularyFile, logger), nil
+	case "mock":
+		return NewMockEmbeddingService(), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q (supported: openai, local, keyword, mock)", name)
+	}
+}
+
+// newEmbeddingServiceFromConfig creates the configured embedding provider. With fallbacks
+// configured, the provider and its fallbacks form a CompositeEmbeddingService; providers
+// that cannot be created (e.g. OpenAI without an API key) are left out of the chain.
+func newEmbeddingServiceFromConfig(cfg config.SemanticCacheConfig, logger *logger.Logger) EmbeddingService {
+	if len(cfg.EmbeddingFallbacks) == 0 {
+		if cfg.EmbeddingProvider == "openai" {
+			if openaiKey := os.Getenv("OPENAI_API_KEY"); openaiKey != "" {
+				return NewOpenAIEmbeddingService(openaiKey)
+			}
+			logger.Warn("OpenAI provider selected but OPENAI_API_KEY not set - using keyword embedding service")
+		}
+		return newKeywordEmbeddingServiceFromConfig(cfg.KeywordVocabularyFile, logger)
+	}
+
+	var providers []EmbeddingProvider
+	for _, name := range append([]string{cfg.EmbeddingProvider}, cfg.EmbeddingFallbacks...) {
+		service, err := newEmbeddingProvider(name, cfg, logger)
+		if err != nil {
+			logger.Warn("Skipping embedding provider %s in the fallback chain: %v", name, err)
+			continue
+		}
+		providers = append(providers, EmbeddingProvider{Name: strings.ToLower(strings.TrimSpace(name)), Service: service})
+	}
+	switch len(providers) {
+	case 0:
+		logger.Warn("No embedding provider in the fallback chain could be created - using keyword embedding service")
+		return newKeywordEmbeddingServiceFromConfig(cfg.KeywordVocabularyFile, logger)
+	case 1:
+		return providers[0].Service
+	}
+	return NewCompositeEmbeddingService(providers, logger)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/config"
+	"github.com/forward-mcp/internal/forward"
+)
+
+// outageEmbeddingService returns a fixed embedding, or an error while down
+type outageEmbeddingService struct {
+	down      bool
+	embedding []float64
+}
+
+func (o *outageEmbeddingService) GenerateEmbedding(text string) ([]float64, error) {
+	if o.down {
+		return nil, fmt.Errorf("embedding provider unavailable")
+	}
+	return o.embedding, nil
+}
+
+func TestCompositeEmbeddingServiceFallsThroughOnError(t *testing.T) {
+	local := NewLocalEmbeddingService()
+	composite := NewCompositeEmbeddingService([]EmbeddingProvider{
+		{Name: "openai", Service: &failingEmbeddingService{}},
+		{Name: "local", Service: local},
+		{Name: "keyword", Service: NewKeywordEmbeddingService()},
+	}, createTestLogger())
+
+	embedding, provider, err := composite.GenerateEmbeddingWithProvider("bgp neighbor state")
+	if err != nil {
+		t.Fatalf("Expected the secondary provider to serve the request, got: %v", err)
+	}
+	if provider != "local" {
+		t.Errorf("Expected the local provider to be reported, got %q", provider)
+	}
+	want, _ := local.GenerateEmbedding("bgp neighbor state")
+	if !reflect.DeepEqual(embedding, want) {
+		t.Error("Expected the embedding generated by the secondary provider")
+	}
+
+	if _, err := composite.GenerateEmbedding("ospf adjacencies"); err != nil {
+		t.Fatalf("GenerateEmbedding failed: %v", err)
+	}
+	if composite.LastServed() != "local" || composite.ServedCounts()["local"] != 2 || composite.ServedCounts()["openai"] != 0 {
+		t.Errorf("Expected both requests served by local, got last %q and counts %v", composite.LastServed(), composite.ServedCounts())
+	}
+	if got := composite.describe(); got != "openai>local>keyword (last served by local)" {
+		t.Errorf("Unexpected description %q", got)
+	}
+}
+
+func TestCompositeEmbeddingServiceReportsAllFailures(t *testing.T) {
+	composite := NewCompositeEmbeddingService([]EmbeddingProvider{
+		{Name: "openai", Service: &failingEmbeddingService{}},
+		{Name: "local", Service: NewLocalEmbeddingService()},
+	}, createTestLogger())
+
+	_, err := composite.GenerateEmbedding("")
+	if err == nil || !strings.Contains(err.Error(), "openai: embedding provider unavailable") || !strings.Contains(err.Error(), "local: empty text provided") {
+		t.Errorf("Expected every provider's failure in the error, got %v", err)
+	}
+	if composite.LastServed() != "" {
+		t.Errorf("Expected no provider recorded as served, got %q", composite.LastServed())
+	}
+}
+
+func TestNewEmbeddingServiceFromConfigBuildsChain(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+
+	service := newEmbeddingServiceFromConfig(config.SemanticCacheConfig{
+		EmbeddingProvider:  "openai",
+		EmbeddingFallbacks: []string{"local", "keyword"},
+	}, createTestLogger())
+	composite, ok := service.(*CompositeEmbeddingService)
+	if !ok {
+		t.Fatalf("Expected a composite embedding service, got %T", service)
+	}
+	if names := composite.Names(); !reflect.DeepEqual(names, []string{"local", "keyword"}) {
+		t.Errorf("Expected OpenAI left out without an API key, got chain %v", names)
+	}
+
+	single := newEmbeddingServiceFromConfig(config.SemanticCacheConfig{EmbeddingProvider: "openai"}, createTestLogger())
+	if _, ok := single.(*KeywordEmbeddingService); !ok {
+		t.Errorf("Expected the keyword service without fallbacks or an API key, got %T", single)
+	}
+}
+
+func TestGenerateEmbeddingsNeverStoresFallbackVectors(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "nqe-embeddings.json")
+	primary := &scriptedEmbeddingService{keyword: NewKeywordEmbeddingService(), errors: []error{nil, fmt.Errorf("connection reset"), nil}}
+	composite := NewCompositeEmbeddingService([]EmbeddingProvider{
+		{Name: "openai", Service: primary},
+		{Name: "local", Service: NewLocalEmbeddingService()},
+	}, createTestLogger())
+
+	idx := newCheckpointTestIndex(composite, cachePath, 3)
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+	if len(idx.queries[0].Embedding) == 0 || len(idx.queries[1].Embedding) != 0 || len(idx.queries[2].Embedding) == 0 {
+		t.Error("Expected only the queries served by the primary provider to be embedded")
+	}
+
+	reloaded := newCheckpointTestIndex(composite, cachePath, 3)
+	if err := reloaded.loadEmbeddingsFromCache(); err != nil {
+		t.Fatalf("Failed to load embeddings cache: %v", err)
+	}
+	if len(reloaded.embeddings) != 2 || len(reloaded.queries[1].Embedding) != 0 {
+		t.Errorf("Expected the fallback-served vector not to be persisted, loaded %d embeddings", len(reloaded.embeddings))
+	}
+	want := EmbeddingTag{Provider: "openai", Dimensions: len(idx.queries[0].Embedding)}
+	if reloaded.embeddingTag != want {
+		t.Errorf("Expected cached embeddings tagged %s, got %s", want, reloaded.embeddingTag)
+	}
+	if data, err := os.ReadFile(cachePath + embeddingTagSuffix); err != nil || strings.TrimSpace(string(data)) != want.String() {
+		t.Errorf("Expected the tag file to record %s, got %q (%v)", want, data, err)
+	}
+}
+
+func TestSemanticCacheNeverMatchesAcrossProviders(t *testing.T) {
+	vector := []float64{0.6, 0.8, 0}
+	primary := &outageEmbeddingService{down: true, embedding: vector}
+	composite := NewCompositeEmbeddingService([]EmbeddingProvider{
+		{Name: "openai", Service: primary},
+		{Name: "local", Service: &fixedEmbeddingService{embedding: vector}},
+	}, createTestLogger())
+	cache := NewSemanticCache(composite, createTestLogger())
+	result := &forward.NQERunResult{Items: []map[string]interface{}{{"device": "r1"}}}
+
+	// Identical vectors from different providers still never match
+	if err := cache.Put("bgp neighbors", "162112", "", result); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	primary.down = false
+	if _, hit := cache.Get("show bgp peers", "162112", ""); hit {
+		t.Error("Expected no match between a fallback provider's embedding and the primary's")
+	}
+
+	if err := cache.Put("ospf neighbors", "162112", "", result); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, hit := cache.Get("show ospf peers", "162112", ""); !hit {
+		t.Error("Expected a match between embeddings from the same provider")
+	}
+}
+
+func TestIsRealEmbeddingProvider(t *testing.T) {
+	mockChain := NewCompositeEmbeddingService([]EmbeddingProvider{
+		{Name: "mock", Service: NewMockEmbeddingService()},
+		{Name: "mock", Service: NewMockEmbeddingService()},
+	}, createTestLogger())
+	realChain := NewCompositeEmbeddingService([]EmbeddingProvider{
+		{Name: "mock", Service: NewMockEmbeddingService()},
+		{Name: "local", Service: NewLocalEmbeddingService()},
+	}, createTestLogger())
+
+	if isRealEmbeddingProvider(NewMockEmbeddingService()) || isRealEmbeddingProvider(mockChain) {
+		t.Error("Expected mock services not to count as real providers")
+	}
+	if !isRealEmbeddingProvider(NewLocalEmbeddingService()) || !isRealEmbeddingProvider(realChain) {
+		t.Error("Expected a chain with a real provider to count as real")
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Policies for tool calls that arrive while every slot is taken
+const (
+	// ConcurrencyPolicyQueue waits for a slot to free up (the default)
+	ConcurrencyPolicyQueue = "queue"
+	// ConcurrencyPolicyReject fails the call immediately with ErrServerBusy
+	ConcurrencyPolicyReject = "reject"
+)
+
+// ErrServerBusy is returned when a tool call is rejected because the concurrency limit is reached
+var ErrServerBusy = errors.New("server busy")
+
+// ToolCallLimiter caps how many tool calls execute at once, protecting the Forward
+// backend from bursts of expensive API calls. A nil limiter allows unlimited calls.
+type ToolCallLimiter struct {
+	slots  chan struct{}
+	policy string
+}
+
+// NewToolCallLimiter creates a limiter allowing maxConcurrent simultaneous calls.
+// Excess calls wait when policy is "queue" and fail fast when it is "reject".
+func NewToolCallLimiter(maxConcurrent int, policy string) (*ToolCallLimiter, error) {
+	if maxConcurrent <= 0 {
+		return nil, fmt.Errorf("invalid concurrency limit %d: must be positive", maxConcurrent)
+	}
+
+	policy = strings.ToLower(strings.TrimSpace(policy))
+	if policy == "" {
+		policy = ConcurrencyPolicyQueue
+	}
+	if policy != ConcurrencyPolicyQueue && policy != ConcurrencyPolicyReject {
+		return nil, fmt.Errorf("invalid concurrency policy %q: must be %q or %q", policy, ConcurrencyPolicyQueue, ConcurrencyPolicyReject)
+	}
+
+	return &ToolCallLimiter{slots: make(chan struct{}, maxConcurrent), policy: policy}, nil
+}
+
+// newToolCallLimiterFromConfig returns nil (unlimited) when maxConcurrent is zero or less
+func newToolCallLimiterFromConfig(maxConcurrent int, policy string) (*ToolCallLimiter, error) {
+	if maxConcurrent <= 0 {
+		return nil, nil
+	}
+	return NewToolCallLimiter(maxConcurrent, policy)
+}
+
+// Acquire takes a slot for toolName, waiting or failing per the limiter's policy. The
+// returned release function must be called when the call finishes.
+func (l *ToolCallLimiter) Acquire(toolName string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	release := func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if l.policy == ConcurrencyPolicyReject {
+		return nil, fmt.Errorf("%w: %d tool calls already in progress, %s was not run; retry shortly", ErrServerBusy, cap(l.slots), toolName)
+	}
+
+	l.slots <- struct{}{}
+	return release, nil
+}
+
+// InFlight returns how many tool calls currently hold a slot
+func (l *ToolCallLimiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
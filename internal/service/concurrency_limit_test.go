@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// blockingHandler returns a tool handler that signals when it starts and then waits
+// for release, standing in for a slow backend call
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) func(*ForwardMCPService, struct{}) (*mcp.ToolResponse, error) {
+	return func(*ForwardMCPService, struct{}) (*mcp.ToolResponse, error) {
+		started <- struct{}{}
+		<-release
+		return mcp.NewToolResponse(mcp.NewTextContent("done")), nil
+	}
+}
+
+func TestToolCallLimiterRejectsWhenBusy(t *testing.T) {
+	service := createTestService()
+	limiter, err := NewToolCallLimiter(1, ConcurrencyPolicyReject)
+	if err != nil {
+		t.Fatalf("Failed to create limiter: %v", err)
+	}
+	service.limiter = limiter
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	handler := withToolMiddleware(service, "slow_tool", blockingHandler(started, release))
+
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := handler(struct{}{})
+		firstDone <- err
+	}()
+	<-started
+
+	if _, err := handler(struct{}{}); !errors.Is(err, ErrServerBusy) {
+		t.Fatalf("Expected second call to be rejected with ErrServerBusy, got %v", err)
+	}
+
+	close(release)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("Expected first call to succeed, got %v", err)
+	}
+	if limiter.InFlight() != 0 {
+		t.Errorf("Expected slot to be released, %d still in flight", limiter.InFlight())
+	}
+}
+
+func TestToolCallLimiterQueuesWhenBusy(t *testing.T) {
+	service := createTestService()
+	limiter, err := NewToolCallLimiter(1, ConcurrencyPolicyQueue)
+	if err != nil {
+		t.Fatalf("Failed to create limiter: %v", err)
+	}
+	service.limiter = limiter
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{}, 2)
+	handler := withToolMiddleware(service, "slow_tool", blockingHandler(started, release))
+
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := handler(struct{}{})
+			done <- err
+		}()
+	}
+
+	<-started
+	select {
+	case <-started:
+		t.Fatal("Expected second call to wait while the first holds the only slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release <- struct{}{}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("Expected queued call to start once the first finished")
+	}
+	release <- struct{}{}
+
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("Expected queued calls to succeed, got %v", err)
+		}
+	}
+}
+
+func TestNewToolCallLimiterFromConfig(t *testing.T) {
+	if limiter, err := newToolCallLimiterFromConfig(0, ""); limiter != nil || err != nil {
+		t.Errorf("Expected nil limiter for unlimited concurrency, got %v, %v", limiter, err)
+	}
+	if _, err := newToolCallLimiterFromConfig(2, "drop"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
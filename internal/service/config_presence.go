@@ -0,0 +1,120 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// configSearchQueryID is the library Config Search query, parameterized by searchPattern
+const configSearchQueryID = "FQ_e636c47826ad7144f09eaf6bc14dfb0b560e7cc9"
+
+// configPresencePageSize is the page size used when collecting config search matches
+const configPresencePageSize = 1000
+
+// ConfigPresence lists the devices whose configuration does (or, in inverse mode, does
+// not) match a config search pattern
+type ConfigPresence struct {
+	Pattern string   `json:"pattern"`
+	Inverse bool     `json:"inverse"`
+	Devices []string `json:"devices"`
+	Matched int      `json:"matched"`
+	Total   int      `json:"total"`
+}
+
+// configMatchedDevices pages through the config search results and returns the distinct
+// device names they mention
+func (s *ForwardMCPService) configMatchedDevices(networkID, snapshotID, pattern string) (map[string]string, error) {
+	matched := make(map[string]string)
+	for offset := 0; ; offset += configPresencePageSize {
+		_, result, _, err := s.fetchNQEResult(RunNQEQueryByIDArgs{
+			NetworkID:  networkID,
+			SnapshotID: snapshotID,
+			QueryID:    configSearchQueryID,
+			Parameters: map[string]interface{}{"searchPattern": pattern},
+			Options:    &NQEQueryOptions{Limit: configPresencePageSize, Offset: offset},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Items {
+			if name, ok := firstString(row, lifecycleDeviceColumns...); ok && name != "" {
+				matched[strings.ToLower(name)] = name
+			}
+		}
+		if len(result.Items) < configPresencePageSize {
+			return matched, nil
+		}
+	}
+}
+
+// findDevicesByConfig reports which devices have configuration matching a pattern, or
+// which lack it when inverse is set
+func (s *ForwardMCPService) findDevicesByConfig(args FindDevicesByConfigArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("find_devices_by_config", args, nil)
+
+	if strings.TrimSpace(args.Pattern) == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+	networkID := s.getNetworkID(args.NetworkID)
+	snapshotID, err := s.resolveSnapshotID(networkID, s.getSnapshotID(args.SnapshotID))
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := s.configMatchedDevices(networkID, snapshotID, args.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search configurations: %w", err)
+	}
+	devices, err := s.fetchAllDevices(networkID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+
+	presence := ConfigPresence{Pattern: args.Pattern, Inverse: args.Inverse, Devices: []string{}}
+	seen := make(map[string]bool, len(devices))
+	accounted := make(map[string]bool, len(matched))
+	for _, device := range devices {
+		key := inventoryKey(device)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		presence.Total++
+
+		isMatch := false
+		for _, name := range []string{device.Name, device.Hostname} {
+			if _, ok := matched[strings.ToLower(name)]; ok && name != "" {
+				accounted[strings.ToLower(name)] = true
+				isMatch = true
+			}
+		}
+		if isMatch {
+			presence.Matched++
+		}
+		if isMatch != args.Inverse {
+			presence.Devices = append(presence.Devices, device.Name)
+		}
+	}
+	// Matches for devices missing from the inventory are still reported
+	for key, name := range matched {
+		if accounted[key] {
+			continue
+		}
+		presence.Total++
+		presence.Matched++
+		if !args.Inverse {
+			presence.Devices = append(presence.Devices, name)
+		}
+	}
+	sort.Strings(presence.Devices)
+
+	verb := "match"
+	if args.Inverse {
+		verb = "do not match"
+	}
+	header := fmt.Sprintf("%d of %d devices %s the config pattern", len(presence.Devices), presence.Total, verb)
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, presence.Devices, presence, args.Pretty))), nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// telnetSearchService returns a service whose config search matches only router-1,
+// which the query result names by hostname
+func telnetSearchService() *ForwardMCPService {
+	service := createTestService()
+	service.client().(*MockForwardClient).nqeResult = &forward.NQERunResult{
+		Items: []map[string]interface{}{
+			{"device": "rtr1.example.com", "line": "transport input telnet"},
+			{"device": "rtr1.example.com", "line": "transport input telnet ssh"},
+		},
+	}
+	return service
+}
+
+func TestFindDevicesByConfigMatchesSubset(t *testing.T) {
+	service := telnetSearchService()
+
+	response, err := service.findDevicesByConfig(FindDevicesByConfigArgs{Pattern: "transport input telnet"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.HasPrefix(text, "1 of 2 devices match the config pattern") {
+		t.Errorf("Expected one of two devices to match, got:\n%s", text)
+	}
+	if !strings.Contains(text, `"router-1"`) || strings.Contains(text, "switch-1") {
+		t.Errorf("Expected only router-1 to be listed, got:\n%s", text)
+	}
+}
+
+func TestFindDevicesByConfigInverse(t *testing.T) {
+	service := telnetSearchService()
+
+	response, err := service.findDevicesByConfig(FindDevicesByConfigArgs{Pattern: "transport input telnet", Inverse: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.HasPrefix(text, "1 of 2 devices do not match the config pattern") {
+		t.Errorf("Expected one non-matching device, got:\n%s", text)
+	}
+	if !strings.Contains(text, `"switch-1"`) || strings.Contains(text, "router-1") {
+		t.Errorf("Expected only switch-1 to be listed, got:\n%s", text)
+	}
+
+	if _, err := service.findDevicesByConfig(FindDevicesByConfigArgs{Pattern: "  "}); err == nil {
+		t.Error("Expected an error for an empty pattern")
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// deviceConfigQuery selects the collected running configuration of one device. The
+// device name is inserted as a quoted NQE string literal.
+const deviceConfigQuery = `foreach device in network.devices
+where device.name == %s
+foreach command in device.outputs.commands
+where command.commandType == CommandType.CONFIG
+select {device: device.name, config: command.response}`
+
+// deviceConfigText assembles configuration text from NQE rows. Rows either carry the
+// whole configuration (config or response) or one line each (line or text, ordered by
+// lineNumber when present).
+func deviceConfigText(rows []map[string]interface{}) string {
+	var blocks []string
+	type numberedLine struct {
+		number float64
+		text   string
+	}
+	var lines []numberedLine
+
+	for i, row := range rows {
+		if text, ok := firstString(row, "config", "response"); ok {
+			blocks = append(blocks, text)
+			continue
+		}
+		if text, ok := firstString(row, "line", "text"); ok {
+			number, isNumber := row["lineNumber"].(float64)
+			if !isNumber {
+				number = float64(i)
+			}
+			lines = append(lines, numberedLine{number: number, text: text})
+		}
+	}
+
+	if len(lines) > 0 {
+		sort.SliceStable(lines, func(i, j int) bool { return lines[i].number < lines[j].number })
+		texts := make([]string, len(lines))
+		for i, line := range lines {
+			texts[i] = line.text
+		}
+		blocks = append(blocks, strings.Join(texts, "\n"))
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// firstString returns the first of keys that holds a string in row
+func firstString(row map[string]interface{}, keys ...string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := row[key].(string); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// getDeviceConfig returns one device's running configuration, optionally writing it to a file
+func (s *ForwardMCPService) getDeviceConfig(args GetDeviceConfigArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("get_device_config", args, nil)
+
+	if args.DeviceName == "" {
+		return nil, fmt.Errorf("device_name is required")
+	}
+	networkID := s.getNetworkID(args.NetworkID)
+	snapshotID, err := s.resolveSnapshotID(networkID, s.getSnapshotID(args.SnapshotID))
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := s.fetchAllDevices(networkID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+	deviceName, err := resolveDeviceName(devices, args.DeviceName)
+	if err != nil {
+		return nil, fmt.Errorf("network %s: %w", networkID, err)
+	}
+
+	result, err := s.client().RunNQEQueryByString(&forward.NQEQueryParams{
+		NetworkID:  networkID,
+		SnapshotID: snapshotID,
+		Query:      fmt.Sprintf(deviceConfigQuery, strconv.Quote(deviceName)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration for %s: %w", deviceName, err)
+	}
+
+	config := deviceConfigText(result.Items)
+	if strings.TrimSpace(config) == "" {
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("No configuration was collected for device %s in this snapshot.", deviceName))), nil
+	}
+	lineCount := strings.Count(config, "\n") + 1
+
+	if args.OutputFile != "" {
+		if err := os.WriteFile(args.OutputFile, []byte(config+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write configuration to %s: %w", args.OutputFile, err)
+		}
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Wrote configuration of %s (%d lines, %d bytes) to %s", deviceName, lineCount, len(config)+1, args.OutputFile))), nil
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Configuration of %s (%d lines):\n\n%s", deviceName, lineCount, config))), nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestGetDeviceConfigReturnsConfigText(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).nqeResult = &forward.NQERunResult{
+		Items: []map[string]interface{}{
+			{"device": "router-1", "config": "hostname router-1\ninterface GigabitEthernet0/0\n ip address 10.0.0.1 255.255.255.0"},
+		},
+	}
+
+	response, err := service.getDeviceConfig(GetDeviceConfigArgs{DeviceName: "rtr1.example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "Configuration of router-1 (3 lines)") || !strings.Contains(text, "hostname router-1") {
+		t.Errorf("Expected the device configuration, got:\n%s", text)
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "router-1.cfg")
+	if _, err := service.getDeviceConfig(GetDeviceConfigArgs{DeviceName: "router-1", OutputFile: outputFile}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if data, err := os.ReadFile(outputFile); err != nil || !strings.HasPrefix(string(data), "hostname router-1") {
+		t.Errorf("Expected configuration written to file, got %q (err %v)", data, err)
+	}
+}
+
+func TestDeviceConfigTextOrdersLineRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"line": " ip address 10.0.0.1 255.255.255.0", "lineNumber": float64(3)},
+		{"line": "hostname router-1", "lineNumber": float64(1)},
+		{"line": "interface GigabitEthernet0/0", "lineNumber": float64(2)},
+	}
+	expected := "hostname router-1\ninterface GigabitEthernet0/0\n ip address 10.0.0.1 255.255.255.0"
+	if text := deviceConfigText(rows); text != expected {
+		t.Errorf("Expected lines in order, got:\n%s", text)
+	}
+}
+
+func TestGetDeviceConfigUnknownDevice(t *testing.T) {
+	service := createTestService()
+
+	_, err := service.getDeviceConfig(GetDeviceConfigArgs{DeviceName: "core-99"})
+	if err == nil || !strings.Contains(err.Error(), `device "core-99" not found`) {
+		t.Errorf("Expected a device not found error, got: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// SnapshotInventoryDiff is the device-level change between two snapshots of one network
+type SnapshotInventoryDiff struct {
+	Added     []string                `json:"added"`
+	Removed   []string                `json:"removed"`
+	Changed   []DeviceInventoryChange `json:"changed"`
+	Unchanged int                     `json:"unchanged"`
+}
+
+// snapshotInventoryDiff reads an inventory comparison of before (a) against after (b) as
+// added, removed, and changed devices
+func snapshotInventoryDiff(diff DeviceInventoryDiff) SnapshotInventoryDiff {
+	return SnapshotInventoryDiff{
+		Added:     diff.OnlyInB,
+		Removed:   diff.OnlyInA,
+		Changed:   diff.Different,
+		Unchanged: diff.Matching,
+	}
+}
+
+// formatSnapshotInventoryDiff renders added, removed, and changed devices as compact sections
+func formatSnapshotInventoryDiff(networkID, before, after string, countBefore, countAfter int, diff SnapshotInventoryDiff) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Device inventory of %s from %s (%d devices) to %s (%d devices): %d added, %d removed, %d changed, %d unchanged\n",
+		networkID, before, countBefore, after, countAfter, len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged)
+
+	writeNames := func(label string, names []string) {
+		fmt.Fprintf(&b, "\n%s (%d):", label, len(names))
+		if len(names) == 0 {
+			b.WriteString(" none\n")
+			return
+		}
+		fmt.Fprintf(&b, " %s\n", strings.Join(names, ", "))
+	}
+	writeNames("Added", diff.Added)
+	writeNames("Removed", diff.Removed)
+
+	fmt.Fprintf(&b, "\nChanged (%d):", len(diff.Changed))
+	if len(diff.Changed) == 0 {
+		b.WriteString(" none\n")
+		return b.String()
+	}
+	b.WriteString("\n")
+	for _, device := range diff.Changed {
+		parts := make([]string, 0, len(device.Changes))
+		for _, change := range device.Changes {
+			parts = append(parts, fmt.Sprintf("%s %s -> %s", change.Field, orNone(change.A), orNone(change.B)))
+		}
+		fmt.Fprintf(&b, "• %s: %s\n", device.Name, strings.Join(parts, "; "))
+	}
+	return b.String()
+}
+
+// diffDeviceInventory reports the devices added, removed, or changed between two snapshots
+// of one network
+func (s *ForwardMCPService) diffDeviceInventory(args DiffDeviceInventoryArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("diff_device_inventory", args, nil)
+
+	if args.BeforeSnapshot == "" || args.AfterSnapshot == "" {
+		return nil, fmt.Errorf("both before_snapshot and after_snapshot are required")
+	}
+	networkID := s.getNetworkID(args.NetworkID)
+	before, err := s.resolveSnapshotID(networkID, args.BeforeSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.resolveSnapshotID(networkID, args.AfterSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	devicesBefore, err := s.fetchAllDevices(networkID, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices at snapshot %s: %w", before, err)
+	}
+	devicesAfter, err := s.fetchAllDevices(networkID, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices at snapshot %s: %w", after, err)
+	}
+
+	diff := snapshotInventoryDiff(CompareDeviceInventories(devicesBefore, devicesAfter))
+	return mcp.NewToolResponse(mcp.NewTextContent(formatSnapshotInventoryDiff(networkID, before, after, len(devicesBefore), len(devicesAfter), diff))), nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// pagedSnapshotDeviceClient returns a different device inventory per snapshot, at most
+// pageSize devices per call
+type pagedSnapshotDeviceClient struct {
+	*MockForwardClient
+	inventories map[string][]forward.Device
+	pageSize    int
+	calls       int
+}
+
+func (c *pagedSnapshotDeviceClient) GetDevices(networkID string, params *forward.DeviceQueryParams) (*forward.DeviceResponse, error) {
+	c.calls++
+	devices := c.inventories[params.SnapshotID]
+	start := min(params.Offset, len(devices))
+	end := min(start+c.pageSize, len(devices))
+	return &forward.DeviceResponse{Devices: devices[start:end], TotalCount: len(devices)}, nil
+}
+
+func TestDiffDeviceInventoryClassifiesDevices(t *testing.T) {
+	service := createTestService()
+	client := &pagedSnapshotDeviceClient{
+		MockForwardClient: service.client().(*MockForwardClient),
+		pageSize:          2,
+		inventories: map[string][]forward.Device{
+			"snap-before": {
+				{Name: "core-1", Vendor: "cisco", Model: "ASR1001", OSVersion: "17.3.1"},
+				{Name: "edge-1", Vendor: "juniper", Model: "MX204", OSVersion: "21.4R1"},
+				{Name: "old-fw-1", Vendor: "cisco", Model: "ASA5506", OSVersion: "9.8"},
+			},
+			"snap-after": {
+				{Name: "core-1", Vendor: "cisco", Model: "ASR1001", OSVersion: "17.6.4"},
+				{Name: "edge-1", Vendor: "juniper", Model: "MX204", OSVersion: "21.4R1"},
+				{Name: "fw-1", Vendor: "paloalto", Model: "PA-3220", OSVersion: "10.1"},
+				{Name: "fw-2", Vendor: "paloalto", Model: "PA-3220", OSVersion: "10.1"},
+			},
+		},
+	}
+	service.active().client = client
+
+	response, err := service.diffDeviceInventory(DiffDeviceInventoryArgs{NetworkID: "162112", BeforeSnapshot: "snap-before", AfterSnapshot: "snap-after"})
+	if err != nil {
+		t.Fatalf("diffDeviceInventory failed: %v", err)
+	}
+	if client.calls != 4 {
+		t.Errorf("Expected both inventories fetched in pages of 2 (4 calls), got %d calls", client.calls)
+	}
+
+	text := response.Content[0].TextContent.Text
+	for _, want := range []string{
+		"from snap-before (3 devices) to snap-after (4 devices): 2 added, 1 removed, 1 changed, 1 unchanged",
+		"Added (2): fw-1, fw-2",
+		"Removed (1): old-fw-1",
+		"• core-1: os_version 17.3.1 -> 17.6.4",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the diff, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestSnapshotInventoryDiffMapsSides(t *testing.T) {
+	diff := snapshotInventoryDiff(DeviceInventoryDiff{OnlyInA: []string{"gone"}, OnlyInB: []string{"new"}, Matching: 3})
+	if !reflect.DeepEqual(diff.Removed, []string{"gone"}) || !reflect.DeepEqual(diff.Added, []string{"new"}) || diff.Unchanged != 3 {
+		t.Errorf("Unexpected snapshot diff %+v", diff)
+	}
+}
+
+func TestDetectMissingDevicesReportsBaselineDevices(t *testing.T) {
+	service := createTestService()
+	client := &pagedSnapshotDeviceClient{
+		MockForwardClient: service.client().(*MockForwardClient),
+		pageSize:          10,
+		inventories: map[string][]forward.Device{
+			"snap-baseline": {
+				{Name: "core-1", Vendor: "cisco", Model: "ASR1001"},
+				{Name: "edge-1", Vendor: "juniper", Model: "MX204", OSVersion: "21.4R1", ManagementIPs: []string{"10.0.0.2"}},
+			},
+			"snapshot-123": {
+				{Name: "core-1", Vendor: "cisco", Model: "ASR1001"},
+				{Name: "fw-1", Vendor: "paloalto", Model: "PA-3220"},
+			},
+		},
+	}
+	service.active().client = client
+
+	response, err := service.detectMissingDevices(DetectMissingDevicesArgs{NetworkID: "162112", BaselineSnapshot: "snap-baseline"})
+	if err != nil {
+		t.Fatalf("detectMissingDevices failed: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	for _, want := range []string{"1 of 2 baseline devices are missing from the latest snapshot snapshot-123", `"name": "edge-1"`, `"osVersion": "21.4R1"`, "10.0.0.2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the report, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "core-1") || strings.Contains(text, "fw-1") {
+		t.Errorf("Expected only the missing device to be reported, got:\n%s", text)
+	}
+
+	if _, err := service.detectMissingDevices(DetectMissingDevicesArgs{NetworkID: "162112", BaselineSnapshot: "snapshot-123"}); err == nil {
+		t.Error("Expected the latest snapshot to be rejected as a baseline")
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// DeviceReachability is the outcome of one source/destination device pair. BlockingHop
+// and Reason are set when the destination is unreachable.
+type DeviceReachability struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	SrcIP       string `json:"src_ip"`
+	DstIP       string `json:"dst_ip"`
+	Reachable   bool   `json:"reachable"`
+	Outcome     string `json:"outcome,omitempty"`
+	BlockingHop string `json:"blocking_hop,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// status is the matrix cell for the pair
+func (r DeviceReachability) status() string {
+	switch {
+	case r.Error != "":
+		return "ERROR"
+	case r.Reachable:
+		return "REACHABLE"
+	default:
+		return "UNREACHABLE"
+	}
+}
+
+// DeviceReachabilityMatrix maps source device to destination device to a pair's status
+type DeviceReachabilityMatrix map[string]map[string]string
+
+// deviceManagementIP resolves a device reference to its device name and first management IP
+func deviceManagementIP(devices []forward.Device, reference string) (string, string, error) {
+	name, err := resolveDeviceName(devices, reference)
+	if err != nil {
+		return "", "", err
+	}
+	for _, device := range devices {
+		if device.Name == name {
+			if len(device.ManagementIPs) == 0 {
+				return "", "", fmt.Errorf("device %s has no management IP", name)
+			}
+			return name, device.ManagementIPs[0], nil
+		}
+	}
+	return "", "", fmt.Errorf("device %q not found; use list_devices to see device names", reference)
+}
+
+// classifyDeviceReachability fills in a pair's outcome from its path search response. The
+// pair is reachable when any path is delivered; otherwise the first path's blocking hop
+// and reason explain why.
+func classifyDeviceReachability(result *DeviceReachability, response forward.PathSearchResponse) {
+	if response.Error != "" {
+		result.Error = response.Error
+		return
+	}
+	if len(response.Paths) == 0 {
+		result.Outcome = string(OutcomeUnreachable)
+		result.Reason = "no paths found"
+		return
+	}
+	for _, path := range response.Paths {
+		if ClassifyPath(path).Class == OutcomeDelivered {
+			result.Reachable = true
+			result.Outcome = string(OutcomeDelivered)
+			return
+		}
+	}
+	classification := ClassifyPath(response.Paths[0])
+	result.Outcome = string(classification.Class)
+	result.Reason = classification.Reason
+	if classification.BlockingHop != nil {
+		result.BlockingHop = classification.BlockingHop.Device
+	}
+}
+
+// checkDeviceReachability resolves each device pair to management IPs, traces them in one
+// paths-bulk request, and reports a reachability matrix
+func (s *ForwardMCPService) checkDeviceReachability(args CheckDeviceReachabilityArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("check_device_reachability", args, nil)
+
+	if len(args.Pairs) == 0 {
+		return nil, fmt.Errorf("pairs must contain at least one device pair")
+	}
+	if len(args.Pairs) > maxBulkPathFlows {
+		return nil, fmt.Errorf("too many pairs: %d (max %d per check)", len(args.Pairs), maxBulkPathFlows)
+	}
+
+	networkID := s.getNetworkID(args.NetworkID)
+	snapshotID, err := s.pathSearchSnapshot(networkID, args.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := s.fetchAllDevices(networkID, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	results := make([]DeviceReachability, len(args.Pairs))
+	requests := make([]forward.PathSearchParams, len(args.Pairs))
+	for i, pair := range args.Pairs {
+		source, srcIP, err := deviceManagementIP(devices, pair.Source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source in pair %d: %w", i+1, err)
+		}
+		destination, dstIP, err := deviceManagementIP(devices, pair.Destination)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination in pair %d: %w", i+1, err)
+		}
+		results[i] = DeviceReachability{Source: source, Destination: destination, SrcIP: srcIP, DstIP: dstIP}
+		requests[i] = forward.PathSearchParams{
+			From:       source,
+			SrcIP:      srcIP,
+			DstIP:      dstIP,
+			SnapshotID: snapshotID,
+		}
+		s.applyPathSearchDefaults(&requests[i])
+	}
+
+	responses, err := s.client().SearchPathsBulk(networkID, requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search paths: %w", err)
+	}
+	if len(responses) != len(requests) {
+		s.logger.Warn("Bulk path search returned %d responses for %d device pairs", len(responses), len(requests))
+	}
+
+	matrix := make(DeviceReachabilityMatrix)
+	reachable := 0
+	identifiers := make([]string, 0, len(results))
+	for i := range results {
+		result := &results[i]
+		if i < len(responses) {
+			classifyDeviceReachability(result, responses[i])
+		} else {
+			result.Error = fmt.Sprintf("no response returned (API answered %d of %d flows)", len(responses), len(requests))
+		}
+		if result.Reachable {
+			reachable++
+		}
+		if matrix[result.Source] == nil {
+			matrix[result.Source] = make(map[string]string)
+		}
+		matrix[result.Source][result.Destination] = result.status()
+
+		identifier := fmt.Sprintf("%s -> %s=%s", result.Source, result.Destination, result.status())
+		if result.BlockingHop != "" {
+			identifier += " at " + result.BlockingHop
+		}
+		identifiers = append(identifiers, identifier)
+	}
+
+	header := fmt.Sprintf("Device reachability on %s (snapshot %s): %d of %d pairs reachable", networkID, snapshotID, reachable, len(results))
+	payload := struct {
+		Matrix DeviceReachabilityMatrix `json:"matrix"`
+		Pairs  []DeviceReachability     `json:"pairs"`
+	}{matrix, results}
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, identifiers, payload, args.Pretty))), nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// reachabilityClient answers each bulk flow by destination IP and records the requests
+type reachabilityClient struct {
+	*MockForwardClient
+	byDstIP  map[string]forward.PathSearchResponse
+	requests []forward.PathSearchParams
+}
+
+func (c *reachabilityClient) SearchPathsBulk(networkID string, requests []forward.PathSearchParams) ([]forward.PathSearchResponse, error) {
+	c.requests = requests
+	responses := make([]forward.PathSearchResponse, len(requests))
+	for i, request := range requests {
+		responses[i] = c.byDstIP[request.DstIP]
+	}
+	return responses, nil
+}
+
+func TestCheckDeviceReachabilityBuildsMatrix(t *testing.T) {
+	service := createTestService()
+	client := &reachabilityClient{
+		MockForwardClient: service.client().(*MockForwardClient),
+		byDstIP: map[string]forward.PathSearchResponse{
+			"192.168.1.2": {Paths: []forward.Path{{Outcome: "DELIVERED", Hops: []forward.Hop{{Device: "router-1"}, {Device: "switch-1"}}}}},
+			"192.168.1.1": {Paths: []forward.Path{{Outcome: "DROPPED", OutcomeType: "ACL denied", Hops: []forward.Hop{
+				{Device: "switch-1", Action: "FORWARD"},
+				{Device: "router-1", Action: "DROP"},
+			}}}},
+		},
+	}
+	service.active().client = client
+
+	response, err := service.checkDeviceReachability(CheckDeviceReachabilityArgs{
+		NetworkID:  "162112",
+		SnapshotID: "snap-1",
+		Pairs: []DevicePair{
+			{Source: "ROUTER-1", Destination: "sw1.example.com"},
+			{Source: "switch-1", Destination: "router-1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("checkDeviceReachability failed: %v", err)
+	}
+
+	if len(client.requests) != 2 {
+		t.Fatalf("Expected one bulk request with 2 flows, got %d", len(client.requests))
+	}
+	first := client.requests[0]
+	if first.From != "router-1" || first.SrcIP != "192.168.1.1" || first.DstIP != "192.168.1.2" {
+		t.Errorf("Expected device references resolved to names and management IPs, got %+v", first)
+	}
+
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "1 of 2 pairs reachable") {
+		t.Errorf("Expected a reachable count in the header, got:\n%s", text)
+	}
+
+	var payload struct {
+		Matrix DeviceReachabilityMatrix `json:"matrix"`
+		Pairs  []DeviceReachability     `json:"pairs"`
+	}
+	if err := json.Unmarshal([]byte(text[strings.Index(text, "{"):]), &payload); err != nil {
+		t.Fatalf("Failed to parse payload: %v\n%s", err, text)
+	}
+	if payload.Matrix["router-1"]["switch-1"] != "REACHABLE" || payload.Matrix["switch-1"]["router-1"] != "UNREACHABLE" {
+		t.Errorf("Unexpected matrix: %v", payload.Matrix)
+	}
+	if blocked := payload.Pairs[1]; blocked.BlockingHop != "router-1" || blocked.Outcome != string(OutcomeDroppedACL) {
+		t.Errorf("Expected the unreachable pair blocked by an ACL at router-1, got %+v", blocked)
+	}
+}
+
+func TestCheckDeviceReachabilityRejectsUnknownDevice(t *testing.T) {
+	service := createTestService()
+
+	_, err := service.checkDeviceReachability(CheckDeviceReachabilityArgs{
+		NetworkID: "162112",
+		Pairs:     []DevicePair{{Source: "router-1", Destination: "core-9"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), `invalid destination in pair 1: device "core-9" not found`) {
+		t.Errorf("Expected an unknown device error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// defaultCoverageThreshold is the embedding coverage below which a category is flagged
+const defaultCoverageThreshold = 0.8
+
+// EmbeddingCoverage is how many queries of a category or subcategory have embeddings
+type EmbeddingCoverage struct {
+	Name          string              `json:"name"`
+	Total         int                 `json:"total"`
+	Embedded      int                 `json:"embedded"`
+	Coverage      float64             `json:"coverage"`
+	Subcategories []EmbeddingCoverage `json:"subcategories,omitempty"`
+}
+
+// sortCoverage orders entries from least to most covered, then by name
+func sortCoverage(entries []EmbeddingCoverage) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Coverage != entries[j].Coverage {
+			return entries[i].Coverage < entries[j].Coverage
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// GetEmbeddingCoverage breaks the embedding coverage counted by GetStatistics down by
+// category and subcategory, least covered first. Queries without a category are reported
+// under "Other".
+func (idx *NQEQueryIndex) GetEmbeddingCoverage() []EmbeddingCoverage {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	type counts struct{ total, embedded int }
+	categories := make(map[string]*counts)
+	subcategories := make(map[string]map[string]*counts)
+	for _, query := range idx.queries {
+		category := query.Category
+		if category == "" {
+			category = "Other"
+		}
+		if categories[category] == nil {
+			categories[category] = &counts{}
+			subcategories[category] = make(map[string]*counts)
+		}
+		embedded := len(query.Embedding) > 0 || idx.isSpilled(query)
+		tallies := []*counts{categories[category]}
+		if query.Subcategory != "" {
+			if subcategories[category][query.Subcategory] == nil {
+				subcategories[category][query.Subcategory] = &counts{}
+			}
+			tallies = append(tallies, subcategories[category][query.Subcategory])
+		}
+		for _, tally := range tallies {
+			tally.total++
+			if embedded {
+				tally.embedded++
+			}
+		}
+	}
+
+	coverage := func(name string, c *counts) EmbeddingCoverage {
+		return EmbeddingCoverage{Name: name, Total: c.total, Embedded: c.embedded, Coverage: float64(c.embedded) / float64(c.total)}
+	}
+	result := make([]EmbeddingCoverage, 0, len(categories))
+	for name, c := range categories {
+		entry := coverage(name, c)
+		for subName, sub := range subcategories[name] {
+			entry.Subcategories = append(entry.Subcategories, coverage(subName, sub))
+		}
+		sortCoverage(entry.Subcategories)
+		result = append(result, entry)
+	}
+	sortCoverage(result)
+	return result
+}
+
+// getEmbeddingCoverage reports embedded versus total queries per category and subcategory,
+// flagging those below the coverage threshold
+func (s *ForwardMCPService) getEmbeddingCoverage(args GetEmbeddingCoverageArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("get_embedding_coverage", args, nil)
+
+	threshold := args.Threshold
+	if threshold == 0 {
+		threshold = defaultCoverageThreshold
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("invalid threshold %v: must be between 0 and 1", args.Threshold)
+	}
+
+	stats := s.queryIndex.GetStatistics()
+	total, _ := stats["total_queries"].(int)
+	if total == 0 {
+		return mcp.NewToolResponse(mcp.NewTextContent("Query index is empty. Run `initialize_query_index` to load queries from the spec file.")), nil
+	}
+	embedded, _ := stats["embedded_queries"].(int)
+
+	coverage := s.queryIndex.GetEmbeddingCoverage()
+	response := fmt.Sprintf("Embedding coverage: %d of %d queries (%.1f%%), threshold %.0f%%\n\n", embedded, total, float64(embedded)/float64(total)*100, threshold*100)
+
+	gaps := 0
+	for _, category := range coverage {
+		marker := "✅"
+		if category.Coverage < threshold {
+			marker = "⚠️"
+			gaps++
+		}
+		response += fmt.Sprintf("%s %s: %d/%d (%.1f%%)\n", marker, category.Name, category.Embedded, category.Total, category.Coverage*100)
+		for _, sub := range category.Subcategories {
+			if sub.Coverage < threshold {
+				response += fmt.Sprintf("    - %s: %d/%d (%.1f%%)\n", sub.Name, sub.Embedded, sub.Total, sub.Coverage*100)
+			}
+		}
+	}
+
+	if gaps == 0 {
+		response += "\nEvery category meets the coverage threshold.\n"
+	} else {
+		response += fmt.Sprintf("\n%d categories are below the threshold, so semantic search there falls back to keywords. Run `initialize_query_index` with `generate_embeddings: true` to regenerate embeddings.\n", gaps)
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmbeddingCoverageByCategory(t *testing.T) {
+	service := setupSmartSearchTestService()
+	seedQueryIndex(service.queryIndex,
+		"/L3/BGP/BGP Neighbor State",
+		"/L3/BGP/BGP Route Count",
+		"/L3/OSPF/OSPF Adjacencies",
+		"/Security/ACL/Permit Any Rules",
+		"/Security/ACL/Deny Rules",
+	)
+	// Embed every Security query and one BGP query
+	for _, i := range []int{0, 3, 4} {
+		service.queryIndex.queries[i].Embedding = []float32{0.1, 0.2}
+	}
+
+	coverage := service.queryIndex.GetEmbeddingCoverage()
+	if len(coverage) != 2 || coverage[0].Name != "L3" || coverage[0].Embedded != 1 || coverage[0].Total != 3 {
+		t.Fatalf("Expected L3 first with 1 of 3 embedded, got %+v", coverage)
+	}
+	if subs := coverage[0].Subcategories; len(subs) != 2 || subs[0].Name != "OSPF" || subs[0].Coverage != 0 || subs[1].Name != "BGP" || subs[1].Coverage != 0.5 {
+		t.Errorf("Expected OSPF (0%%) then BGP (50%%), got %+v", subs)
+	}
+	if coverage[1].Name != "Security" || coverage[1].Coverage != 1 {
+		t.Errorf("Expected Security fully covered, got %+v", coverage[1])
+	}
+
+	response, err := service.getEmbeddingCoverage(GetEmbeddingCoverageArgs{})
+	if err != nil {
+		t.Fatalf("getEmbeddingCoverage failed: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	for _, want := range []string{"3 of 5 queries (60.0%)", "⚠️ L3: 1/3 (33.3%)", "    - OSPF: 0/1 (0.0%)", "✅ Security: 2/2 (100.0%)", "generate_embeddings: true"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the report, got:\n%s", want, text)
+		}
+	}
+
+	if _, err := service.getEmbeddingCoverage(GetEmbeddingCoverageArgs{Threshold: 1.5}); err == nil {
+		t.Error("Expected a threshold above 1 to be rejected")
+	}
+}
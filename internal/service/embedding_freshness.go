@@ -0,0 +1,223 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/forward-mcp/internal/config"
+)
+
+// specHashSuffix names the file next to the embeddings cache that records the SHA-256 of
+// the spec file the embeddings were generated from
+const specHashSuffix = ".spec-sha256"
+
+// textHashSuffix names the file next to the embeddings cache that records, per query path,
+// the SHA-256 of the text each cached embedding was generated from
+const textHashSuffix = ".text-sha256.json"
+
+// EmbeddingsFreshness reports whether the embeddings cache still matches the spec file
+type EmbeddingsFreshness struct {
+	Stale             bool   `json:"stale"`
+	Reason            string `json:"reason,omitempty"`
+	MissingEmbeddings int    `json:"missing_embeddings"`
+}
+
+// hashSpecFile returns the hex SHA-256 of the spec file
+func (idx *NQEQueryIndex) hashSpecFile() (string, error) {
+	specPath, err := idx.specFilePath()
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(specPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// embeddingText is the text a query's embedding is generated from, using all parsed fields
+// for richer context
+func embeddingText(query *NQEQueryIndexEntry) string {
+	return fmt.Sprintf(
+		"Query Path: %s\nCategory: %s\nSubcategory: %s\nIntent: %s",
+		query.Path, query.Category, query.Subcategory, query.Intent,
+	)
+}
+
+// embeddingTextHash returns the hex SHA-256 of a query's embedding text
+func embeddingTextHash(query *NQEQueryIndexEntry) string {
+	hash := sha256.Sum256([]byte(embeddingText(query)))
+	return hex.EncodeToString(hash[:])
+}
+
+// recordTextHashes stores the embedding-text hash of each query whose embedding is in the
+// cache, keyed by path, so a query whose text changed is embedded again
+func (idx *NQEQueryIndex) recordTextHashes(cached map[string][]float32) error {
+	hashes := make(map[string]string, len(cached))
+	for _, query := range idx.queries {
+		if _, ok := cached[query.Path]; ok {
+			hashes[query.Path] = embeddingTextHash(query)
+		}
+	}
+	data, err := json.MarshalIndent(hashes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal embedding text hashes: %w", err)
+	}
+	if err := writeFileAtomic(idx.embeddingsCachePath+textHashSuffix, data, 0644); err != nil {
+		return fmt.Errorf("failed to record embedding text hashes: %w", err)
+	}
+	return nil
+}
+
+// readTextHashes reads the embedding-text hashes recorded next to the embeddings cache
+func (idx *NQEQueryIndex) readTextHashes() (map[string]string, error) {
+	data, err := os.ReadFile(idx.embeddingsCachePath + textHashSuffix)
+	if err != nil {
+		return nil, err
+	}
+	var hashes map[string]string
+	if err := json.Unmarshal(data, &hashes); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding text hashes: %w", err)
+	}
+	return hashes, nil
+}
+
+// staleText reports whether a cached embedding was generated from text other than the
+// query's current embedding text. Embeddings without a recorded hash are kept.
+func staleText(hashes map[string]string, query *NQEQueryIndexEntry) bool {
+	recorded, ok := hashes[query.Path]
+	return ok && recorded != embeddingTextHash(query)
+}
+
+// recordSpecHash stores the hash of the current spec file next to the embeddings cache,
+// so a later start can tell whether the spec changed since the embeddings were saved.
+// It is only recorded once a run has embedded every query; checkpoints leave the previous
+// hash, so an interrupted regeneration is still reported as stale.
+func (idx *NQEQueryIndex) recordSpecHash() error {
+	specHash, err := idx.hashSpecFile()
+	if err != nil {
+		return fmt.Errorf("failed to hash spec file: %w", err)
+	}
+	if err := writeFileAtomic(idx.embeddingsCachePath+specHashSuffix, []byte(specHash+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to record spec hash: %w", err)
+	}
+	return nil
+}
+
+// CheckEmbeddingsFreshness compares the spec file with the one the embeddings cache was
+// generated from. The recorded spec hash is compared when there is one; caches written
+// before hashes were recorded fall back to comparing modification times. Without an
+// embeddings cache there is nothing to be stale.
+func (idx *NQEQueryIndex) CheckEmbeddingsFreshness() (EmbeddingsFreshness, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	var freshness EmbeddingsFreshness
+	for _, query := range idx.queries {
+		if len(query.Embedding) == 0 && !idx.isSpilled(query) {
+			freshness.MissingEmbeddings++
+		}
+	}
+
+	cacheInfo, err := os.Stat(idx.embeddingsCachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return freshness, nil
+	}
+	if err != nil {
+		return freshness, fmt.Errorf("failed to stat embeddings cache: %w", err)
+	}
+
+	recorded, err := os.ReadFile(idx.embeddingsCachePath + specHashSuffix)
+	if err == nil {
+		current, err := idx.hashSpecFile()
+		if err != nil {
+			return freshness, fmt.Errorf("failed to hash spec file: %w", err)
+		}
+		if strings.TrimSpace(string(recorded)) != current {
+			freshness.Stale = true
+			freshness.Reason = "the spec file changed since the embeddings were generated"
+		}
+		return freshness, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return freshness, fmt.Errorf("failed to read recorded spec hash: %w", err)
+	}
+
+	specPath, err := idx.specFilePath()
+	if err != nil {
+		return freshness, err
+	}
+	specInfo, err := os.Stat(specPath)
+	if err != nil {
+		return freshness, fmt.Errorf("failed to stat spec file: %w", err)
+	}
+	if specInfo.ModTime().After(cacheInfo.ModTime()) {
+		freshness.Stale = true
+		freshness.Reason = "the spec file is newer than the embeddings cache"
+	}
+	return freshness, nil
+}
+
+// autoRegenerateEmbeddings reports whether stale embeddings are regenerated at startup
+func autoRegenerateEmbeddings(cfg *config.Config) bool {
+	return cfg != nil && cfg.Forward.SemanticCache.AutoRegenerateEmbeddings
+}
+
+// checkEmbeddingsFreshness warns when the embeddings cache is stale and, when
+// FORWARD_MCP_AUTO_REGENERATE_EMBEDDINGS is set, starts a background build that embeds
+// the queries still missing one. It returns the build token, or "" when none started.
+func (s *ForwardMCPService) checkEmbeddingsFreshness() string {
+	if s.queryIndex == nil {
+		return ""
+	}
+	freshness, err := s.queryIndex.CheckEmbeddingsFreshness()
+	if err != nil {
+		s.logger.Debug("Skipping embeddings freshness check: %v", err)
+		return ""
+	}
+	if !freshness.Stale {
+		return ""
+	}
+
+	s.logger.Warn("⚠️  NQE EMBEDDINGS ARE STALE: %s (%d queries have no embedding). Semantic search is degraded until embeddings are regenerated with 'initialize_query_index' and 'generate_embeddings: true'.",
+		freshness.Reason, freshness.MissingEmbeddings)
+	if !autoRegenerateEmbeddings(s.config) {
+		return ""
+	}
+	if !isRealEmbeddingProvider(s.queryIndex.embeddingService) {
+		s.logger.Warn("Not regenerating embeddings: no embedding provider is configured")
+		return ""
+	}
+
+	// Embeddings loaded from the cache are kept, so only new, renamed, or changed queries
+	// are embedded
+	token, err := s.indexBuilds.Start(func(progress IndexBuildProgressFunc) error {
+		return s.queryIndex.GenerateEmbeddingsWithProgress(func(processed, total int) {
+			progress("generating_embeddings", processed, total)
+		})
+	})
+	if err != nil {
+		s.logger.Warn("Not regenerating embeddings: %v", err)
+		return ""
+	}
+	s.logger.Info("Regenerating stale embeddings in background build %s", token)
+	return token
+}
+
+// StartEmbeddingsFreshnessCheck runs the embeddings freshness check at startup. Any
+// regeneration runs in the background and can be followed with get_index_build_status.
+func (s *ForwardMCPService) StartEmbeddingsFreshnessCheck() {
+	s.checkEmbeddingsFreshness()
+}
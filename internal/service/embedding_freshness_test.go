@@ -0,0 +1,178 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/config"
+	"github.com/forward-mcp/internal/logger"
+)
+
+func TestChangedSpecWarnsAndRegeneratesEmbeddings(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "NQELibrary.json")
+	writeTestSpec(t, specPath, map[string]string{"FQ_bgp": "/L3/BGP/BGP Neighbor State"})
+
+	logs := &lockedBuffer{}
+	idx := NewNQEQueryIndex(NewKeywordEmbeddingService(), logger.NewWithWriter(logs))
+	if err := idx.SetSpecDir(dir); err != nil {
+		t.Fatalf("SetSpecDir failed: %v", err)
+	}
+	if err := idx.LoadFromSpec(); err != nil {
+		t.Fatalf("LoadFromSpec failed: %v", err)
+	}
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+	if freshness, err := idx.CheckEmbeddingsFreshness(); err != nil || freshness.Stale {
+		t.Fatalf("Expected fresh embeddings right after generation, got %+v (err: %v)", freshness, err)
+	}
+
+	// The operator adds a query to the spec and restarts
+	writeTestSpec(t, specPath, map[string]string{
+		"FQ_bgp":  "/L3/BGP/BGP Neighbor State",
+		"FQ_ospf": "/L3/OSPF/OSPF Adjacencies",
+	})
+	if err := idx.LoadFromSpec(); err != nil {
+		t.Fatalf("LoadFromSpec failed: %v", err)
+	}
+	service := &ForwardMCPService{
+		config:      &config.Config{},
+		logger:      logger.NewWithWriter(logs),
+		queryIndex:  idx,
+		indexBuilds: NewIndexBuilder(),
+	}
+
+	if token := service.checkEmbeddingsFreshness(); token != "" {
+		t.Errorf("Expected no regeneration while it is disabled, got build %s", token)
+	}
+	if !strings.Contains(logs.String(), "NQE EMBEDDINGS ARE STALE: the spec file changed since the embeddings were generated (1 queries have no embedding)") {
+		t.Errorf("Expected a stale embeddings warning, got:\n%s", logs.String())
+	}
+
+	service.config.Forward.SemanticCache.AutoRegenerateEmbeddings = true
+	if token := service.checkEmbeddingsFreshness(); token == "" {
+		t.Fatal("Expected a background regeneration to start")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for service.indexBuilds.Running() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := service.indexBuilds.Status(); status.State != IndexBuildDone {
+		t.Fatalf("Expected the regeneration to finish, got %+v", status)
+	}
+	freshness, err := idx.CheckEmbeddingsFreshness()
+	if err != nil || freshness.Stale || freshness.MissingEmbeddings != 0 {
+		t.Errorf("Expected fresh, complete embeddings after regeneration, got %+v (err: %v)", freshness, err)
+	}
+}
+
+func TestEmbeddingsFreshnessFallsBackToModTime(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "NQELibrary.json")
+	writeTestSpec(t, specPath, map[string]string{"FQ_bgp": "/L3/BGP/BGP Neighbor State"})
+	cachePath := filepath.Join(dir, "nqe-embeddings.json")
+	if err := os.WriteFile(cachePath, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(cachePath, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := NewNQEQueryIndex(NewKeywordEmbeddingService(), logger.New())
+	if err := idx.SetSpecDir(dir); err != nil {
+		t.Fatalf("SetSpecDir failed: %v", err)
+	}
+	freshness, err := idx.CheckEmbeddingsFreshness()
+	if err != nil || !freshness.Stale || !strings.Contains(freshness.Reason, "newer") {
+		t.Errorf("Expected a cache older than the spec to be stale, got %+v (err: %v)", freshness, err)
+	}
+}
+
+func TestInterruptedRegenerationStaysStale(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "NQELibrary.json")
+	writeTestSpec(t, specPath, map[string]string{"FQ_bgp": "/L3/BGP/BGP Neighbor State"})
+
+	service := &scriptedEmbeddingService{keyword: NewKeywordEmbeddingService()}
+	idx := NewNQEQueryIndex(service, logger.New())
+	if err := idx.SetSpecDir(dir); err != nil {
+		t.Fatalf("SetSpecDir failed: %v", err)
+	}
+	if err := idx.LoadFromSpec(); err != nil {
+		t.Fatalf("LoadFromSpec failed: %v", err)
+	}
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+
+	// The spec grows and the regeneration stops partway after repeated failures
+	queries := map[string]string{"FQ_bgp": "/L3/BGP/BGP Neighbor State"}
+	for i := 0; i <= maxConsecutiveEmbeddingFailures; i++ {
+		queries[fmt.Sprintf("FQ_%d", i)] = fmt.Sprintf("/L3/Test/Query %d", i)
+	}
+	writeTestSpec(t, specPath, queries)
+	if err := idx.LoadFromSpec(); err != nil {
+		t.Fatalf("LoadFromSpec failed: %v", err)
+	}
+	for i := 0; i < maxConsecutiveEmbeddingFailures; i++ {
+		service.errors = append(service.errors, fmt.Errorf("connection reset"))
+	}
+	if err := idx.GenerateEmbeddings(); err == nil {
+		t.Fatal("Expected the regeneration to stop after repeated failures")
+	}
+
+	freshness, err := idx.CheckEmbeddingsFreshness()
+	if err != nil || !freshness.Stale {
+		t.Errorf("Expected an interrupted regeneration to leave the embeddings stale, got %+v (err: %v)", freshness, err)
+	}
+}
+
+func TestChangedEmbeddingTextIsReembedded(t *testing.T) {
+	dir := t.TempDir()
+	writeTestSpec(t, filepath.Join(dir, "NQELibrary.json"), map[string]string{
+		"FQ_bgp":  "/L3/BGP/BGP Neighbor State",
+		"FQ_ospf": "/L3/OSPF/OSPF Adjacencies",
+	})
+	newIndex := func(service EmbeddingService) *NQEQueryIndex {
+		idx := NewNQEQueryIndex(service, logger.New())
+		if err := idx.SetSpecDir(dir); err != nil {
+			t.Fatalf("SetSpecDir failed: %v", err)
+		}
+		if err := idx.LoadFromSpec(); err != nil {
+			t.Fatalf("LoadFromSpec failed: %v", err)
+		}
+		return idx
+	}
+	idx := newIndex(&scriptedEmbeddingService{keyword: NewKeywordEmbeddingService()})
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+
+	// Record the OSPF embedding as generated from older text
+	hashesPath := idx.embeddingsCachePath + textHashSuffix
+	hashes, err := idx.readTextHashes()
+	if err != nil || len(hashes) != 2 {
+		t.Fatalf("Expected text hashes for both queries, got %v (err: %v)", hashes, err)
+	}
+	hashes["/L3/OSPF/OSPF Adjacencies"] = "outdated"
+	data, _ := json.Marshal(hashes)
+	if err := os.WriteFile(hashesPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	service := &scriptedEmbeddingService{keyword: NewKeywordEmbeddingService()}
+	reloaded := newIndex(service)
+	if err := reloaded.GenerateEmbeddings(); err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+	if len(service.calls) != 1 || !strings.Contains(service.calls[0], "OSPF Adjacencies") {
+		t.Errorf("Expected only the changed query to be embedded again, got calls %q", service.calls)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// Adaptive slowdown applied by EmbeddingRateLimiter when the provider still rate limits
+const (
+	maxEmbeddingSlowdown       = 8  // the spacing grows to at most 8x the configured rate
+	embeddingRecoverySuccesses = 20 // successful requests before the spacing halves again
+)
+
+// EmbeddingRateLimiter spaces embedding API requests to a requests-per-minute budget,
+// shared by every caller of one embedding service. It is a token bucket holding a single
+// token, so requests start at most once per interval. A rate-limited response doubles the
+// interval and sustained success halves it back towards the configured rate. A nil
+// limiter does not wait.
+type EmbeddingRateLimiter struct {
+	mutex     sync.Mutex
+	interval  time.Duration
+	slowdown  int
+	successes int
+	next      time.Time
+	now       func() time.Time
+	sleep     func(time.Duration)
+}
+
+// NewEmbeddingRateLimiter creates a limiter for requestsPerMinute, or nil when it is not positive
+func NewEmbeddingRateLimiter(requestsPerMinute int) *EmbeddingRateLimiter {
+	if requestsPerMinute <= 0 {
+		return nil
+	}
+	return &EmbeddingRateLimiter{
+		interval: time.Minute / time.Duration(requestsPerMinute),
+		slowdown: 1,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+}
+
+// Wait blocks until the caller may send its request. Slots are reserved in call order, so
+// concurrent callers are spaced out rather than released together.
+func (l *EmbeddingRateLimiter) Wait() {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	now := l.now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval * time.Duration(l.slowdown))
+	l.mutex.Unlock()
+
+	if delay := start.Sub(now); delay > 0 {
+		l.sleep(delay)
+	}
+}
+
+// Throttle slows the limiter down after the provider rate limited a request
+func (l *EmbeddingRateLimiter) Throttle() {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.slowdown < maxEmbeddingSlowdown {
+		l.slowdown *= 2
+	}
+	l.successes = 0
+}
+
+// Succeeded records a successful request, easing a previous slowdown over time
+func (l *EmbeddingRateLimiter) Succeeded() {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.slowdown == 1 {
+		return
+	}
+	l.successes++
+	if l.successes >= embeddingRecoverySuccesses {
+		l.slowdown /= 2
+		l.successes = 0
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeLimiterClock returns a limiter whose sleeps advance a fake clock, recording when each
+// call to Wait returns
+func fakeLimiterClock(rpm int) (*EmbeddingRateLimiter, *time.Time) {
+	limiter := NewEmbeddingRateLimiter(rpm)
+	now := time.Unix(0, 0)
+	limiter.now = func() time.Time { return now }
+	limiter.sleep = func(d time.Duration) { now = now.Add(d) }
+	return limiter, &now
+}
+
+func TestEmbeddingRateLimiterSpacesCalls(t *testing.T) {
+	limiter, now := fakeLimiterClock(2)
+	start := *now
+
+	var offsets []time.Duration
+	for i := 0; i < 3; i++ {
+		limiter.Wait()
+		offsets = append(offsets, now.Sub(start))
+	}
+	expected := []time.Duration{0, 30 * time.Second, 60 * time.Second}
+	for i := range expected {
+		if offsets[i] != expected[i] {
+			t.Fatalf("Expected calls at %v, got %v", expected, offsets)
+		}
+	}
+}
+
+func TestEmbeddingRateLimiterSlowsDownAfterRateLimit(t *testing.T) {
+	limiter, now := fakeLimiterClock(2)
+	limiter.Wait()
+	limiter.Throttle()
+
+	before := *now
+	limiter.Wait()
+	limiter.Wait()
+	if gap := now.Sub(before); gap != 30*time.Second+60*time.Second {
+		t.Errorf("Expected the spacing to double after a rate limit, waited %v", gap)
+	}
+
+	for i := 0; i < embeddingRecoverySuccesses; i++ {
+		limiter.Succeeded()
+	}
+	if limiter.slowdown != 1 {
+		t.Errorf("Expected sustained success to restore the configured rate, slowdown is %d", limiter.slowdown)
+	}
+}
+
+func TestNilEmbeddingRateLimiterDoesNotWait(t *testing.T) {
+	if limiter := NewEmbeddingRateLimiter(0); limiter != nil {
+		t.Fatal("Expected no limiter for an unlimited rate")
+	}
+	var limiter *EmbeddingRateLimiter
+	limiter.Wait()
+	limiter.Throttle()
+	limiter.Succeeded()
+}
@@ -4,29 +4,118 @@ import (
 	"bytes"
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// ErrEmbeddingRateLimited is returned when the embedding provider rejects a request for
+// exceeding its rate limit; callers may back off and retry
+var ErrEmbeddingRateLimited = errors.New("embedding provider rate limit exceeded")
+
+// ErrInvalidEmbedding is returned for embeddings that cannot be compared by cosine
+// similarity: empty, zero-norm, or containing NaN or Inf values
+var ErrInvalidEmbedding = errors.New("invalid embedding")
+
+// validateEmbedding rejects embeddings that would poison similarity math
+func validateEmbedding[F float32 | float64](embedding []F) error {
+	if len(embedding) == 0 {
+		return fmt.Errorf("%w: empty vector", ErrInvalidEmbedding)
+	}
+	var norm float64
+	for i, value := range embedding {
+		v := float64(value)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: non-finite value at dimension %d", ErrInvalidEmbedding, i)
+		}
+		norm += v * v
+	}
+	if norm == 0 {
+		return fmt.Errorf("%w: zero-norm vector", ErrInvalidEmbedding)
+	}
+	return nil
+}
+
+// openAIEmbeddingsURL is the OpenAI embeddings endpoint
+const openAIEmbeddingsURL = "https://api.openai.com/v1/embeddings"
+
+// Defaults for the embedding HTTP client, separate from the Forward API client because
+// OpenAI rate limits are routine during large generation runs
+const (
+	defaultOpenAITimeout      = 30 * time.Second
+	defaultOpenAIMaxRetries   = 3
+	defaultOpenAIRetryBackoff = time.Second
+)
+
+// OpenAIEmbeddingOptions configures the OpenAI embedding HTTP client
+type OpenAIEmbeddingOptions struct {
+	// Timeout bounds each HTTP request
+	Timeout time.Duration
+	// MaxRetries is how many times a rate-limited, failed, or 5xx request is retried
+	MaxRetries int
+	// RequestsPerMinute caps the request rate across all callers (0 = unlimited)
+	RequestsPerMinute int
+}
+
+// OpenAIEmbeddingOptionsFromEnv reads OPENAI_TIMEOUT (seconds), OPENAI_MAX_RETRIES, and
+// OPENAI_REQUESTS_PER_MINUTE, falling back to the defaults for unset or invalid values
+func OpenAIEmbeddingOptionsFromEnv() OpenAIEmbeddingOptions {
+	options := OpenAIEmbeddingOptions{Timeout: defaultOpenAITimeout, MaxRetries: defaultOpenAIMaxRetries}
+	if seconds, err := strconv.Atoi(os.Getenv("OPENAI_TIMEOUT")); err == nil && seconds > 0 {
+		options.Timeout = time.Duration(seconds) * time.Second
+	}
+	if retries, err := strconv.Atoi(os.Getenv("OPENAI_MAX_RETRIES")); err == nil && retries >= 0 {
+		options.MaxRetries = retries
+	}
+	if rpm, err := strconv.Atoi(os.Getenv("OPENAI_REQUESTS_PER_MINUTE")); err == nil && rpm >= 0 {
+		options.RequestsPerMinute = rpm
+	}
+	return options
+}
+
 // OpenAIEmbeddingService implements the EmbeddingService interface using OpenAI
 type OpenAIEmbeddingService struct {
 	apiKey     string
 	model      string
 	httpClient *http.Client
+	endpoint   string
+	maxRetries int
+	backoff    time.Duration
+	sleep      func(time.Duration)
+	limiter    *EmbeddingRateLimiter
 }
 
-// NewOpenAIEmbeddingService creates a new OpenAI embedding service
+// NewOpenAIEmbeddingService creates a new OpenAI embedding service configured from the environment
 func NewOpenAIEmbeddingService(apiKey string) *OpenAIEmbeddingService {
+	return NewOpenAIEmbeddingServiceWithOptions(apiKey, OpenAIEmbeddingOptionsFromEnv())
+}
+
+// NewOpenAIEmbeddingServiceWithOptions creates a new OpenAI embedding service with explicit
+// timeout and retry settings
+func NewOpenAIEmbeddingServiceWithOptions(apiKey string, options OpenAIEmbeddingOptions) *OpenAIEmbeddingService {
+	if options.Timeout <= 0 {
+		options.Timeout = defaultOpenAITimeout
+	}
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
 	return &OpenAIEmbeddingService{
 		apiKey: apiKey,
 		model:  "text-embedding-3-small",
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: options.Timeout,
 		},
+		endpoint:   openAIEmbeddingsURL,
+		maxRetries: options.MaxRetries,
+		backoff:    defaultOpenAIRetryBackoff,
+		sleep:      time.Sleep,
+		limiter:    NewEmbeddingRateLimiter(options.RequestsPerMinute),
 	}
 }
 
@@ -49,7 +138,8 @@ type openAIError struct {
 	Code    string `json:"code"`
 }
 
-// GenerateEmbedding generates an embedding for the given text using OpenAI's API
+// GenerateEmbedding generates an embedding for the given text using OpenAI's API,
+// retrying rate-limited and transient failures
 func (s *OpenAIEmbeddingService) GenerateEmbedding(text string) ([]float64, error) {
 	if text == "" {
 		return nil, fmt.Errorf("text cannot be empty")
@@ -66,10 +156,33 @@ func (s *OpenAIEmbeddingService) GenerateEmbedding(text string) ([]float64, erro
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	for attempt := 0; ; attempt++ {
+		s.limiter.Wait()
+		embedding, retryAfter, err := s.requestEmbedding(jsonData)
+		if errors.Is(err, ErrEmbeddingRateLimited) {
+			s.limiter.Throttle()
+		} else if err == nil {
+			s.limiter.Succeeded()
+		}
+		if err == nil || retryAfter < 0 || attempt >= s.maxRetries {
+			return embedding, err
+		}
+
+		wait := retryAfter
+		if wait == 0 {
+			wait = s.backoff * time.Duration(1<<attempt)
+		}
+		s.sleep(wait)
+	}
+}
+
+// requestEmbedding makes one embeddings request. For retryable failures it returns the
+// server's Retry-After delay (zero when absent); for permanent failures it returns -1.
+func (s *OpenAIEmbeddingService) requestEmbedding(jsonData []byte) ([]float64, time.Duration, error) {
 	// Create HTTP request
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/embeddings", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("POST", s.endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, -1, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -78,33 +191,57 @@ func (s *OpenAIEmbeddingService) GenerateEmbedding(text string) ([]float64, erro
 	// Make request
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, 0, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse response
+	// Parse response; error pages from a proxy may not be JSON
 	var embeddingResp openAIEmbeddingResponse
-	if err := json.Unmarshal(body, &embeddingResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
+	parseErr := json.Unmarshal(body, &embeddingResp)
 
 	// Check for API errors
+	if resp.StatusCode == http.StatusTooManyRequests || (embeddingResp.Error != nil && embeddingResp.Error.Code == "rate_limit_exceeded") {
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("%w: %s", ErrEmbeddingRateLimited, resp.Status)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("OpenAI API error: %s", resp.Status)
+	}
+	if parseErr != nil {
+		return nil, -1, fmt.Errorf("failed to parse response: %w", parseErr)
+	}
 	if embeddingResp.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s (%s)", embeddingResp.Error.Message, embeddingResp.Error.Type)
+		return nil, -1, fmt.Errorf("OpenAI API error: %s (%s)", embeddingResp.Error.Message, embeddingResp.Error.Type)
 	}
 
 	// Check response data
 	if len(embeddingResp.Data) == 0 {
-		return nil, fmt.Errorf("no embedding data returned")
+		return nil, -1, fmt.Errorf("no embedding data returned")
 	}
 
-	return embeddingResp.Data[0].Embedding, nil
+	return embeddingResp.Data[0].Embedding, 0, nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date,
+// returning zero when it is absent or unparseable
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
 }
 
 // MockEmbeddingService provides a mock implementation for testing
@@ -115,6 +252,11 @@ func NewMockEmbeddingService() *MockEmbeddingService {
 	return &MockEmbeddingService{}
 }
 
+// IsRealProvider reports false: mock embeddings are hashes, not worth generating or caching
+func (m *MockEmbeddingService) IsRealProvider() bool {
+	return false
+}
+
 // GenerateEmbedding generates a deterministic fake embedding for testing
 func (m *MockEmbeddingService) GenerateEmbedding(text string) ([]float64, error) {
 	if text == "" {
@@ -158,11 +300,20 @@ func (m *MockEmbeddingService) GenerateEmbedding(text string) ([]float64, error)
 }
 
 // KeywordEmbeddingService provides keyword-based similarity without external APIs
-type KeywordEmbeddingService struct{}
+type KeywordEmbeddingService struct {
+	vocabulary *KeywordVocabulary
+}
 
-// NewKeywordEmbeddingService creates a new keyword-based embedding service
+// NewKeywordEmbeddingService creates a new keyword-based embedding service using the
+// built-in network synonyms and stopwords
 func NewKeywordEmbeddingService() *KeywordEmbeddingService {
-	return &KeywordEmbeddingService{}
+	return &KeywordEmbeddingService{vocabulary: DefaultKeywordVocabulary()}
+}
+
+// NewKeywordEmbeddingServiceWithVocabulary creates a keyword-based embedding service
+// that normalizes tokens with the given vocabulary
+func NewKeywordEmbeddingServiceWithVocabulary(vocabulary *KeywordVocabulary) *KeywordEmbeddingService {
+	return &KeywordEmbeddingService{vocabulary: vocabulary}
 }
 
 // Common network keywords for better semantic matching
@@ -221,12 +372,12 @@ func (k *KeywordEmbeddingService) GenerateEmbedding(text string) ([]float64, err
 	// Create a 384-dimensional embedding (smaller but still effective)
 	embedding := make([]float64, 384)
 
-	// Convert to lowercase for matching
-	lowerText := strings.ToLower(text)
-	words := strings.Fields(lowerText)
+	// Normalize tokens so synonyms hash alike and stopwords don't dilute matches
+	words := k.vocabulary.Normalize(text)
+	normalizedText := strings.Join(words, " ")
 
 	// Initialize with base hash for uniqueness
-	hash := md5.Sum([]byte(text))
+	hash := md5.Sum([]byte(normalizedText))
 	for i := range embedding {
 		byteIndex := i % len(hash)
 		embedding[i] = float64(hash[byteIndex]) / 1000.0
@@ -234,10 +385,7 @@ func (k *KeywordEmbeddingService) GenerateEmbedding(text string) ([]float64, err
 
 	// Add keyword-based features
 	keywordCount := 0
-	for _, word := range words {
-		// Remove punctuation
-		cleanWord := strings.Trim(word, ".,;:!?()[]{}")
-
+	for _, cleanWord := range words {
 		if weight, exists := networkKeywords[cleanWord]; exists {
 			keywordCount++
 			// Distribute keyword influence across embedding dimensions
@@ -256,7 +404,7 @@ func (k *KeywordEmbeddingService) GenerateEmbedding(text string) ([]float64, err
 	}
 
 	// Add length-based features
-	textLength := float64(len(text))
+	textLength := float64(len(normalizedText))
 	wordCount := float64(len(words))
 
 	// Encode text statistics in specific dimensions
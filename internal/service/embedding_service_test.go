@@ -0,0 +1,85 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestOpenAIEmbeddingService points an OpenAI embedding service at a test server and
+// records backoff waits instead of sleeping
+func newTestOpenAIEmbeddingService(url string, maxRetries int, waits *[]time.Duration) *OpenAIEmbeddingService {
+	service := NewOpenAIEmbeddingServiceWithOptions("test-key", OpenAIEmbeddingOptions{Timeout: 5 * time.Second, MaxRetries: maxRetries})
+	service.endpoint = url
+	service.sleep = func(d time.Duration) { *waits = append(*waits, d) }
+	return service
+}
+
+func TestOpenAIEmbeddingRetriesAfterRateLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error": {"message": "Rate limit reached", "type": "requests", "code": "rate_limit_exceeded"}}`))
+			return
+		}
+		w.Write([]byte(`{"data": [{"embedding": [0.1, 0.2, 0.3]}]}`))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	service := newTestOpenAIEmbeddingService(server.URL, 3, &waits)
+
+	embedding, err := service.GenerateEmbedding("show bgp neighbors")
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got: %v", err)
+	}
+	if len(embedding) != 3 {
+		t.Errorf("Expected a 3-dimensional embedding, got %v", embedding)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if len(waits) != 1 || waits[0] != 7*time.Second {
+		t.Errorf("Expected to honor Retry-After of 7s, waited %v", waits)
+	}
+}
+
+func TestOpenAIEmbeddingGivesUpAfterMaxRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	service := newTestOpenAIEmbeddingService(server.URL, 2, &waits)
+
+	if _, err := service.GenerateEmbedding("show bgp neighbors"); err == nil {
+		t.Fatal("Expected an error after exhausting retries")
+	}
+	if requests != 3 {
+		t.Errorf("Expected 1 attempt plus 2 retries, got %d requests", requests)
+	}
+	if len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Errorf("Expected exponential backoff without Retry-After, waited %v", waits)
+	}
+}
+
+func TestOpenAIEmbeddingOptionsFromEnv(t *testing.T) {
+	t.Setenv("OPENAI_TIMEOUT", "90")
+	t.Setenv("OPENAI_MAX_RETRIES", "6")
+	if options := OpenAIEmbeddingOptionsFromEnv(); options.Timeout != 90*time.Second || options.MaxRetries != 6 {
+		t.Errorf("Expected 90s timeout and 6 retries, got %+v", options)
+	}
+
+	t.Setenv("OPENAI_TIMEOUT", "soon")
+	t.Setenv("OPENAI_MAX_RETRIES", "")
+	if options := OpenAIEmbeddingOptionsFromEnv(); options.Timeout != defaultOpenAITimeout || options.MaxRetries != defaultOpenAIMaxRetries {
+		t.Errorf("Expected defaults for invalid values, got %+v", options)
+	}
+}
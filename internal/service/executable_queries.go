@@ -16,6 +16,9 @@ type ExecutableQuery struct {
 	// New fields for semantic mapping
 	SemanticKeywords []string `json:"semantic_keywords"` // Additional keywords for semantic matching
 	RelatedQueries   []string `json:"related_queries"`   // Query paths that map to this executable query
+	// NeedsInput describes input the query cannot run without; ask_network never runs such
+	// a query on its own and names the tool to call instead
+	NeedsInput string `json:"needs_input,omitempty"`
 }
 
 // GetExecutableQueries returns the curated list of queries that can actually be executed
@@ -71,6 +74,7 @@ func GetExecutableQueries() []ExecutableQuery {
 			WhenToUse:        "Use for configuration auditing, compliance checking, and finding specific settings",
 			SemanticKeywords: []string{"config search", "find config", "configuration audit", "search commands", "config patterns", "setting search"},
 			RelatedQueries:   []string{"config_search", "configuration_search", "config_audit", "command_search"},
+			NeedsInput:       "a search pattern; call search_configs with search_term set",
 		},
 		{
 			QueryID:          "FQ_51f090cbea069b4049eb283716ab3bbb3f578aea",
@@ -81,6 +85,7 @@ func GetExecutableQueries() []ExecutableQuery {
 			WhenToUse:        "Use for change tracking, troubleshooting configuration drift, and impact analysis",
 			SemanticKeywords: []string{"config diff", "configuration changes", "compare configs", "config drift", "change tracking", "configuration comparison"},
 			RelatedQueries:   []string{"config_diff", "configuration_diff", "config_changes", "change_tracking"},
+			NeedsInput:       "two snapshots to compare; call get_config_diff with before_snapshot and after_snapshot",
 		},
 		{
 			QueryID:          "FQ_af8404fc747f814842b8c0cee31491614b904bd5",
@@ -215,6 +220,20 @@ func MapSemanticToExecutable(semanticResults []*QuerySearchResult) []QueryMappin
 	return mappings
 }
 
+// defaultMinMappingConfidence is the confidence below which executable mappings are not recommended
+const defaultMinMappingConfidence = 0.6
+
+// FilterMappingsByConfidence keeps mappings at or above minConfidence, preserving order
+func FilterMappingsByConfidence(mappings []QueryMappingResult, minConfidence float64) []QueryMappingResult {
+	var filtered []QueryMappingResult
+	for _, mapping := range mappings {
+		if mapping.MappingConfidence >= minConfidence {
+			filtered = append(filtered, mapping)
+		}
+	}
+	return filtered
+}
+
 // calculateMappingConfidence determines how well a semantic result maps to an executable query
 func calculateMappingConfidence(execQuery ExecutableQuery, semanticResult *QuerySearchResult) float64 {
 	confidence := 0.0
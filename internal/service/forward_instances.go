@@ -0,0 +1,278 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/forward-mcp/internal/config"
+	"github.com/forward-mcp/internal/forward"
+	"github.com/forward-mcp/internal/logger"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// defaultInstanceName names the instance configured through the FORWARD_API_* settings
+const defaultInstanceName = "default"
+
+// GenerateInstanceID derives a short, stable ID for a Forward instance from its base URL
+func GenerateInstanceID(baseURL string) string {
+	normalized := strings.TrimRight(strings.ToLower(strings.TrimSpace(baseURL)), "/")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:6])
+}
+
+// ForwardInstanceInfo describes a configured Forward instance
+type ForwardInstanceInfo struct {
+	Name       string `json:"name"`
+	BaseURL    string `json:"base_url"`
+	InstanceID string `json:"instance_id"`
+	Active     bool   `json:"active"`
+}
+
+// instanceState is everything tied to one Forward instance: its name, its client, and
+// the caches and trackers built from its data. A state is never modified after it is
+// installed; switching instances installs a new one.
+type instanceState struct {
+	// name is the instance name (empty = the default instance)
+	name            string
+	client          forward.ClientInterface
+	latestSnapshots *LatestSnapshotCache
+	listCache       *ListCache
+	nqePages        *NQEPageCache
+	queryRuntimes   *NQERuntimeTracker
+	resultSizes     *NQEResultSizeTracker
+	pathSearches    *PathSearchTracker
+	snapshotCadence *SnapshotCadenceTracker
+}
+
+// lookupKey scopes a flight group key to the instance, so concurrent lookups against
+// different instances never share a result
+func (st *instanceState) lookupKey(key string) string {
+	name := st.name
+	if name == "" {
+		name = defaultInstanceName
+	}
+	return name + ":" + key
+}
+
+// newInstanceState creates the state of an instance with fresh caches; caches disabled
+// in the configuration stay nil
+func newInstanceState(name string, client forward.ClientInterface, forwardConfig *config.ForwardConfig, logger *logger.Logger) *instanceState {
+	state := &instanceState{
+		name:            name,
+		client:          client,
+		queryRuntimes:   NewNQERuntimeTracker(),
+		resultSizes:     NewNQEResultSizeTracker(),
+		pathSearches:    NewPathSearchTracker(defaultPathSearchHistorySize),
+		snapshotCadence: NewSnapshotCadenceTracker(),
+	}
+	if forwardConfig.LatestSnapshotTTL > 0 {
+		state.latestSnapshots = NewLatestSnapshotCache(time.Duration(forwardConfig.LatestSnapshotTTL)*time.Second, logger)
+	}
+	if forwardConfig.ListCacheTTL > 0 {
+		state.listCache = NewListCache(time.Duration(forwardConfig.ListCacheTTL) * time.Second)
+	}
+	if forwardConfig.SemanticCache.Enabled && forwardConfig.NQEPageCacheTTL > 0 {
+		cacheConfig := forwardConfig.SemanticCache
+		state.nqePages = NewNQEPageCache(time.Duration(forwardConfig.NQEPageCacheTTL) * time.Second)
+		state.nqePages.SetEntryLimits(cacheConfig.MaxEntryRows, cacheConfig.MaxEntryBytes, cacheConfig.MaxTotalBytes)
+	}
+	return state
+}
+
+// newForwardClient creates a Forward client whose API requests are recorded in metrics
+func newForwardClient(forwardConfig *config.ForwardConfig, metrics *ServiceMetrics) forward.ClientInterface {
+	return forward.NewObservedClient(forwardConfig, metrics.RecordAPIRequest)
+}
+
+// instanceHolder guards the active instance state. The service and every per-call copy
+// of it share one holder, so a switch made during one tool call is seen by all later ones.
+type instanceHolder struct {
+	mu    sync.RWMutex
+	state *instanceState
+}
+
+// newInstanceHolder returns a holder with state installed
+func newInstanceHolder(state *instanceState) *instanceHolder {
+	return &instanceHolder{state: state}
+}
+
+// active returns the state of the instance tool calls go to
+func (s *ForwardMCPService) active() *instanceState {
+	if s.instance == nil {
+		return &instanceState{}
+	}
+	s.instance.mu.RLock()
+	defer s.instance.mu.RUnlock()
+	if s.instance.state == nil {
+		return &instanceState{}
+	}
+	return s.instance.state
+}
+
+// client returns the Forward client of the active instance
+func (s *ForwardMCPService) client() forward.ClientInterface {
+	return s.active().client
+}
+
+// activeInstanceName returns the name of the instance tool calls go to
+func (s *ForwardMCPService) activeInstanceName() string {
+	if name := s.active().name; name != "" {
+		return name
+	}
+	return defaultInstanceName
+}
+
+// instanceForwardConfig returns the Forward settings for a named instance: the configured
+// settings with the instance's base URL, credentials from its environment variables, and
+// default network
+func (s *ForwardMCPService) instanceForwardConfig(name string) (config.ForwardConfig, error) {
+	if s.config == nil {
+		return config.ForwardConfig{}, fmt.Errorf("no Forward configuration loaded")
+	}
+	forwardConfig := s.config.Forward
+	if name == defaultInstanceName {
+		return forwardConfig, nil
+	}
+
+	for _, instance := range s.config.Forward.Instances {
+		if instance.Name != name {
+			continue
+		}
+		forwardConfig.APIBaseURL = instance.APIBaseURL
+		forwardConfig.DefaultNetworkID = instance.DefaultNetworkID
+		forwardConfig.DefaultSnapshotID = ""
+		for _, credential := range []struct {
+			env   string
+			value *string
+		}{
+			{instance.APIKeyEnv, &forwardConfig.APIKey},
+			{instance.APISecretEnv, &forwardConfig.APISecret},
+		} {
+			if credential.env == "" {
+				return config.ForwardConfig{}, fmt.Errorf("instance %q must set apiKeyEnv and apiSecretEnv", name)
+			}
+			if *credential.value = os.Getenv(credential.env); *credential.value == "" {
+				return config.ForwardConfig{}, fmt.Errorf("environment variable %s for instance %q is not set", credential.env, name)
+			}
+		}
+		return forwardConfig, nil
+	}
+	return config.ForwardConfig{}, fmt.Errorf("unknown instance %q: use list_instances to see the configured instances", name)
+}
+
+// instanceInfos lists the default instance followed by the configured named instances
+func (s *ForwardMCPService) instanceInfos() []ForwardInstanceInfo {
+	if s.config == nil {
+		return nil
+	}
+	active := s.activeInstanceName()
+	infos := []ForwardInstanceInfo{{
+		Name:       defaultInstanceName,
+		BaseURL:    s.config.Forward.APIBaseURL,
+		InstanceID: GenerateInstanceID(s.config.Forward.APIBaseURL),
+		Active:     active == defaultInstanceName,
+	}}
+	for _, instance := range s.config.Forward.Instances {
+		infos = append(infos, ForwardInstanceInfo{
+			Name:       instance.Name,
+			BaseURL:    instance.APIBaseURL,
+			InstanceID: GenerateInstanceID(instance.APIBaseURL),
+			Active:     active == instance.Name,
+		})
+	}
+	return infos
+}
+
+// activeInstanceID returns the ID of the instance tool calls go to
+func (s *ForwardMCPService) activeInstanceID() string {
+	for _, info := range s.instanceInfos() {
+		if info.Active {
+			return info.InstanceID
+		}
+	}
+	return ""
+}
+
+// switchInstance points the service at a named instance: it installs a new client with
+// fresh per-instance caches, drops cached results, and applies the instance's default
+// network. The swap happens under the holder's lock, so concurrent calls see either the
+// old instance or the new one, never a mix.
+func (s *ForwardMCPService) switchInstance(name string) (ForwardInstanceInfo, error) {
+	forwardConfig, err := s.instanceForwardConfig(name)
+	if err != nil {
+		return ForwardInstanceInfo{}, err
+	}
+	if s.instance == nil {
+		return ForwardInstanceInfo{}, fmt.Errorf("instance switching is not available")
+	}
+
+	state := newInstanceState(name, newForwardClient(&forwardConfig, s.metrics), &forwardConfig, s.logger)
+	s.instance.mu.Lock()
+	s.instance.state = state
+	if s.semanticCache != nil {
+		s.semanticCache.Clear()
+	}
+	if s.defaults != nil {
+		s.defaults.SetScope(forwardConfig.DefaultNetworkID, forwardConfig.DefaultSnapshotID)
+	}
+	s.instance.mu.Unlock()
+	s.logger.Info("Switched to Forward instance %s (%s)", name, forwardConfig.APIBaseURL)
+
+	return ForwardInstanceInfo{
+		Name:       name,
+		BaseURL:    forwardConfig.APIBaseURL,
+		InstanceID: GenerateInstanceID(forwardConfig.APIBaseURL),
+		Active:     true,
+	}, nil
+}
+
+// listInstances shows the configured Forward instances and which one is active
+func (s *ForwardMCPService) listInstances(args ListInstancesArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("list_instances", args, nil)
+
+	infos := s.instanceInfos()
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("no Forward configuration loaded")
+	}
+	response := fmt.Sprintf("Forward instances (%d):\n", len(infos))
+	for _, info := range infos {
+		marker := " "
+		if info.Active {
+			marker = "*"
+		}
+		response += fmt.Sprintf("%s %s  %s  (instance ID %s)\n", marker, info.Name, info.BaseURL, info.InstanceID)
+	}
+	if len(infos) == 1 {
+		response += "\nAdd named instances under forward.instances in the config file to switch between them.\n"
+	} else {
+		response += "\nUse set_active_instance to switch the instance tool calls go to.\n"
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
+
+// setActiveInstance switches the Forward instance tool calls go to without a restart
+func (s *ForwardMCPService) setActiveInstance(args SetActiveInstanceArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("set_active_instance", args, nil)
+
+	if args.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	info, err := s.switchInstance(args.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	response := fmt.Sprintf("Active Forward instance: %s (%s, instance ID %s)\n", info.Name, info.BaseURL, info.InstanceID)
+	response += "Cached results, latest snapshots, and query and path search history from the previous instance were cleared.\n"
+	if networkID := s.getNetworkID(""); networkID != "" {
+		response += fmt.Sprintf("Default network: %s\n", networkID)
+	} else {
+		response += "No default network is set for this instance: use set_default_network or pass network_id.\n"
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
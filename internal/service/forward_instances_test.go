@@ -0,0 +1,183 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/forward-mcp/internal/config"
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestGenerateInstanceIDNormalizesBaseURL(t *testing.T) {
+	if GenerateInstanceID("https://fwd.app/") != GenerateInstanceID("HTTPS://fwd.app") {
+		t.Error("Expected trailing slashes and case to be ignored")
+	}
+	if GenerateInstanceID("https://fwd.app") == GenerateInstanceID("https://fwd.lab.example.com") {
+		t.Error("Expected different base URLs to get different instance IDs")
+	}
+}
+
+func TestSetActiveInstanceSwitchesClientAndInstanceID(t *testing.T) {
+	t.Setenv("FORWARD_LAB_API_KEY", "lab-key")
+	t.Setenv("FORWARD_LAB_API_SECRET", "lab-secret")
+
+	service := createTestService()
+	service.config.Forward.DefaultNetworkID = "162112"
+	service.config.Forward.Instances = []config.ForwardInstance{{
+		Name:             "lab",
+		APIBaseURL:       "https://fwd.lab.example.com",
+		APIKeyEnv:        "FORWARD_LAB_API_KEY",
+		APISecretEnv:     "FORWARD_LAB_API_SECRET",
+		DefaultNetworkID: "202",
+	}}
+	service.active().queryRuntimes = NewNQERuntimeTracker()
+	service.active().queryRuntimes.Record("FQ_configs", 0)
+	service.active().pathSearches = NewPathSearchTracker(10)
+	service.active().pathSearches.Track("162112", "", SearchPathsArgs{DstIP: "10.0.0.1"}, &forward.PathSearchResponse{})
+
+	defaultID := service.activeInstanceID()
+	if defaultID != GenerateInstanceID("https://test.example.com") {
+		t.Fatalf("Expected the default instance to be active, got ID %s", defaultID)
+	}
+
+	response, err := service.setActiveInstance(SetActiveInstanceArgs{Name: "lab"})
+	if err != nil {
+		t.Fatalf("setActiveInstance failed: %v", err)
+	}
+	if !strings.Contains(response.Content[0].TextContent.Text, "https://fwd.lab.example.com") {
+		t.Errorf("Expected the new base URL in the response, got:\n%s", response.Content[0].TextContent.Text)
+	}
+	if id := service.activeInstanceID(); id == defaultID || id != GenerateInstanceID("https://fwd.lab.example.com") {
+		t.Errorf("Expected the lab instance ID after switching, got %s", id)
+	}
+	if _, ok := service.client().(*forward.Client); !ok {
+		t.Errorf("Expected a rebuilt Forward client, got %T", service.client())
+	}
+	labConfig, _ := service.instanceForwardConfig(service.activeInstanceName())
+	if labConfig.APIBaseURL != "https://fwd.lab.example.com" || labConfig.APIKey != "lab-key" {
+		t.Errorf("Expected the lab base URL and credentials, got %s / %s", labConfig.APIBaseURL, labConfig.APIKey)
+	}
+	if service.getNetworkID("") != "202" || len(service.active().queryRuntimes.Runtimes("FQ_configs")) != 0 || len(service.active().pathSearches.Records("")) != 0 {
+		t.Errorf("Expected the lab default network and no carried-over history, got network %s", service.getNetworkID(""))
+	}
+
+	listing, err := service.listInstances(ListInstancesArgs{})
+	if err != nil {
+		t.Fatalf("listInstances failed: %v", err)
+	}
+	if !strings.Contains(listing.Content[0].TextContent.Text, "* lab  https://fwd.lab.example.com") {
+		t.Errorf("Expected lab marked active, got:\n%s", listing.Content[0].TextContent.Text)
+	}
+
+	// Switching back restores the environment-configured instance
+	if _, err := service.setActiveInstance(SetActiveInstanceArgs{Name: "default"}); err != nil {
+		t.Fatalf("setActiveInstance failed: %v", err)
+	}
+	if service.activeInstanceID() != defaultID || service.getNetworkID("") != "162112" {
+		t.Errorf("Expected the default instance and network back, got %s / %s", service.activeInstanceID(), service.getNetworkID(""))
+	}
+}
+
+func TestDefaultScopeIsSafeDuringInstanceSwitches(t *testing.T) {
+	t.Setenv("FORWARD_LAB_API_KEY", "lab-key")
+	t.Setenv("FORWARD_LAB_API_SECRET", "lab-secret")
+
+	service := createTestService()
+	service.config.Forward.DefaultNetworkID = "162112"
+	service.config.Forward.Instances = []config.ForwardInstance{{
+		Name:             "lab",
+		APIBaseURL:       "https://fwd.lab.example.com",
+		APIKeyEnv:        "FORWARD_LAB_API_KEY",
+		APISecretEnv:     "FORWARD_LAB_API_SECRET",
+		DefaultNetworkID: "202",
+	}}
+
+	// Run with -race: switches write the default scope while calls read it
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			name := "lab"
+			if i%2 == 0 {
+				name = "default"
+			}
+			if _, err := service.switchInstance(name); err != nil {
+				t.Errorf("switchInstance failed: %v", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if networkID := service.getNetworkID(""); networkID != "162112" && networkID != "202" {
+				t.Errorf("Unexpected default network %q", networkID)
+			}
+			service.getSnapshotID("")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetActiveInstanceRejectsUnknownOrUnconfigured(t *testing.T) {
+	service := createTestService()
+	service.config.Forward.Instances = []config.ForwardInstance{{
+		Name:         "lab",
+		APIBaseURL:   "https://fwd.lab.example.com",
+		APIKeyEnv:    "FORWARD_LAB_API_KEY",
+		APISecretEnv: "FORWARD_LAB_API_SECRET",
+	}}
+	t.Setenv("FORWARD_LAB_API_KEY", "")
+	client := service.client()
+
+	if _, err := service.setActiveInstance(SetActiveInstanceArgs{Name: "staging"}); err == nil || !strings.Contains(err.Error(), "unknown instance") {
+		t.Errorf("Expected an unknown instance error, got %v", err)
+	}
+	if _, err := service.setActiveInstance(SetActiveInstanceArgs{Name: "lab"}); err == nil || !strings.Contains(err.Error(), "FORWARD_LAB_API_KEY") {
+		t.Errorf("Expected a missing credential error, got %v", err)
+	}
+	if service.client() != client || service.activeInstanceName() != defaultInstanceName {
+		t.Error("Expected a failed switch to leave the active instance unchanged")
+	}
+}
+
+func TestSetActiveInstanceThroughMiddlewareAppliesToLaterCalls(t *testing.T) {
+	t.Setenv("FORWARD_LAB_API_KEY", "lab-key")
+	t.Setenv("FORWARD_LAB_API_SECRET", "lab-secret")
+	lab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/networks" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": "202", "name": "Lab Network"}]`))
+	}))
+	defer lab.Close()
+
+	service := createTestService()
+	service.config.Forward.Instances = []config.ForwardInstance{{
+		Name:             "lab",
+		APIBaseURL:       lab.URL,
+		APIKeyEnv:        "FORWARD_LAB_API_KEY",
+		APISecretEnv:     "FORWARD_LAB_API_SECRET",
+		DefaultNetworkID: "202",
+	}}
+	setActiveInstance := withToolMiddleware(service, "set_active_instance", (*ForwardMCPService).setActiveInstance)
+	listNetworks := withToolMiddleware(service, "list_networks", (*ForwardMCPService).listNetworks)
+
+	if _, err := setActiveInstance(SetActiveInstanceArgs{Name: "lab"}); err != nil {
+		t.Fatalf("set_active_instance failed: %v", err)
+	}
+	if service.activeInstanceName() != "lab" || service.activeInstanceID() != GenerateInstanceID(lab.URL) {
+		t.Errorf("Expected the switch to outlive the call, got instance %s", service.activeInstanceName())
+	}
+
+	response, err := listNetworks(ListNetworksArgs{})
+	if err != nil {
+		t.Fatalf("list_networks failed: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; !strings.Contains(text, "Lab Network") {
+		t.Errorf("Expected the follow-up call to use the lab instance's client, got:\n%s", text)
+	}
+}
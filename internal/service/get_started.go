@@ -0,0 +1,98 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// networkPlaceholder stands in for the network ID when no default network is set
+const networkPlaceholder = "<network_id>"
+
+// GuidedStep is one recommended tool call with example arguments
+type GuidedStep struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Purpose   string                 `json:"purpose"`
+}
+
+// GuidedGoal is an ordered set of tool calls that accomplish a common goal
+type GuidedGoal struct {
+	Goal  string       `json:"goal"`
+	Steps []GuidedStep `json:"steps"`
+}
+
+// guidedGoals returns the curated goals with arguments pre-filled for networkID
+func guidedGoals(networkID string) []GuidedGoal {
+	network := map[string]interface{}{"network_id": networkID}
+	with := func(extra map[string]interface{}) map[string]interface{} {
+		args := map[string]interface{}{"network_id": networkID}
+		for key, value := range extra {
+			args[key] = value
+		}
+		return args
+	}
+
+	return []GuidedGoal{
+		{Goal: "audit inventory", Steps: []GuidedStep{
+			{Tool: "get_network_summary", Arguments: network, Purpose: "Device, snapshot, and location counts at a glance"},
+			{Tool: "list_devices", Arguments: with(map[string]interface{}{"limit": 50}), Purpose: "Devices with their platform, model, and OS version"},
+			{Tool: "get_os_support", Arguments: network, Purpose: "OS versions approaching or past end of support"},
+			{Tool: "get_device_hardware", Arguments: network, Purpose: "Hardware models and serial numbers"},
+		}},
+		{Goal: "verify connectivity", Steps: []GuidedStep{
+			{Tool: "search_paths", Arguments: with(map[string]interface{}{"src_ip": "10.0.0.1", "dst_ip": "10.0.1.1", "explain": true}), Purpose: "Trace one flow and explain where it is delivered or dropped"},
+			{Tool: "search_paths_bulk", Arguments: with(map[string]interface{}{"flows": []map[string]interface{}{{"src_ip": "10.0.0.1", "dst_ip": "10.0.1.1"}, {"src_ip": "10.0.0.2", "dst_ip": "10.0.1.2"}}}), Purpose: "Check a list of required flows in one call"},
+			{Tool: "get_path_search_history", Arguments: network, Purpose: "Review the outcomes of this session's searches"},
+		}},
+		{Goal: "investigate a change", Steps: []GuidedStep{
+			{Tool: "list_snapshots", Arguments: with(map[string]interface{}{"limit": 2}), Purpose: "Find the snapshots before and after the change"},
+			{Tool: "summarize_changes", Arguments: with(map[string]interface{}{"before_snapshot": "<older_snapshot_id>", "after_snapshot": "<newer_snapshot_id>"}), Purpose: "Prioritized summary of device, interface, and config changes"},
+			{Tool: "get_config_diff", Arguments: with(map[string]interface{}{"before_snapshot": "<older_snapshot_id>", "after_snapshot": "<newer_snapshot_id>"}), Purpose: "Line-level config changes per device"},
+		}},
+	}
+}
+
+// getStarted recommends tool calls for common goals, pre-filled with the default network
+func (s *ForwardMCPService) getStarted(args GetStartedArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("get_started", args, nil)
+
+	networkID := s.getNetworkID("")
+	if networkID == "" {
+		networkID = networkPlaceholder
+	}
+
+	goals := guidedGoals(networkID)
+	if args.Goal != "" {
+		var selected []GuidedGoal
+		names := make([]string, 0, len(goals))
+		for _, goal := range goals {
+			names = append(names, goal.Goal)
+			if strings.EqualFold(goal.Goal, strings.TrimSpace(args.Goal)) {
+				selected = append(selected, goal)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("unknown goal %q (available: %s)", args.Goal, strings.Join(names, ", "))
+		}
+		goals = selected
+	}
+
+	var b strings.Builder
+	b.WriteString("🚀 Getting started with Forward Networks\n")
+	if networkID == networkPlaceholder {
+		b.WriteString("\nNo default network is set. Run list_networks, then set_default_network {\"network_identifier\": \"<network name or ID>\"} to pre-fill these examples.\n")
+	} else {
+		fmt.Fprintf(&b, "\nExamples use your default network %s.\n", networkID)
+	}
+	for _, goal := range goals {
+		fmt.Fprintf(&b, "\n## To %s\n", goal.Goal)
+		for i, step := range goal.Steps {
+			arguments, _ := json.Marshal(step.Arguments)
+			fmt.Fprintf(&b, "%d. %s %s\n   %s\n", i+1, step.Tool, arguments, step.Purpose)
+		}
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(b.String())), nil
+}
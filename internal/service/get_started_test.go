@@ -0,0 +1,51 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport/stdio"
+)
+
+func TestGetStartedReferencesRegisteredTools(t *testing.T) {
+	service := createTestService()
+	service.toolCatalog = NewToolCatalog()
+	if err := service.RegisterTools(mcp.NewServer(stdio.NewStdioServerTransport())); err != nil {
+		t.Fatalf("Failed to register tools: %v", err)
+	}
+	registered := make(map[string]bool)
+	for _, tool := range service.toolCatalog.Tools() {
+		registered[tool.Name] = true
+	}
+
+	for _, goal := range guidedGoals("162112") {
+		for _, step := range goal.Steps {
+			if !registered[step.Tool] {
+				t.Errorf("Goal %q recommends unregistered tool %s", goal.Goal, step.Tool)
+			}
+		}
+	}
+
+	response, err := service.getStarted(GetStartedArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "default network 162112") || !strings.Contains(text, `get_network_summary {"network_id":"162112"}`) {
+		t.Errorf("Expected examples pre-filled with the default network, got:\n%s", text)
+	}
+	for _, goal := range []string{"audit inventory", "verify connectivity", "investigate a change"} {
+		if !strings.Contains(text, "## To "+goal) {
+			t.Errorf("Expected goal %q, got:\n%s", goal, text)
+		}
+	}
+
+	response, err = service.getStarted(GetStartedArgs{Goal: "Verify Connectivity"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; strings.Contains(text, "audit inventory") || !strings.Contains(text, "search_paths_bulk") {
+		t.Errorf("Expected only the connectivity goal, got:\n%s", text)
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// IndexBuildState is the lifecycle state of a background index build
+type IndexBuildState string
+
+const (
+	IndexBuildIdle    IndexBuildState = "idle"
+	IndexBuildRunning IndexBuildState = "running"
+	IndexBuildDone    IndexBuildState = "done"
+	IndexBuildFailed  IndexBuildState = "failed"
+)
+
+// ErrIndexBuildInProgress is returned when a build is requested while another is running
+var ErrIndexBuildInProgress = errors.New("an index build is already in progress")
+
+// IndexBuildStatus reports the progress of the most recent index build
+type IndexBuildStatus struct {
+	Token      string          `json:"token,omitempty"`
+	State      IndexBuildState `json:"state"`
+	Stage      string          `json:"stage,omitempty"`
+	Processed  int             `json:"processed"`
+	Total      int             `json:"total"`
+	StartedAt  time.Time       `json:"started_at,omitempty"`
+	FinishedAt time.Time       `json:"finished_at,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// IndexBuildProgressFunc reports the current stage and item progress of a build
+type IndexBuildProgressFunc func(stage string, processed, total int)
+
+// IndexBuilder runs query index rebuilds in the background, one at a time, and
+// tracks the progress of the most recent build
+type IndexBuilder struct {
+	mutex    sync.RWMutex
+	status   IndexBuildStatus
+	sequence int
+}
+
+// NewIndexBuilder creates an idle index builder
+func NewIndexBuilder() *IndexBuilder {
+	return &IndexBuilder{status: IndexBuildStatus{State: IndexBuildIdle}}
+}
+
+// Start launches build in a goroutine and returns a token identifying it. It returns
+// ErrIndexBuildInProgress instead of starting a second concurrent build.
+func (b *IndexBuilder) Start(build func(progress IndexBuildProgressFunc) error) (string, error) {
+	b.mutex.Lock()
+	if b.status.State == IndexBuildRunning {
+		token := b.status.Token
+		b.mutex.Unlock()
+		return token, ErrIndexBuildInProgress
+	}
+	b.sequence++
+	token := fmt.Sprintf("index-build-%d-%d", time.Now().Unix(), b.sequence)
+	b.status = IndexBuildStatus{
+		Token:     token,
+		State:     IndexBuildRunning,
+		Stage:     "starting",
+		StartedAt: time.Now(),
+	}
+	b.mutex.Unlock()
+
+	go func() {
+		err := build(func(stage string, processed, total int) {
+			b.mutex.Lock()
+			defer b.mutex.Unlock()
+			b.status.Stage = stage
+			b.status.Processed = processed
+			b.status.Total = total
+		})
+
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		b.status.FinishedAt = time.Now()
+		if err != nil {
+			b.status.State = IndexBuildFailed
+			b.status.Error = err.Error()
+			return
+		}
+		b.status.State = IndexBuildDone
+		b.status.Stage = "complete"
+	}()
+
+	return token, nil
+}
+
+// Status returns a snapshot of the most recent build's progress
+func (b *IndexBuilder) Status() IndexBuildStatus {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.status
+}
+
+// Running reports whether a build is currently in progress
+func (b *IndexBuilder) Running() bool {
+	return b.Status().State == IndexBuildRunning
+}
+
+// startBackgroundIndexBuild reloads the index from spec and optionally generates
+// embeddings in the background, returning a token to poll with get_index_build_status
+func (s *ForwardMCPService) startBackgroundIndexBuild(args InitializeQueryIndexArgs) (*mcp.ToolResponse, error) {
+	if args.GenerateEmbeddings {
+		if !isRealEmbeddingProvider(s.queryIndex.embeddingService) {
+			return s.failureResponse("Cannot generate embeddings: OpenAI API key not configured\nSet OPENAI_API_KEY environment variable to enable embedding generation"), nil
+		}
+	}
+
+	token, err := s.indexBuilds.Start(func(progress IndexBuildProgressFunc) error {
+		progress("loading_spec", 0, 0)
+		if err := s.queryIndex.LoadFromSpec(); err != nil {
+			return fmt.Errorf("failed to load query index: %w", err)
+		}
+		if !args.GenerateEmbeddings {
+			return nil
+		}
+		return s.queryIndex.GenerateEmbeddingsWithProgress(func(processed, total int) {
+			progress("generating_embeddings", processed, total)
+		})
+	})
+	if errors.Is(err, ErrIndexBuildInProgress) {
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("⏳ Index build %s is already running. Poll `get_index_build_status` and retry when it finishes.", token))), nil
+	}
+
+	s.logger.Info("Started background query index build %s (embeddings: %v)", token, args.GenerateEmbeddings)
+	response := "🔧 Query index build started in the background.\n\n"
+	response += fmt.Sprintf("**Build token:** `%s`\n", token)
+	response += "Poll progress with:\n"
+	response += fmt.Sprintf("```json\n{\"tool\": \"get_index_build_status\", \"arguments\": {\"token\": \"%s\"}}\n```\n", token)
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
+
+// getIndexBuildStatus reports the progress of the most recent background index build
+func (s *ForwardMCPService) getIndexBuildStatus(args GetIndexBuildStatusArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("get_index_build_status", args, nil)
+
+	if s.indexBuilds == nil {
+		return nil, fmt.Errorf("background index builds are not available")
+	}
+
+	status := s.indexBuilds.Status()
+	if status.State == IndexBuildIdle {
+		return mcp.NewToolResponse(mcp.NewTextContent("No index build has been started. Run `initialize_query_index` with `background: true`.")), nil
+	}
+
+	response := ""
+	if args.Token != "" && args.Token != status.Token {
+		response += fmt.Sprintf("⚠️  Token %s is not the most recent build; showing %s instead.\n\n", args.Token, status.Token)
+	}
+
+	response += fmt.Sprintf("📊 **Index Build %s**\n", status.Token)
+	response += fmt.Sprintf("• State: %s\n", status.State)
+	response += fmt.Sprintf("• Stage: %s\n", status.Stage)
+	if status.Total > 0 {
+		response += fmt.Sprintf("• Progress: %d/%d (%.1f%%)\n", status.Processed, status.Total, float64(status.Processed)/float64(status.Total)*100)
+	}
+	response += fmt.Sprintf("• Started: %s\n", status.StartedAt.Format(time.RFC3339))
+	if !status.FinishedAt.IsZero() {
+		response += fmt.Sprintf("• Finished: %s (%s)\n", status.FinishedAt.Format(time.RFC3339), status.FinishedAt.Sub(status.StartedAt).Round(time.Millisecond))
+	}
+	if status.Error != "" {
+		response += fmt.Sprintf("• Error: %s\n", status.Error)
+	}
+	if status.State == IndexBuildDone {
+		response += fmt.Sprintf("\nQuery index ready with %d queries.\n", len(s.queryIndex.Queries()))
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
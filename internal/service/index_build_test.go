@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitForBuild polls the builder until the current build leaves the running state
+func waitForBuild(t *testing.T, builder *IndexBuilder) IndexBuildStatus {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if status := builder.Status(); status.State != IndexBuildRunning {
+			return status
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for index build to finish")
+	return IndexBuildStatus{}
+}
+
+func TestIndexBuilder_RunningThenDone(t *testing.T) {
+	builder := NewIndexBuilder()
+	release := make(chan struct{})
+
+	token, err := builder.Start(func(progress IndexBuildProgressFunc) error {
+		progress("generating_embeddings", 1, 2)
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected build to start, got: %v", err)
+	}
+
+	if status := builder.Status(); status.State != IndexBuildRunning || status.Token != token {
+		t.Errorf("Expected running build %s, got %+v", token, status)
+	}
+
+	runningToken, err := builder.Start(func(progress IndexBuildProgressFunc) error { return nil })
+	if !errors.Is(err, ErrIndexBuildInProgress) {
+		t.Errorf("Expected concurrent build to be rejected, got: %v", err)
+	}
+	if runningToken != token {
+		t.Errorf("Expected rejection to report running token %s, got %s", token, runningToken)
+	}
+
+	close(release)
+	status := waitForBuild(t, builder)
+	if status.State != IndexBuildDone || status.Processed != 1 || status.Total != 2 {
+		t.Errorf("Expected done build with recorded progress, got %+v", status)
+	}
+
+	if _, err := builder.Start(func(progress IndexBuildProgressFunc) error { return errors.New("spec missing") }); err != nil {
+		t.Fatalf("Expected a new build after the previous finished, got: %v", err)
+	}
+	if status := waitForBuild(t, builder); status.State != IndexBuildFailed || status.Error != "spec missing" {
+		t.Errorf("Expected failed build with error, got %+v", status)
+	}
+}
+
+func TestInitializeQueryIndex_Background(t *testing.T) {
+	service := setupSmartSearchTestService()
+	service.indexBuilds = NewIndexBuilder()
+
+	// A build already in flight blocks both background and synchronous rebuilds
+	release := make(chan struct{})
+	if _, err := service.indexBuilds.Start(func(progress IndexBuildProgressFunc) error {
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Expected build to start, got: %v", err)
+	}
+	for _, background := range []bool{true, false} {
+		response, err := service.initializeQueryIndex(InitializeQueryIndexArgs{Background: background})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if text := response.Content[0].TextContent.Text; !contains(text, "already running") {
+			t.Errorf("Expected concurrent rebuild (background=%v) to be rejected, got: %s", background, text)
+		}
+	}
+	close(release)
+	waitForBuild(t, service.indexBuilds)
+
+	response, err := service.initializeQueryIndex(InitializeQueryIndexArgs{Background: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; !contains(text, "Build token") {
+		t.Errorf("Expected build token in response, got: %s", text)
+	}
+
+	if status := waitForBuild(t, service.indexBuilds); status.State != IndexBuildDone {
+		t.Fatalf("Expected background build to finish, got %+v", status)
+	}
+	response, err = service.getIndexBuildStatus(GetIndexBuildStatusArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; !contains(text, "State: done") || !contains(text, "Query index ready") {
+		t.Errorf("Expected completed build status, got: %s", text)
+	}
+}
@@ -74,7 +74,7 @@ func TestIntegrationSearchPaths(t *testing.T) {
 	service := setupIntegrationTest(t)
 
 	// First get available networks
-	networks, err := service.forwardClient.GetNetworks()
+	networks, err := service.client().GetNetworks()
 	if err != nil {
 		t.Fatalf("Failed to get networks: %v", err)
 	}
@@ -113,7 +113,7 @@ func TestIntegrationSearchPathsSpecificIPs(t *testing.T) {
 	service := setupIntegrationTest(t)
 
 	// First get available networks
-	networks, err := service.forwardClient.GetNetworks()
+	networks, err := service.client().GetNetworks()
 	if err != nil {
 		t.Fatalf("Failed to get networks: %v", err)
 	}
@@ -264,7 +264,7 @@ func TestIntegrationSearchPathsSpecificIPs(t *testing.T) {
 func TestIntegrationPathSearchResponseStructure(t *testing.T) {
 	service := setupIntegrationTest(t)
 
-	networks, err := service.forwardClient.GetNetworks()
+	networks, err := service.client().GetNetworks()
 	if err != nil {
 		t.Fatalf("Failed to get networks: %v", err)
 	}
@@ -329,7 +329,7 @@ func TestIntegrationRunNQEQuery(t *testing.T) {
 	service := setupIntegrationTest(t)
 
 	// First get available networks
-	networks, err := service.forwardClient.GetNetworks()
+	networks, err := service.client().GetNetworks()
 	if err != nil {
 		t.Fatalf("Failed to get networks: %v", err)
 	}
@@ -370,7 +370,7 @@ func TestIntegrationPathSearchSpecificCustomerIPs(t *testing.T) {
 	service := setupIntegrationTest(t)
 
 	// First get available networks
-	networks, err := service.forwardClient.GetNetworks()
+	networks, err := service.client().GetNetworks()
 	if err != nil {
 		t.Fatalf("Failed to get networks: %v", err)
 	}
@@ -569,7 +569,7 @@ func TestIntegrationListDevices(t *testing.T) {
 	service := setupIntegrationTest(t)
 
 	// First get available networks
-	networks, err := service.forwardClient.GetNetworks()
+	networks, err := service.client().GetNetworks()
 	if err != nil {
 		t.Fatalf("Failed to get networks: %v", err)
 	}
@@ -604,7 +604,7 @@ func TestIntegrationListSnapshots(t *testing.T) {
 	service := setupIntegrationTest(t)
 
 	// First get available networks
-	networks, err := service.forwardClient.GetNetworks()
+	networks, err := service.client().GetNetworks()
 	if err != nil {
 		t.Fatalf("Failed to get networks: %v", err)
 	}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSONMode controls how tool output JSON is rendered
+type JSONMode string
+
+const (
+	// JSONModeFormatted indents JSON for readability (the default)
+	JSONModeFormatted JSONMode = "formatted"
+	// JSONModeCompact omits all insignificant whitespace to save tokens
+	JSONModeCompact JSONMode = "compact"
+	// JSONModeAuto indents small payloads and compacts large ones
+	JSONModeAuto JSONMode = "auto"
+)
+
+// autoCompactThreshold is the compact size in bytes above which auto mode stops indenting
+const autoCompactThreshold = 2048
+
+// ParseJSONMode validates a JSON mode name; an empty name selects JSONModeFormatted
+func ParseJSONMode(name string) (JSONMode, error) {
+	switch mode := JSONMode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case "":
+		return JSONModeFormatted, nil
+	case JSONModeFormatted, JSONModeCompact, JSONModeAuto:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid JSON format %q: must be %q, %q, or %q", name, JSONModeFormatted, JSONModeCompact, JSONModeAuto)
+	}
+}
+
+// MarshalJSON renders v in the given mode. Auto mode indents only when the compact form
+// is at most autoCompactThreshold bytes, where readability costs few tokens.
+func MarshalJSON(v interface{}, mode JSONMode) ([]byte, error) {
+	switch mode {
+	case JSONModeCompact:
+		return json.Marshal(v)
+	case JSONModeAuto:
+		data, err := json.Marshal(v)
+		if err != nil || len(data) > autoCompactThreshold {
+			return data, err
+		}
+		return json.MarshalIndent(v, "", "  ")
+	default:
+		return json.MarshalIndent(v, "", "  ")
+	}
+}
+
+// jsonMode returns the mode for a tool call: pretty overrides the configured default
+func (s *ForwardMCPService) jsonMode(pretty *bool) JSONMode {
+	if pretty != nil {
+		if *pretty {
+			return JSONModeFormatted
+		}
+		return JSONModeCompact
+	}
+	if s.config != nil {
+		if mode, err := ParseJSONMode(s.config.MCP.JSONFormat); err == nil {
+			return mode
+		}
+	}
+	return JSONModeFormatted
+}
+
+// toJSON renders tool output in the call's JSON mode
+func (s *ForwardMCPService) toJSON(v interface{}, pretty *bool) string {
+	data, _ := MarshalJSON(v, s.jsonMode(pretty))
+	return string(data)
+}
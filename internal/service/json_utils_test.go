@@ -0,0 +1,82 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// listNetworksJSON runs list_networks and returns the JSON payload after the header line
+func listNetworksJSON(t *testing.T, service *ForwardMCPService, args ListNetworksArgs) string {
+	t.Helper()
+	response, err := service.listNetworks(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	_, payload, found := strings.Cut(response.Content[0].TextContent.Text, ":\n")
+	if !found {
+		t.Fatalf("Expected header followed by JSON, got: %s", response.Content[0].TextContent.Text)
+	}
+	return payload
+}
+
+func TestListNetworksCompactJSONIsSmallerWithSameData(t *testing.T) {
+	service := createTestService()
+
+	service.config.MCP.JSONFormat = string(JSONModeFormatted)
+	formatted := listNetworksJSON(t, service, ListNetworksArgs{})
+
+	service.config.MCP.JSONFormat = string(JSONModeCompact)
+	compact := listNetworksJSON(t, service, ListNetworksArgs{})
+
+	if len(compact) >= len(formatted) {
+		t.Errorf("Expected compact output (%d bytes) to be smaller than formatted (%d bytes)", len(compact), len(formatted))
+	}
+	if strings.Contains(compact, "\n") {
+		t.Errorf("Expected compact output on one line, got:\n%s", compact)
+	}
+
+	var formattedData, compactData interface{}
+	if err := json.Unmarshal([]byte(formatted), &formattedData); err != nil {
+		t.Fatalf("Formatted output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(compact), &compactData); err != nil {
+		t.Fatalf("Compact output is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(formattedData, compactData) {
+		t.Error("Expected compact and formatted output to contain the same data")
+	}
+}
+
+func TestPrettyArgOverridesDefaultJSONFormat(t *testing.T) {
+	service := createTestService()
+	service.config.MCP.JSONFormat = string(JSONModeCompact)
+
+	pretty := true
+	if payload := listNetworksJSON(t, service, ListNetworksArgs{Pretty: &pretty}); !strings.Contains(payload, "\n  ") {
+		t.Errorf("Expected pretty: true to indent output, got:\n%s", payload)
+	}
+
+	service.config.MCP.JSONFormat = string(JSONModeFormatted)
+	pretty = false
+	if payload := listNetworksJSON(t, service, ListNetworksArgs{Pretty: &pretty}); strings.Contains(payload, "\n") {
+		t.Errorf("Expected pretty: false to compact output, got:\n%s", payload)
+	}
+}
+
+func TestMarshalJSONAutoMode(t *testing.T) {
+	small, _ := MarshalJSON(map[string]string{"name": "router-1"}, JSONModeAuto)
+	if !strings.Contains(string(small), "\n") {
+		t.Errorf("Expected auto mode to indent small payloads, got %s", small)
+	}
+
+	large, _ := MarshalJSON(map[string]string{"config": strings.Repeat("x", autoCompactThreshold)}, JSONModeAuto)
+	if strings.Contains(string(large), "\n") {
+		t.Error("Expected auto mode to compact large payloads")
+	}
+
+	if _, err := ParseJSONMode("yaml"); err == nil {
+		t.Error("Expected error for unknown JSON format")
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/forward-mcp/internal/logger"
+)
+
+// KeywordVocabulary normalizes tokens for the keyword embedding service: synonyms are
+// mapped to a canonical term and stopwords are dropped, so equivalent phrasings hash
+// to the same features
+type KeywordVocabulary struct {
+	synonyms     map[string]string
+	stopwords    map[string]bool
+	maxPhraseLen int
+}
+
+// keywordVocabularyFile is the JSON layout accepted by LoadKeywordVocabulary
+type keywordVocabularyFile struct {
+	Synonyms        map[string]string `json:"synonyms"`
+	Stopwords       []string          `json:"stopwords"`
+	ReplaceDefaults bool              `json:"replace_defaults"`
+}
+
+// defaultKeywordSynonyms unify common network jargon; keys may be multi-word phrases
+var defaultKeywordSynonyms = map[string]string{
+	"access-list": "acl", "access-lists": "acls", "accesslist": "acl", "accesslists": "acls",
+	"access list": "acl", "access lists": "acls", "access control list": "acl", "access control lists": "acls",
+	"iface": "interface", "ifaces": "interfaces", "intf": "interface", "intfs": "interfaces",
+	"neighbour": "neighbor", "neighbours": "neighbors", "nbr": "neighbor", "nbrs": "neighbors",
+	"rtr": "router", "rtrs": "routers",
+	"cfg": "config", "conf": "config", "configs": "config",
+	"hw": "hardware", "mem": "memory", "processor": "cpu",
+	"addr": "address", "addrs": "addresses",
+	"ver": "version", "sw version": "version", "software version": "version",
+	"vulnerability": "cve", "vulnerabilities": "cves", "vuln": "cve", "vulns": "cves",
+	"vlan id": "vlan",
+}
+
+// defaultKeywordStopwords carry no meaning for query matching
+var defaultKeywordStopwords = []string{
+	"a", "an", "the", "and", "or", "of", "for", "to", "on", "at", "by", "with",
+	"is", "are", "be", "this", "that", "these", "those", "it", "its",
+	"i", "me", "my", "we", "our", "you", "please", "can", "what", "which", "how", "do", "does",
+}
+
+// NewKeywordVocabulary builds a vocabulary from synonyms and stopwords. Terms are
+// matched case-insensitively.
+func NewKeywordVocabulary(synonyms map[string]string, stopwords []string) *KeywordVocabulary {
+	v := &KeywordVocabulary{
+		synonyms:     make(map[string]string, len(synonyms)),
+		stopwords:    make(map[string]bool, len(stopwords)),
+		maxPhraseLen: 1,
+	}
+	for term, canonical := range synonyms {
+		key := strings.Join(strings.Fields(strings.ToLower(term)), " ")
+		if key == "" {
+			continue
+		}
+		v.synonyms[key] = strings.ToLower(strings.TrimSpace(canonical))
+		if words := len(strings.Fields(key)); words > v.maxPhraseLen {
+			v.maxPhraseLen = words
+		}
+	}
+	for _, word := range stopwords {
+		v.stopwords[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+	return v
+}
+
+// DefaultKeywordVocabulary returns the built-in network synonyms and stopwords
+func DefaultKeywordVocabulary() *KeywordVocabulary {
+	return NewKeywordVocabulary(defaultKeywordSynonyms, defaultKeywordStopwords)
+}
+
+// LoadKeywordVocabulary reads a JSON file of the form
+// {"synonyms": {"access-list": "acl"}, "stopwords": ["the"], "replace_defaults": false}.
+// Entries are merged onto the built-in defaults unless replace_defaults is true.
+func LoadKeywordVocabulary(path string) (*KeywordVocabulary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyword vocabulary: %w", err)
+	}
+
+	var file keywordVocabularyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keyword vocabulary %s: %w", path, err)
+	}
+
+	if file.ReplaceDefaults {
+		return NewKeywordVocabulary(file.Synonyms, file.Stopwords), nil
+	}
+
+	synonyms := make(map[string]string, len(defaultKeywordSynonyms)+len(file.Synonyms))
+	for term, canonical := range defaultKeywordSynonyms {
+		synonyms[term] = canonical
+	}
+	for term, canonical := range file.Synonyms {
+		synonyms[term] = canonical
+	}
+	stopwords := append(append([]string{}, defaultKeywordStopwords...), file.Stopwords...)
+	return NewKeywordVocabulary(synonyms, stopwords), nil
+}
+
+// Normalize lowercases text, strips punctuation, replaces synonyms (longest phrase
+// first), and drops stopwords. A nil vocabulary only lowercases and strips punctuation.
+func (v *KeywordVocabulary) Normalize(text string) []string {
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		if word = strings.Trim(word, ".,;:!?()[]{}\"'"); word != "" {
+			words = append(words, word)
+		}
+	}
+	if v == nil {
+		return words
+	}
+
+	tokens := make([]string, 0, len(words))
+	for i := 0; i < len(words); {
+		matched := false
+		for n := min(v.maxPhraseLen, len(words)-i); n > 0; n-- {
+			if canonical, ok := v.synonyms[strings.Join(words[i:i+n], " ")]; ok {
+				tokens = append(tokens, canonical)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if !v.stopwords[words[i]] {
+			tokens = append(tokens, words[i])
+		}
+		i++
+	}
+	return tokens
+}
+
+// newKeywordEmbeddingServiceFromConfig creates a keyword embedding service using the
+// vocabulary file at path, falling back to the built-in vocabulary if it cannot be loaded
+func newKeywordEmbeddingServiceFromConfig(path string, logger *logger.Logger) *KeywordEmbeddingService {
+	if path == "" {
+		return NewKeywordEmbeddingService()
+	}
+	vocabulary, err := LoadKeywordVocabulary(path)
+	if err != nil {
+		logger.Warn("Using built-in keyword vocabulary: %v", err)
+		return NewKeywordEmbeddingService()
+	}
+	return NewKeywordEmbeddingServiceWithVocabulary(vocabulary)
+}
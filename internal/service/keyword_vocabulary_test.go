@@ -0,0 +1,76 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestKeywordVocabularyNormalize(t *testing.T) {
+	vocabulary := DefaultKeywordVocabulary()
+
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{"Show the ACL rules", []string{"show", "acl", "rules"}},
+		{"show access-list rules", []string{"show", "acl", "rules"}},
+		{"Show Access Control Lists for my routers", []string{"show", "acls", "routers"}},
+		{"iface status (down)", []string{"interface", "status", "down"}},
+	}
+
+	for _, tc := range testCases {
+		if got := vocabulary.Normalize(tc.input); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Normalize(%q) = %v, expected %v", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestKeywordEmbeddingSynonymsMatchCanonicalTerm(t *testing.T) {
+	service := NewKeywordEmbeddingService()
+	cache := NewSemanticCache(service, createTestLogger())
+
+	canonical, _ := service.GenerateEmbedding("show ACL rules")
+	synonym, _ := service.GenerateEmbedding("show the access-list rules")
+	unrelated, _ := service.GenerateEmbedding("show bgp neighbors")
+
+	if similarity := cache.cosineSimilarity(canonical, synonym); similarity < 0.999 {
+		t.Errorf("Expected synonym phrasing to match the canonical term, similarity %.4f", similarity)
+	}
+	if cache.cosineSimilarity(canonical, unrelated) >= cache.cosineSimilarity(canonical, synonym) {
+		t.Error("Expected synonym phrasing to be closer than an unrelated query")
+	}
+}
+
+func TestLoadKeywordVocabulary(t *testing.T) {
+	dir := t.TempDir()
+
+	merged := filepath.Join(dir, "merged.json")
+	if err := os.WriteFile(merged, []byte(`{"synonyms": {"pfx": "prefix"}, "stopwords": ["kindly"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	vocabulary, err := LoadKeywordVocabulary(merged)
+	if err != nil {
+		t.Fatalf("Failed to load vocabulary: %v", err)
+	}
+	if got := vocabulary.Normalize("kindly list pfx on the access-list"); !reflect.DeepEqual(got, []string{"list", "prefix", "acl"}) {
+		t.Errorf("Expected custom entries merged onto defaults, got %v", got)
+	}
+
+	replaced := filepath.Join(dir, "replaced.json")
+	if err := os.WriteFile(replaced, []byte(`{"synonyms": {"pfx": "prefix"}, "replace_defaults": true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	vocabulary, err = LoadKeywordVocabulary(replaced)
+	if err != nil {
+		t.Fatalf("Failed to load vocabulary: %v", err)
+	}
+	if got := vocabulary.Normalize("the pfx access-list"); !reflect.DeepEqual(got, []string{"the", "prefix", "access-list"}) {
+		t.Errorf("Expected only custom entries when replacing defaults, got %v", got)
+	}
+
+	if _, err := LoadKeywordVocabulary(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected error for missing vocabulary file")
+	}
+}
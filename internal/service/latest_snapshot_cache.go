@@ -0,0 +1,142 @@
+package service
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+	"github.com/forward-mcp/internal/logger"
+)
+
+// defaultLatestSnapshotTTL is how long a resolved latest snapshot is reused
+const defaultLatestSnapshotTTL = 60 * time.Second
+
+// latestSnapshotRefreshLead is the final fraction of an entry's lifetime during which a
+// read triggers a background refresh, so hot networks rarely wait on the API
+const latestSnapshotRefreshLead = 0.25
+
+// latestSnapshotEntry is one network's cached latest snapshot
+type latestSnapshotEntry struct {
+	snapshot     *forward.Snapshot
+	refreshAfter time.Time
+	expiresAt    time.Time
+	refreshing   bool
+}
+
+// LatestSnapshotCache remembers each network's latest snapshot for a short TTL. Reads
+// near the end of an entry's lifetime refresh it in the background; the refresh point
+// is jittered so networks cached together do not all refresh at once. Errors are never
+// cached. A nil cache fetches on every call.
+type LatestSnapshotCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]*latestSnapshotEntry
+	logger  *logger.Logger
+	now     func() time.Time
+	jitter  func(max time.Duration) time.Duration
+	pending sync.WaitGroup // in-flight background refreshes
+}
+
+// NewLatestSnapshotCache creates a cache whose entries live for ttl
+func NewLatestSnapshotCache(ttl time.Duration, logger *logger.Logger) *LatestSnapshotCache {
+	return &LatestSnapshotCache{
+		ttl:     ttl,
+		entries: make(map[string]*latestSnapshotEntry),
+		logger:  logger,
+		now:     time.Now,
+		jitter: func(max time.Duration) time.Duration {
+			if max <= 0 {
+				return 0
+			}
+			return time.Duration(rand.Int63n(int64(max)))
+		},
+	}
+}
+
+// Get returns the network's latest snapshot, calling fetch only when no fresh entry exists
+func (c *LatestSnapshotCache) Get(networkID string, fetch func(string) (*forward.Snapshot, error)) (*forward.Snapshot, error) {
+	if c == nil {
+		return fetch(networkID)
+	}
+
+	c.mutex.Lock()
+	now := c.now()
+	if entry, ok := c.entries[networkID]; ok && now.Before(entry.expiresAt) {
+		if !entry.refreshing && !now.Before(entry.refreshAfter) {
+			entry.refreshing = true
+			c.pending.Add(1)
+			go c.refresh(networkID, fetch)
+		}
+		snapshot := entry.snapshot
+		c.mutex.Unlock()
+		return snapshot, nil
+	}
+	c.mutex.Unlock()
+
+	snapshot, err := fetch(networkID)
+	if err != nil {
+		return nil, err
+	}
+	c.Store(networkID, snapshot)
+	return snapshot, nil
+}
+
+// Store caches a freshly fetched latest snapshot
+func (c *LatestSnapshotCache) Store(networkID string, snapshot *forward.Snapshot) {
+	if c == nil || snapshot == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	lead := time.Duration(float64(c.ttl) * latestSnapshotRefreshLead)
+	c.entries[networkID] = &latestSnapshotEntry{
+		snapshot:     snapshot,
+		refreshAfter: now.Add(c.ttl - lead + c.jitter(lead/2)),
+		expiresAt:    now.Add(c.ttl),
+	}
+}
+
+// refresh re-fetches a network's latest snapshot in the background, keeping the current
+// entry if the fetch fails
+func (c *LatestSnapshotCache) refresh(networkID string, fetch func(string) (*forward.Snapshot, error)) {
+	defer c.pending.Done()
+
+	snapshot, err := fetch(networkID)
+	if err == nil && snapshot != nil {
+		c.Store(networkID, snapshot)
+		return
+	}
+
+	c.mutex.Lock()
+	if entry, ok := c.entries[networkID]; ok {
+		entry.refreshing = false
+	}
+	c.mutex.Unlock()
+	if c.logger != nil {
+		c.logger.Debug("Background refresh of latest snapshot for network %s failed: %v", networkID, err)
+	}
+}
+
+// latestSnapshotLookupKey is the flight group key of a network's latest-snapshot lookup
+func latestSnapshotLookupKey(networkID string) string {
+	return "latest-snapshot/" + networkID
+}
+
+// latestSnapshot resolves a network's latest snapshot through the cache, sharing one API
+// call between concurrent lookups for the same network
+func (s *ForwardMCPService) latestSnapshot(networkID string) (*forward.Snapshot, error) {
+	instance := s.active()
+	return instance.latestSnapshots.Get(networkID, func(id string) (*forward.Snapshot, error) {
+		value, err, _ := s.lookups.Do(instance.lookupKey(latestSnapshotLookupKey(id)), func() (interface{}, error) {
+			return instance.client.GetLatestSnapshot(id)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return value.(*forward.Snapshot), nil
+	})
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// countingSnapshotFetch returns a fetch func that serves snapshot-<n> on the nth call
+func countingSnapshotFetch() (func(string) (*forward.Snapshot, error), func() int) {
+	var mutex sync.Mutex
+	calls := 0
+	fetch := func(networkID string) (*forward.Snapshot, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls++
+		return &forward.Snapshot{ID: fmt.Sprintf("snapshot-%d", calls), NetworkID: networkID}, nil
+	}
+	count := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return calls
+	}
+	return fetch, count
+}
+
+func newTestLatestSnapshotCache(clock *time.Time) *LatestSnapshotCache {
+	cache := NewLatestSnapshotCache(60*time.Second, createTestLogger())
+	cache.now = func() time.Time { return *clock }
+	cache.jitter = func(time.Duration) time.Duration { return 0 }
+	return cache
+}
+
+func TestLatestSnapshotCacheServesWithinWindow(t *testing.T) {
+	clock := time.Now()
+	cache := newTestLatestSnapshotCache(&clock)
+	fetch, calls := countingSnapshotFetch()
+
+	for i := 0; i < 3; i++ {
+		snapshot, err := cache.Get("162112", fetch)
+		if err != nil || snapshot.ID != "snapshot-1" {
+			t.Fatalf("Expected cached snapshot-1, got %v (err %v)", snapshot, err)
+		}
+		clock = clock.Add(10 * time.Second)
+	}
+	if calls() != 1 {
+		t.Errorf("Expected one API call within the window, got %d", calls())
+	}
+
+	clock = clock.Add(61 * time.Second)
+	snapshot, err := cache.Get("162112", fetch)
+	if err != nil || snapshot.ID != "snapshot-2" {
+		t.Fatalf("Expected expiry to refresh to snapshot-2, got %v (err %v)", snapshot, err)
+	}
+	if calls() != 2 {
+		t.Errorf("Expected a second API call after expiry, got %d", calls())
+	}
+}
+
+func TestLatestSnapshotCacheRefreshesInBackground(t *testing.T) {
+	clock := time.Now()
+	cache := newTestLatestSnapshotCache(&clock)
+	fetch, calls := countingSnapshotFetch()
+
+	if _, err := cache.Get("162112", fetch); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Near the end of the window the cached value is served while a refresh runs
+	clock = clock.Add(50 * time.Second)
+	snapshot, _ := cache.Get("162112", fetch)
+	if snapshot.ID != "snapshot-1" {
+		t.Errorf("Expected the cached snapshot while refreshing, got %s", snapshot.ID)
+	}
+	cache.pending.Wait()
+
+	snapshot, _ = cache.Get("162112", fetch)
+	if snapshot.ID != "snapshot-2" || calls() != 2 {
+		t.Errorf("Expected the background refresh to serve snapshot-2 after 2 calls, got %s after %d", snapshot.ID, calls())
+	}
+}
+
+func TestLatestSnapshotCacheDoesNotCacheErrors(t *testing.T) {
+	service := createTestService()
+	service.active().latestSnapshots = NewLatestSnapshotCache(time.Minute, createTestLogger())
+	service.client().(*MockForwardClient).snapshots = nil
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.latestSnapshot("162112"); err == nil {
+			t.Fatal("Expected an error for a network without snapshots")
+		}
+	}
+	if len(service.active().latestSnapshots.entries) != 0 {
+		t.Error("Expected failed lookups not to be cached")
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// lifecycleWarningDays flags devices whose support ends within this many days
+const lifecycleWarningDays = 180
+
+// lifecycleReportLimit caps how many at-risk devices the lifecycle summary lists
+const lifecycleReportLimit = 10
+
+// lifecycleDateMarkers identify end-of-life / end-of-support columns anywhere in their
+// normalized (lowercase, alphanumeric) name
+var lifecycleDateMarkers = []string{"endofsupport", "endoflife", "lastdayofsupport", "lastsupport"}
+
+// lifecycleDatePrefixes are abbreviations that only count at the start of a column name,
+// so "eosDate" matches but "geolocation" does not
+var lifecycleDatePrefixes = []string{"eos", "eol"}
+
+// lifecycleDeviceColumns are tried in order to name the device a row describes
+var lifecycleDeviceColumns = []string{"device", "deviceName", "name", "hostname"}
+
+// lifecycleDateLayouts are the date formats accepted in lifecycle columns
+var lifecycleDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02", "01/02/2006", "Jan 2, 2006", "2 Jan 2006"}
+
+// LifecycleRisk is the nearest end-of-life or end-of-support date found for one device
+type LifecycleRisk struct {
+	Device        string    `json:"device"`
+	Column        string    `json:"column"`
+	Date          time.Time `json:"date"`
+	DaysRemaining int       `json:"days_remaining"` // negative when support has already ended
+}
+
+// Overdue reports whether the device is already past the date
+func (r LifecycleRisk) Overdue() bool {
+	return r.DaysRemaining < 0
+}
+
+// LifecycleReport summarizes lifecycle risk across NQE result rows
+type LifecycleReport struct {
+	DateColumns []string        `json:"date_columns"`
+	Risks       []LifecycleRisk `json:"risks"` // most urgent first
+	PastSupport int             `json:"past_support"`
+	Expiring    int             `json:"expiring"` // within lifecycleWarningDays
+}
+
+// isLifecycleDateColumn reports whether a column holds an end-of-life or end-of-support date
+func isLifecycleDateColumn(column string) bool {
+	var normalized strings.Builder
+	for _, r := range strings.ToLower(column) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			normalized.WriteRune(r)
+		}
+	}
+	name := normalized.String()
+	for _, marker := range lifecycleDateMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	for _, prefix := range lifecycleDatePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLifecycleDate parses a lifecycle column value, ignoring empty or non-date values
+func parseLifecycleDate(value interface{}) (time.Time, bool) {
+	text, ok := value.(string)
+	if !ok || strings.TrimSpace(text) == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range lifecycleDateLayouts {
+		if date, err := time.Parse(layout, strings.TrimSpace(text)); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// lifecycleDeviceName returns the device a row describes, falling back to its row number
+func lifecycleDeviceName(item map[string]interface{}, row int) string {
+	for _, column := range lifecycleDeviceColumns {
+		if name, ok := item[column].(string); ok && name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("row %d", row+1)
+}
+
+// AnalyzeLifecycle finds end-of-life and end-of-support dates in NQE result rows and
+// ranks devices by how soon (or how long ago) their earliest date falls relative to now
+func AnalyzeLifecycle(items []map[string]interface{}, now time.Time) LifecycleReport {
+	var report LifecycleReport
+	for _, column := range nqeResultColumns(items) {
+		if isLifecycleDateColumn(column) {
+			report.DateColumns = append(report.DateColumns, column)
+		}
+	}
+	if len(report.DateColumns) == 0 {
+		return report
+	}
+
+	today := now.Truncate(24 * time.Hour)
+	for row, item := range items {
+		var earliest *LifecycleRisk
+		for _, column := range report.DateColumns {
+			date, ok := parseLifecycleDate(item[column])
+			if !ok || (earliest != nil && !date.Before(earliest.Date)) {
+				continue
+			}
+			earliest = &LifecycleRisk{
+				Device:        lifecycleDeviceName(item, row),
+				Column:        column,
+				Date:          date,
+				DaysRemaining: int(date.Sub(today).Hours() / 24),
+			}
+		}
+		if earliest == nil {
+			continue
+		}
+
+		report.Risks = append(report.Risks, *earliest)
+		switch {
+		case earliest.Overdue():
+			report.PastSupport++
+		case earliest.DaysRemaining <= lifecycleWarningDays:
+			report.Expiring++
+		}
+	}
+
+	sort.SliceStable(report.Risks, func(i, j int) bool {
+		return report.Risks[i].DaysRemaining < report.Risks[j].DaysRemaining
+	})
+	return report
+}
+
+// formatLifecycleReport renders the lifecycle risk summary, or "" when no dates were found
+func formatLifecycleReport(report LifecycleReport) string {
+	if len(report.Risks) == 0 {
+		return ""
+	}
+
+	response := fmt.Sprintf("Lifecycle summary: %d of %d devices are past support, %d reach end of support within %d days (from %s).\n",
+		report.PastSupport, len(report.Risks), report.Expiring, lifecycleWarningDays, strings.Join(report.DateColumns, ", "))
+
+	for i, risk := range report.Risks {
+		if i == lifecycleReportLimit {
+			response += fmt.Sprintf("• ... and %d more\n", len(report.Risks)-lifecycleReportLimit)
+			break
+		}
+		status := fmt.Sprintf("%d days remaining", risk.DaysRemaining)
+		if risk.Overdue() {
+			status = fmt.Sprintf("OVERDUE by %d days", -risk.DaysRemaining)
+		}
+		response += fmt.Sprintf("• %s: %s %s (%s)\n", risk.Device, risk.Column, risk.Date.Format("2006-01-02"), status)
+	}
+	return response + "\n"
+}
+
+// runLifecycleQuery runs a predefined inventory query and prefixes the result with a
+// lifecycle risk summary when the rows carry end-of-life or end-of-support dates. A
+// preview skips the summary, since a few rows say nothing about overall risk.
+func (s *ForwardMCPService) runLifecycleQuery(args RunNQEQueryByIDArgs) (*mcp.ToolResponse, error) {
+	if args.Preview {
+		return s.previewNQEQuery(args)
+	}
+	args, sizeNote, err := s.nqeSizeAdvice(args)
+	if err != nil {
+		return nil, err
+	}
+	params, result, cachedAt, err := s.fetchNQEResult(args)
+	if err != nil {
+		return nil, err
+	}
+	if result, err = flattenNQEResult(result, args.Flatten, args.FlattenDepth); err != nil {
+		return nil, err
+	}
+	result, filterNote, err := applyNQEPostFilters(result, args.PostFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	response := cacheBypassNote(args.NoCache) + sizeNote + filterNote + formatLifecycleReport(AnalyzeLifecycle(result.Items, time.Now())) + s.formatNQEResult(params, result, cachedAt, args.Columns, args.Pretty)
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func lifecycleTestRows() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"device": "edge-1", "os": "eos", "endOfSupport": "2027-06-01"},
+		{"device": "core-1", "os": "ios-xe", "endOfSupport": "2025-01-15", "endOfLife": "2026-01-15"},
+		{"device": "leaf-1", "os": "nx-os", "endOfSupport": "2026-11-15"},
+		{"device": "spine-1", "os": "junos", "endOfSupport": "2026-03-01"},
+		{"device": "lab-1", "os": "junos", "endOfSupport": ""},
+	}
+}
+
+func TestAnalyzeLifecycleRanksByRisk(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	report := AnalyzeLifecycle(lifecycleTestRows(), now)
+
+	if strings.Join(report.DateColumns, ",") != "endOfLife,endOfSupport" {
+		t.Errorf("Expected endOfLife and endOfSupport date columns, got %v", report.DateColumns)
+	}
+
+	var order []string
+	for _, risk := range report.Risks {
+		order = append(order, risk.Device)
+	}
+	if got := strings.Join(order, ","); got != "core-1,spine-1,leaf-1,edge-1" {
+		t.Errorf("Expected devices ordered by risk, got %s", got)
+	}
+
+	if report.PastSupport != 2 || report.Expiring != 1 {
+		t.Errorf("Expected 2 past support and 1 expiring, got %d and %d", report.PastSupport, report.Expiring)
+	}
+
+	core := report.Risks[0]
+	if core.Column != "endOfSupport" || core.DaysRemaining != -639 || !core.Overdue() {
+		t.Errorf("Expected core-1 overdue by 639 days on endOfSupport, got %+v", core)
+	}
+	if leaf := report.Risks[2]; leaf.DaysRemaining != 30 || leaf.Overdue() {
+		t.Errorf("Expected leaf-1 to have 30 days remaining, got %+v", leaf)
+	}
+}
+
+func TestAnalyzeLifecycleIgnoresRowsWithoutDates(t *testing.T) {
+	rows := []map[string]interface{}{{"device": "router-1", "geolocation": "2020-01-01", "model": "MX480"}}
+	report := AnalyzeLifecycle(rows, time.Now())
+	if len(report.DateColumns) != 0 || formatLifecycleReport(report) != "" {
+		t.Errorf("Expected no lifecycle report without EoL/EoS columns, got %+v", report)
+	}
+}
+
+func TestGetOSSupportIncludesLifecycleSummary(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).nqeResult = &forward.NQERunResult{Items: lifecycleTestRows()}
+
+	response, err := service.getOSSupport(GetOSSupportArgs{NetworkID: "162112"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	if !strings.HasPrefix(content, "Lifecycle summary: ") {
+		t.Errorf("Expected lifecycle summary first, got:\n%s", content)
+	}
+	if !contains(content, "core-1: endOfSupport 2025-01-15 (OVERDUE by") {
+		t.Errorf("Expected overdue device to be flagged, got:\n%s", content)
+	}
+	if !contains(content, "NQE query completed. Found 5 items") {
+		t.Errorf("Expected raw results after the summary, got:\n%s", content)
+	}
+}
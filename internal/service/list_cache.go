@@ -0,0 +1,100 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// networksListKey is the list cache key of the network list
+const networksListKey = "networks"
+
+// locationsListKey is the list cache key of a network's locations
+func locationsListKey(networkID string) string {
+	return "locations/" + networkID
+}
+
+// listCacheEntry is one cached list response
+type listCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// ListCache keeps the results of read-only list endpoints (networks, locations) for a short
+// TTL, keyed by endpoint and network. It is separate from the semantic NQE cache and only
+// holds successful responses. A nil cache fetches on every call.
+type ListCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]listCacheEntry
+	now     func() time.Time
+}
+
+// NewListCache creates a cache whose entries live for ttl
+func NewListCache(ttl time.Duration) *ListCache {
+	return &ListCache{ttl: ttl, entries: make(map[string]listCacheEntry), now: time.Now}
+}
+
+// get returns the cached value for key, calling fetch when there is no fresh entry or
+// refresh forces a new lookup
+func (c *ListCache) get(key string, refresh bool, fetch func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return fetch()
+	}
+
+	c.mutex.Lock()
+	if entry, ok := c.entries[key]; ok && !refresh && c.now().Before(entry.expiresAt) {
+		c.mutex.Unlock()
+		return entry.value, nil
+	}
+	c.mutex.Unlock()
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	c.entries[key] = listCacheEntry{value: value, expiresAt: c.now().Add(c.ttl)}
+	c.mutex.Unlock()
+	return value, nil
+}
+
+// Invalidate drops the given keys, so writes are visible to the next list call
+func (c *ListCache) Invalidate(keys ...string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+// cachedNetworks lists networks through the list cache, sharing one API call between
+// concurrent lookups
+func (s *ForwardMCPService) cachedNetworks(refresh bool) ([]forward.Network, error) {
+	instance := s.active()
+	value, err := instance.listCache.get(networksListKey, refresh, func() (interface{}, error) {
+		value, err, _ := s.lookups.Do(instance.lookupKey(networksListKey), func() (interface{}, error) {
+			return instance.client.GetNetworks()
+		})
+		return value, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]forward.Network), nil
+}
+
+// cachedLocations lists a network's locations through the list cache
+func (s *ForwardMCPService) cachedLocations(networkID string, refresh bool) ([]forward.Location, error) {
+	value, err := s.active().listCache.get(locationsListKey(networkID), refresh, func() (interface{}, error) {
+		return s.client().GetLocations(networkID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]forward.Location), nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// countingListClient counts GetNetworks calls
+type countingListClient struct {
+	*MockForwardClient
+	networkCalls int
+}
+
+func (c *countingListClient) GetNetworks() ([]forward.Network, error) {
+	c.networkCalls++
+	return c.MockForwardClient.GetNetworks()
+}
+
+func TestListNetworksUsesListCache(t *testing.T) {
+	service := createTestService()
+	client := &countingListClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+	service.active().listCache = NewListCache(30 * time.Second)
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.listNetworks(ListNetworksArgs{}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if client.networkCalls != 1 {
+		t.Errorf("Expected two rapid calls to hit the API once, got %d calls", client.networkCalls)
+	}
+
+	if _, err := service.listNetworks(ListNetworksArgs{Refresh: true}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if client.networkCalls != 2 {
+		t.Errorf("Expected refresh to bypass the cache, got %d calls", client.networkCalls)
+	}
+}
+
+func TestListCacheExpiresAndInvalidates(t *testing.T) {
+	cache := NewListCache(30 * time.Second)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	cache.get(networksListKey, false, fetch)
+	cache.get(networksListKey, false, fetch)
+	now = now.Add(31 * time.Second)
+	cache.get(networksListKey, false, fetch)
+	if calls != 2 {
+		t.Errorf("Expected an expired entry to be fetched again, got %d fetches", calls)
+	}
+
+	cache.Invalidate(networksListKey)
+	if value, _ := cache.get(networksListKey, false, fetch); value != 3 {
+		t.Errorf("Expected an invalidated entry to be fetched again, got %v", value)
+	}
+}
@@ -101,7 +101,7 @@ func (idx *NQEQueryIndex) FormatForLLM(searchQuery string, results []*QuerySearc
 		SearchQuery:  searchQuery,
 		SearchMethod: inferSearchMethod(results),
 		ResultCount:  len(results),
-		TotalQueries: len(idx.queries),
+		TotalQueries: len(idx.Queries()),
 		SearchTimeMs: searchTimeMs,
 		Queries:      optimizedResults,
 
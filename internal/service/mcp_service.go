@@ -2,13 +2,16 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/forward-mcp/internal/config"
+	"github.com/forward-mcp/internal/cursor"
 	"github.com/forward-mcp/internal/forward"
 	"github.com/forward-mcp/internal/logger"
 	mcp "github.com/metoro-io/mcp-golang"
@@ -58,63 +61,172 @@ func (wm *WorkflowManager) SetState(sessionID string, state *WorkflowState) {
 
 // ForwardMCPService implements Forward Networks MCP tools using mcp-golang
 type ForwardMCPService struct {
-	forwardClient   forward.ClientInterface
 	config          *config.Config
 	logger          *logger.Logger
 	defaults        *ServiceDefaults
 	workflowManager *WorkflowManager
 	semanticCache   *SemanticCache
 	queryIndex      *NQEQueryIndex
+	metrics         *ServiceMetrics
+	redactor        *Redactor
+	assetMasker     *AssetMasker
+	indexBuilds     *IndexBuilder
+	limiter         *ToolCallLimiter
+	lookups         *FlightGroup
+	toolCatalog     *ToolCatalog
+	nqePolicy       *NQEDirectoryPolicy
+	// instance holds the active Forward instance's client and caches; every per-call
+	// copy of the service shares it
+	instance *instanceHolder
+	// toolFailed is set on a per-call copy when the handler reports a failure in its
+	// response text rather than as an error
+	toolFailed bool
 }
 
 // ServiceDefaults holds default values for the MCP service
 type ServiceDefaults struct {
+	// NetworkID and SnapshotID change during tool calls (set_default_network, instance
+	// switches, profiles), so they are read and written through Scope and SetScope
+	scopeMu    sync.RWMutex
 	NetworkID  string
 	SnapshotID string
 	QueryLimit int
+	// MaxQueryLimit is the largest row limit an NQE call may request (0 = no cap)
+	MaxQueryLimit int
+	// DefaultFormat is the NQE output format used when a call does not set options.format
+	DefaultFormat string
+	// NQEResponseBudgetBytes is the NQE response size above which a smaller limit is
+	// suggested, predicted from earlier runs (0 = no budget); NQEAutoLimit applies it
+	NQEResponseBudgetBytes int
+	NQEAutoLimit           bool
+	// ResponseDetail is "full" (default) or "summary" for compact tool output
+	ResponseDetail string
+	// HideNQESchema omits the inferred column schema from NQE query responses
+	HideNQESchema bool
+	// NormalizeNQEUnits adds human-friendly <column>_display values for columns with units
+	NormalizeNQEUnits bool
+	// Path search limits applied when search_paths leaves them unset (0 uses the built-in default)
+	PathMaxCandidates        int
+	PathMaxResults           int
+	PathMaxReturnPathResults int
+	PathMaxSeconds           int
+}
+
+// Scope returns the default network and snapshot IDs
+func (d *ServiceDefaults) Scope() (networkID, snapshotID string) {
+	d.scopeMu.RLock()
+	defer d.scopeMu.RUnlock()
+	return d.NetworkID, d.SnapshotID
+}
+
+// SetScope replaces the default network and snapshot IDs
+func (d *ServiceDefaults) SetScope(networkID, snapshotID string) {
+	d.scopeMu.Lock()
+	defer d.scopeMu.Unlock()
+	d.NetworkID, d.SnapshotID = networkID, snapshotID
+}
+
+// SetNetwork replaces the default network ID, keeping the default snapshot
+func (d *ServiceDefaults) SetNetwork(networkID string) {
+	d.scopeMu.Lock()
+	defer d.scopeMu.Unlock()
+	d.NetworkID = networkID
 }
 
 // NewForwardMCPService creates a new Forward MCP service
 func NewForwardMCPService(cfg *config.Config, logger *logger.Logger) *ForwardMCPService {
-	// Create Forward Networks client
-	forwardClient := forward.NewClient(&cfg.Forward)
-
-	// Create embedding service based on config
-	var embeddingService EmbeddingService
-	if cfg.Forward.SemanticCache.EmbeddingProvider == "openai" {
-		if openaiKey := os.Getenv("OPENAI_API_KEY"); openaiKey != "" {
-			embeddingService = NewOpenAIEmbeddingService(openaiKey)
-		} else {
-			embeddingService = NewKeywordEmbeddingService()
-			logger.Warn("OpenAI provider selected but OPENAI_API_KEY not set - using keyword embedding service")
-		}
-	} else {
-		embeddingService = NewKeywordEmbeddingService()
-	}
+	// Create embedding service (or fallback chain) based on config
+	embeddingService := newEmbeddingServiceFromConfig(cfg.Forward.SemanticCache, logger)
 
 	// Create semantic cache
 	semanticCache := NewSemanticCache(embeddingService, logger)
+	if cfg.Forward.SemanticCache.SuggestionFloor > 0 {
+		semanticCache.SetSuggestionFloor(cfg.Forward.SemanticCache.SuggestionFloor)
+	}
+	semanticCache.SetEntryLimits(cfg.Forward.SemanticCache.MaxEntryRows, cfg.Forward.SemanticCache.MaxEntryBytes, cfg.Forward.SemanticCache.MaxTotalBytes)
 
 	// Create query index
 	queryIndex := NewNQEQueryIndex(embeddingService, logger)
+	if err := queryIndex.SetSpecDir(cfg.Forward.SemanticCache.SpecDir); err != nil {
+		logger.Warn("Failed to apply spec directory: %v", err)
+	}
+	if err := queryIndex.SetMaxResidentEmbeddings(cfg.Forward.SemanticCache.MaxResidentEmbeddings); err != nil {
+		logger.Warn("Failed to apply embedding memory cap: %v", err)
+	}
+
+	// Hide and refuse queries outside the allowed NQE directories (nil allows all)
+	nqePolicy := NewNQEDirectoryPolicy(cfg.Forward.NQEAllowDirectories, cfg.Forward.NQEDenyDirectories)
+	queryIndex.SetDirectoryPolicy(nqePolicy)
 
 	// Initialize query index
 	if err := queryIndex.LoadFromSpec(); err != nil {
 		logger.Warn("Failed to initialize query index: %v", err)
 	}
 
+	// Create response redactor (nil when redaction is off)
+	redactor, err := newRedactorFromConfig(cfg.MCP.Redact, cfg.MCP.RedactPatterns)
+	if err != nil {
+		logger.Warn("Ignoring custom redaction patterns: %v", err)
+		redactor, _ = newRedactorFromConfig(true, nil)
+	}
+
+	// Mask asset identifiers in responses (nil when masking is off)
+	var assetMasker *AssetMasker
+	if cfg.MCP.MaskAssets {
+		assetMasker = &AssetMasker{}
+	}
+
+	// Create tool call limiter (nil when concurrency is unlimited)
+	limiter, err := newToolCallLimiterFromConfig(cfg.MCP.MaxConcurrentToolCalls, cfg.MCP.ConcurrencyPolicy)
+	if err != nil {
+		logger.Warn("Falling back to queueing excess tool calls: %v", err)
+		limiter, _ = newToolCallLimiterFromConfig(cfg.MCP.MaxConcurrentToolCalls, ConcurrencyPolicyQueue)
+	}
+
+	// Share one API call between concurrent identical lookups (nil when disabled)
+	var lookups *FlightGroup
+	if cfg.Forward.CoalesceLookups {
+		lookups = NewFlightGroup()
+	}
+
+	// Record tool calls and Forward API requests for get_metrics
+	metrics := NewServiceMetrics()
+
+	if _, err := ParseJSONMode(cfg.MCP.JSONFormat); err != nil {
+		logger.Warn("Using formatted JSON output: %v", err)
+	}
+
 	return &ForwardMCPService{
-		forwardClient: forwardClient,
-		config:        cfg,
-		logger:        logger,
+		config: cfg,
+		logger: logger,
 		defaults: &ServiceDefaults{
-			NetworkID:  cfg.Forward.DefaultNetworkID,
-			SnapshotID: cfg.Forward.DefaultSnapshotID,
-			QueryLimit: cfg.Forward.DefaultQueryLimit,
+			NetworkID:     cfg.Forward.DefaultNetworkID,
+			SnapshotID:    cfg.Forward.DefaultSnapshotID,
+			QueryLimit:    cfg.Forward.DefaultQueryLimit,
+			MaxQueryLimit: cfg.Forward.MaxQueryLimit,
+			DefaultFormat: cfg.Forward.DefaultNQEFormat,
+
+			NQEResponseBudgetBytes: cfg.Forward.NQEResponseBudgetBytes,
+			NQEAutoLimit:           cfg.Forward.NQEAutoLimit,
+
+			PathMaxCandidates:        cfg.Forward.PathMaxCandidates,
+			PathMaxResults:           cfg.Forward.PathMaxResults,
+			PathMaxReturnPathResults: cfg.Forward.PathMaxReturnPathResults,
+			PathMaxSeconds:           cfg.Forward.PathMaxSeconds,
 		},
 		workflowManager: NewWorkflowManager(),
 		semanticCache:   semanticCache,
 		queryIndex:      queryIndex,
+		metrics:         metrics,
+		redactor:        redactor,
+		assetMasker:     assetMasker,
+		indexBuilds:     NewIndexBuilder(),
+		limiter:         limiter,
+		lookups:         lookups,
+		toolCatalog:     NewToolCatalog(),
+		nqePolicy:       nqePolicy,
+		// The Forward client and the caches of its data (disabled caches stay nil)
+		instance: newInstanceHolder(newInstanceState("", newForwardClient(&cfg.Forward, metrics), &cfg.Forward, logger)),
 	}
 }
 
@@ -124,7 +236,8 @@ func (s *ForwardMCPService) getNetworkID(networkID string) string {
 		return networkID
 	}
 	if s.defaults != nil {
-		return s.defaults.NetworkID
+		networkID, _ := s.defaults.Scope()
+		return networkID
 	}
 	return ""
 }
@@ -135,7 +248,8 @@ func (s *ForwardMCPService) getSnapshotID(snapshotID string) string {
 		return snapshotID
 	}
 	if s.defaults != nil {
-		return s.defaults.SnapshotID
+		_, snapshotID := s.defaults.Scope()
+		return snapshotID
 	}
 	return ""
 }
@@ -151,6 +265,29 @@ func (s *ForwardMCPService) getQueryLimit(limit int) int {
 	return 1000 // Default fallback if no defaults are set
 }
 
+// nqeRowLimit returns the row limit for an NQE call: the default query limit when the
+// caller omits one, or the requested limit when it is within the configured cap
+func (s *ForwardMCPService) nqeRowLimit(limit int) (int, error) {
+	if limit < 0 {
+		return 0, fmt.Errorf("invalid limit %d: must be positive", limit)
+	}
+	maxLimit := 0
+	if s.defaults != nil {
+		maxLimit = s.defaults.MaxQueryLimit
+	}
+	if limit == 0 {
+		limit = s.getQueryLimit(0)
+		if maxLimit > 0 && limit > maxLimit {
+			limit = maxLimit
+		}
+		return limit, nil
+	}
+	if maxLimit > 0 && limit > maxLimit {
+		return 0, fmt.Errorf("limit %d exceeds the maximum of %d rows per NQE query: narrow the query with filters or page through the results with offset", limit, maxLimit)
+	}
+	return limit, nil
+}
+
 // Helper function to log tool calls with detailed information
 func (s *ForwardMCPService) logToolCall(toolName string, args interface{}, err error) {
 	argsJSON, _ := json.MarshalIndent(args, "", "  ")
@@ -162,195 +299,405 @@ func (s *ForwardMCPService) logToolCall(toolName string, args interface{}, err e
 }
 
 // RegisterTools registers all Forward Networks tools with the MCP server
-func (s *ForwardMCPService) RegisterTools(server *mcp.Server) error {
+func (s *ForwardMCPService) RegisterTools(mcpServer *mcp.Server) error {
+	if s.toolCatalog == nil {
+		s.toolCatalog = NewToolCatalog()
+	}
+	server := catalogingServer{server: mcpServer, catalog: s.toolCatalog}
+
 	// Network Management Tools
 	if err := server.RegisterTool("list_networks",
 		"List all networks in the Forward platform. Returns network IDs, names, and descriptions. Use this to discover available networks or find network IDs for other operations.",
-		s.listNetworks); err != nil {
+		withToolMiddleware(s, "list_networks", (*ForwardMCPService).listNetworks)); err != nil {
 		return fmt.Errorf("failed to register list_networks tool: %w", err)
 	}
 
 	if err := server.RegisterTool("create_network",
 		"Create a new network in the Forward platform. Requires a network name. Returns the new network with ID for subsequent operations.",
-		s.createNetwork); err != nil {
+		withToolMiddleware(s, "create_network", (*ForwardMCPService).createNetwork)); err != nil {
 		return fmt.Errorf("failed to register create_network tool: %w", err)
 	}
 
 	if err := server.RegisterTool("delete_network",
 		"Delete a network from the Forward platform. Requires network_id. WARNING: This permanently deletes all associated data.",
-		s.deleteNetwork); err != nil {
+		withToolMiddleware(s, "delete_network", (*ForwardMCPService).deleteNetwork)); err != nil {
 		return fmt.Errorf("failed to register delete_network tool: %w", err)
 	}
 
 	if err := server.RegisterTool("update_network",
 		"Update network properties in the Forward platform. Requires network_id and at least one property to update (name or description).",
-		s.updateNetwork); err != nil {
+		withToolMiddleware(s, "update_network", (*ForwardMCPService).updateNetwork)); err != nil {
 		return fmt.Errorf("failed to register update_network tool: %w", err)
 	}
 
+	if err := server.RegisterTool("get_network_summary",
+		"Get a one-shot overview of a network: device count from the latest snapshot, snapshot count and latest snapshot state, and location count. Uses the default network if network_id is omitted. Reports partial results if some data cannot be retrieved.",
+		withToolMiddleware(s, "get_network_summary", (*ForwardMCPService).getNetworkSummary)); err != nil {
+		return fmt.Errorf("failed to register get_network_summary tool: %w", err)
+	}
+
 	// Path Search Tools
 	if err := server.RegisterTool("search_paths",
-		"Search for network paths by tracing packets through the network. Requires network_id from, or src_ip and dst_ip. Use for connectivity verification, troubleshooting, and routing analysis. Can specify source IP, ports, and protocols for detailed path tracing.",
-		s.searchPaths); err != nil {
+		"Search for network paths by tracing packets through the network. Requires network_id from, or src_ip and dst_ip. Use for connectivity verification, troubleshooting, and routing analysis. Can specify source IP, ports, and protocols for detailed path tracing. Set explain: true for a plain-language narrative of each path, and include_return_path: true to check the return path for asymmetric routing.",
+		withToolMiddleware(s, "search_paths", (*ForwardMCPService).searchPaths)); err != nil {
 		return fmt.Errorf("failed to register search_paths tool: %w", err)
 	}
 
+	if err := server.RegisterTool("search_paths_bulk",
+		"Trace several src/dst flows in one request, e.g. to verify a list of required connections. Reports each flow's path count and outcomes, and names the specific flows that failed or got no response.",
+		withToolMiddleware(s, "search_paths_bulk", (*ForwardMCPService).searchPathsBulk)); err != nil {
+		return fmt.Errorf("failed to register search_paths_bulk tool: %w", err)
+	}
+
+	if err := server.RegisterTool("check_device_reachability",
+		"Check reachability between pairs of devices by name. Resolves each device to its management IP, traces every pair in one bulk path search, and returns a reachable/unreachable matrix with the blocking hop for unreachable pairs.",
+		withToolMiddleware(s, "check_device_reachability", (*ForwardMCPService).checkDeviceReachability)); err != nil {
+		return fmt.Errorf("failed to register check_device_reachability tool: %w", err)
+	}
+
+	if err := server.RegisterTool("get_path_search_history",
+		"List the path searches (reachability checks) run in this session, newest first, with source/destination, snapshot, and classified outcomes such as DELIVERED or DROPPED_ACL.",
+		withToolMiddleware(s, "get_path_search_history", (*ForwardMCPService).getPathSearchHistory)); err != nil {
+		return fmt.Errorf("failed to register get_path_search_history tool: %w", err)
+	}
+
 	// NQE Tools
+	if err := server.RegisterTool("validate_nqe_query",
+		"Check inline NQE query source for syntax and type errors. A query that compiles is executed for a single row, so this is a cheap check rather than a dry run. Reports each error with its line and column so the query can be fixed before a potentially long run.",
+		withToolMiddleware(s, "validate_nqe_query", (*ForwardMCPService).validateNQEQuery)); err != nil {
+		return fmt.Errorf("failed to register validate_nqe_query tool: %w", err)
+	}
+
 	if err := server.RegisterTool("run_nqe_query_by_id",
 		"Run a Network Query Engine (NQE) query using a predefined query ID from the library. Use for standard reports, compliance checks, and consistent analysis. First use list_nqe_queries to discover available queries and their IDs.",
-		s.runNQEQueryByID); err != nil {
+		withToolMiddleware(s, "run_nqe_query_by_id", (*ForwardMCPService).runNQEQueryByID)); err != nil {
 		return fmt.Errorf("failed to register run_nqe_query_by_id tool: %w", err)
 	}
 
 	if err := server.RegisterTool("list_nqe_queries",
 		"List available NQE queries from the Forward Networks query library. Use to discover predefined queries for reports and analysis. Can filter by directory (/L3/Basic/, /L3/Advanced/, /L3/Security/). Returns query IDs for use with run_nqe_query_by_id.",
-		s.listNQEQueries); err != nil {
+		withToolMiddleware(s, "list_nqe_queries", (*ForwardMCPService).listNQEQueries)); err != nil {
 		return fmt.Errorf("failed to register list_nqe_queries tool: %w", err)
 	}
 
 	// First-Class Query Tools - Most Important Network Operations
 	if err := server.RegisterTool("get_device_basic_info",
 		"Get basic device information including names, platforms, and management IPs. Essential for device inventory and discovery. Uses predefined Device Basic Info query.",
-		s.getDeviceBasicInfo); err != nil {
+		withToolMiddleware(s, "get_device_basic_info", (*ForwardMCPService).getDeviceBasicInfo)); err != nil {
 		return fmt.Errorf("failed to register get_device_basic_info tool: %w", err)
 	}
 
 	if err := server.RegisterTool("get_device_hardware",
 		"Get device hardware information including models, serial numbers, and hardware details. Critical for hardware inventory and lifecycle management.",
-		s.getDeviceHardware); err != nil {
+		withToolMiddleware(s, "get_device_hardware", (*ForwardMCPService).getDeviceHardware)); err != nil {
 		return fmt.Errorf("failed to register get_device_hardware tool: %w", err)
 	}
 
 	if err := server.RegisterTool("get_hardware_support",
 		"Get hardware support status including end-of-life and support dates. Essential for compliance and planning hardware refreshes.",
-		s.getHardwareSupport); err != nil {
+		withToolMiddleware(s, "get_hardware_support", (*ForwardMCPService).getHardwareSupport)); err != nil {
 		return fmt.Errorf("failed to register get_hardware_support tool: %w", err)
 	}
 
 	if err := server.RegisterTool("get_os_support",
 		"Get operating system support status including OS versions and support dates. Critical for security compliance and OS upgrade planning.",
-		s.getOSSupport); err != nil {
+		withToolMiddleware(s, "get_os_support", (*ForwardMCPService).getOSSupport)); err != nil {
 		return fmt.Errorf("failed to register get_os_support tool: %w", err)
 	}
 
 	if err := server.RegisterTool("search_configs",
 		"Search device configurations for specific patterns, commands, or settings.\n\nTo create a block pattern, use triple backticks (```) to start and end the pattern, and indent lines to show hierarchy. Example:\n\npattern = ```\ninterface\n  zone-member security\n  ip address {ip:string}\n```\n\nEach line is a line pattern. Indentation defines parent/child relationships. Use curly braces for variable extraction (e.g., {ip:string}). For more, see the data extraction guide.",
-		s.searchConfigs); err != nil {
+		withToolMiddleware(s, "search_configs", (*ForwardMCPService).searchConfigs)); err != nil {
 		return fmt.Errorf("failed to register search_configs tool: %w", err)
 	}
 
+	if err := server.RegisterTool("get_device_config",
+		"Get the full running configuration text of one device by name. Set output_file to write large configurations to disk instead of returning them.",
+		withToolMiddleware(s, "get_device_config", (*ForwardMCPService).getDeviceConfig)); err != nil {
+		return fmt.Errorf("failed to register get_device_config tool: %w", err)
+	}
+
+	if err := server.RegisterTool("find_devices_by_config",
+		"Find which devices have configuration matching a pattern (for example telnet enabled), or set inverse to find the devices that lack it. Accepts the same pattern syntax as search_configs.",
+		withToolMiddleware(s, "find_devices_by_config", (*ForwardMCPService).findDevicesByConfig)); err != nil {
+		return fmt.Errorf("failed to register find_devices_by_config tool: %w", err)
+	}
+
 	if err := server.RegisterTool("get_config_diff",
 		"Compare network configurations between snapshots to identify changes. Essential for change tracking and troubleshooting configuration drift.",
-		s.getConfigDiff); err != nil {
+		withToolMiddleware(s, "get_config_diff", (*ForwardMCPService).getConfigDiff)); err != nil {
 		return fmt.Errorf("failed to register get_config_diff tool: %w", err)
 	}
 
+	if err := server.RegisterTool("summarize_changes",
+		"Summarize what changed across a whole network between two snapshots: devices added or removed, interfaces that went down or came up, and devices whose configuration changed, most impactful first. Checks that fail are listed without hiding the others.",
+		withToolMiddleware(s, "summarize_changes", (*ForwardMCPService).summarizeChanges)); err != nil {
+		return fmt.Errorf("failed to register summarize_changes tool: %w", err)
+	}
+
+	if err := server.RegisterTool("preview_nqe_options",
+		"Check an NQE query's filters and sort_by before a full run: fetches one row to discover the real column names, flags any filter or sort column that does not exist, and lists the available columns. Use it to avoid failed executions.",
+		withToolMiddleware(s, "preview_nqe_options", (*ForwardMCPService).previewNQEOptions)); err != nil {
+		return fmt.Errorf("failed to register preview_nqe_options tool: %w", err)
+	}
+
+	if err := server.RegisterTool("diff_nqe_runs",
+		"Run the same NQE query against two snapshots and report which rows were added, removed, or changed, matched by a key column. Works for any query, e.g. to see which BGP neighbors appeared or disappeared.",
+		withToolMiddleware(s, "diff_nqe_runs", (*ForwardMCPService).diffNQERuns)); err != nil {
+		return fmt.Errorf("failed to register diff_nqe_runs tool: %w", err)
+	}
+
+	if err := server.RegisterTool("run_nqe_query_over_time",
+		"Run one NQE query against the most recent snapshots and return a time series of its result (row count or the sum of a column) per snapshot date. Use for trend analysis such as device count over time; failed snapshots are reported without failing the series.",
+		withToolMiddleware(s, "run_nqe_query_over_time", (*ForwardMCPService).runNQEQueryOverTime)); err != nil {
+		return fmt.Errorf("failed to register run_nqe_query_over_time tool: %w", err)
+	}
+
+	if err := server.RegisterTool("run_nqe_query_batch",
+		"Run one parameterized NQE query with several parameter sets in a single call (e.g. the same location query for each site) and return a per-set summary of row count and columns. Each set is cached separately; failed sets are reported without failing the batch.",
+		withToolMiddleware(s, "run_nqe_query_batch", (*ForwardMCPService).runNQEQueryBatch)); err != nil {
+		return fmt.Errorf("failed to register run_nqe_query_batch tool: %w", err)
+	}
+
+	if err := server.RegisterTool("combine_nqe_results",
+		"Run two NQE queries (by query_id or inline source) on the same snapshot and combine their rows on a key column: intersection (e.g. devices both past end-of-life and running BGP), union, or difference (first without second).",
+		withToolMiddleware(s, "combine_nqe_results", (*ForwardMCPService).combineNQEResults)); err != nil {
+		return fmt.Errorf("failed to register combine_nqe_results tool: %w", err)
+	}
+
+	if err := server.RegisterTool("compare_networks",
+		"Compare the device inventories of two networks (e.g. staging vs production). Devices are aligned by name and reported as only in one network or present in both with a different vendor, model, platform, or OS version.",
+		withToolMiddleware(s, "compare_networks", (*ForwardMCPService).compareNetworks)); err != nil {
+		return fmt.Errorf("failed to register compare_networks tool: %w", err)
+	}
+
+	if err := server.RegisterTool("diff_device_inventory",
+		"Diff one network's device inventory between two snapshots to find hardware churn and decommissions. Devices are aligned by name and reported as added, removed, or changed in vendor, model, platform, or OS version.",
+		withToolMiddleware(s, "diff_device_inventory", (*ForwardMCPService).diffDeviceInventory)); err != nil {
+		return fmt.Errorf("failed to register diff_device_inventory tool: %w", err)
+	}
+
+	if err := server.RegisterTool("detect_missing_devices",
+		"Alert on devices that disappeared: compare a known-good baseline snapshot with the network's latest snapshot and list the devices present in the baseline but absent now, with their last-known vendor, model, OS, and management IPs.",
+		withToolMiddleware(s, "detect_missing_devices", (*ForwardMCPService).detectMissingDevices)); err != nil {
+		return fmt.Errorf("failed to register detect_missing_devices tool: %w", err)
+	}
+
 	// Device Management Tools
 	if err := server.RegisterTool("list_devices",
-		"List devices in a network. Requires network_id. Returns basic device inventory with names, types, and status. Supports pagination with limit and offset. Use for device discovery and inventory management.",
-		s.listDevices); err != nil {
+		"List devices in a network. Requires network_id. Returns basic device inventory with names, types, and status. Supports pagination with limit and offset, or the next_cursor of the previous page. Use for device discovery and inventory management.",
+		withToolMiddleware(s, "list_devices", (*ForwardMCPService).listDevices)); err != nil {
 		return fmt.Errorf("failed to register list_devices tool: %w", err)
 	}
 
 	if err := server.RegisterTool("get_device_locations",
 		"Get device location mappings for a network. Requires network_id. Shows which devices are assigned to which physical locations. Use for topology planning and device organization.",
-		s.getDeviceLocations); err != nil {
+		withToolMiddleware(s, "get_device_locations", (*ForwardMCPService).getDeviceLocations)); err != nil {
 		return fmt.Errorf("failed to register get_device_locations tool: %w", err)
 	}
 
 	// Snapshot Management Tools
 	if err := server.RegisterTool("list_snapshots",
-		"List network configuration snapshots. Requires network_id. Shows historical network states with timestamps and status. Use to view configuration history and find specific snapshots for queries.",
-		s.listSnapshots); err != nil {
+		"List network configuration snapshots. Requires network_id. Shows historical network states with timestamps and status. Results are newest-first and include drafts unless include_drafts is false; filter by state (e.g. PROCESSED). Page with limit and offset, or the next_cursor of the previous page. Use to view configuration history and find specific snapshots for queries.",
+		withToolMiddleware(s, "list_snapshots", (*ForwardMCPService).listSnapshots)); err != nil {
 		return fmt.Errorf("failed to register list_snapshots tool: %w", err)
 	}
 
+	if err := server.RegisterTool("resolve_snapshot",
+		"Resolve a relative snapshot reference to a snapshot ID: 'latest', 'latest-N' (the Nth processed snapshot before the latest), 'today', 'yesterday', or a date like '2024-05-01' (the newest processed snapshot taken on or before that day, UTC). Every tool's snapshot arguments also accept these references directly.",
+		withToolMiddleware(s, "resolve_snapshot", (*ForwardMCPService).resolveSnapshot)); err != nil {
+		return fmt.Errorf("failed to register resolve_snapshot tool: %w", err)
+	}
+
 	if err := server.RegisterTool("get_latest_snapshot",
 		"Get the latest processed snapshot for a network. Requires network_id. Returns the most recent network state. Use to ensure queries run against current configuration.",
-		s.getLatestSnapshot); err != nil {
+		withToolMiddleware(s, "get_latest_snapshot", (*ForwardMCPService).getLatestSnapshot)); err != nil {
 		return fmt.Errorf("failed to register get_latest_snapshot tool: %w", err)
 	}
 
 	// Location Management Tools
 	if err := server.RegisterTool("list_locations",
 		"List locations in a network. Requires network_id. Returns physical locations with names and coordinates. Use to view network topology and organize devices by location.",
-		s.listLocations); err != nil {
+		withToolMiddleware(s, "list_locations", (*ForwardMCPService).listLocations)); err != nil {
 		return fmt.Errorf("failed to register list_locations tool: %w", err)
 	}
 
 	if err := server.RegisterTool("create_location",
 		"Create a new location in a network. Requires network_id and location name. Optional description and coordinates. Use to set up new sites or data centers for device organization.",
-		s.createLocation); err != nil {
+		withToolMiddleware(s, "create_location", (*ForwardMCPService).createLocation)); err != nil {
 		return fmt.Errorf("failed to register create_location tool: %w", err)
 	}
 
 	// Default Settings Management Tools
 	if err := server.RegisterTool("get_default_settings",
 		"View current default settings for network operations. Shows the default network ID, snapshot ID, and query limits configured for this session.",
-		s.getDefaultSettings); err != nil {
+		withToolMiddleware(s, "get_default_settings", (*ForwardMCPService).getDefaultSettings)); err != nil {
 		return fmt.Errorf("failed to register get_default_settings tool: %w", err)
 	}
 
 	if err := server.RegisterTool("set_default_network",
 		"Set the default network for all operations. Accepts either a network ID or network name. This will be used when network_id is not specified in other tools.",
-		s.setDefaultNetwork); err != nil {
+		withToolMiddleware(s, "set_default_network", (*ForwardMCPService).setDefaultNetwork)); err != nil {
 		return fmt.Errorf("failed to register set_default_network tool: %w", err)
 	}
 
+	if err := server.RegisterTool("set_default_settings",
+		"Update session-wide default settings. response_detail: 'summary' returns only counts and key identifiers (e.g. 'Found 12 devices; top: router-1, switch-1') to conserve tokens; 'full' (default) returns complete JSON. include_nqe_schema toggles the inferred column schema shown above NQE results. normalize_units adds readable values (e.g. 1.5 GiB, 42.0%, RFC 3339 dates) next to columns named *_bytes, *_percent, or *Millis. default_format sets the NQE output format used when a call omits options.format.",
+		withToolMiddleware(s, "set_default_settings", (*ForwardMCPService).setDefaultSettings)); err != nil {
+		return fmt.Errorf("failed to register set_default_settings tool: %w", err)
+	}
+
+	if err := server.RegisterTool("save_profile",
+		"Save the current session defaults (network, snapshot, query limit, NQE format, and response detail) as a named profile for this Forward instance, so load_profile can switch back to them in one call.",
+		withToolMiddleware(s, "save_profile", (*ForwardMCPService).saveProfile)); err != nil {
+		return fmt.Errorf("failed to register save_profile tool: %w", err)
+	}
+
+	if err := server.RegisterTool("load_profile",
+		"Replace the current session defaults with a profile saved by save_profile. The response names the profile's default network for confirmation.",
+		withToolMiddleware(s, "load_profile", (*ForwardMCPService).loadProfile)); err != nil {
+		return fmt.Errorf("failed to register load_profile tool: %w", err)
+	}
+
+	if err := server.RegisterTool("list_profiles",
+		"List the session profiles saved for this Forward instance with their network, snapshot, limit, format, and detail settings.",
+		withToolMiddleware(s, "list_profiles", (*ForwardMCPService).listProfiles)); err != nil {
+		return fmt.Errorf("failed to register list_profiles tool: %w", err)
+	}
+
+	// Forward Instance Tools
+	if err := server.RegisterTool("list_instances",
+		"List the configured Forward instances with their base URL and instance ID, marking the active one.",
+		withToolMiddleware(s, "list_instances", (*ForwardMCPService).listInstances)); err != nil {
+		return fmt.Errorf("failed to register list_instances tool: %w", err)
+	}
+
+	if err := server.RegisterTool("set_active_instance",
+		"Switch the Forward instance tool calls go to, by name from list_instances ('default' is the environment-configured instance). Rebuilds the API client, clears cached results from the previous instance, and applies the instance's default network.",
+		withToolMiddleware(s, "set_active_instance", (*ForwardMCPService).setActiveInstance)); err != nil {
+		return fmt.Errorf("failed to register set_active_instance tool: %w", err)
+	}
+
 	// Semantic Cache and AI Enhancement Tools
 	if err := server.RegisterTool("get_cache_stats",
 		"View semantic cache performance statistics including hit rates, total queries, and cache efficiency metrics.",
-		s.getCacheStats); err != nil {
+		withToolMiddleware(s, "get_cache_stats", (*ForwardMCPService).getCacheStats)); err != nil {
 		return fmt.Errorf("failed to register get_cache_stats tool: %w", err)
 	}
 
 	if err := server.RegisterTool("suggest_similar_queries",
 		"Get suggestions for similar NQE queries based on semantic similarity to your query intent. Helps discover relevant existing queries.",
-		s.suggestSimilarQueries); err != nil {
+		withToolMiddleware(s, "suggest_similar_queries", (*ForwardMCPService).suggestSimilarQueries)); err != nil {
 		return fmt.Errorf("failed to register suggest_similar_queries tool: %w", err)
 	}
 
 	if err := server.RegisterTool("clear_cache",
 		"Clear expired entries from the semantic cache to free up memory and improve performance.",
-		s.clearCache); err != nil {
+		withToolMiddleware(s, "clear_cache", (*ForwardMCPService).clearCache)); err != nil {
 		return fmt.Errorf("failed to register clear_cache tool: %w", err)
 	}
 
 	// AI-Powered Query Discovery Tools
 	if err := server.RegisterTool("search_nqe_queries",
 		"🧠 AI-powered search through 6000+ predefined NQE queries using natural language. Describe what you want to analyze (e.g., 'AWS security issues', 'BGP routing problems', 'interface utilization') and get relevant query suggestions with similarity scores. Use this for EXPLORATION when you want to see what queries are available for a topic. For actionable results that can be immediately executed, use 'find_executable_query' instead.",
-		s.searchNQEQueries); err != nil {
+		withToolMiddleware(s, "search_nqe_queries", (*ForwardMCPService).searchNQEQueries)); err != nil {
 		return fmt.Errorf("failed to register search_nqe_queries tool: %w", err)
 	}
 
 	if err := server.RegisterTool("find_executable_query",
 		"🎯 BEST TOOL for query discovery! Smart query discovery that finds executable NQE queries for your needs. Uses AI semantic search across 6000+ queries, then maps results to actually runnable queries with real Forward Networks IDs. Use this when user asks 'I want to do X, what query should I run?' or wants actionable results. Returns queries you can immediately execute with 'run_nqe_query_by_id'. Always try this first before search_nqe_queries.",
-		s.findExecutableQuery); err != nil {
+		withToolMiddleware(s, "find_executable_query", (*ForwardMCPService).findExecutableQuery)); err != nil {
 		return fmt.Errorf("failed to register find_executable_query tool: %w", err)
 	}
 
+	if err := server.RegisterTool("ask_network",
+		"Answer a natural-language question about the network in one step. Finds the best executable NQE query and, when the match is confident, runs it against the default network and returns both the chosen query and its results. When no match is confident it returns the candidate queries instead of guessing.",
+		withToolMiddleware(s, "ask_network", (*ForwardMCPService).askNetwork)); err != nil {
+		return fmt.Errorf("failed to register ask_network tool: %w", err)
+	}
+
+	if err := server.RegisterTool("lookup_query",
+		"Find NQE queries by exact Query ID, Query ID prefix, or a fragment of their path (e.g. 'bgp neighbor'). Returns the matching candidates with their path, category, and intent so you can pick one before running it with run_nqe_query_by_id. Use search_nqe_queries instead to search by what a query does.",
+		withToolMiddleware(s, "lookup_query", (*ForwardMCPService).lookupQuery)); err != nil {
+		return fmt.Errorf("failed to register lookup_query tool: %w", err)
+	}
+
+	if err := server.RegisterTool("estimate_query_cost",
+		"Estimate how expensive an NQE query is before running it: returns a low/medium/high cost tier from the query source (length, nested loops, cross-joins) and the runtimes observed for it in this session, plus the last observed runtime.",
+		withToolMiddleware(s, "estimate_query_cost", (*ForwardMCPService).estimateQueryCost)); err != nil {
+		return fmt.Errorf("failed to register estimate_query_cost tool: %w", err)
+	}
+
 	if err := server.RegisterTool("initialize_query_index",
-		"Initialize or rebuild the AI-powered NQE query index from the spec file. REQUIRED before using search_nqe_queries or find_executable_query. Run this once at startup or when you get 'query index is empty' errors. Can generate embeddings for semantic search if OpenAI API key is available.",
-		s.initializeQueryIndex); err != nil {
+		"Initialize or rebuild the AI-powered NQE query index from the spec file. REQUIRED before using search_nqe_queries or find_executable_query. Run this once at startup or when you get 'query index is empty' errors. Can generate embeddings for semantic search if OpenAI API key is available. Set background: true to rebuild asynchronously and poll get_index_build_status.",
+		withToolMiddleware(s, "initialize_query_index", (*ForwardMCPService).initializeQueryIndex)); err != nil {
 		return fmt.Errorf("failed to register initialize_query_index tool: %w", err)
 	}
 
+	if err := server.RegisterTool("reload_query_index",
+		"Reload the NQE query index from the spec file without restarting the server, e.g. after the NQE library is updated. Keeps existing embeddings for unchanged queries and reports how many queries were added, removed, or changed.",
+		withToolMiddleware(s, "reload_query_index", (*ForwardMCPService).reloadQueryIndex)); err != nil {
+		return fmt.Errorf("failed to register reload_query_index tool: %w", err)
+	}
+
+	if err := server.RegisterTool("get_index_build_status",
+		"Check the progress of a background query index build started with initialize_query_index (background: true). Shows state (running/done/failed), current stage, and embedding progress.",
+		withToolMiddleware(s, "get_index_build_status", (*ForwardMCPService).getIndexBuildStatus)); err != nil {
+		return fmt.Errorf("failed to register get_index_build_status tool: %w", err)
+	}
+
 	if err := server.RegisterTool("get_query_index_stats",
 		"View statistics about the AI-powered NQE query index including total queries, categories, and embedding coverage.",
-		s.getQueryIndexStats); err != nil {
+		withToolMiddleware(s, "get_query_index_stats", (*ForwardMCPService).getQueryIndexStats)); err != nil {
 		return fmt.Errorf("failed to register get_query_index_stats tool: %w", err)
 	}
 
-	if err := server.RegisterTool("test_semantic_cache", "Test the semantic cache with a query, network_id, and snapshot_id.", s.testSemanticCache); err != nil {
+	if err := server.RegisterTool("get_embedding_coverage",
+		"Report how many NQE queries have AI embeddings per category and subcategory, flagging those below a coverage threshold where semantic search falls back to keywords.",
+		withToolMiddleware(s, "get_embedding_coverage", (*ForwardMCPService).getEmbeddingCoverage)); err != nil {
+		return fmt.Errorf("failed to register get_embedding_coverage tool: %w", err)
+	}
+
+	if err := server.RegisterTool("browse_nqe_library",
+		"📚 Browse the NQE query library by category. Returns the category tree with query counts and example query paths for each category. Works offline from the local query index. Use this to see what the library contains before searching with search_nqe_queries.",
+		withToolMiddleware(s, "browse_nqe_library", (*ForwardMCPService).browseNQELibrary)); err != nil {
+		return fmt.Errorf("failed to register browse_nqe_library tool: %w", err)
+	}
+
+	if err := server.RegisterTool("list_nqe_directories",
+		"📂 List the NQE library's directories as a tree with query counts per directory. Works offline from the local query index. Use it to find a directory to drill into, or to pass as the directory filter of list_nqe_queries.",
+		withToolMiddleware(s, "list_nqe_directories", (*ForwardMCPService).listNQEDirectories)); err != nil {
+		return fmt.Errorf("failed to register list_nqe_directories tool: %w", err)
+	}
+
+	if err := server.RegisterTool("test_semantic_cache", "Test the semantic cache with a query, network_id, and snapshot_id.", withToolMiddleware(s, "test_semantic_cache", (*ForwardMCPService).testSemanticCache)); err != nil {
 		return fmt.Errorf("failed to register test_semantic_cache tool: %w", err)
 	}
 
 	if err := server.RegisterTool("run_semantic_nqe_query",
 		"Finds the most relevant NQE query using semantic search and executes it. Provide a natural language description of what you want to analyze.",
-		s.runSemanticNQEQuery); err != nil {
+		withToolMiddleware(s, "run_semantic_nqe_query", (*ForwardMCPService).runSemanticNQEQuery)); err != nil {
 		return fmt.Errorf("failed to register run_semantic_nqe_query tool: %w", err)
 	}
 
+	if err := server.RegisterTool("get_metrics",
+		"View server metrics in Prometheus text format: total and per-tool call counts, error counts, tool latency histograms, and semantic cache hit rate.",
+		withToolMiddleware(s, "get_metrics", (*ForwardMCPService).getMetrics)); err != nil {
+		return fmt.Errorf("failed to register get_metrics tool: %w", err)
+	}
+
+	if err := server.RegisterTool("get_started",
+		"New here? Start with this. Returns ordered, ready-to-run tool calls for common goals (audit inventory, verify connectivity, investigate a change) with example arguments pre-filled with your default network.",
+		withToolMiddleware(s, "get_started", (*ForwardMCPService).getStarted)); err != nil {
+		return fmt.Errorf("failed to register get_started tool: %w", err)
+	}
+
+	if err := server.RegisterTool("describe_tools",
+		"Describe the registered tools as a machine-readable catalog: each tool's name, description, and the JSON schema of its arguments. Pass tool to describe a single tool.",
+		withToolMiddleware(s, "describe_tools", (*ForwardMCPService).describeTools)); err != nil {
+		return fmt.Errorf("failed to register describe_tools tool: %w", err)
+	}
+
 	return nil
 }
 
@@ -443,7 +790,7 @@ func (s *ForwardMCPService) nqeQueryDiscoveryWorkflow(args NQEDiscoveryArgs) (*m
 
 // networkDiscoveryWorkflow implements the network discovery workflow
 func (s *ForwardMCPService) networkDiscoveryWorkflow(args NetworkDiscoveryArgs) (*mcp.ToolResponse, error) {
-	networks, err := s.forwardClient.GetNetworks()
+	networks, err := s.client().GetNetworks()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get networks: %w", err)
 	}
@@ -459,7 +806,7 @@ func (s *ForwardMCPService) networkDiscoveryWorkflow(args NetworkDiscoveryArgs)
 
 // getNetworkContext provides contextual network information as a resource
 func (s *ForwardMCPService) getNetworkContext(args NetworkContextArgs) (interface{}, error) {
-	networks, err := s.forwardClient.GetNetworks()
+	networks, err := s.client().GetNetworks()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network context: %w", err)
 	}
@@ -488,7 +835,7 @@ func (s *ForwardMCPService) startQueryDiscovery(sessionID string) (*mcp.ToolResp
 
 // listQueriesInCategory lists available queries in the selected category
 func (s *ForwardMCPService) listQueriesInCategory(sessionID, directory string) (*mcp.ToolResponse, error) {
-	queries, err := s.forwardClient.GetNQEQueries(directory)
+	queries, err := s.client().GetNQEQueries(directory)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get queries: %w", err)
 	}
@@ -532,16 +879,20 @@ func (s *ForwardMCPService) collectQueryParameters(sessionID string) (*mcp.ToolR
 // This function is part of the workflow system that is now activated via MCP prompt registration
 func (s *ForwardMCPService) executeSelectedQuery(sessionID string) (*mcp.ToolResponse, error) {
 	state := s.workflowManager.GetState(sessionID)
+	if err := s.checkNQEPolicy(state.SelectedQuery); err != nil {
+		return nil, err
+	}
 
 	params := &forward.NQEQueryParams{
 		NetworkID:  state.NetworkID,
 		QueryID:    state.SelectedQuery,
 		SnapshotID: state.SnapshotID,
+		Options:    &forward.NQEQueryOptions{Limit: s.getQueryLimit(0)},
 	}
 
-	result, err := s.forwardClient.RunNQEQueryByID(params)
+	result, err := s.client().RunNQEQueryByID(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, describeNQERunError(err)
 	}
 
 	resultJSON, _ := json.MarshalIndent(result, "", "  ")
@@ -554,35 +905,38 @@ func (s *ForwardMCPService) executeSelectedQuery(sessionID string) (*mcp.ToolRes
 func (s *ForwardMCPService) listNetworks(args ListNetworksArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("list_networks", args, nil)
 
-	networks, err := s.forwardClient.GetNetworks()
+	networks, err := s.cachedNetworks(args.Refresh)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list networks: %w", err)
 	}
 
-	result, _ := json.MarshalIndent(networks, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Found %d networks:\n%s", len(networks), string(result)))), nil
+	names := make([]string, 0, len(networks))
+	for _, network := range networks {
+		names = append(names, fmt.Sprintf("%s (%s)", network.Name, network.ID))
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(fmt.Sprintf("Found %d networks", len(networks)), names, networks, args.Pretty))), nil
 }
 
 func (s *ForwardMCPService) createNetwork(args CreateNetworkArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("create_network", args, nil)
-	network, err := s.forwardClient.CreateNetwork(args.Name)
+	network, err := s.client().CreateNetwork(args.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create network: %w", err)
 	}
+	s.active().listCache.Invalidate(networksListKey)
 
-	result, _ := json.MarshalIndent(network, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Network created successfully:\n%s", string(result)))), nil
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail("Network created successfully", []string{fmt.Sprintf("%s (%s)", network.Name, network.ID)}, network, args.Pretty))), nil
 }
 
 func (s *ForwardMCPService) deleteNetwork(args DeleteNetworkArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("delete_network", args, nil)
-	network, err := s.forwardClient.DeleteNetwork(args.NetworkID)
+	network, err := s.client().DeleteNetwork(args.NetworkID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete network: %w", err)
 	}
+	s.active().listCache.Invalidate(networksListKey, locationsListKey(args.NetworkID))
 
-	result, _ := json.MarshalIndent(network, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Network deleted successfully:\n%s", string(result)))), nil
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail("Network deleted successfully", []string{fmt.Sprintf("%s (%s)", network.Name, network.ID)}, network, args.Pretty))), nil
 }
 
 func (s *ForwardMCPService) updateNetwork(args UpdateNetworkArgs) (*mcp.ToolResponse, error) {
@@ -595,40 +949,135 @@ func (s *ForwardMCPService) updateNetwork(args UpdateNetworkArgs) (*mcp.ToolResp
 		update.Description = &args.Description
 	}
 
-	network, err := s.forwardClient.UpdateNetwork(args.NetworkID, update)
+	network, err := s.client().UpdateNetwork(args.NetworkID, update)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update network: %w", err)
 	}
+	s.active().listCache.Invalidate(networksListKey)
 
-	result, _ := json.MarshalIndent(network, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Network updated successfully:\n%s", string(result)))), nil
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail("Network updated successfully", []string{fmt.Sprintf("%s (%s)", network.Name, network.ID)}, network, args.Pretty))), nil
+}
+
+// getNetworkSummary aggregates device, snapshot, and location counts into one overview.
+// Each section is gathered independently so a failing sub-call only degrades that section.
+func (s *ForwardMCPService) getNetworkSummary(args GetNetworkSummaryArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("get_network_summary", args, nil)
+
+	networkID := s.getNetworkID(args.NetworkID)
+	if networkID == "" {
+		return nil, fmt.Errorf("network_id is required (no default network is set)")
+	}
+
+	var failures []string
+	summary := fmt.Sprintf("📊 Network Summary: %s\n\n", networkID)
+
+	latest, err := s.latestSnapshot(networkID)
+	if errors.Is(err, forward.ErrNoProcessedSnapshot) {
+		summary += "• Latest snapshot: none yet (trigger collection first)\n"
+	} else if err != nil || latest == nil {
+		failures = append(failures, fmt.Sprintf("latest snapshot: %v", err))
+		summary += "• Latest snapshot: unavailable\n"
+	} else {
+		s.observeSnapshot(networkID, latest)
+		summary += fmt.Sprintf("• Latest snapshot: %s", latest.ID)
+		if latest.State != "" {
+			summary += fmt.Sprintf(" (%s)", latest.State)
+		}
+		if taken := snapshotTime(latest); !taken.IsZero() {
+			summary += fmt.Sprintf(", taken %s", taken.UTC().Format(time.RFC3339))
+		}
+		summary += "\n"
+	}
+
+	// The API reports no total, so the whole device list is fetched and counted
+	deviceParams := &forward.DeviceQueryParams{}
+	if latest != nil {
+		deviceParams.SnapshotID = latest.ID
+	}
+	devices, err := s.client().GetDevices(networkID, deviceParams)
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("devices: %v", err))
+		summary += "• Devices: unavailable\n"
+	} else {
+		summary += fmt.Sprintf("• Devices: %d\n", devices.TotalCount)
+	}
+
+	snapshots, err := s.client().GetSnapshots(networkID)
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("snapshots: %v", err))
+		summary += "• Snapshots: unavailable\n"
+	} else {
+		processed := filterSnapshots(snapshots, "", false)
+		summary += fmt.Sprintf("• Snapshots: %d (%d excluding drafts)\n", len(snapshots), len(processed))
+	}
+
+	locations, err := s.client().GetLocations(networkID)
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("locations: %v", err))
+		summary += "• Locations: unavailable\n"
+	} else {
+		summary += fmt.Sprintf("• Locations: %d\n", len(locations))
+	}
+
+	if len(failures) == 4 {
+		return nil, fmt.Errorf("failed to get network summary: %s", strings.Join(failures, "; "))
+	}
+	if len(failures) > 0 {
+		s.logger.Warn("Partial network summary for %s: %s", networkID, strings.Join(failures, "; "))
+		summary += "\n⚠️  Some sections could not be retrieved:\n"
+		for _, failure := range failures {
+			summary += fmt.Sprintf("  - %s\n", failure)
+		}
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(summary)), nil
+}
+
+// pathSearchSnapshot resolves the snapshot a path search runs against; with no snapshot
+// given or set as default, it fetches the latest snapshot for the network
+func (s *ForwardMCPService) pathSearchSnapshot(networkID, snapshotID string) (string, error) {
+	snapshotID = s.getSnapshotID(snapshotID)
+	if snapshotID != "" && snapshotID != "latest" {
+		return s.resolveSnapshotID(networkID, snapshotID)
+	}
+	s.logger.Info("searchPaths - No snapshot ID provided or in defaults, fetching latest snapshot for network %s", networkID)
+
+	snapshot, err := s.latestSnapshot(networkID)
+	if err != nil {
+		s.logger.Error("Failed to fetch latest snapshot for network %s: %v", networkID, err)
+		return "", latestSnapshotError(networkID, err)
+	}
+	if snapshot == nil || snapshot.ID == "" {
+		s.logger.Warn("No valid snapshot found for network %s", networkID)
+		return "", fmt.Errorf("no valid snapshot found for network %s - ensure the network has been processed", networkID)
+	}
+
+	s.observeSnapshot(networkID, snapshot)
+	s.logger.Info("searchPaths - Using latest snapshot ID: %s", snapshot.ID)
+	return snapshot.ID, nil
 }
 
 // Path Search Tool Implementations
 func (s *ForwardMCPService) searchPaths(args SearchPathsArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("search_paths", args, nil)
 
+	if err := validateSearchPathsArgs(&args); err != nil {
+		return nil, fmt.Errorf("invalid path search arguments: %w", err)
+	}
+
 	// Use defaults if not specified (like other functions do)
 	networkID := s.getNetworkID(args.NetworkID)
-	snapshotID := s.getSnapshotID(args.SnapshotID)
-
-	// If no snapshot ID is available, fetch the latest snapshot for the network
-	if snapshotID == "" || snapshotID == "latest" {
-		s.logger.Info("searchPaths - No snapshot ID provided or in defaults, fetching latest snapshot for network %s", networkID)
+	snapshotID, err := s.pathSearchSnapshot(networkID, args.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
 
-		snapshot, err := s.forwardClient.GetLatestSnapshot(networkID)
+	if args.From != "" {
+		from, err := s.resolvePathSource(networkID, snapshotID, args.From)
 		if err != nil {
-			s.logger.Error("Failed to fetch latest snapshot for network %s: %v", networkID, err)
-			return nil, fmt.Errorf("failed to get latest snapshot for network %s: %w", networkID, err)
-		}
-
-		if snapshot != nil && snapshot.ID != "" {
-			snapshotID = snapshot.ID
-			s.logger.Info("searchPaths - Using latest snapshot ID: %s", snapshotID)
-		} else {
-			s.logger.Warn("No valid snapshot found for network %s", networkID)
-			return nil, fmt.Errorf("no valid snapshot found for network %s - ensure the network has been processed", networkID)
+			return nil, err
 		}
+		args.From = from
 	}
 
 	s.logger.Debug("Path search: networkID=%s, snapshotID=%s, srcIP=%s, dstIP=%s",
@@ -642,6 +1091,9 @@ func (s *ForwardMCPService) searchPaths(args SearchPathsArgs) (*mcp.ToolResponse
 		SrcPort:                 args.SrcPort,
 		DstPort:                 args.DstPort,
 		MaxResults:              args.MaxResults,
+		MaxCandidates:           args.MaxCandidates,
+		MaxReturnPathResults:    args.MaxReturnPathResults,
+		MaxSeconds:              args.MaxSeconds,
 		IncludeNetworkFunctions: args.IncludeNetworkFunctions,
 		SnapshotID:              snapshotID, // Now uses latest snapshot if not provided
 	}
@@ -649,8 +1101,12 @@ func (s *ForwardMCPService) searchPaths(args SearchPathsArgs) (*mcp.ToolResponse
 	if args.IPProto != 0 {
 		params.IPProto = &args.IPProto
 	}
+	s.applyPathSearchDefaults(params)
+	if args.IncludeReturnPath && params.MaxReturnPathResults == 0 {
+		params.MaxReturnPathResults = params.MaxResults
+	}
 
-	response, err := s.forwardClient.SearchPaths(networkID, params)
+	response, err := s.client().SearchPaths(networkID, params)
 	if err != nil {
 		s.logger.Error("Path search failed: %v", err)
 		return nil, fmt.Errorf("failed to search paths: %w", err)
@@ -659,7 +1115,7 @@ func (s *ForwardMCPService) searchPaths(args SearchPathsArgs) (*mcp.ToolResponse
 	s.logger.Debug("Path search completed: found %d paths, searchTime=%dms, candidates=%d, snapshotID=%s",
 		len(response.Paths), response.SearchTimeMs, response.NumCandidatesFound, response.SnapshotID)
 
-	result, _ := json.MarshalIndent(response, "", "  ")
+	s.active().pathSearches.Track(networkID, snapshotID, args, response)
 
 	// Enhanced response with debugging info
 	debugInfo := ""
@@ -673,19 +1129,38 @@ func (s *ForwardMCPService) searchPaths(args SearchPathsArgs) (*mcp.ToolResponse
 		debugInfo += fmt.Sprintf("\n💡 No candidates found for source IP %s - this IP might not exist in the network topology\n", args.SrcIP)
 	}
 
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Path search completed. Found %d paths:%s\n%s", len(response.Paths), debugInfo, string(result)))), nil
+	outcomes := formatPathClassifications(response.Paths)
+	if args.Explain {
+		source := args.SrcIP
+		if source == "" {
+			source = args.From
+		}
+		outcomes += formatPathExplanations(response.Paths, source, args.DstIP)
+	}
+	if args.IncludeNetworkFunctions {
+		outcomes += formatHopFunctions(response.Paths)
+	}
+	if args.IncludeReturnPath {
+		outcomes += formatReturnPaths(response.Paths, response.ReturnPaths)
+	}
+
+	if s.summaryMode() {
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Path search completed. Found %d paths (snapshot %s).%s", len(response.Paths), response.SnapshotID, outcomes))), nil
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Path search completed. Found %d paths:%s%s\n%s", len(response.Paths), debugInfo, outcomes, s.toJSON(response, args.Pretty)))), nil
 }
 
-// Helper function to convert service NQEQueryOptions to forward NQEQueryOptions
-func (s *ForwardMCPService) convertNQEQueryOptions(options *NQEQueryOptions) *forward.NQEQueryOptions {
+// Helper function to convert service NQEQueryOptions to forward NQEQueryOptions, applying
+// the default query limit when none is set and rejecting limits above the cap
+func (s *ForwardMCPService) convertNQEQueryOptions(options *NQEQueryOptions) (*forward.NQEQueryOptions, error) {
 	if options == nil {
-		return nil
+		options = &NQEQueryOptions{}
 	}
 
-	// Apply default limit if not specified
-	limit := options.Limit
-	if limit == 0 {
-		limit = s.getQueryLimit(0)
+	limit, err := s.nqeRowLimit(options.Limit)
+	if err != nil {
+		return nil, err
 	}
 
 	forwardOptions := &forward.NQEQueryOptions{
@@ -693,6 +1168,9 @@ func (s *ForwardMCPService) convertNQEQueryOptions(options *NQEQueryOptions) *fo
 		Offset: options.Offset,
 		Format: options.Format,
 	}
+	if forwardOptions.Format == "" && s.defaults != nil {
+		forwardOptions.Format = s.defaults.DefaultFormat
+	}
 
 	if options.SortBy != nil {
 		forwardOptions.SortBy = make([]forward.NQESortBy, len(options.SortBy))
@@ -714,42 +1192,215 @@ func (s *ForwardMCPService) convertNQEQueryOptions(options *NQEQueryOptions) *fo
 		}
 	}
 
-	return forwardOptions
+	return forwardOptions, nil
 }
 
 // NQE Tool Implementations
 func (s *ForwardMCPService) runNQEQueryByID(args RunNQEQueryByIDArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("run_nqe_query_by_id", args, nil)
 
+	if args.Preview {
+		return s.previewNQEQuery(args)
+	}
+
+	args, sizeNote, err := s.nqeSizeAdvice(args)
+	if err != nil {
+		return nil, err
+	}
+	params, result, cachedAt, err := s.fetchNQEResult(args)
+	if err != nil {
+		return nil, err
+	}
+	if result, err = flattenNQEResult(result, args.Flatten, args.FlattenDepth); err != nil {
+		return nil, err
+	}
+	result, filterNote, err := applyNQEPostFilters(result, args.PostFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(cacheBypassNote(args.NoCache) + sizeNote + filterNote + s.formatNQEResult(params, result, cachedAt, args.Columns, args.Pretty))), nil
+}
+
+// cacheBypassNote tells the caller that results were run live because no_cache was set
+func cacheBypassNote(noCache bool) string {
+	if !noCache {
+		return ""
+	}
+	return "Cache bypassed (no_cache): results were run live and not cached.\n\n"
+}
+
+// fetchNQEResult runs a predefined NQE query, serving it from the cache when possible.
+// The returned time is when a cached result was stored, or zero for a fresh result.
+func (s *ForwardMCPService) fetchNQEResult(args RunNQEQueryByIDArgs) (*forward.NQEQueryParams, *forward.NQERunResult, time.Time, error) {
+	if err := s.checkNQEPolicy(args.QueryID); err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	if _, err := compileNQEPostFilters(args.PostFilters); err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	if args.Flatten {
+		if _, err := nqeFlattenDepth(args.FlattenDepth); err != nil {
+			return nil, nil, time.Time{}, err
+		}
+	}
+
 	// Use defaults if not specified
 	networkID := s.getNetworkID(args.NetworkID)
-	snapshotID := s.getSnapshotID(args.SnapshotID)
+	snapshotID, err := s.resolveSnapshotID(networkID, s.getSnapshotID(args.SnapshotID))
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	parameters, err := s.typedNQEParameters(args.QueryID, args.Parameters)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	options, err := s.convertNQEQueryOptions(args.Options)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
 
 	params := &forward.NQEQueryParams{
 		NetworkID:  networkID,
 		QueryID:    args.QueryID,
 		SnapshotID: snapshotID,
-		Parameters: args.Parameters,
-		Options:    s.convertNQEQueryOptions(args.Options),
+		Parameters: parameters,
+		Options:    options,
+	}
+
+	instance := s.active()
+	var result *forward.NQERunResult
+	var cachedAt time.Time
+
+	useCache := s.config != nil && s.config.Forward.SemanticCache.Enabled && !args.NoCache
+	cacheSnapshotID := snapshotID
+	if useCache && cacheSnapshotID == "" {
+		cacheSnapshotID = s.latestSnapshotCacheID(networkID)
+		useCache = cacheSnapshotID != ""
 	}
 
-	// Ensure we have options even if none were provided
-	if params.Options == nil {
-		params.Options = &forward.NQEQueryOptions{
-			Limit: s.getQueryLimit(0),
+	// Pages are keyed by the resolved snapshot too, so a new snapshot starts a new result
+	pageParams := *params
+	pageParams.SnapshotID = cacheSnapshotID
+	if useCache {
+		if page, storedAt, found := instance.nqePages.Get(&pageParams); found {
+			s.logger.Debug("NQE page served from the page cache with %d items", len(page.Items))
+			return params, page, storedAt, nil
+		}
+	}
+	if useCache && s.semanticCache != nil {
+		if entry, found := s.semanticCache.GetNQEResult(params.QueryID, params.Parameters, params.Options, networkID, cacheSnapshotID); found {
+			result = entry.Result
+			cachedAt = entry.Timestamp
 		}
 	}
 
-	result, err := s.forwardClient.RunNQEQueryByID(params)
-	if err != nil {
-		s.logToolCall("run_nqe_query_by_id", args, err)
-		return nil, fmt.Errorf("failed to run NQE query: %w", err)
+	if result == nil {
+		var err error
+		started := time.Now()
+		result, err = instance.client.RunNQEQueryByID(params)
+		if err != nil {
+			s.logToolCall("run_nqe_query_by_id", args, err)
+			return nil, nil, time.Time{}, describeNQERunError(err)
+		}
+		instance.queryRuntimes.Record(params.QueryID, time.Since(started))
+		instance.resultSizes.Record(networkID, params.QueryID, result.Items)
+		if useCache && s.semanticCache != nil {
+			s.semanticCache.PutNQEResult(params.QueryID, params.Parameters, params.Options, networkID, cacheSnapshotID, result)
+		}
+	}
+	if useCache {
+		instance.nqePages.Store(&pageParams, result)
 	}
 
-	resultJSON, _ := json.MarshalIndent(result, "", "  ")
 	s.logger.Debug("NQE query completed with %d items", len(result.Items))
+	return params, result, cachedAt, nil
+}
+
+// latestSnapshotCacheID resolves the snapshot a call without a snapshot ID runs against,
+// so its cached result is keyed by that snapshot and a newly processed one is never
+// answered from the previous. It returns "" when the snapshot cannot be resolved and the
+// result must not be cached.
+func (s *ForwardMCPService) latestSnapshotCacheID(networkID string) string {
+	snapshot, err := s.latestSnapshot(networkID)
+	if err != nil || snapshot == nil || snapshot.ID == "" {
+		s.logger.Debug("Not caching NQE result: latest snapshot of network %s is unknown: %v", networkID, err)
+		return ""
+	}
+	return snapshot.ID
+}
 
-	response := fmt.Sprintf("NQE query completed. Found %d items:\n%s\n\n", len(result.Items), string(resultJSON))
+// describeNQERunError words a failed NQE run for the caller: an unknown query ID points at
+// search_nqe_queries, an execution failure keeps the API's detail, and anything else is a
+// generic run failure
+func describeNQERunError(err error) error {
+	var execErr *forward.NQEExecutionError
+	switch {
+	case errors.Is(err, forward.ErrNQEQueryNotFound):
+		return fmt.Errorf("%w; use search_nqe_queries to find a valid query ID", err)
+	case errors.As(err, &execErr):
+		return err
+	default:
+		return fmt.Errorf("failed to run NQE query: %w", err)
+	}
+}
+
+// formatNQEResult renders an NQE result at the session's response detail level, with
+// pretty overriding the JSON format. When columns is set, rows are projected to those
+// columns in that order and unknown columns are reported as a warning.
+func (s *ForwardMCPService) formatNQEResult(params *forward.NQEQueryParams, result *forward.NQERunResult, cachedAt time.Time, columns []string, pretty *bool) string {
+	if len(result.Items) == 0 {
+		return "NQE query completed. Found 0 items.\n\n" + emptyNQEResultGuidance(params, result)
+	}
+
+	items := result.Items
+	var payload interface{} = result
+	var warning string
+	resultColumns := nqeResultColumns(items)
+	if len(columns) > 0 {
+		var unknown []string
+		items, resultColumns, unknown = projectNQEColumns(result.Items, columns)
+		if len(unknown) > 0 {
+			warning = fmt.Sprintf("⚠️  Unknown columns ignored: %s (available: %s)\n", strings.Join(unknown, ", "), strings.Join(nqeResultColumns(result.Items), ", "))
+		}
+	}
+	if s.defaults != nil && s.defaults.NormalizeNQEUnits {
+		items, resultColumns = NormalizeNQEUnits(items, resultColumns)
+		payload = &forward.NQERunResult{SnapshotID: result.SnapshotID, Items: items}
+	}
+	if len(columns) > 0 {
+		rows := make([]orderedNQERow, len(items))
+		for i, item := range items {
+			rows[i] = orderedNQERow{columns: resultColumns, values: item}
+		}
+		payload = projectedNQEResult{SnapshotID: result.SnapshotID, Items: rows}
+	}
+
+	if s.summaryMode() {
+		response := fmt.Sprintf("NQE query completed. Found %d items", len(items))
+		if len(resultColumns) > 0 {
+			response += fmt.Sprintf("; columns: %s", strings.Join(resultColumns, ", "))
+		}
+		if !cachedAt.IsZero() {
+			response += "; served from cache"
+		}
+		if warning != "" {
+			response += "\n" + strings.TrimSuffix(warning, "\n")
+		}
+		return response
+	}
+
+	response := fmt.Sprintf("NQE query completed. Found %d items:\n", len(items)) + warning
+	if s.defaults == nil || !s.defaults.HideNQESchema {
+		response += formatNQESchema(InferNQESchema(items))
+	}
+	response += fmt.Sprintf("%s\n\n", s.toJSON(payload, pretty))
+
+	if !cachedAt.IsZero() {
+		response += fmt.Sprintf("Served from cache (cached %s ago).\n\n", time.Since(cachedAt).Round(time.Second))
+	}
 
 	// Add helpful suggestions for predefined queries
 	response += "Would you like to:\n" +
@@ -757,20 +1408,29 @@ func (s *ForwardMCPService) runNQEQueryByID(args RunNQEQueryByIDArgs) (*mcp.Tool
 		"2. Create a custom query?\n" +
 		"3. Export these results?"
 
-	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+	return response
 }
 
 func (s *ForwardMCPService) listNQEQueries(args ListNQEQueriesArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("list_nqe_queries", args, nil)
 
-	queries, err := s.forwardClient.GetNQEQueries(args.Directory)
+	queries, err := s.client().GetNQEQueries(args.Directory)
 	if err != nil {
 		s.logToolCall("list_nqe_queries", args, err)
 		return nil, fmt.Errorf("failed to list NQE queries: %w", err)
 	}
+	if s.nqePolicy != nil {
+		allowed := make([]forward.NQEQuery, 0, len(queries))
+		for _, query := range queries {
+			if s.nqePolicy.Allows(query.Path) {
+				allowed = append(allowed, query)
+			}
+		}
+		queries = allowed
+	}
 
 	// Format the response with proper JSON structure
-	result, err := json.MarshalIndent(queries, "", "  ")
+	result, err := MarshalJSON(queries, s.jsonMode(args.Pretty))
 	if err != nil {
 		s.logger.Error("Failed to marshal queries: %v", err)
 		return nil, fmt.Errorf("failed to format query results: %w", err)
@@ -778,6 +1438,14 @@ func (s *ForwardMCPService) listNQEQueries(args ListNQEQueriesArgs) (*mcp.ToolRe
 
 	s.logger.Debug("Found %d valid NQE queries", len(queries))
 
+	if s.summaryMode() {
+		paths := make([]string, 0, len(queries))
+		for _, query := range queries {
+			paths = append(paths, query.Path)
+		}
+		return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(fmt.Sprintf("Found %d NQE queries", len(queries)), paths, queries, args.Pretty))), nil
+	}
+
 	// Build a helpful response message
 	response := fmt.Sprintf("Found %d NQE queries:\n%s\n\n", len(queries), string(result))
 
@@ -815,65 +1483,134 @@ func (s *ForwardMCPService) listDevices(args ListDevicesArgs) (*mcp.ToolResponse
 		limit = s.getQueryLimit(0)
 	}
 
+	snapshotID, err := s.resolveSnapshotID(args.NetworkID, args.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := pageStart(args.Cursor, args.Offset, args.NetworkID, snapshotID, "")
+	if err != nil {
+		return nil, err
+	}
+
 	params := &forward.DeviceQueryParams{
-		SnapshotID: args.SnapshotID,
+		SnapshotID: snapshotID,
 		Limit:      limit,
-		Offset:     args.Offset,
+		Offset:     offset,
 	}
 
-	response, err := s.forwardClient.GetDevices(args.NetworkID, params)
+	response, err := s.client().GetDevices(args.NetworkID, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list devices: %w", err)
 	}
 
-	result, _ := json.MarshalIndent(response, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Found %d devices (total: %d):\n%s", len(response.Devices), response.TotalCount, string(result)))), nil
+	names := make([]string, 0, len(response.Devices))
+	for _, device := range response.Devices {
+		names = append(names, device.Name)
+	}
+	text := s.formatDetail(fmt.Sprintf("Found %d devices (total: %d)", len(response.Devices), response.TotalCount), names, response, args.Pretty)
+	page := withNextCursor(newPageInfo(offset, limit, len(response.Devices)), args.NetworkID, snapshotID, "")
+	return mcp.NewToolResponse(mcp.NewTextContent(withPageTrailer(text, page))), nil
 }
 
 func (s *ForwardMCPService) getDeviceLocations(args GetDeviceLocationsArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("get_device_locations", args, nil)
-	locations, err := s.forwardClient.GetDeviceLocations(args.NetworkID)
+	locations, err := s.client().GetDeviceLocations(args.NetworkID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get device locations: %w", err)
 	}
 
-	result, _ := json.MarshalIndent(locations, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Device locations:\n%s", string(result)))), nil
+	assignments := make([]string, 0, len(locations))
+	for _, device := range sortedKeys(locations) {
+		assignments = append(assignments, fmt.Sprintf("%s → %s", device, locations[device]))
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(fmt.Sprintf("Device locations (%d devices)", len(locations)), assignments, locations, args.Pretty))), nil
 }
 
 // Snapshot Management Tool Implementations
 func (s *ForwardMCPService) listSnapshots(args ListSnapshotsArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("list_snapshots", args, nil)
-	snapshots, err := s.forwardClient.GetSnapshots(args.NetworkID)
+	snapshots, err := s.client().GetSnapshots(args.NetworkID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list snapshots: %w", err)
 	}
 
-	result, _ := json.MarshalIndent(snapshots, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Found %d snapshots:\n%s", len(snapshots), string(result)))), nil
+	includeDrafts := args.IncludeDrafts == nil || *args.IncludeDrafts
+	filterHash := cursor.HashFilters(map[string]string{
+		"state":          strings.ToUpper(args.State),
+		"include_drafts": strconv.FormatBool(includeDrafts),
+	})
+	offset, err := pageStart(args.Cursor, args.Offset, args.NetworkID, "", filterHash)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := filterSnapshots(snapshots, args.State, includeDrafts)
+	start, end := paginate(len(filtered), offset, args.Limit)
+	page := filtered[start:end]
+
+	ids := make([]string, 0, len(page))
+	for _, snapshot := range page {
+		ids = append(ids, snapshot.ID)
+	}
+	text := s.formatDetail(fmt.Sprintf("Found %d snapshots (%d total before filtering)", len(filtered), len(snapshots)), ids, page, args.Pretty)
+	return mcp.NewToolResponse(mcp.NewTextContent(withPageTrailer(text, withNextCursor(newPageInfoOfTotal(start, len(page), len(filtered)), args.NetworkID, "", filterHash)))), nil
+}
+
+// filterSnapshots keeps the snapshots in state (any state when empty), dropping drafts
+// unless includeDrafts is set, and orders them newest-first
+func filterSnapshots(snapshots []forward.Snapshot, state string, includeDrafts bool) []forward.Snapshot {
+	filtered := make([]forward.Snapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if state != "" && !strings.EqualFold(snapshot.State, state) {
+			continue
+		}
+		if snapshot.IsDraft && !includeDrafts {
+			continue
+		}
+		filtered = append(filtered, snapshot)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].CreationDateMillis > filtered[j].CreationDateMillis
+	})
+	return filtered
 }
 
 func (s *ForwardMCPService) getLatestSnapshot(args GetLatestSnapshotArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("get_latest_snapshot", args, nil)
-	snapshot, err := s.forwardClient.GetLatestSnapshot(args.NetworkID)
+	snapshot, err := s.client().GetLatestSnapshot(args.NetworkID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest snapshot: %w", err)
+		return nil, latestSnapshotError(args.NetworkID, err)
 	}
+	s.active().latestSnapshots.Store(args.NetworkID, snapshot)
+	s.observeSnapshot(args.NetworkID, snapshot)
+
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail("Latest snapshot", []string{fmt.Sprintf("%s (%s)", snapshot.ID, snapshot.State)}, snapshot, args.Pretty))), nil
+}
 
-	result, _ := json.MarshalIndent(snapshot, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Latest snapshot:\n%s", string(result)))), nil
+// latestSnapshotError explains a failed latest-snapshot lookup, turning the case of a
+// network that was never processed into guidance instead of a raw API error
+func latestSnapshotError(networkID string, err error) error {
+	if errors.Is(err, forward.ErrNoProcessedSnapshot) {
+		return fmt.Errorf("network %s has no processed snapshots yet; trigger collection first and retry once processing completes", networkID)
+	}
+	return fmt.Errorf("failed to get latest snapshot: %w", err)
 }
 
 // Location Management Tool Implementations
 func (s *ForwardMCPService) listLocations(args ListLocationsArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("list_locations", args, nil)
-	locations, err := s.forwardClient.GetLocations(args.NetworkID)
+	locations, err := s.cachedLocations(args.NetworkID, args.Refresh)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list locations: %w", err)
 	}
 
-	result, _ := json.MarshalIndent(locations, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Found %d locations:\n%s", len(locations), string(result)))), nil
+	names := make([]string, 0, len(locations))
+	for _, location := range locations {
+		names = append(names, location.Name)
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(fmt.Sprintf("Found %d locations", len(locations)), names, locations, args.Pretty))), nil
 }
 
 func (s *ForwardMCPService) createLocation(args CreateLocationArgs) (*mcp.ToolResponse, error) {
@@ -885,18 +1622,18 @@ func (s *ForwardMCPService) createLocation(args CreateLocationArgs) (*mcp.ToolRe
 		Longitude:   args.Longitude,
 	}
 
-	newLocation, err := s.forwardClient.CreateLocation(args.NetworkID, location)
+	newLocation, err := s.client().CreateLocation(args.NetworkID, location)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create location: %w", err)
 	}
+	s.active().listCache.Invalidate(locationsListKey(args.NetworkID))
 
-	result, _ := json.MarshalIndent(newLocation, "", "  ")
-	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Location created successfully:\n%s", string(result)))), nil
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail("Location created successfully", []string{fmt.Sprintf("%s (%s)", newLocation.Name, newLocation.ID)}, newLocation, args.Pretty))), nil
 }
 
 // resolveNetworkIDByName resolves a network name to its networkId using a case-insensitive match.
 func (s *ForwardMCPService) resolveNetworkIDByName(name string) (string, error) {
-	networks, err := s.forwardClient.GetNetworks()
+	networks, err := s.client().GetNetworks()
 	if err != nil {
 		return "", err
 	}
@@ -921,10 +1658,17 @@ func (s *ForwardMCPService) getDeviceBasicInfo(args GetDeviceBasicInfoArgs) (*mc
 	s.logToolCall("get_device_basic_info", args, nil)
 
 	queryArgs := RunNQEQueryByIDArgs{
-		NetworkID:  args.NetworkID,
-		SnapshotID: args.SnapshotID,
-		QueryID:    "FQ_ac651cb2901b067fe7dbfb511613ab44776d8029", // Device Basic Info
-		Options:    args.Options,
+		NetworkID:    args.NetworkID,
+		SnapshotID:   args.SnapshotID,
+		QueryID:      "FQ_ac651cb2901b067fe7dbfb511613ab44776d8029", // Device Basic Info
+		Options:      args.Options,
+		Columns:      args.Columns,
+		Pretty:       args.Pretty,
+		NoCache:      args.NoCache,
+		PostFilters:  args.PostFilters,
+		Preview:      args.Preview,
+		Flatten:      args.Flatten,
+		FlattenDepth: args.FlattenDepth,
 	}
 
 	return s.runNQEQueryByID(queryArgs)
@@ -934,39 +1678,60 @@ func (s *ForwardMCPService) getDeviceHardware(args GetDeviceHardwareArgs) (*mcp.
 	s.logToolCall("get_device_hardware", args, nil)
 
 	queryArgs := RunNQEQueryByIDArgs{
-		NetworkID:  args.NetworkID,
-		SnapshotID: args.SnapshotID,
-		QueryID:    "FQ_7ec4a8148b48a91271f342c512b2af1cdb276744", // Device Hardware
-		Options:    args.Options,
-	}
-
-	return s.runNQEQueryByID(queryArgs)
+		NetworkID:    args.NetworkID,
+		SnapshotID:   args.SnapshotID,
+		QueryID:      "FQ_7ec4a8148b48a91271f342c512b2af1cdb276744", // Device Hardware
+		Options:      args.Options,
+		Columns:      args.Columns,
+		Pretty:       args.Pretty,
+		NoCache:      args.NoCache,
+		PostFilters:  args.PostFilters,
+		Preview:      args.Preview,
+		Flatten:      args.Flatten,
+		FlattenDepth: args.FlattenDepth,
+	}
+
+	return s.runLifecycleQuery(queryArgs)
 }
 
 func (s *ForwardMCPService) getHardwareSupport(args GetHardwareSupportArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("get_hardware_support", args, nil)
 
 	queryArgs := RunNQEQueryByIDArgs{
-		NetworkID:  args.NetworkID,
-		SnapshotID: args.SnapshotID,
-		QueryID:    "FQ_f0984b777b940b4376ed3ec4317ad47437426e7c", // Hardware Support
-		Options:    args.Options,
-	}
-
-	return s.runNQEQueryByID(queryArgs)
+		NetworkID:    args.NetworkID,
+		SnapshotID:   args.SnapshotID,
+		QueryID:      "FQ_f0984b777b940b4376ed3ec4317ad47437426e7c", // Hardware Support
+		Options:      args.Options,
+		Columns:      args.Columns,
+		Pretty:       args.Pretty,
+		NoCache:      args.NoCache,
+		PostFilters:  args.PostFilters,
+		Preview:      args.Preview,
+		Flatten:      args.Flatten,
+		FlattenDepth: args.FlattenDepth,
+	}
+
+	return s.runLifecycleQuery(queryArgs)
 }
 
 func (s *ForwardMCPService) getOSSupport(args GetOSSupportArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("get_os_support", args, nil)
 
 	queryArgs := RunNQEQueryByIDArgs{
-		NetworkID:  args.NetworkID,
-		SnapshotID: args.SnapshotID,
-		QueryID:    "FQ_fc33d9fd70ba19a18455b0e4d26ca8420003d9cc", // OS Support
-		Options:    args.Options,
-	}
-
-	return s.runNQEQueryByID(queryArgs)
+		NetworkID:    args.NetworkID,
+		SnapshotID:   args.SnapshotID,
+		QueryID:      "FQ_fc33d9fd70ba19a18455b0e4d26ca8420003d9cc", // OS Support
+		Options:      args.Options,
+		Columns:      args.Columns,
+		Pretty:       args.Pretty,
+		NoCache:      args.NoCache,
+		PostFilters:  args.PostFilters,
+		Preview:      args.Preview,
+		Flatten:      args.Flatten,
+		FlattenDepth: args.FlattenDepth,
+	}
+
+	return s.runLifecycleQuery(queryArgs)
 }
 
 func (s *ForwardMCPService) searchConfigs(args SearchConfigsArgs) (*mcp.ToolResponse, error) {
@@ -975,11 +1740,18 @@ func (s *ForwardMCPService) searchConfigs(args SearchConfigsArgs) (*mcp.ToolResp
 	queryArgs := RunNQEQueryByIDArgs{
 		NetworkID:  args.NetworkID,
 		SnapshotID: args.SnapshotID,
-		QueryID:    "FQ_e636c47826ad7144f09eaf6bc14dfb0b560e7cc9", // Config Search
+		QueryID:    configSearchQueryID,
 		Parameters: map[string]interface{}{
 			"searchPattern": args.SearchTerm,
 		},
-		Options: args.Options,
+		Options:      args.Options,
+		Columns:      args.Columns,
+		Pretty:       args.Pretty,
+		NoCache:      args.NoCache,
+		PostFilters:  args.PostFilters,
+		Preview:      args.Preview,
+		Flatten:      args.Flatten,
+		FlattenDepth: args.FlattenDepth,
 	}
 
 	return s.runNQEQueryByID(queryArgs)
@@ -988,17 +1760,28 @@ func (s *ForwardMCPService) searchConfigs(args SearchConfigsArgs) (*mcp.ToolResp
 func (s *ForwardMCPService) getConfigDiff(args GetConfigDiffArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("get_config_diff", args, nil)
 
+	afterSnapshot, err := s.resolveSnapshotID(s.getNetworkID(args.NetworkID), args.AfterSnapshot)
+	if err != nil {
+		return nil, err
+	}
 	params := map[string]interface{}{}
-	if args.AfterSnapshot != "" {
-		params["compareSnapshotId"] = args.AfterSnapshot
+	if afterSnapshot != "" {
+		params["compareSnapshotId"] = afterSnapshot
 	}
 
 	queryArgs := RunNQEQueryByIDArgs{
-		NetworkID:  args.NetworkID,
-		SnapshotID: args.BeforeSnapshot,
-		QueryID:    "FQ_51f090cbea069b4049eb283716ab3bbb3f578aea", // Config Diff
-		Parameters: params,
-		Options:    args.Options,
+		NetworkID:    args.NetworkID,
+		SnapshotID:   args.BeforeSnapshot,
+		QueryID:      configDiffQueryID,
+		Parameters:   params,
+		Options:      args.Options,
+		Columns:      args.Columns,
+		Pretty:       args.Pretty,
+		NoCache:      args.NoCache,
+		PostFilters:  args.PostFilters,
+		Preview:      args.Preview,
+		Flatten:      args.Flatten,
+		FlattenDepth: args.FlattenDepth,
 	}
 
 	return s.runNQEQueryByID(queryArgs)
@@ -1009,13 +1792,16 @@ func (s *ForwardMCPService) getConfigDiff(args GetConfigDiffArgs) (*mcp.ToolResp
 func (s *ForwardMCPService) getDefaultSettings(args GetDefaultSettingsArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("get_default_settings", args, nil)
 
+	defaultNetworkID, defaultSnapshotID := s.defaults.Scope()
+
 	// Get network name if possible
 	networkName := "Not set"
-	if s.defaults.NetworkID != "" {
-		networks, err := s.forwardClient.GetNetworks()
+	if defaultNetworkID != "" {
+		networkName = defaultNetworkID + " (name unavailable)"
+		networks, err := s.client().GetNetworks()
 		if err == nil {
 			for _, network := range networks {
-				if network.ID == s.defaults.NetworkID {
+				if network.ID == defaultNetworkID {
 					networkName = fmt.Sprintf("%s (%s)", network.Name, network.ID)
 					break
 				}
@@ -1023,29 +1809,75 @@ func (s *ForwardMCPService) getDefaultSettings(args GetDefaultSettingsArgs) (*mc
 		}
 	}
 
+	effectiveSnapshot := defaultSnapshotID
+	if effectiveSnapshot == "" {
+		effectiveSnapshot = "latest"
+	}
+
 	settings := map[string]interface{}{
-		"default_network_id":   s.defaults.NetworkID,
+		"default_network_id":   defaultNetworkID,
 		"default_network_name": networkName,
-		"default_snapshot_id":  s.defaults.SnapshotID,
+		"default_snapshot_id":  defaultSnapshotID,
+		"effective_snapshot":   effectiveSnapshot,
 		"default_query_limit":  s.defaults.QueryLimit,
+		"max_query_limit":      s.defaults.MaxQueryLimit,
+		"default_format":       s.defaults.DefaultFormat,
+		"nqe_response_budget":  s.defaults.NQEResponseBudgetBytes,
+		"nqe_auto_limit":       s.defaults.NQEAutoLimit,
+		"path_search_limits":   s.pathSearchDefaults(),
+		"response_detail":      s.responseDetail(),
+		"include_nqe_schema":   !s.defaults.HideNQESchema,
+		"normalize_units":      s.defaults.NormalizeNQEUnits,
+		"embedding_provider":   s.embeddingProvider(),
+		"semantic_cache":       s.cacheSettings(),
 		"environment_source":   "Loaded from environment variables and config files",
 	}
 
-	result, _ := json.MarshalIndent(settings, "", "  ")
-
-	response := fmt.Sprintf("Current default settings:\n%s\n\n", string(result))
+	response := fmt.Sprintf("Network: %s\nSnapshot: %s\nQuery limit: %d\nResponse detail: %s\nEmbedding provider: %s\n\n",
+		networkName, effectiveSnapshot, s.defaults.QueryLimit, s.responseDetail(), s.embeddingProvider())
+	response += fmt.Sprintf("Current default settings:\n%s\n\n", s.toJSON(settings, args.Pretty))
 	response += "To change defaults:\n"
 	response += "• Use set_default_network to change the default network\n"
+	response += "• Use set_default_settings to switch response_detail between full and summary\n"
 	response += "• Update environment variables (FORWARD_DEFAULT_NETWORK_ID, etc.)\n"
 	response += "• Modify your .env file or config.json\n\n"
 
-	if s.defaults.NetworkID == "" {
+	if defaultNetworkID == "" {
 		response += " No default network is set. Consider setting FORWARD_DEFAULT_NETWORK_ID in your environment."
 	}
 
 	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
 }
 
+// embeddingProvider names the embedding service actually in use, which differs from the
+// configured provider when OpenAI was requested without an API key
+func (s *ForwardMCPService) embeddingProvider() string {
+	if s.semanticCache == nil {
+		return "none"
+	}
+	return embeddingProviderName(s.semanticCache.embeddingService)
+}
+
+// cacheSettings reports the semantic and latest-snapshot cache configuration in effect
+func (s *ForwardMCPService) cacheSettings() map[string]interface{} {
+	settings := map[string]interface{}{
+		"enabled": s.semanticCache != nil,
+	}
+	if s.config != nil {
+		settings["enabled"] = s.semanticCache != nil && s.config.Forward.SemanticCache.Enabled
+		settings["latest_snapshot_ttl_seconds"] = s.config.Forward.LatestSnapshotTTL
+	}
+	if s.semanticCache != nil {
+		s.semanticCache.mutex.RLock()
+		settings["max_entries"] = s.semanticCache.maxEntries
+		settings["ttl_hours"] = s.semanticCache.ttl.Hours()
+		settings["similarity_threshold"] = s.semanticCache.similarityThreshold
+		settings["suggestion_floor"] = s.semanticCache.suggestionFloor
+		s.semanticCache.mutex.RUnlock()
+	}
+	return settings
+}
+
 func (s *ForwardMCPService) setDefaultNetwork(args SetDefaultNetworkArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("set_default_network", args, nil)
 
@@ -1054,11 +1886,11 @@ func (s *ForwardMCPService) setDefaultNetwork(args SetDefaultNetworkArgs) (*mcp.
 
 	// Try to resolve the network identifier (could be ID or name)
 	if args.NetworkIdentifier == "" {
-		return mcp.NewToolResponse(mcp.NewTextContent("Please provide either a network ID or network name.")), nil
+		return s.failureResponse("Please provide either a network ID or network name."), nil
 	}
 
 	// First, try as network ID by listing networks and checking if it exists
-	networks, err := s.forwardClient.GetNetworks()
+	networks, err := s.client().GetNetworks()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get networks: %w", err)
 	}
@@ -1082,7 +1914,7 @@ func (s *ForwardMCPService) setDefaultNetwork(args SetDefaultNetworkArgs) (*mcp.
 				availableNetworks += fmt.Sprintf("%d. %s (ID: %s)\n", i+1, network.Name, network.ID)
 			}
 
-			return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Network '%s' not found.\n\n%s\nPlease use either a valid network ID or exact network name.", args.NetworkIdentifier, availableNetworks))), nil
+			return s.failureResponse(fmt.Sprintf("Network '%s' not found.\n\n%s\nPlease use either a valid network ID or exact network name.", args.NetworkIdentifier, availableNetworks)), nil
 		}
 
 		networkID = resolvedID
@@ -1096,7 +1928,7 @@ func (s *ForwardMCPService) setDefaultNetwork(args SetDefaultNetworkArgs) (*mcp.
 	}
 
 	// Update the default (for this session)
-	s.defaults.NetworkID = networkID
+	s.defaults.SetNetwork(networkID)
 
 	response := "Default network updated successfully!\n\n"
 	response += fmt.Sprintf("New default: %s (ID: %s)\n\n", networkName, networkID)
@@ -1116,13 +1948,30 @@ func (s *ForwardMCPService) getCacheStats(args GetCacheStatsArgs) (*mcp.ToolResp
 
 	stats := s.semanticCache.GetStats()
 
-	statsJSON, _ := json.MarshalIndent(stats, "", "  ")
-
-	summary := fmt.Sprintf("Semantic Cache Performance Statistics:\n%s\n\nCache Summary:\n", string(statsJSON))
+	summary := fmt.Sprintf("Semantic Cache Performance Statistics:\n%s\n\nCache Summary:\n", s.toJSON(stats, args.Pretty))
 	summary += fmt.Sprintf("• Total Queries: %v\n", stats["total_queries"])
 	summary += fmt.Sprintf("• Hit Rate: %v\n", stats["hit_rate_percent"])
 	summary += fmt.Sprintf("• Active Entries: %v/%v\n", stats["total_entries"], stats["max_entries"])
 	summary += fmt.Sprintf("• Similarity Threshold: %v\n", stats["threshold"])
+	if maxBytes, _ := stats["max_total_bytes"].(int); maxBytes > 0 {
+		summary += fmt.Sprintf("• Cached Bytes: %v/%d\n", stats["total_bytes"], maxBytes)
+	}
+	if skips, _ := stats["oversized_skips"].(int64); skips > 0 {
+		summary += fmt.Sprintf("• Results Too Large to Cache: %d\n", skips)
+	}
+
+	if recommendations := s.active().snapshotCadence.Recommendations(); len(recommendations) > 0 {
+		autoTune := s.config != nil && s.config.Forward.SemanticCache.AutoTuneTTL
+		summary += "\nTTL Recommendations (based on snapshot cadence):\n"
+		for _, rec := range recommendations {
+			summary += fmt.Sprintf("• Network %s: snapshots every ~%s → recommended TTL %s (current %s, %d intervals observed)\n",
+				rec.NetworkID, rec.SmoothedInterval.Round(time.Minute), rec.RecommendedTTL.Round(time.Minute),
+				s.semanticCache.NetworkTTL(rec.NetworkID), rec.Intervals)
+		}
+		if !autoTune {
+			summary += "Set FORWARD_SEMANTIC_CACHE_AUTO_TTL=true to apply these automatically.\n"
+		}
+	}
 
 	return mcp.NewToolResponse(mcp.NewTextContent(summary)), nil
 }
@@ -1140,13 +1989,21 @@ func (s *ForwardMCPService) suggestSimilarQueries(args SuggestSimilarQueriesArgs
 		limit = 5
 	}
 
-	similarQueries, err := s.semanticCache.FindSimilarQueries(args.Query, limit)
+	if args.MinSimilarity < 0 || args.MinSimilarity > 1 {
+		return nil, fmt.Errorf("min_similarity must be between 0 and 1, got %g", args.MinSimilarity)
+	}
+	floor := s.semanticCache.SuggestionFloor()
+	if args.MinSimilarity > 0 {
+		floor = args.MinSimilarity
+	}
+
+	similarQueries, err := s.semanticCache.FindSimilarQueriesAbove(args.Query, limit, floor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find similar queries: %w", err)
 	}
 
 	if len(similarQueries) == 0 {
-		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("No similar queries found for: '%s'\n\nTry running some NQE queries first to build up the cache.", args.Query))), nil
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("No similar queries found for: '%s' above %.0f%% similarity\n\nTry running some NQE queries first to build up the cache, or lower min_similarity.", args.Query, floor*100))), nil
 	}
 
 	response := fmt.Sprintf("Similar queries found for: '%s'\n\n", args.Query)
@@ -1175,27 +2032,7 @@ func (s *ForwardMCPService) clearCache(args ClearCacheArgs) (*mcp.ToolResponse,
 	var operation string
 
 	if args.ClearAll {
-		// For simplicity, we'll implement a full clear by creating a new cache
-		// In production, you might want a more sophisticated approach
-		stats := s.semanticCache.GetStats()
-		totalEntries := stats["total_entries"].(int)
-
-		// Reinitialize the cache
-		var embeddingService EmbeddingService
-		if s.config.Forward.SemanticCache.EmbeddingProvider == "openai" {
-			if openaiKey := os.Getenv("OPENAI_API_KEY"); openaiKey != "" {
-				embeddingService = NewOpenAIEmbeddingService(openaiKey)
-			} else {
-				embeddingService = NewMockEmbeddingService()
-			}
-		} else if s.config.Forward.SemanticCache.EmbeddingProvider == "keyword" {
-			embeddingService = NewKeywordEmbeddingService()
-		} else {
-			embeddingService = NewMockEmbeddingService()
-		}
-		s.semanticCache = NewSemanticCache(embeddingService, s.logger)
-
-		removed = totalEntries
+		removed = s.semanticCache.Clear()
 		operation = "Cleared all cache entries"
 	} else {
 		removed = s.semanticCache.ClearExpired()
@@ -1220,7 +2057,7 @@ func (s *ForwardMCPService) searchNQEQueries(args SearchNQEQueriesArgs) (*mcp.To
 	s.logToolCall("search_nqe_queries", args, nil)
 
 	if args.Query == "" {
-		return mcp.NewToolResponse(mcp.NewTextContent("Please provide a search query describing what you want to analyze (e.g., 'AWS security vulnerabilities', 'BGP routing issues', 'interface statistics')")), nil
+		return s.failureResponse("Please provide a search query describing what you want to analyze (e.g., 'AWS security vulnerabilities', 'BGP routing issues', 'interface statistics')"), nil
 	}
 
 	// Set default limit
@@ -1235,15 +2072,15 @@ func (s *ForwardMCPService) searchNQEQueries(args SearchNQEQueriesArgs) (*mcp.To
 	if totalQueries == 0 {
 		s.logger.Info("Query index empty, initializing...")
 		if err := s.queryIndex.LoadFromSpec(); err != nil {
-			return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Failed to initialize query index: %v\n\n**Manual Fix:** Run this command:\n```json\n{\"tool\": \"initialize_query_index\", \"arguments\": {\"generate_embeddings\": false}}\n```", err))), nil
+			return s.failureResponse(fmt.Sprintf("Failed to initialize query index: %v\n\n**Manual Fix:** Run this command:\n```json\n{\"tool\": \"initialize_query_index\", \"arguments\": {\"generate_embeddings\": false}}\n```", err)), nil
 		}
 		s.logger.Info("Query index initialized successfully")
 	}
 
 	// Use keyword-based search directly
-	results, err := s.queryIndex.searchWithKeywords(args.Query, limit)
+	results, err := s.queryIndex.SearchByKeywords(args.Query, limit)
 	if err != nil {
-		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Search failed: %v", err))), nil
+		return s.failureResponse(fmt.Sprintf("Search failed: %v", err)), nil
 	}
 
 	// Apply category/subcategory filters if specified
@@ -1262,23 +2099,9 @@ func (s *ForwardMCPService) searchNQEQueries(args SearchNQEQueriesArgs) (*mcp.To
 	}
 
 	if len(filteredResults) == 0 {
-		response := fmt.Sprintf("No exact matches found for: '%s'", args.Query)
-		if categoryFilterApplied || subcategoryFilterApplied {
-			response += fmt.Sprintf(" (filtered by category: %s, subcategory: %s)", args.Category, args.Subcategory)
-			response += "\n\n **Try:**\n• Using broader search terms\n• Removing category filters\n• Running 'get_query_index_stats' to see available categories"
-		} else {
-			// No filters applied but still no results - provide helpful suggestions
-			response += "\n\n**Search Tips:**\n"
-			response += "• Try using more general terms (e.g. 'security' instead of 'security vulnerabilities')\n"
-			response += "• Break down complex queries into simpler parts\n"
-			response += "• Check common categories: Security, L3, Cloud, Interfaces\n"
-			response += "• Use related terms (e.g. 'routing' for 'BGP')\n"
-			response += "\n**Available Tools:**\n"
-			response += "• Run 'get_query_index_stats' to see all categories\n"
-			response += "• Try 'find_executable_query' for a different search approach\n"
-			response += "• Use 'list_nqe_queries' to browse by directory"
-		}
-		return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+		categories, _ := s.queryIndex.GetStatistics()["categories"].(map[string]int)
+		guidance := nqeSearchGuidance(args.Query, args.Category, args.Subcategory, categories)
+		return mcp.NewToolResponse(mcp.NewTextContent(s.formatNQESearchGuidance(guidance))), nil
 	}
 
 	// Build response with search type indicator
@@ -1322,12 +2145,22 @@ func (s *ForwardMCPService) searchNQEQueries(args SearchNQEQueriesArgs) (*mcp.To
 func (s *ForwardMCPService) initializeQueryIndex(args InitializeQueryIndexArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("initialize_query_index", args, nil)
 
+	if s.indexBuilds != nil {
+		if args.Background {
+			return s.startBackgroundIndexBuild(args)
+		}
+		if s.indexBuilds.Running() {
+			status := s.indexBuilds.Status()
+			return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("⏳ Index build %s is already running in the background. Poll `get_index_build_status` and retry when it finishes.", status.Token))), nil
+		}
+	}
+
 	response := "🔧 Initializing AI-powered NQE query index...\n\n"
 
 	// Check if spec file exists using robust path resolution
-	specPath, err := findSpecFile("NQELibrary.json")
+	specPath, err := s.queryIndex.specFilePath()
 	if err != nil {
-		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("NQE spec file not found. Searched in multiple locations but could not locate 'NQELibrary.json'. Error: %v\n\n💡 **Troubleshooting:**\n• Ensure the spec file exists in the 'spec' directory\n• Check that the MCP server is running from the correct directory\n• Verify file permissions", err))), nil
+		return s.failureResponse(fmt.Sprintf("NQE spec file not found. Searched in multiple locations but could not locate 'NQELibrary.json'. Error: %v\n\n💡 **Troubleshooting:**\n• Ensure the spec file exists in the 'spec' directory\n• Check that the MCP server is running from the correct directory\n• Verify file permissions", err)), nil
 	}
 
 	response += fmt.Sprintf("📁 Found spec file at: %s\n", specPath)
@@ -1352,7 +2185,7 @@ func (s *ForwardMCPService) initializeQueryIndex(args InitializeQueryIndexArgs)
 
 	// Generate embeddings if requested
 	if args.GenerateEmbeddings {
-		if _, ok := s.queryIndex.embeddingService.(*MockEmbeddingService); ok {
+		if !isRealEmbeddingProvider(s.queryIndex.embeddingService) {
 			response += "Cannot generate embeddings: OpenAI API key not configured\n"
 			response += "Set OPENAI_API_KEY environment variable to enable embedding generation\n"
 			response += "Current functionality limited to keyword-based search\n\n"
@@ -1374,7 +2207,7 @@ func (s *ForwardMCPService) initializeQueryIndex(args InitializeQueryIndexArgs)
 				newCoverage := updatedStats["embedding_coverage"].(float64)
 
 				response += fmt.Sprintf("Generated and cached %d embeddings (%.1f%% coverage)\n", newEmbeddedCount, newCoverage*100)
-				response += "Embeddings saved to spec/nqe-embeddings.json for offline use\n\n"
+				response += fmt.Sprintf("Embeddings saved to %s for offline use\n\n", s.queryIndex.embeddingsCachePath)
 			}
 		}
 	}
@@ -1387,9 +2220,9 @@ func (s *ForwardMCPService) initializeQueryIndex(args InitializeQueryIndexArgs)
 	if categories, ok := finalStats["categories"].(map[string]int); ok {
 		response += "• Categories:\n"
 		categoryCount := 0
-		for category, count := range categories {
+		for _, category := range keysByCount(categories) {
 			if category != "" && categoryCount < 5 { // Show top 5 categories
-				response += fmt.Sprintf("  - %s: %d queries\n", category, count)
+				response += fmt.Sprintf("  - %s: %d queries\n", category, categories[category])
 				categoryCount++
 			}
 		}
@@ -1441,6 +2274,9 @@ func (s *ForwardMCPService) getQueryIndexStats(args GetQueryIndexStatsArgs) (*mc
 		coverage := stats["embedding_coverage"].(float64)
 		response += fmt.Sprintf("**AI Embeddings:** %d queries (%.1f%% coverage)\n", embeddedCount, coverage*100)
 
+		if corrupt, _ := stats["embeddings_corrupt"].(bool); corrupt {
+			response += "⚠️ The embeddings cache was corrupt and has been set aside; search uses keywords until it is regenerated\n"
+		}
 		if embeddedCount == 0 {
 			response += "Run `initialize_query_index` with `generate_embeddings: true` for AI search\n"
 		}
@@ -1450,40 +2286,26 @@ func (s *ForwardMCPService) getQueryIndexStats(args GetQueryIndexStatsArgs) (*mc
 		response += "\n**Query Categories:**\n"
 
 		// Sort categories by count
-		type categoryCount struct {
-			name  string
-			count int
-		}
-		sortedCategories := make([]categoryCount, 0, len(categories))
-		for category, count := range categories {
+		var sortedCategories []string
+		for _, category := range keysByCount(categories) {
 			if category != "" {
-				sortedCategories = append(sortedCategories, categoryCount{category, count})
+				sortedCategories = append(sortedCategories, category)
 			}
 		}
-		sort.Slice(sortedCategories, func(i, j int) bool {
-			return sortedCategories[i].count > sortedCategories[j].count
-		})
 
 		// Display categories with subcategories
 		if subcategories, ok := stats["subcategories"].(map[string]map[string]int); ok {
 			for _, cat := range sortedCategories {
-				response += fmt.Sprintf("• **%s** (%d queries)\n", cat.name, cat.count)
+				response += fmt.Sprintf("• **%s** (%d queries)\n", cat, categories[cat])
 
-				if subCats, exists := subcategories[cat.name]; exists && len(subCats) > 0 {
+				if subCats, exists := subcategories[cat]; exists && len(subCats) > 0 {
 					// Sort subcategories
-					type subCategoryCount struct {
-						name  string
-						count int
-					}
-					sortedSubCats := make([]subCategoryCount, 0, len(subCats))
-					for subCat, count := range subCats {
+					var sortedSubCats []string
+					for _, subCat := range keysByCount(subCats) {
 						if subCat != "" {
-							sortedSubCats = append(sortedSubCats, subCategoryCount{subCat, count})
+							sortedSubCats = append(sortedSubCats, subCat)
 						}
 					}
-					sort.Slice(sortedSubCats, func(i, j int) bool {
-						return sortedSubCats[i].count > sortedSubCats[j].count
-					})
 
 					// Show top 5 subcategories
 					for i, subCat := range sortedSubCats {
@@ -1491,7 +2313,7 @@ func (s *ForwardMCPService) getQueryIndexStats(args GetQueryIndexStatsArgs) (*mc
 							response += fmt.Sprintf("    ... and %d more subcategories\n", len(sortedSubCats)-5)
 							break
 						}
-						response += fmt.Sprintf("    - %s (%d queries)\n", subCat.name, subCat.count)
+						response += fmt.Sprintf("    - %s (%d queries)\n", subCat, subCats[subCat])
 					}
 				}
 			}
@@ -1512,12 +2334,99 @@ func (s *ForwardMCPService) getQueryIndexStats(args GetQueryIndexStatsArgs) (*mc
 	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
 }
 
+// browseNQELibrary lists the NQE library categories with counts and example query paths
+func (s *ForwardMCPService) browseNQELibrary(args BrowseNQELibraryArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("browse_nqe_library", args, nil)
+
+	examples := args.Examples
+	if examples <= 0 {
+		examples = 3
+	}
+	if examples > 10 {
+		examples = 10
+	}
+
+	// Initialize query index if needed
+	if s.queryIndex.GetStatistics()["total_queries"].(int) == 0 {
+		s.logger.Info("Query index empty, initializing...")
+		if err := s.queryIndex.LoadFromSpec(); err != nil {
+			return s.failureResponse(fmt.Sprintf("Failed to initialize query index: %v\n\n**Manual Fix:** Run `initialize_query_index` and try again.", err)), nil
+		}
+	}
+
+	summaries := s.queryIndex.GetCategorySummaries(examples)
+	if args.Category != "" {
+		var filtered []*NQECategorySummary
+		for _, summary := range summaries {
+			if strings.EqualFold(summary.Name, args.Category) {
+				filtered = append(filtered, summary)
+			}
+		}
+		if len(filtered) == 0 {
+			available := make([]string, 0, len(summaries))
+			for _, summary := range summaries {
+				available = append(available, summary.Name)
+			}
+			return s.failureResponse(fmt.Sprintf("Category '%s' not found in the NQE library.\n\n**Available categories:** %s", args.Category, strings.Join(available, ", "))), nil
+		}
+		summaries = filtered
+	}
+
+	totalQueries := 0
+	for _, summary := range summaries {
+		totalQueries += summary.QueryCount
+	}
+
+	response := "📚 **NQE Library Browser**\n\n"
+	response += fmt.Sprintf("%d queries across %d categories\n\n", totalQueries, len(summaries))
+
+	for _, summary := range summaries {
+		response += fmt.Sprintf("• **%s** (%d queries)\n", summary.Name, summary.QueryCount)
+
+		if len(summary.Subcategories) > 0 {
+			subcategoryNames := make([]string, 0, len(summary.Subcategories))
+			for name := range summary.Subcategories {
+				subcategoryNames = append(subcategoryNames, name)
+			}
+			sort.Slice(subcategoryNames, func(i, j int) bool {
+				ci, cj := summary.Subcategories[subcategoryNames[i]], summary.Subcategories[subcategoryNames[j]]
+				if ci != cj {
+					return ci > cj
+				}
+				return subcategoryNames[i] < subcategoryNames[j]
+			})
+			parts := make([]string, 0, 5)
+			for i, name := range subcategoryNames {
+				if i >= 5 {
+					parts = append(parts, fmt.Sprintf("... and %d more", len(subcategoryNames)-5))
+					break
+				}
+				parts = append(parts, fmt.Sprintf("%s (%d)", name, summary.Subcategories[name]))
+			}
+			response += fmt.Sprintf("    Subcategories: %s\n", strings.Join(parts, ", "))
+		}
+
+		for _, path := range summary.ExamplePaths {
+			response += fmt.Sprintf("    - %s\n", path)
+		}
+	}
+
+	response += "\n**Next Steps:**\n"
+	response += "• Use `search_nqe_queries` with a `category` filter to search within a category\n"
+	response += "• Use `browse_nqe_library` with `category` to focus on one area"
+
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
+
+// degradedSearchNote is prepended to search responses that fell back to keyword matching
+const degradedSearchNote = "⚠️ **Degraded matching:** the embedding service is unavailable, so these results use keyword matching instead of AI semantic search.\n\n"
+
 // findExecutableQuery performs intelligent query discovery using semantic search + executable mapping
 func (s *ForwardMCPService) findExecutableQuery(args FindExecutableQueryArgs) (*mcp.ToolResponse, error) {
 	s.logToolCall("find_executable_query", args, nil)
 
 	if args.Query == "" {
-		return mcp.NewToolResponse(mcp.NewTextContent("Please describe what you want to analyze (e.g., 'show me all BGP neighbors', 'find devices with high CPU', 'check configuration compliance')")), nil
+		return s.failureResponse("Please describe what you want to analyze (e.g., 'show me all BGP neighbors', 'find devices with high CPU', 'check configuration compliance')"), nil
 	}
 
 	// Set default limit for semantic search
@@ -1539,7 +2448,7 @@ func (s *ForwardMCPService) findExecutableQuery(args FindExecutableQueryArgs) (*
 			autoInitResponse = "🔧 Query index not initialized. Auto-initializing now...\n\n"
 
 			if err := s.queryIndex.LoadFromSpec(); err != nil {
-				return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Auto-initialization failed: %v\n\n**Manual Fix:** Run this command:\n```json\n{\"tool\": \"initialize_query_index\", \"arguments\": {\"generate_embeddings\": false}}\n```", err))), nil
+				return s.failureResponse(fmt.Sprintf("Auto-initialization failed: %v\n\n**Manual Fix:** Run this command:\n```json\n{\"tool\": \"initialize_query_index\", \"arguments\": {\"generate_embeddings\": false}}\n```", err)), nil
 			}
 
 			autoInitResponse += "Query index loaded successfully! Retrying your search...\n\n"
@@ -1547,7 +2456,7 @@ func (s *ForwardMCPService) findExecutableQuery(args FindExecutableQueryArgs) (*
 			// Retry the search after initialization
 			semanticResults, err = s.queryIndex.SearchQueries(args.Query, semanticLimit)
 			if err != nil {
-				return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("%sSearch failed after auto-initialization: %v", autoInitResponse, err))), nil
+				return s.failureResponse(fmt.Sprintf("%sSearch failed after auto-initialization: %v", autoInitResponse, err)), nil
 			}
 
 			// Continue with search results processing
@@ -1562,12 +2471,29 @@ func (s *ForwardMCPService) findExecutableQuery(args FindExecutableQueryArgs) (*
 		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("No relevant queries found for: '%s'\n\n💡 Try:\n• Using different search terms\n• Being more specific about what you want to analyze\n• Running 'get_query_index_stats' to see available categories", args.Query))), nil
 	}
 
-	// Step 2: Map semantic results to executable queries
-	mappings := MapSemanticToExecutable(semanticResults)
+	// Surface degraded matching when the embedding service failed mid-search
+	var degradedNote string
+	if IsDegradedSearch(semanticResults) {
+		degradedNote = degradedSearchNote
+	}
+
+	// Step 2: Map semantic results to executable queries, dropping weak mappings
+	minConfidence := args.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = defaultMinMappingConfidence
+	}
+	allMappings := MapSemanticToExecutable(semanticResults)
+	mappings := FilterMappingsByConfidence(allMappings, minConfidence)
 
 	if len(mappings) == 0 {
-		// No direct mappings found, show semantic results with explanation
-		response := fmt.Sprintf("Found %d relevant queries for '%s', but none map to currently executable queries.\n\n", len(semanticResults), args.Query)
+		// No confident mappings found, show semantic results with explanation
+		response := degradedNote
+		if len(allMappings) > 0 {
+			response += fmt.Sprintf("Found %d relevant queries for '%s', but no executable query mapping reached the %.0f%% confidence threshold (best was %.1f%%).\n\n",
+				len(semanticResults), args.Query, minConfidence*100, allMappings[0].MappingConfidence*100)
+		} else {
+			response += fmt.Sprintf("Found %d relevant queries for '%s', but none map to currently executable queries.\n\n", len(semanticResults), args.Query)
+		}
 		response += "**Related queries found:**\n"
 
 		displayLimit := 5
@@ -1598,6 +2524,8 @@ func (s *ForwardMCPService) findExecutableQuery(args FindExecutableQueryArgs) (*
 	searchType := "AI-semantic"
 	if len(semanticResults) > 0 && semanticResults[0].MatchType == "keyword" {
 		searchType = "Keyword-based"
+	} else if degradedNote != "" {
+		searchType = "Keyword-based (fallback)"
 	}
 
 	response := ""
@@ -1606,6 +2534,7 @@ func (s *ForwardMCPService) findExecutableQuery(args FindExecutableQueryArgs) (*
 	if autoInitResponse != "" {
 		response += autoInitResponse
 	}
+	response += degradedNote
 
 	response += fmt.Sprintf("%s search found %d executable queries for: '%s'\n\n", searchType, len(mappings), args.Query)
 
@@ -1624,6 +2553,10 @@ func (s *ForwardMCPService) findExecutableQuery(args FindExecutableQueryArgs) (*
 		response += fmt.Sprintf("   **Purpose:** %s\n", eq.Description)
 		response += fmt.Sprintf("   **When to use:** %s\n", eq.WhenToUse)
 		response += fmt.Sprintf("   **Mapping reason:** %s\n", mapping.MappingReason)
+		response += fmt.Sprintf("   **Estimated cost:** %s\n", formatCostSummary(s.estimateQueryCostFor(eq.QueryID)))
+		if args.IncludeHistory {
+			response += fmt.Sprintf("   **History:** %s\n", s.formatQueryHistory(eq.QueryID))
+		}
 
 		if args.IncludeRelated && len(mapping.SemanticMatches) > 0 {
 			response += fmt.Sprintf("   **Related queries found:** %d\n", len(mapping.SemanticMatches))
@@ -1647,7 +2580,7 @@ func (s *ForwardMCPService) findExecutableQuery(args FindExecutableQueryArgs) (*
 	response += "• Use the dedicated tools (e.g., `get_device_basic_info`) for easier execution\n"
 	response += "• Add `include_related: true` to see the semantic matches that led to these recommendations\n"
 
-	if searchType == "🔍 Keyword-based" {
+	if searchType == "Keyword-based" {
 		response += "• Generate embeddings with `initialize_query_index` for better AI semantic matching\n"
 	}
 
@@ -1776,6 +2709,7 @@ type RunSemanticNQEQueryArgs struct {
 	NetworkID  string           `json:"network_id"`
 	SnapshotID string           `json:"snapshot_id"`
 	Options    *NQEQueryOptions `json:"options"`
+	NoCache    bool             `json:"no_cache,omitempty"`
 }
 
 // runSemanticNQEQuery implements the handler for the run_semantic_nqe_query tool
@@ -1783,7 +2717,7 @@ func (s *ForwardMCPService) runSemanticNQEQuery(args RunSemanticNQEQueryArgs) (*
 	s.logToolCall("run_semantic_nqe_query", args, nil)
 
 	if args.Query == "" {
-		return mcp.NewToolResponse(mcp.NewTextContent("Please provide a natural language query describing what you want to analyze.")), nil
+		return s.failureResponse("Please provide a natural language query describing what you want to analyze."), nil
 	}
 
 	// Use semantic search to find the best matching query
@@ -1799,8 +2733,18 @@ func (s *ForwardMCPService) runSemanticNQEQuery(args RunSemanticNQEQueryArgs) (*
 		SnapshotID: args.SnapshotID,
 		QueryID:    bestQuery.QueryID,
 		Options:    args.Options,
+		NoCache:    args.NoCache,
+	}
+	response, err := s.runNQEQueryByID(runArgs)
+	if err != nil || !IsDegradedSearch(results) {
+		return response, err
+	}
+
+	// Let the caller know the query was picked with degraded matching
+	if len(response.Content) > 0 && response.Content[0].TextContent != nil {
+		response.Content[0].TextContent.Text = degradedSearchNote + response.Content[0].TextContent.Text
 	}
-	return s.runNQEQueryByID(runArgs)
+	return response, nil
 }
 
 // promptForParameter prompts the user for a required parameter in the workflow
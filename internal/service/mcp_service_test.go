@@ -1,7 +1,12 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/forward-mcp/internal/config"
@@ -26,6 +31,7 @@ type MockForwardClient struct {
 	deviceLocations map[string]string
 	pathResponse    *forward.PathSearchResponse
 	nqeResult       *forward.NQERunResult
+	nqeValidation   *forward.NQEValidationResult
 	shouldError     bool
 	errorMessage    string
 }
@@ -245,13 +251,35 @@ func (m *MockForwardClient) DiffNQEQuery(before, after string, request *forward.
 	return &forward.NQEDiffResult{TotalNumValues: 2, Rows: []map[string]interface{}{{"diff": "example"}}}, nil
 }
 
+func (m *MockForwardClient) ValidateNQEQuery(networkID, query string) (*forward.NQEValidationResult, error) {
+	if m.shouldError {
+		return nil, &MockError{m.errorMessage}
+	}
+	if m.nqeValidation != nil {
+		return m.nqeValidation, nil
+	}
+	return &forward.NQEValidationResult{Valid: true}, nil
+}
+
 func (m *MockForwardClient) GetDevices(networkID string, params *forward.DeviceQueryParams) (*forward.DeviceResponse, error) {
 	if m.shouldError {
 		return nil, &MockError{m.errorMessage}
 	}
+	// Like the API, offset and limit select a page and the count covers only that page
+	devices := m.devices
+	if params != nil {
+		if params.Offset >= len(devices) {
+			devices = nil
+		} else {
+			devices = devices[params.Offset:]
+		}
+		if params.Limit > 0 && len(devices) > params.Limit {
+			devices = devices[:params.Limit]
+		}
+	}
 	return &forward.DeviceResponse{
-		Devices:    m.devices,
-		TotalCount: len(m.devices),
+		Devices:    devices,
+		TotalCount: len(devices),
 	}, nil
 }
 
@@ -284,7 +312,7 @@ func (m *MockForwardClient) GetLatestSnapshot(networkID string) (*forward.Snapsh
 	if len(m.snapshots) > 0 {
 		return &m.snapshots[0], nil
 	}
-	return nil, &MockError{"no snapshots found"}
+	return nil, forward.ErrNoProcessedSnapshot
 }
 
 func (m *MockForwardClient) DeleteSnapshot(snapshotID string) error {
@@ -379,9 +407,9 @@ func createTestService() *ForwardMCPService {
 	semanticCache := NewSemanticCache(embeddingService, logger)
 
 	service := &ForwardMCPService{
-		forwardClient: NewMockForwardClient(),
-		config:        cfg,
-		logger:        logger,
+		instance: newInstanceHolder(&instanceState{client: NewMockForwardClient()}),
+		config:   cfg,
+		logger:   logger,
 		defaults: &ServiceDefaults{
 			NetworkID:  "162112",
 			SnapshotID: "",
@@ -465,6 +493,26 @@ func TestDeleteNetwork(t *testing.T) {
 	}
 }
 
+func TestGetDefaultSettingsResolvesNames(t *testing.T) {
+	service := createTestService()
+
+	response, err := service.getDefaultSettings(GetDefaultSettingsArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	if !contains(content, "Network: Test Network (162112)") {
+		t.Errorf("Expected the default network ID to resolve to its name, got: %s", content)
+	}
+	if !contains(content, "Snapshot: latest") {
+		t.Errorf("Expected an unpinned snapshot to show as latest, got: %s", content)
+	}
+	if !contains(content, "Embedding provider: mock") || !contains(content, "\"suggestion_floor\"") {
+		t.Errorf("Expected embedding provider and cache settings, got: %s", content)
+	}
+}
+
 // Path Search Tests
 func TestSearchPaths(t *testing.T) {
 	service := createTestService()
@@ -570,6 +618,138 @@ func TestRunNQEQueryByID(t *testing.T) {
 	}
 }
 
+func TestRunNQEQueryEmptyResultGuidance(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).nqeResult = &forward.NQERunResult{SnapshotID: "snap-42"}
+
+	response, err := service.searchConfigs(SearchConfigsArgs{
+		NetworkID:  "162112",
+		SearchTerm: "ntp server 10.0.0.1",
+		Options: &NQEQueryOptions{
+			Filters: []NQEColumnFilter{{ColumnName: "device", Value: "Router-1"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	for _, want := range []string{
+		"Found 0 items",
+		"network 162112, snapshot snap-42",
+		`device="Router-1"`,
+		"searchPattern",
+		"search_nqe_queries",
+	} {
+		if !contains(content, want) {
+			t.Errorf("Expected empty-result guidance to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestRunNQEQueryEnforcesQueryLimit(t *testing.T) {
+	service := createTestService()
+	service.config.Forward.SemanticCache.Enabled = false
+	service.defaults.MaxQueryLimit = 500
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "FQ_test"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "FQ_test", Options: &NQEQueryOptions{Offset: 10}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "FQ_test", Options: &NQEQueryOptions{Limit: 500}}); err != nil {
+		t.Fatalf("Expected a limit at the cap to be allowed, got: %v", err)
+	}
+	if want := []int{100, 100, 500}; !reflect.DeepEqual(client.limits(), want) {
+		t.Errorf("Expected limits %v, got %v", want, client.limits())
+	}
+
+	_, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "FQ_test", Options: &NQEQueryOptions{Limit: 501}})
+	if err == nil || !contains(err.Error(), "exceeds the maximum of 500 rows") {
+		t.Errorf("Expected an over-cap limit to be rejected, got: %v", err)
+	}
+	if len(client.limits()) != 3 {
+		t.Errorf("Expected the rejected query not to run, got %d runs", len(client.limits()))
+	}
+}
+
+func TestRunNQEQueryNoCacheBypassesSemanticCache(t *testing.T) {
+	service := createTestService()
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+	cachedResult := &forward.NQERunResult{SnapshotID: "snap-1", Items: []map[string]interface{}{{"device": "cached-router"}}}
+	client.nqeResult = cachedResult
+
+	args := RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_test"}
+	if _, err := service.runNQEQueryByID(args); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// A live run ignores the cached entry and does not replace it
+	client.nqeResult = &forward.NQERunResult{SnapshotID: "snap-1", Items: []map[string]interface{}{{"device": "live-router"}}}
+	args.NoCache = true
+	response, err := service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if len(client.queryIDs) != 2 || !contains(text, "live-router") || !contains(text, "Cache bypassed") {
+		t.Errorf("Expected no_cache to run the query live and say so, got %d runs:\n%s", len(client.queryIDs), text)
+	}
+
+	args.NoCache = false
+	response, err = service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; len(client.queryIDs) != 2 || !contains(text, "cached-router") {
+		t.Errorf("Expected the original cached result to be served, got %d runs:\n%s", len(client.queryIDs), text)
+	}
+}
+
+func TestRunNQEQueryCachesLatestSnapshotResultsBySnapshotID(t *testing.T) {
+	service := createTestService()
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+	client.snapshots = []forward.Snapshot{{ID: "snap-1"}}
+	client.nqeResult = &forward.NQERunResult{Items: []map[string]interface{}{{"device": "old-router"}}}
+
+	args := RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_test"}
+	for i := 0; i < 2; i++ {
+		if _, err := service.runNQEQueryByID(args); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if len(client.queryIDs) != 1 {
+		t.Fatalf("Expected the second call to be served from the cache, got %d runs", len(client.queryIDs))
+	}
+
+	// Once a new snapshot is processed the old snapshot's result is no longer served
+	client.snapshots = []forward.Snapshot{{ID: "snap-2"}}
+	client.nqeResult = &forward.NQERunResult{Items: []map[string]interface{}{{"device": "new-router"}}}
+	response, err := service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; len(client.queryIDs) != 2 || !contains(text, "new-router") {
+		t.Errorf("Expected a live run against the new snapshot, got %d runs:\n%s", len(client.queryIDs), text)
+	}
+
+	// Without a known latest snapshot nothing is cached
+	client.snapshots = nil
+	for i := 0; i < 2; i++ {
+		if _, err := service.runNQEQueryByID(args); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if len(client.queryIDs) != 4 {
+		t.Errorf("Expected uncached runs when the latest snapshot is unknown, got %d runs", len(client.queryIDs))
+	}
+}
+
 func TestListNQEQueries(t *testing.T) {
 	service := createTestService()
 
@@ -616,6 +796,74 @@ func TestListDevices(t *testing.T) {
 	}
 }
 
+func TestListSnapshotsFilters(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).snapshots = []forward.Snapshot{
+		{ID: "snap-old", State: "PROCESSED", CreationDateMillis: 1000},
+		{ID: "snap-draft", State: "PROCESSED", IsDraft: true, CreationDateMillis: 4000},
+		{ID: "snap-new", State: "PROCESSED", CreationDateMillis: 3000},
+		{ID: "snap-failed", State: "FAILED", CreationDateMillis: 2000},
+	}
+
+	excludeDrafts := false
+	testCases := []struct {
+		name        string
+		args        ListSnapshotsArgs
+		expectedIDs []string
+	}{
+		{"drafts included by default", ListSnapshotsArgs{}, []string{"snap-draft", "snap-new", "snap-failed", "snap-old"}},
+		{"exclude drafts", ListSnapshotsArgs{IncludeDrafts: &excludeDrafts}, []string{"snap-new", "snap-failed", "snap-old"}},
+		{"processed only", ListSnapshotsArgs{State: "processed"}, []string{"snap-draft", "snap-new", "snap-old"}},
+		{"processed non-drafts", ListSnapshotsArgs{State: "processed", IncludeDrafts: &excludeDrafts}, []string{"snap-new", "snap-old"}},
+		{"no matches", ListSnapshotsArgs{State: "PROCESSING"}, []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.args.NetworkID = "162112"
+			response, err := service.listSnapshots(tc.args)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			content := response.Content[0].TextContent.Text
+			if !contains(content, fmt.Sprintf("Found %d snapshots (4 total before filtering)", len(tc.expectedIDs))) {
+				t.Errorf("Expected filtered and total counts, got: %s", content)
+			}
+
+			var snapshots []forward.Snapshot
+			if err := json.Unmarshal([]byte(content[strings.Index(content, "\n")+1:strings.LastIndex(content, "\n")]), &snapshots); err != nil {
+				t.Fatalf("Failed to parse snapshots: %v", err)
+			}
+			ids := make([]string, 0, len(snapshots))
+			for _, snapshot := range snapshots {
+				ids = append(ids, snapshot.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.expectedIDs, ",") {
+				t.Errorf("Expected snapshots %v (newest first), got %v", tc.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestGetLatestSnapshotWithoutProcessedSnapshots(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).snapshots = nil
+
+	_, err := service.getLatestSnapshot(GetLatestSnapshotArgs{NetworkID: "162112"})
+	if err == nil {
+		t.Fatal("Expected an error for a network without processed snapshots")
+	}
+	if !contains(err.Error(), "network 162112 has no processed snapshots yet; trigger collection first") {
+		t.Errorf("Expected friendly no-snapshot message, got: %v", err)
+	}
+
+	_, err = service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.0.1"})
+	if err == nil || !contains(err.Error(), "no processed snapshots yet") {
+		t.Errorf("Expected path search to explain the missing snapshot, got: %v", err)
+	}
+}
+
 func TestGetDeviceLocations(t *testing.T) {
 	service := createTestService()
 
@@ -638,10 +886,94 @@ func TestGetDeviceLocations(t *testing.T) {
 	}
 }
 
+func TestMapOutputsAreDeterministic(t *testing.T) {
+	service := createTestService()
+	locations := make(map[string]string)
+	for i := 0; i < 20; i++ {
+		locations[fmt.Sprintf("device-%02d", i)] = fmt.Sprintf("site-%d", i%3)
+	}
+	service.client().(*MockForwardClient).deviceLocations = locations
+
+	service.queryIndex = NewNQEQueryIndex(NewKeywordEmbeddingService(), createTestLogger())
+	seedQueryIndex(service.queryIndex,
+		"/L3/BGP/Neighbors", "/L2/VLAN/Members", "/Security/ACL/Rules", "/Interfaces/Errors/CRC",
+		"/L3/OSPF/Areas", "/L2/STP/Roots", "/Security/AAA/Servers", "/Interfaces/Optics/Levels")
+
+	render := func() string {
+		locationsResponse, err := service.getDeviceLocations(GetDeviceLocationsArgs{NetworkID: "162112"})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		statsResponse, err := service.getQueryIndexStats(GetQueryIndexStatsArgs{Detailed: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return locationsResponse.Content[0].TextContent.Text + statsResponse.Content[0].TextContent.Text
+	}
+
+	first := render()
+	for i := 0; i < 10; i++ {
+		if output := render(); output != first {
+			t.Fatalf("Expected byte-identical output on repeated calls, got:\n%s\nthen:\n%s", first, output)
+		}
+	}
+}
+
+// locationErrorClient fails only location lookups, for partial-failure tests
+type locationErrorClient struct {
+	*MockForwardClient
+}
+
+func (c *locationErrorClient) GetLocations(networkID string) ([]forward.Location, error) {
+	return nil, &MockError{"locations service unavailable"}
+}
+
+func TestGetNetworkSummary(t *testing.T) {
+	service := createTestService()
+
+	response, err := service.getNetworkSummary(GetNetworkSummaryArgs{NetworkID: "162112"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	for _, expected := range []string{"Devices: 2", "Locations: 2", "Snapshots:", "Latest snapshot:"} {
+		if !contains(content, expected) {
+			t.Errorf("Expected summary to contain %q, got: %s", expected, content)
+		}
+	}
+	if contains(content, "could not be retrieved") {
+		t.Errorf("Expected no partial-failure notice, got: %s", content)
+	}
+}
+
+func TestGetNetworkSummaryPartialFailure(t *testing.T) {
+	service := createTestService()
+	service.active().client = &locationErrorClient{NewMockForwardClient()}
+
+	response, err := service.getNetworkSummary(GetNetworkSummaryArgs{})
+	if err != nil {
+		t.Fatalf("Expected partial summary, got error: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	if !contains(content, "Devices: 2") || !contains(content, "Locations: unavailable") {
+		t.Errorf("Expected device count and unavailable locations, got: %s", content)
+	}
+	if !contains(content, "locations service unavailable") {
+		t.Errorf("Expected failure reason in summary, got: %s", content)
+	}
+
+	service.client().(*locationErrorClient).SetError(true, "API down")
+	if _, err := service.getNetworkSummary(GetNetworkSummaryArgs{}); err == nil {
+		t.Error("Expected error when every sub-call fails")
+	}
+}
+
 // Error Handling Tests
 func TestErrorHandling(t *testing.T) {
 	service := createTestService()
-	mockClient := service.forwardClient.(*MockForwardClient)
+	mockClient := service.client().(*MockForwardClient)
 
 	// Test error in listNetworks
 	mockClient.SetError(true, "API connection failed")
@@ -670,9 +1002,9 @@ func TestMCPIntegration(t *testing.T) {
 
 	// Create service with mock client and proper initialization
 	service := &ForwardMCPService{
-		forwardClient: NewMockForwardClient(),
-		config:        cfg,
-		logger:        logger.New(),
+		instance: newInstanceHolder(&instanceState{client: NewMockForwardClient()}),
+		config:   cfg,
+		logger:   logger.New(),
 		defaults: &ServiceDefaults{
 			NetworkID:  "162112",
 			SnapshotID: "",
@@ -835,3 +1167,108 @@ func (m *MockForwardClient) RunNQEQueryByString(params *forward.NQEQueryParams)
 	}
 	return m.nqeResult, nil
 }
+
+// recordingNQEClient records every RunNQEQueryByID call and answers it with respond, or
+// with the embedded mock's result when respond is nil
+type recordingNQEClient struct {
+	*MockForwardClient
+	respond  func(params *forward.NQEQueryParams) (*forward.NQERunResult, error)
+	mutex    sync.Mutex
+	queryIDs []string
+	params   []forward.NQEQueryParams
+}
+
+func (c *recordingNQEClient) RunNQEQueryByID(params *forward.NQEQueryParams) (*forward.NQERunResult, error) {
+	c.mutex.Lock()
+	c.queryIDs = append(c.queryIDs, params.QueryID)
+	c.params = append(c.params, *params)
+	c.mutex.Unlock()
+	if c.respond != nil {
+		return c.respond(params)
+	}
+	return c.MockForwardClient.RunNQEQueryByID(params)
+}
+
+// limits returns the row limit each run was sent
+func (c *recordingNQEClient) limits() []int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	limits := make([]int, 0, len(c.params))
+	for _, params := range c.params {
+		if params.Options != nil {
+			limits = append(limits, params.Options.Limit)
+		}
+	}
+	return limits
+}
+
+// resultsBy answers each run with the result stored under key(params), failing runs
+// whose key has no result with "unknown <kind> <key>"
+func resultsBy(kind string, key func(*forward.NQEQueryParams) string, results map[string]*forward.NQERunResult) func(*forward.NQEQueryParams) (*forward.NQERunResult, error) {
+	return func(params *forward.NQEQueryParams) (*forward.NQERunResult, error) {
+		result, ok := results[key(params)]
+		if !ok {
+			return nil, &MockError{fmt.Sprintf("unknown %s %s", kind, key(params))}
+		}
+		return result, nil
+	}
+}
+
+// resultsBySnapshot answers each run with the result for its snapshot ID
+func resultsBySnapshot(results map[string]*forward.NQERunResult) func(*forward.NQEQueryParams) (*forward.NQERunResult, error) {
+	return resultsBy("snapshot", func(params *forward.NQEQueryParams) string { return params.SnapshotID }, results)
+}
+
+// resultsByQueryID answers each run with the result for its query ID
+func resultsByQueryID(results map[string]*forward.NQERunResult) func(*forward.NQEQueryParams) (*forward.NQERunResult, error) {
+	return resultsBy("query", func(params *forward.NQEQueryParams) string { return params.QueryID }, results)
+}
+
+// nqeErrorClient fails every NQE run with err
+type nqeErrorClient struct {
+	*MockForwardClient
+	err error
+}
+
+func (c *nqeErrorClient) RunNQEQueryByID(params *forward.NQEQueryParams) (*forward.NQERunResult, error) {
+	return nil, c.err
+}
+
+func TestRunNQEQueryByIDDistinguishesNotFoundFromExecutionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    string
+		notWant string
+	}{
+		{
+			name:    "unknown query ID",
+			err:     fmt.Errorf("%w: FQ_missing", forward.ErrNQEQueryNotFound),
+			want:    "NQE query not found: FQ_missing; use search_nqe_queries to find a valid query ID",
+			notWant: "failed to execute",
+		},
+		{
+			name:    "execution error",
+			err:     &forward.NQEExecutionError{QueryID: "FQ_missing", Detail: "Division by zero", Err: &forward.APIError{StatusCode: 400}},
+			want:    "NQE query FQ_missing failed to execute: Division by zero",
+			notWant: "search_nqe_queries",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createTestService()
+			service.active().client = &nqeErrorClient{MockForwardClient: NewMockForwardClient(), err: tt.err}
+
+			_, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "FQ_missing", NoCache: true})
+			if err == nil {
+				t.Fatal("Expected the run to fail")
+			}
+			if !strings.Contains(err.Error(), tt.want) || strings.Contains(err.Error(), tt.notWant) {
+				t.Errorf("Expected %q (and not %q), got: %v", tt.want, tt.notWant, err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected the typed error to be kept, got: %v", err)
+			}
+		})
+	}
+}
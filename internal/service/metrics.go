@@ -0,0 +1,230 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// latencyBuckets are the histogram upper bounds, in seconds, for tool call and Forward
+// API request latency
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// latencyHistogram accumulates latency observations for a single tool or API route
+type latencyHistogram struct {
+	bucketCounts []int64 // non-cumulative counts per bucket; rendered cumulatively
+	sum          float64
+	count        int64
+}
+
+// observe adds one latency observation
+func (h *latencyHistogram) observe(duration time.Duration) {
+	seconds := duration.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.bucketCounts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// histogramFor returns the histogram stored under key, creating it on first use
+func histogramFor(histograms map[string]*latencyHistogram, key string) *latencyHistogram {
+	histogram, exists := histograms[key]
+	if !exists {
+		histogram = &latencyHistogram{bucketCounts: make([]int64, len(latencyBuckets))}
+		histograms[key] = histogram
+	}
+	return histogram
+}
+
+// ServiceMetrics collects Prometheus-style counters for MCP tool calls and Forward API
+// requests. A nil *ServiceMetrics is valid and records nothing.
+type ServiceMetrics struct {
+	mutex      sync.Mutex
+	toolCalls  map[string]int64
+	toolErrors map[string]int64
+	latencies  map[string]*latencyHistogram
+	// API request latency and failures, keyed by "<method> <endpoint>"
+	apiLatencies map[string]*latencyHistogram
+	apiErrors    map[string]int64
+}
+
+// NewServiceMetrics creates an empty metrics collector
+func NewServiceMetrics() *ServiceMetrics {
+	return &ServiceMetrics{
+		toolCalls:    make(map[string]int64),
+		toolErrors:   make(map[string]int64),
+		latencies:    make(map[string]*latencyHistogram),
+		apiLatencies: make(map[string]*latencyHistogram),
+		apiErrors:    make(map[string]int64),
+	}
+}
+
+// RecordToolCall records one tool invocation, its latency, and whether it failed, either
+// with an error or with a failure explained in its response
+func (m *ServiceMetrics) RecordToolCall(toolName string, duration time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.toolCalls[toolName]++
+	if failed {
+		m.toolErrors[toolName]++
+	}
+	histogramFor(m.latencies, toolName).observe(duration)
+}
+
+// RecordAPIRequest records the latency of one Forward API request and whether it failed
+// (no response, or a non-2xx status). It is the client's RequestObserver.
+func (m *ServiceMetrics) RecordAPIRequest(method, endpoint string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	route := method + " " + endpoint
+	histogramFor(m.apiLatencies, route).observe(duration)
+	if status < 200 || status >= 300 {
+		m.apiErrors[route]++
+	}
+}
+
+// WritePrometheus writes the tool metrics and cache counters in Prometheus text format
+func (m *ServiceMetrics) WritePrometheus(w io.Writer, cache *SemanticCache) {
+	if m != nil {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		var total int64
+		for _, count := range m.toolCalls {
+			total += count
+		}
+
+		fmt.Fprintln(w, "# HELP forward_mcp_requests_total Total number of MCP tool calls.")
+		fmt.Fprintln(w, "# TYPE forward_mcp_requests_total counter")
+		fmt.Fprintf(w, "forward_mcp_requests_total %d\n", total)
+
+		writeLabeledCounter(w, "forward_mcp_tool_calls_total", "Number of calls per MCP tool.", m.toolCalls)
+		writeLabeledCounter(w, "forward_mcp_tool_errors_total", "Number of failed calls per MCP tool.", m.toolErrors)
+
+		fmt.Fprintln(w, "# HELP forward_mcp_tool_duration_seconds Latency of MCP tool calls, including Forward API time.")
+		fmt.Fprintln(w, "# TYPE forward_mcp_tool_duration_seconds histogram")
+		for _, toolName := range sortedKeys(m.latencies) {
+			writeHistogram(w, "forward_mcp_tool_duration_seconds", fmt.Sprintf("tool=%q", toolName), m.latencies[toolName])
+		}
+
+		fmt.Fprintln(w, "# HELP forward_mcp_api_request_duration_seconds Latency of Forward API requests.")
+		fmt.Fprintln(w, "# TYPE forward_mcp_api_request_duration_seconds histogram")
+		for _, route := range sortedKeys(m.apiLatencies) {
+			writeHistogram(w, "forward_mcp_api_request_duration_seconds", routeLabels(route), m.apiLatencies[route])
+		}
+		fmt.Fprintln(w, "# HELP forward_mcp_api_request_errors_total Forward API requests that failed or returned a non-2xx status.")
+		fmt.Fprintln(w, "# TYPE forward_mcp_api_request_errors_total counter")
+		for _, route := range sortedKeys(m.apiErrors) {
+			fmt.Fprintf(w, "forward_mcp_api_request_errors_total{%s} %d\n", routeLabels(route), m.apiErrors[route])
+		}
+	}
+
+	if cache != nil {
+		hits, misses, entries := cache.Counters()
+		hitRatio := 0.0
+		if hits+misses > 0 {
+			hitRatio = float64(hits) / float64(hits+misses)
+		}
+
+		fmt.Fprintln(w, "# HELP forward_mcp_cache_hits_total Semantic cache hits.")
+		fmt.Fprintln(w, "# TYPE forward_mcp_cache_hits_total counter")
+		fmt.Fprintf(w, "forward_mcp_cache_hits_total %d\n", hits)
+		fmt.Fprintln(w, "# HELP forward_mcp_cache_misses_total Semantic cache misses.")
+		fmt.Fprintln(w, "# TYPE forward_mcp_cache_misses_total counter")
+		fmt.Fprintf(w, "forward_mcp_cache_misses_total %d\n", misses)
+		fmt.Fprintln(w, "# HELP forward_mcp_cache_hit_ratio Fraction of semantic cache lookups that hit.")
+		fmt.Fprintln(w, "# TYPE forward_mcp_cache_hit_ratio gauge")
+		fmt.Fprintf(w, "forward_mcp_cache_hit_ratio %g\n", hitRatio)
+		fmt.Fprintln(w, "# HELP forward_mcp_cache_entries Current number of semantic cache entries.")
+		fmt.Fprintln(w, "# TYPE forward_mcp_cache_entries gauge")
+		fmt.Fprintf(w, "forward_mcp_cache_entries %d\n", entries)
+	}
+}
+
+// writeHistogram writes the cumulative buckets, sum, and count of one histogram series
+func writeHistogram(w io.Writer, name, labels string, histogram *latencyHistogram) {
+	var cumulative int64
+	for i, bound := range latencyBuckets {
+		cumulative += histogram.bucketCounts[i]
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"%g\"} %d\n", name, labels, bound, cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, histogram.count)
+	fmt.Fprintf(w, "%s_sum{%s} %g\n", name, labels, histogram.sum)
+	fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, histogram.count)
+}
+
+// routeLabels renders a "<method> <endpoint>" key as Prometheus labels
+func routeLabels(route string) string {
+	method, endpoint, _ := strings.Cut(route, " ")
+	return fmt.Sprintf("method=%q,endpoint=%q", method, endpoint)
+}
+
+// writeLabeledCounter writes a counter family with one sample per tool
+func writeLabeledCounter(w io.Writer, name, help string, values map[string]int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	for _, toolName := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s{tool=%q} %d\n", name, toolName, values[toolName])
+	}
+}
+
+// sortedKeys returns map keys in lexical order for stable output
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keysByCount returns map keys ordered by descending count, breaking ties lexically so
+// equal counts render in a stable order
+func keysByCount(counts map[string]int) []string {
+	keys := sortedKeys(counts)
+	sort.SliceStable(keys, func(i, j int) bool { return counts[keys[i]] > counts[keys[j]] })
+	return keys
+}
+
+// renderMetrics returns the current metrics in Prometheus text format
+func (s *ForwardMCPService) renderMetrics() string {
+	var builder strings.Builder
+	s.metrics.WritePrometheus(&builder, s.semanticCache)
+	return builder.String()
+}
+
+// MetricsHandler serves the metrics in Prometheus text format for scraping
+func (s *ForwardMCPService) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		s.metrics.WritePrometheus(w, s.semanticCache)
+	})
+}
+
+// getMetrics returns tool call, error, latency, API latency, and cache metrics in Prometheus
+// text format
+func (s *ForwardMCPService) getMetrics(args GetMetricsArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("get_metrics", args, nil)
+
+	return mcp.NewToolResponse(mcp.NewTextContent(s.renderMetrics())), nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/forward-mcp/internal/config"
+)
+
+// Test that tool calls made through the middleware are reflected in the metrics output
+func TestMetricsReflectToolCalls(t *testing.T) {
+	service := createTestService()
+	service.metrics = NewServiceMetrics()
+
+	listNetworks := withToolMiddleware(service, "list_networks", (*ForwardMCPService).listNetworks)
+	for i := 0; i < 2; i++ {
+		if _, err := listNetworks(ListNetworksArgs{}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	// A failing Forward API call surfaces as a tool error
+	service.client().(*MockForwardClient).SetError(true, "API unavailable")
+	listSnapshots := withToolMiddleware(service, "list_snapshots", (*ForwardMCPService).listSnapshots)
+	if _, err := listSnapshots(ListSnapshotsArgs{NetworkID: "162112"}); err == nil {
+		t.Fatal("Expected error from failing client")
+	}
+
+	// Record a cache miss and a cache hit
+	service.semanticCache.Get("show devices", "162112", "")
+	if err := service.semanticCache.Put("show devices", "162112", "", service.client().(*MockForwardClient).nqeResult); err != nil {
+		t.Fatalf("Failed to populate cache: %v", err)
+	}
+	service.semanticCache.Get("show devices", "162112", "")
+
+	response, err := service.getMetrics(GetMetricsArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	output := response.Content[0].TextContent.Text
+
+	for _, expected := range []string{
+		"# TYPE forward_mcp_requests_total counter",
+		"forward_mcp_requests_total 3",
+		`forward_mcp_tool_calls_total{tool="list_networks"} 2`,
+		`forward_mcp_tool_calls_total{tool="list_snapshots"} 1`,
+		`forward_mcp_tool_errors_total{tool="list_snapshots"} 1`,
+		`forward_mcp_tool_duration_seconds_bucket{tool="list_networks",le="+Inf"} 2`,
+		`forward_mcp_tool_duration_seconds_count{tool="list_snapshots"} 1`,
+		"forward_mcp_cache_hits_total 1",
+		"forward_mcp_cache_misses_total 1",
+		"forward_mcp_cache_hit_ratio 0.5",
+	} {
+		if !contains(output, expected) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if contains(output, `forward_mcp_tool_errors_total{tool="list_networks"}`) {
+		t.Errorf("Expected no errors recorded for list_networks, got:\n%s", output)
+	}
+
+	// The HTTP handler serves the same metrics
+	recorder := httptest.NewRecorder()
+	service.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if !contains(recorder.Body.String(), `forward_mcp_tool_calls_total{tool="list_networks"} 2`) {
+		t.Errorf("Expected HTTP metrics to include tool counts, got:\n%s", recorder.Body.String())
+	}
+}
+
+// Test that failures explained in the response text count as tool errors
+func TestMetricsCountFailureResponsesAsErrors(t *testing.T) {
+	service := createTestService()
+	service.metrics = NewServiceMetrics()
+
+	setDefaultNetwork := withToolMiddleware(service, "set_default_network", (*ForwardMCPService).setDefaultNetwork)
+	if _, err := setDefaultNetwork(SetDefaultNetworkArgs{}); err != nil {
+		t.Fatalf("Expected the failure in the response, got error: %v", err)
+	}
+	listNetworks := withToolMiddleware(service, "list_networks", (*ForwardMCPService).listNetworks)
+	if _, err := listNetworks(ListNetworksArgs{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := service.renderMetrics()
+	if !contains(output, `forward_mcp_tool_errors_total{tool="set_default_network"} 1`) {
+		t.Errorf("Expected the failure response counted as an error, got:\n%s", output)
+	}
+	if contains(output, `forward_mcp_tool_errors_total{tool="list_networks"}`) {
+		t.Errorf("Expected the failure flag not to leak into later calls, got:\n%s", output)
+	}
+}
+
+// Test that Forward API requests are recorded per route with IDs collapsed
+func TestMetricsRecordForwardAPILatency(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/networks" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[]`))
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer api.Close()
+
+	service := createTestService()
+	service.metrics = NewServiceMetrics()
+	service.active().client = newForwardClient(&config.ForwardConfig{APIBaseURL: api.URL, Timeout: 5}, service.metrics)
+
+	if _, err := service.client().GetNetworks(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, networkID := range []string{"101", "202"} {
+		if _, err := service.client().GetSnapshots(networkID); err == nil {
+			t.Fatal("Expected an error from the failing endpoint")
+		}
+	}
+
+	output := service.renderMetrics()
+	for _, expected := range []string{
+		"# TYPE forward_mcp_api_request_duration_seconds histogram",
+		`forward_mcp_api_request_duration_seconds_count{method="GET",endpoint="/api/networks"} 1`,
+		`forward_mcp_api_request_duration_seconds_count{method="GET",endpoint="/api/networks/{id}/snapshots"} 2`,
+		`forward_mcp_api_request_errors_total{method="GET",endpoint="/api/networks/{id}/snapshots"} 2`,
+	} {
+		if !contains(output, expected) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if contains(output, `forward_mcp_api_request_errors_total{method="GET",endpoint="/api/networks"}`) {
+		t.Errorf("Expected no errors recorded for /api/networks, got:\n%s", output)
+	}
+}
+
+// Test that a service without metrics still serves tool calls
+func TestMetricsNilSafe(t *testing.T) {
+	service := createTestService()
+
+	handler := withToolMiddleware(service, "list_networks", (*ForwardMCPService).listNetworks)
+	if _, err := handler(ListNetworksArgs{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// newCorrelationID returns a short random ID that tags the log lines of one tool call
+func newCorrelationID() string {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "00000000"
+	}
+	return hex.EncodeToString(id)
+}
+
+// forCall returns a copy of the service whose logger tags every line with the call's
+// correlation ID. The copy shares all other state with s.
+func (s *ForwardMCPService) forCall(correlationID string) *ForwardMCPService {
+	call := *s
+	call.logger = s.logger.WithCorrelationID(correlationID)
+	call.toolFailed = false
+	return &call
+}
+
+// failureResponse returns text as the tool response and marks the call as failed, for
+// handlers that explain a failure in their response instead of returning an error
+func (s *ForwardMCPService) failureResponse(text string) *mcp.ToolResponse {
+	s.toolFailed = true
+	return mcp.NewToolResponse(mcp.NewTextContent(text))
+}
+
+// withToolMiddleware wraps a tool handler with the cross-cutting behaviour shared by
+// every tool: a per-call correlation ID on all log lines, concurrency limiting, call
+// counting, latency measurement, and response redaction and asset masking. Handlers are method
+// expressions so each call runs against its own correlation-scoped service.
+func withToolMiddleware[T any](s *ForwardMCPService, toolName string, handler func(*ForwardMCPService, T) (*mcp.ToolResponse, error)) func(T) (*mcp.ToolResponse, error) {
+	return func(args T) (*mcp.ToolResponse, error) {
+		start := time.Now()
+		call := s.forCall(newCorrelationID())
+		release, err := s.limiter.Acquire(toolName)
+		if err != nil {
+			s.metrics.RecordToolCall(toolName, time.Since(start), true)
+			call.logger.Debug("Tool %s rejected: %v", toolName, err)
+			return nil, err
+		}
+		defer release()
+
+		response, err := handler(call, args)
+		elapsed := time.Since(start)
+		s.metrics.RecordToolCall(toolName, elapsed, err != nil || call.toolFailed)
+		if err != nil {
+			call.logger.Debug("Tool %s failed after %s: %v", toolName, elapsed.Round(time.Millisecond), err)
+		} else {
+			call.logger.Debug("Tool %s completed in %s", toolName, elapsed.Round(time.Millisecond))
+		}
+		s.redactor.RedactResponse(response)
+		s.assetMasker.MaskResponse(response)
+		return response, err
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/forward-mcp/internal/logger"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// lockedBuffer serializes writes from the info and debug loggers, which lock separately
+type lockedBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.String()
+}
+
+func TestToolMiddlewareTagsLogsWithCorrelationID(t *testing.T) {
+	service := createTestService()
+	var output lockedBuffer
+	service.logger = logger.NewWithWriter(&output)
+	service.logger.SetDebugMode(true)
+
+	// Both calls log before either finishes, so their lines interleave
+	var started sync.WaitGroup
+	started.Add(2)
+	handler := withToolMiddleware(service, "test_tool", func(call *ForwardMCPService, args ListNetworksArgs) (*mcp.ToolResponse, error) {
+		call.logToolCall("test_tool", args, nil)
+		call.logger.Info("started call")
+		started.Done()
+		started.Wait()
+		call.logger.Info("finished call")
+		return mcp.NewToolResponse(mcp.NewTextContent("ok")), nil
+	})
+
+	var calls sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		calls.Add(1)
+		go func() {
+			defer calls.Done()
+			handler(ListNetworksArgs{})
+		}()
+	}
+	calls.Wait()
+
+	idPattern := regexp.MustCompile(`\[([0-9a-f]{8})\] `)
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	perID := make(map[string]int)
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "[") {
+			continue // continuation of a multi-line message
+		}
+		match := idPattern.FindStringSubmatch(line)
+		if match == nil {
+			t.Fatalf("Expected every log line to carry a correlation ID, got: %s", line)
+		}
+		perID[match[1]]++
+	}
+
+	if len(perID) != 2 {
+		t.Fatalf("Expected two distinct correlation IDs, got %v in:\n%s", perID, output.String())
+	}
+	// Each call logs its arguments, two handler lines, and its completion
+	for id, count := range perID {
+		if count != 4 {
+			t.Errorf("Expected 4 lines tagged %s, got %d in:\n%s", id, count, output.String())
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// MissingDevices lists the devices of a baseline snapshot that the latest snapshot lacks,
+// keeping their last-known attributes from the baseline
+func MissingDevices(baseline, latest []forward.Device) []forward.Device {
+	baselineDevices, baselineKeys := indexInventory(baseline)
+	latestDevices, _ := indexInventory(latest)
+
+	var missing []forward.Device
+	for _, key := range baselineKeys {
+		if _, ok := latestDevices[key]; !ok {
+			missing = append(missing, baselineDevices[key])
+		}
+	}
+	return missing
+}
+
+// detectMissingDevices reports devices present in a known-good baseline snapshot but
+// absent from the network's latest snapshot, a sign of an outage or lost collection
+func (s *ForwardMCPService) detectMissingDevices(args DetectMissingDevicesArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("detect_missing_devices", args, nil)
+
+	if args.BaselineSnapshot == "" {
+		return nil, fmt.Errorf("baseline_snapshot is required")
+	}
+	networkID := s.getNetworkID(args.NetworkID)
+	baseline, err := s.resolveSnapshotID(networkID, args.BaselineSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.latestSnapshot(networkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest snapshot: %w", err)
+	}
+	if latest.ID == baseline {
+		return nil, fmt.Errorf("baseline snapshot %s is the latest snapshot; pick an earlier known-good snapshot", baseline)
+	}
+
+	devicesBaseline, err := s.fetchAllDevices(networkID, baseline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices at snapshot %s: %w", baseline, err)
+	}
+	devicesLatest, err := s.fetchAllDevices(networkID, latest.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices at snapshot %s: %w", latest.ID, err)
+	}
+
+	missing := MissingDevices(devicesBaseline, devicesLatest)
+	if len(missing) == 0 {
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("✅ All %d devices of baseline snapshot %s are present in the latest snapshot %s of network %s.",
+			len(devicesBaseline), baseline, latest.ID, networkID))), nil
+	}
+
+	names := make([]string, len(missing))
+	for i, device := range missing {
+		names[i] = device.Name
+	}
+	header := fmt.Sprintf("🚨 %d of %d baseline devices are missing from the latest snapshot %s of network %s (baseline %s); last-known attributes",
+		len(missing), len(devicesBaseline), latest.ID, networkID, baseline)
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, names, missing, args.Pretty))), nil
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// configDiffQueryID is the library Config Diff query, which compares the run's snapshot
+// against the compareSnapshotId parameter
+const configDiffQueryID = "FQ_51f090cbea069b4049eb283716ab3bbb3f578aea"
+
+// interfaceStatusQuery selects the operational status of every interface
+const interfaceStatusQuery = `foreach device in network.devices
+foreach iface in device.interfaces
+select {device: device.name, interface: iface.name, operStatus: iface.operStatus}`
+
+// changeSummaryPageSize is the page size used when collecting change sub-query rows
+const changeSummaryPageSize = 10000
+
+// Kinds of network change, reported by summarize_changes
+const (
+	ChangeDevicesRemoved  = "devices removed"
+	ChangeInterfacesDown  = "interfaces went down"
+	ChangeDevicesAdded    = "devices added"
+	ChangeConfigChanged   = "device configs changed"
+	ChangeDevicesModified = "devices changed platform or OS"
+	ChangeInterfacesUp    = "interfaces came up"
+)
+
+// changePriority orders change kinds from most to least impactful
+var changePriority = []string{
+	ChangeDevicesRemoved,
+	ChangeInterfacesDown,
+	ChangeDevicesAdded,
+	ChangeConfigChanged,
+	ChangeDevicesModified,
+	ChangeInterfacesUp,
+}
+
+// NetworkChange is one kind of change between two snapshots and the items it affects
+type NetworkChange struct {
+	Kind   string   `json:"kind"`
+	Impact string   `json:"impact"`
+	Count  int      `json:"count"`
+	Items  []string `json:"items"`
+}
+
+// NetworkChangeSummary is the prioritized result of summarize_changes
+type NetworkChangeSummary struct {
+	NetworkID      string          `json:"network_id"`
+	BeforeSnapshot string          `json:"before_snapshot"`
+	AfterSnapshot  string          `json:"after_snapshot"`
+	Changes        []NetworkChange `json:"changes"`
+	Failures       []string        `json:"failures,omitempty"`
+}
+
+// changeRank returns a change kind's position in changePriority
+func changeRank(kind string) int {
+	for i, candidate := range changePriority {
+		if candidate == kind {
+			return i
+		}
+	}
+	return len(changePriority)
+}
+
+// changeImpact labels the top third of change kinds high, the middle medium, the rest low
+func changeImpact(kind string) string {
+	switch rank := changeRank(kind); {
+	case rank < 2:
+		return "high"
+	case rank < 4:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// InterfaceStatusChanges compares interface operational status keyed by "device interface",
+// returning interfaces that left and entered the UP state. Interfaces present in only
+// one snapshot are not counted.
+func InterfaceStatusChanges(before, after map[string]string) (down, up []string) {
+	for _, key := range sortedKeys(after) {
+		previous, existed := before[key]
+		if !existed {
+			continue
+		}
+		wasUp := strings.EqualFold(previous, "UP")
+		isUp := strings.EqualFold(after[key], "UP")
+		if wasUp && !isUp {
+			down = append(down, key)
+		} else if !wasUp && isUp {
+			up = append(up, key)
+		}
+	}
+	return down, up
+}
+
+// SummarizeNetworkChanges aggregates the device, interface, and config differences into
+// change groups, most impactful first. A nil inventory diff skips the device groups.
+func SummarizeNetworkChanges(inventory *DeviceInventoryDiff, interfacesDown, interfacesUp []string, configChanges map[string]int) []NetworkChange {
+	var changes []NetworkChange
+	add := func(kind string, items []string) {
+		if len(items) > 0 {
+			changes = append(changes, NetworkChange{Kind: kind, Impact: changeImpact(kind), Count: len(items), Items: items})
+		}
+	}
+
+	if inventory != nil {
+		add(ChangeDevicesRemoved, inventory.OnlyInA)
+		add(ChangeDevicesAdded, inventory.OnlyInB)
+		var modified []string
+		for _, device := range inventory.Different {
+			modified = append(modified, device.Name)
+		}
+		add(ChangeDevicesModified, modified)
+	}
+	add(ChangeInterfacesDown, interfacesDown)
+	add(ChangeInterfacesUp, interfacesUp)
+
+	devices := sortedKeys(configChanges)
+	sort.SliceStable(devices, func(i, j int) bool { return configChanges[devices[i]] > configChanges[devices[j]] })
+	var configItems []string
+	for _, device := range devices {
+		configItems = append(configItems, fmt.Sprintf("%s (%d lines)", device, configChanges[device]))
+	}
+	add(ChangeConfigChanged, configItems)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if rankI, rankJ := changeRank(changes[i].Kind), changeRank(changes[j].Kind); rankI != rankJ {
+			return rankI < rankJ
+		}
+		return changes[i].Count > changes[j].Count
+	})
+	return changes
+}
+
+// fetchInterfaceStatus pages through the interface status of one snapshot, keyed by
+// "device interface"
+func (s *ForwardMCPService) fetchInterfaceStatus(networkID, snapshotID string) (map[string]string, error) {
+	status := make(map[string]string)
+	for offset := 0; ; offset += changeSummaryPageSize {
+		result, err := s.client().RunNQEQueryByString(&forward.NQEQueryParams{
+			NetworkID:  networkID,
+			SnapshotID: snapshotID,
+			Query:      interfaceStatusQuery,
+			Options:    &forward.NQEQueryOptions{Limit: changeSummaryPageSize, Offset: offset},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Items {
+			device, _ := row["device"].(string)
+			iface, _ := row["interface"].(string)
+			if device == "" || iface == "" {
+				continue
+			}
+			status[device+" "+iface] = fmt.Sprint(row["operStatus"])
+		}
+		if len(result.Items) < changeSummaryPageSize {
+			return status, nil
+		}
+	}
+}
+
+// fetchConfigChanges runs the Config Diff query between two snapshots and counts the
+// changed rows per device
+func (s *ForwardMCPService) fetchConfigChanges(networkID, beforeSnapshot, afterSnapshot string) (map[string]int, error) {
+	changes := make(map[string]int)
+	for offset := 0; ; offset += changeSummaryPageSize {
+		_, result, _, err := s.fetchNQEResult(RunNQEQueryByIDArgs{
+			NetworkID:  networkID,
+			SnapshotID: beforeSnapshot,
+			QueryID:    configDiffQueryID,
+			Parameters: map[string]interface{}{"compareSnapshotId": afterSnapshot},
+			Options:    &NQEQueryOptions{Limit: changeSummaryPageSize, Offset: offset},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Items {
+			if device, ok := firstString(row, lifecycleDeviceColumns...); ok && device != "" {
+				changes[device]++
+			}
+		}
+		if len(result.Items) < changeSummaryPageSize {
+			return changes, nil
+		}
+	}
+}
+
+// formatChangeSummary renders the change groups as a numbered list, then any failed checks
+func formatChangeSummary(summary NetworkChangeSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Changes in network %s from %s to %s: ", summary.NetworkID, summary.BeforeSnapshot, summary.AfterSnapshot)
+	if len(summary.Changes) == 0 {
+		b.WriteString("no changes detected\n")
+	} else {
+		fmt.Fprintf(&b, "%d kinds of change, most impactful first\n", len(summary.Changes))
+		for i, change := range summary.Changes {
+			fmt.Fprintf(&b, "%d. [%s] %d %s: %s\n", i+1, change.Impact, change.Count, change.Kind, summarizeIdentifiers(change.Items))
+		}
+	}
+	if len(summary.Failures) > 0 {
+		b.WriteString("\n⚠️  Some checks could not be run:\n")
+		for _, failure := range summary.Failures {
+			fmt.Fprintf(&b, "  - %s\n", failure)
+		}
+	}
+	return b.String()
+}
+
+// summarizeChanges reports what changed across a network between two snapshots. Each
+// check runs independently, so one failing sub-query does not hide the others.
+func (s *ForwardMCPService) summarizeChanges(args SummarizeChangesArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("summarize_changes", args, nil)
+
+	if args.BeforeSnapshot == "" || args.AfterSnapshot == "" {
+		return nil, fmt.Errorf("both before_snapshot and after_snapshot are required")
+	}
+	networkID := s.getNetworkID(args.NetworkID)
+	var err error
+	if args.BeforeSnapshot, err = s.resolveSnapshotID(networkID, args.BeforeSnapshot); err != nil {
+		return nil, err
+	}
+	if args.AfterSnapshot, err = s.resolveSnapshotID(networkID, args.AfterSnapshot); err != nil {
+		return nil, err
+	}
+	summary := NetworkChangeSummary{NetworkID: networkID, BeforeSnapshot: args.BeforeSnapshot, AfterSnapshot: args.AfterSnapshot}
+
+	var inventory *DeviceInventoryDiff
+	devicesBefore, err := s.fetchAllDevices(networkID, args.BeforeSnapshot)
+	if err == nil {
+		var devicesAfter []forward.Device
+		if devicesAfter, err = s.fetchAllDevices(networkID, args.AfterSnapshot); err == nil {
+			diff := CompareDeviceInventories(devicesBefore, devicesAfter)
+			inventory = &diff
+		}
+	}
+	if err != nil {
+		summary.Failures = append(summary.Failures, fmt.Sprintf("devices: %v", err))
+	}
+
+	var interfacesDown, interfacesUp []string
+	statusBefore, err := s.fetchInterfaceStatus(networkID, args.BeforeSnapshot)
+	if err == nil {
+		var statusAfter map[string]string
+		if statusAfter, err = s.fetchInterfaceStatus(networkID, args.AfterSnapshot); err == nil {
+			interfacesDown, interfacesUp = InterfaceStatusChanges(statusBefore, statusAfter)
+		}
+	}
+	if err != nil {
+		summary.Failures = append(summary.Failures, fmt.Sprintf("interfaces: %v", err))
+	}
+
+	configChanges, err := s.fetchConfigChanges(networkID, args.BeforeSnapshot, args.AfterSnapshot)
+	if err != nil {
+		summary.Failures = append(summary.Failures, fmt.Sprintf("configs: %v", err))
+	}
+
+	if len(summary.Failures) == 3 {
+		return nil, fmt.Errorf("failed to summarize changes: %s", strings.Join(summary.Failures, "; "))
+	}
+	if len(summary.Failures) > 0 {
+		s.logger.Warn("Partial change summary for %s: %s", networkID, strings.Join(summary.Failures, "; "))
+	}
+
+	summary.Changes = SummarizeNetworkChanges(inventory, interfacesDown, interfacesUp, configChanges)
+	if summary.Changes == nil {
+		summary.Changes = []NetworkChange{}
+	}
+
+	text := formatChangeSummary(summary)
+	if !s.summaryMode() {
+		text += "\n" + s.toJSON(summary, args.Pretty)
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(text)), nil
+}
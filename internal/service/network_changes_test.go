@@ -0,0 +1,109 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// changeSummaryClient serves devices, interface status, and config diff rows per
+// snapshot; a nil configDiff fails the Config Diff query
+type changeSummaryClient struct {
+	*MockForwardClient
+	devices    map[string][]forward.Device
+	interfaces map[string][]map[string]interface{}
+	configDiff []map[string]interface{}
+}
+
+func (c *changeSummaryClient) GetDevices(networkID string, params *forward.DeviceQueryParams) (*forward.DeviceResponse, error) {
+	devices := c.devices[params.SnapshotID]
+	return &forward.DeviceResponse{Devices: devices, TotalCount: len(devices)}, nil
+}
+
+func (c *changeSummaryClient) RunNQEQueryByString(params *forward.NQEQueryParams) (*forward.NQERunResult, error) {
+	return &forward.NQERunResult{SnapshotID: params.SnapshotID, Items: c.interfaces[params.SnapshotID]}, nil
+}
+
+func (c *changeSummaryClient) RunNQEQueryByID(params *forward.NQEQueryParams) (*forward.NQERunResult, error) {
+	if c.configDiff == nil {
+		return nil, &MockError{"config diff query timed out"}
+	}
+	return &forward.NQERunResult{Items: c.configDiff}, nil
+}
+
+func newChangeSummaryClient() *changeSummaryClient {
+	return &changeSummaryClient{
+		MockForwardClient: NewMockForwardClient(),
+		devices: map[string][]forward.Device{
+			"snap-1": {{Name: "router-1", OSVersion: "17.3"}, {Name: "switch-1"}, {Name: "switch-2"}},
+			"snap-2": {{Name: "router-1", OSVersion: "17.6"}, {Name: "switch-2"}, {Name: "fw-1"}},
+		},
+		interfaces: map[string][]map[string]interface{}{
+			"snap-1": {
+				{"device": "router-1", "interface": "Gi0/1", "operStatus": "UP"},
+				{"device": "router-1", "interface": "Gi0/2", "operStatus": "DOWN"},
+			},
+			"snap-2": {
+				{"device": "router-1", "interface": "Gi0/1", "operStatus": "DOWN"},
+				{"device": "router-1", "interface": "Gi0/2", "operStatus": "UP"},
+			},
+		},
+		configDiff: []map[string]interface{}{
+			{"device": "switch-2", "line": "+ ntp server 10.0.0.5"},
+			{"device": "router-1", "line": "- ip route 0.0.0.0 0.0.0.0 10.0.0.1"},
+			{"device": "router-1", "line": "+ ip route 0.0.0.0 0.0.0.0 10.0.0.2"},
+		},
+	}
+}
+
+func TestSummarizeChangesAggregatesDeviceAndConfigChanges(t *testing.T) {
+	service := createTestService()
+	service.active().client = newChangeSummaryClient()
+
+	response, err := service.summarizeChanges(SummarizeChangesArgs{NetworkID: "162112", BeforeSnapshot: "snap-1", AfterSnapshot: "snap-2"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+
+	expected := []string{
+		"1. [high] 1 devices removed: switch-1",
+		"2. [high] 1 interfaces went down: router-1 Gi0/1",
+		"3. [medium] 1 devices added: fw-1",
+		"4. [medium] 2 device configs changed: router-1 (2 lines), switch-2 (1 lines)",
+		"5. [low] 1 devices changed platform or OS: router-1",
+		"6. [low] 1 interfaces came up: router-1 Gi0/2",
+	}
+	for _, line := range expected {
+		if !strings.Contains(text, line) {
+			t.Errorf("Expected %q in the summary, got:\n%s", line, text)
+		}
+	}
+	if strings.Contains(text, "could not be run") {
+		t.Errorf("Expected no failed checks, got:\n%s", text)
+	}
+}
+
+func TestSummarizeChangesToleratesFailedSubQuery(t *testing.T) {
+	service := createTestService()
+	client := newChangeSummaryClient()
+	client.configDiff = nil
+	service.active().client = client
+
+	response, err := service.summarizeChanges(SummarizeChangesArgs{NetworkID: "162112", BeforeSnapshot: "snap-1", AfterSnapshot: "snap-2"})
+	if err != nil {
+		t.Fatalf("Expected a partial summary, got error: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "devices removed: switch-1") || strings.Contains(text, "configs changed") {
+		t.Errorf("Expected device changes without config changes, got:\n%s", text)
+	}
+	if !strings.Contains(text, "configs: failed to run NQE query: config diff query timed out") {
+		t.Errorf("Expected the failed config check to be listed, got:\n%s", text)
+	}
+
+	if _, err := service.summarizeChanges(SummarizeChangesArgs{BeforeSnapshot: "snap-1"}); err == nil {
+		t.Error("Expected an error without after_snapshot")
+	}
+}
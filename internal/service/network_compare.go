@@ -0,0 +1,205 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// compareNetworksPageSize is the page size used when fetching full device inventories
+const compareNetworksPageSize = 1000
+
+// DeviceFieldChange is one inventory attribute that differs between two networks
+type DeviceFieldChange struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+// DeviceInventoryChange is a device present in both networks with differing attributes
+type DeviceInventoryChange struct {
+	Name    string              `json:"name"`
+	Changes []DeviceFieldChange `json:"changes"`
+}
+
+// DeviceInventoryDiff is the device-level difference between two network inventories
+type DeviceInventoryDiff struct {
+	OnlyInA   []string                `json:"only_in_a"`
+	OnlyInB   []string                `json:"only_in_b"`
+	Different []DeviceInventoryChange `json:"different"`
+	Matching  int                     `json:"matching"`
+}
+
+// inventoryKey aligns devices across networks by name, falling back to hostname
+func inventoryKey(device forward.Device) string {
+	if device.Name != "" {
+		return strings.ToLower(device.Name)
+	}
+	return strings.ToLower(device.Hostname)
+}
+
+// deviceOSVersion prefers the OS version and falls back to the generic version field
+func deviceOSVersion(device forward.Device) string {
+	if device.OSVersion != "" {
+		return device.OSVersion
+	}
+	return device.Version
+}
+
+// indexInventory maps devices by inventoryKey, skipping devices with neither name nor hostname
+func indexInventory(devices []forward.Device) (map[string]forward.Device, []string) {
+	index := make(map[string]forward.Device, len(devices))
+	keys := make([]string, 0, len(devices))
+	for _, device := range devices {
+		key := inventoryKey(device)
+		if key == "" {
+			continue
+		}
+		if _, exists := index[key]; exists {
+			continue
+		}
+		index[key] = device
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return index, keys
+}
+
+// CompareDeviceInventories classifies devices as present only in a, only in b, or present
+// in both with a different vendor, model, platform, or OS version
+func CompareDeviceInventories(a, b []forward.Device) DeviceInventoryDiff {
+	var diff DeviceInventoryDiff
+
+	aDevices, aKeys := indexInventory(a)
+	bDevices, bKeys := indexInventory(b)
+
+	for _, key := range aKeys {
+		aDevice := aDevices[key]
+		bDevice, ok := bDevices[key]
+		if !ok {
+			diff.OnlyInA = append(diff.OnlyInA, aDevice.Name)
+			continue
+		}
+
+		var changes []DeviceFieldChange
+		fields := []struct {
+			name string
+			a, b string
+		}{
+			{"vendor", aDevice.Vendor, bDevice.Vendor},
+			{"model", aDevice.Model, bDevice.Model},
+			{"platform", aDevice.Platform, bDevice.Platform},
+			{"os_version", deviceOSVersion(aDevice), deviceOSVersion(bDevice)},
+		}
+		for _, field := range fields {
+			if !strings.EqualFold(field.a, field.b) {
+				changes = append(changes, DeviceFieldChange{Field: field.name, A: field.a, B: field.b})
+			}
+		}
+		if len(changes) == 0 {
+			diff.Matching++
+			continue
+		}
+		diff.Different = append(diff.Different, DeviceInventoryChange{Name: aDevice.Name, Changes: changes})
+	}
+
+	for _, key := range bKeys {
+		if _, ok := aDevices[key]; !ok {
+			diff.OnlyInB = append(diff.OnlyInB, bDevices[key].Name)
+		}
+	}
+
+	return diff
+}
+
+// fetchAllDevices pages through a network's full device inventory
+func (s *ForwardMCPService) fetchAllDevices(networkID, snapshotID string) ([]forward.Device, error) {
+	var devices []forward.Device
+	for {
+		response, err := s.client().GetDevices(networkID, &forward.DeviceQueryParams{
+			SnapshotID: snapshotID,
+			Limit:      compareNetworksPageSize,
+			Offset:     len(devices),
+		})
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, response.Devices...)
+		if len(response.Devices) == 0 || len(devices) >= response.TotalCount {
+			return devices, nil
+		}
+	}
+}
+
+// formatInventoryDiff renders the diff as three compact sections
+func formatInventoryDiff(networkA, networkB string, countA, countB int, diff DeviceInventoryDiff) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Device inventory %s (%d devices) vs %s (%d devices): %d only in %s, %d only in %s, %d different, %d matching\n",
+		networkA, countA, networkB, countB, len(diff.OnlyInA), networkA, len(diff.OnlyInB), networkB, len(diff.Different), diff.Matching)
+
+	writeNames := func(label string, names []string) {
+		fmt.Fprintf(&b, "\nOnly in %s (%d):", label, len(names))
+		if len(names) == 0 {
+			b.WriteString(" none\n")
+			return
+		}
+		fmt.Fprintf(&b, " %s\n", strings.Join(names, ", "))
+	}
+	writeNames(networkA, diff.OnlyInA)
+	writeNames(networkB, diff.OnlyInB)
+
+	fmt.Fprintf(&b, "\nDifferent (%d):", len(diff.Different))
+	if len(diff.Different) == 0 {
+		b.WriteString(" none\n")
+		return b.String()
+	}
+	b.WriteString("\n")
+	for _, device := range diff.Different {
+		parts := make([]string, 0, len(device.Changes))
+		for _, change := range device.Changes {
+			parts = append(parts, fmt.Sprintf("%s %s -> %s", change.Field, orNone(change.A), orNone(change.B)))
+		}
+		fmt.Fprintf(&b, "• %s: %s\n", device.Name, strings.Join(parts, "; "))
+	}
+	return b.String()
+}
+
+// orNone renders empty attribute values visibly
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// compareNetworks diffs the device inventories of two networks
+func (s *ForwardMCPService) compareNetworks(args CompareNetworksArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("compare_networks", args, nil)
+
+	if args.NetworkA == "" || args.NetworkB == "" {
+		return nil, fmt.Errorf("both network_a and network_b are required")
+	}
+
+	var err error
+	if args.SnapshotA, err = s.resolveSnapshotID(args.NetworkA, args.SnapshotA); err != nil {
+		return nil, err
+	}
+	if args.SnapshotB, err = s.resolveSnapshotID(args.NetworkB, args.SnapshotB); err != nil {
+		return nil, err
+	}
+
+	devicesA, err := s.fetchAllDevices(args.NetworkA, args.SnapshotA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices for network %s: %w", args.NetworkA, err)
+	}
+	devicesB, err := s.fetchAllDevices(args.NetworkB, args.SnapshotB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices for network %s: %w", args.NetworkB, err)
+	}
+
+	diff := CompareDeviceInventories(devicesA, devicesB)
+	return mcp.NewToolResponse(mcp.NewTextContent(formatInventoryDiff(args.NetworkA, args.NetworkB, len(devicesA), len(devicesB), diff))), nil
+}
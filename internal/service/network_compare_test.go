@@ -0,0 +1,84 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// perNetworkDeviceClient returns a different device inventory for each network
+type perNetworkDeviceClient struct {
+	*MockForwardClient
+	inventories map[string][]forward.Device
+}
+
+func (c *perNetworkDeviceClient) GetDevices(networkID string, params *forward.DeviceQueryParams) (*forward.DeviceResponse, error) {
+	devices := c.inventories[networkID]
+	return &forward.DeviceResponse{Devices: devices, TotalCount: len(devices)}, nil
+}
+
+func compareNetworksTestInventories() map[string][]forward.Device {
+	return map[string][]forward.Device{
+		"staging": {
+			{Name: "core-1", Vendor: "cisco", Model: "ASR1001", OSVersion: "17.3.1"},
+			{Name: "edge-1", Vendor: "juniper", Model: "MX204", OSVersion: "21.4R1"},
+			{Name: "lab-1", Vendor: "arista", Model: "7050", OSVersion: "4.28"},
+		},
+		"production": {
+			{Name: "CORE-1", Vendor: "cisco", Model: "ASR1001", OSVersion: "17.6.4"},
+			{Name: "edge-1", Vendor: "juniper", Model: "MX204", OSVersion: "21.4R1"},
+			{Name: "dc-1", Vendor: "arista", Model: "7280", OSVersion: "4.30"},
+		},
+	}
+}
+
+func TestCompareDeviceInventories(t *testing.T) {
+	inventories := compareNetworksTestInventories()
+	diff := CompareDeviceInventories(inventories["staging"], inventories["production"])
+
+	if !reflect.DeepEqual(diff.OnlyInA, []string{"lab-1"}) {
+		t.Errorf("Expected lab-1 only in staging, got %v", diff.OnlyInA)
+	}
+	if !reflect.DeepEqual(diff.OnlyInB, []string{"dc-1"}) {
+		t.Errorf("Expected dc-1 only in production, got %v", diff.OnlyInB)
+	}
+	if diff.Matching != 1 {
+		t.Errorf("Expected 1 matching device, got %d", diff.Matching)
+	}
+	if len(diff.Different) != 1 || diff.Different[0].Name != "core-1" {
+		t.Fatalf("Expected core-1 to differ, got %+v", diff.Different)
+	}
+	expected := []DeviceFieldChange{{Field: "os_version", A: "17.3.1", B: "17.6.4"}}
+	if !reflect.DeepEqual(diff.Different[0].Changes, expected) {
+		t.Errorf("Expected only the OS version to differ, got %+v", diff.Different[0].Changes)
+	}
+}
+
+func TestCompareNetworksTool(t *testing.T) {
+	service := createTestService()
+	service.active().client = &perNetworkDeviceClient{
+		MockForwardClient: service.client().(*MockForwardClient),
+		inventories:       compareNetworksTestInventories(),
+	}
+
+	response, err := service.compareNetworks(CompareNetworksArgs{NetworkA: "staging", NetworkB: "production"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	for _, expected := range []string{
+		"1 only in staging, 1 only in production, 1 different, 1 matching",
+		"Only in staging (1): lab-1",
+		"Only in production (1): dc-1",
+		"• core-1: os_version 17.3.1 -> 17.6.4",
+	} {
+		if !contains(text, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, text)
+		}
+	}
+
+	if _, err := service.compareNetworks(CompareNetworksArgs{NetworkA: "staging"}); err == nil {
+		t.Error("Expected error when network_b is missing")
+	}
+}
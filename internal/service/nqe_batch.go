@@ -0,0 +1,126 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// Limits for running one NQE query with many parameter sets
+const (
+	maxBatchParameterSets = 50
+	batchConcurrency      = 4
+)
+
+// NQEBatchResult summarizes one parameter set's run; Error is set when the run failed
+type NQEBatchResult struct {
+	Parameters map[string]interface{} `json:"parameters"`
+	Rows       int                    `json:"rows"`
+	Columns    []string               `json:"columns,omitempty"`
+	Cached     bool                   `json:"cached"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// batchParameterKey canonicalizes a parameter set; encoding/json sorts map keys, so equal
+// sets produce equal keys
+func batchParameterKey(parameters map[string]interface{}) string {
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return fmt.Sprint(parameters)
+	}
+	return string(data)
+}
+
+// runNQEQueryBatch runs one query once per parameter set and summarizes each run. Runs are
+// bounded to batchConcurrency at once and each goes through the NQE result cache, so repeated
+// sets are served from the cache; identical sets within one batch run only once. A failed set
+// is reported in its summary rather than failing the batch.
+func (s *ForwardMCPService) runNQEQueryBatch(args RunNQEQueryBatchArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("run_nqe_query_batch", args, nil)
+
+	if args.QueryID == "" {
+		return nil, fmt.Errorf("query_id is required")
+	}
+	if len(args.ParameterSets) == 0 {
+		return nil, fmt.Errorf("parameter_sets must contain at least one parameter map")
+	}
+	if len(args.ParameterSets) > maxBatchParameterSets {
+		return nil, fmt.Errorf("too many parameter sets: %d (max %d per batch)", len(args.ParameterSets), maxBatchParameterSets)
+	}
+	networkID := s.getNetworkID(args.NetworkID)
+
+	results := make([]NQEBatchResult, len(args.ParameterSets))
+	first := make(map[string]int)
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, parameters := range args.ParameterSets {
+		results[i].Parameters = parameters
+		key := batchParameterKey(parameters)
+		if _, seen := first[key]; seen {
+			continue
+		}
+		first[key] = i
+
+		wg.Add(1)
+		go func(result *NQEBatchResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			_, run, cachedAt, err := s.fetchNQEResult(RunNQEQueryByIDArgs{
+				NetworkID:  networkID,
+				QueryID:    args.QueryID,
+				SnapshotID: args.SnapshotID,
+				Parameters: result.Parameters,
+				Options:    args.Options,
+				NoCache:    args.NoCache,
+			})
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			result.Rows = len(run.Items)
+			result.Columns = nqeResultColumns(run.Items)
+			result.Cached = !cachedAt.IsZero()
+		}(&results[i])
+	}
+	wg.Wait()
+
+	// Repeated sets share the first run's result, which they were served from
+	for i := range results {
+		source := first[batchParameterKey(results[i].Parameters)]
+		if source != i {
+			results[i].Rows = results[source].Rows
+			results[i].Columns = results[source].Columns
+			results[i].Error = results[source].Error
+			results[i].Cached = results[source].Error == ""
+		}
+	}
+
+	cached, failed := 0, 0
+	identifiers := make([]string, 0, len(results))
+	for i, result := range results {
+		label := fmt.Sprintf("#%d %s", i+1, batchParameterKey(result.Parameters))
+		switch {
+		case result.Error != "":
+			failed++
+			identifiers = append(identifiers, label+"=error")
+		case result.Cached:
+			cached++
+			identifiers = append(identifiers, fmt.Sprintf("%s=%d rows (cached)", label, result.Rows))
+		default:
+			identifiers = append(identifiers, fmt.Sprintf("%s=%d rows", label, result.Rows))
+		}
+	}
+	if failed == len(results) {
+		return nil, fmt.Errorf("query %s failed for all %d parameter sets: %s", args.QueryID, len(results), results[0].Error)
+	}
+
+	header := fmt.Sprintf("%s ran with %d parameter sets on %s (%d cached, %d failed)", args.QueryID, len(results), networkID, cached, failed)
+	if args.NoCache {
+		header += "; cache bypassed"
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, identifiers, results, args.Pretty))), nil
+}
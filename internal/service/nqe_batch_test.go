@@ -0,0 +1,59 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestRunNQEQueryBatch(t *testing.T) {
+	service := createTestService()
+	client := &recordingNQEClient{
+		MockForwardClient: NewMockForwardClient(),
+		respond: resultsBy("location", func(params *forward.NQEQueryParams) string { return params.Parameters["loc"].(string) }, map[string]*forward.NQERunResult{
+			"NYC": {Items: []map[string]interface{}{{"name": "nyc-1"}, {"name": "nyc-2"}}},
+			"SFO": {Items: []map[string]interface{}{{"name": "sfo-1"}}},
+			"LON": {Items: []map[string]interface{}{}},
+		}),
+	}
+	service.active().client = client
+
+	args := RunNQEQueryBatchArgs{
+		NetworkID: "162112",
+		QueryID:   "FQ_devices_at_location",
+		ParameterSets: []map[string]interface{}{
+			{"loc": "NYC"}, {"loc": "SFO"}, {"loc": "LON"},
+		},
+	}
+	response, err := service.runNQEQueryBatch(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "FQ_devices_at_location ran with 3 parameter sets on 162112 (0 cached, 0 failed)") {
+		t.Errorf("Expected a batch header, got:\n%s", text)
+	}
+	for _, expected := range []string{`"rows": 2`, `"rows": 1`, `"rows": 0`} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected %s in the per-set summaries, got:\n%s", expected, text)
+		}
+	}
+	if len(client.queryIDs) != 3 {
+		t.Errorf("Expected 3 API runs, got %d", len(client.queryIDs))
+	}
+
+	// Identical sets, within a batch and across batches, are served from the cache
+	args.ParameterSets = []map[string]interface{}{{"loc": "NYC"}, {"loc": "NYC"}, {"loc": "BOS"}}
+	response, err = service.runNQEQueryBatch(args)
+	if err != nil {
+		t.Fatalf("Expected a partial batch, got error: %v", err)
+	}
+	text = response.Content[0].TextContent.Text
+	if !strings.Contains(text, "(2 cached, 1 failed)") || !strings.Contains(text, "unknown location") {
+		t.Errorf("Expected two cached sets and one failure, got:\n%s", text)
+	}
+	if len(client.queryIDs) != 4 {
+		t.Errorf("Expected only the new set to reach the API, got %d runs", len(client.queryIDs))
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// NQE result combination modes
+const (
+	CombineIntersection = "intersection"
+	CombineUnion        = "union"
+	CombineDifference   = "difference"
+)
+
+// NQECombineResult is the outcome of combining two NQE result sets on a key column
+type NQECombineResult struct {
+	Mode       string                   `json:"mode"`
+	KeyColumn  string                   `json:"key_column"`
+	FirstRows  int                      `json:"first_rows"`
+	SecondRows int                      `json:"second_rows"`
+	Keys       []string                 `json:"keys"`
+	Items      []map[string]interface{} `json:"items"`
+}
+
+// nqeKeySet collects the keys present in rows, skipping rows without a value for the key
+// column. It fails when no row has the column at all.
+func nqeKeySet(rows []map[string]interface{}, keyColumn, label string) (map[string]bool, error) {
+	keys := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if row[keyColumn] == nil {
+			continue
+		}
+		keys[nqeRowKey(row, keyColumn)] = true
+	}
+	if len(keys) == 0 && len(rows) > 0 {
+		return nil, fmt.Errorf("key column %q not found in the %s query's results (available: %s)", keyColumn, label, strings.Join(nqeResultColumns(rows), ", "))
+	}
+	return keys, nil
+}
+
+// CombineNQERows combines two result sets by the keys in their key columns. Rows keep the
+// columns of the query they came from: intersection returns first rows whose key also
+// appears in second, difference returns first rows whose key does not, and union returns
+// every first row plus the second rows whose key is not in first. Every row sharing a key
+// is kept, so the key columns need not be unique.
+func CombineNQERows(first, second []map[string]interface{}, firstKey, secondKey, mode string) (NQECombineResult, error) {
+	result := NQECombineResult{Mode: mode, KeyColumn: firstKey, FirstRows: len(first), SecondRows: len(second)}
+
+	firstKeys, err := nqeKeySet(first, firstKey, "first")
+	if err != nil {
+		return result, err
+	}
+	secondKeys, err := nqeKeySet(second, secondKey, "second")
+	if err != nil {
+		return result, err
+	}
+
+	seen := make(map[string]bool)
+	keep := func(row map[string]interface{}, keyColumn string) {
+		result.Items = append(result.Items, row)
+		if key := nqeRowKey(row, keyColumn); !seen[key] {
+			seen[key] = true
+			result.Keys = append(result.Keys, key)
+		}
+	}
+	switch mode {
+	case CombineIntersection, CombineDifference:
+		for _, row := range first {
+			if row[firstKey] == nil {
+				continue
+			}
+			if secondKeys[nqeRowKey(row, firstKey)] == (mode == CombineIntersection) {
+				keep(row, firstKey)
+			}
+		}
+	case CombineUnion:
+		for _, row := range first {
+			if row[firstKey] != nil {
+				keep(row, firstKey)
+			}
+		}
+		for _, row := range second {
+			if row[secondKey] != nil && !firstKeys[nqeRowKey(row, secondKey)] {
+				keep(row, secondKey)
+			}
+		}
+	default:
+		return result, fmt.Errorf("invalid mode %q: must be intersection, union, or difference", mode)
+	}
+	return result, nil
+}
+
+// fetchNQESourceRows runs one side of a combination: a library query through the result
+// cache, or inline NQE source directly
+func (s *ForwardMCPService) fetchNQESourceRows(source NQEResultSource, networkID, snapshotID string, options *NQEQueryOptions, noCache bool, label string) ([]map[string]interface{}, error) {
+	if (source.QueryID == "") == (source.Query == "") {
+		return nil, fmt.Errorf("the %s query needs exactly one of query_id or query", label)
+	}
+
+	if source.QueryID != "" {
+		_, result, _, err := s.fetchNQEResult(RunNQEQueryByIDArgs{
+			NetworkID:  networkID,
+			SnapshotID: snapshotID,
+			QueryID:    source.QueryID,
+			Parameters: source.Parameters,
+			Options:    options,
+			NoCache:    noCache,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to run the %s query %s: %w", label, source.QueryID, err)
+		}
+		return result.Items, nil
+	}
+
+	queryOptions, err := s.convertNQEQueryOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.client().RunNQEQueryByString(&forward.NQEQueryParams{
+		NetworkID:  networkID,
+		SnapshotID: snapshotID,
+		Query:      source.Query,
+		Parameters: source.Parameters,
+		Options:    queryOptions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run the %s inline query: %w", label, err)
+	}
+	return result.Items, nil
+}
+
+// combineNQEResults runs two NQE queries on the same snapshot and combines their rows by a
+// key column as an intersection, union, or difference
+func (s *ForwardMCPService) combineNQEResults(args CombineNQEResultsArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("combine_nqe_results", args, nil)
+
+	if args.KeyColumn == "" {
+		return nil, fmt.Errorf("key_column is required")
+	}
+	mode := strings.ToLower(strings.TrimSpace(args.Mode))
+	switch mode {
+	case "":
+		mode = CombineIntersection
+	case CombineIntersection, CombineUnion, CombineDifference:
+	default:
+		return nil, fmt.Errorf("invalid mode %q: must be intersection, union, or difference", args.Mode)
+	}
+	secondKey := args.SecondKeyColumn
+	if secondKey == "" {
+		secondKey = args.KeyColumn
+	}
+
+	// Resolve the snapshot once so both queries see the same data
+	networkID := s.getNetworkID(args.NetworkID)
+	snapshotID, err := s.resolveSnapshotID(networkID, s.getSnapshotID(args.SnapshotID))
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := s.fetchNQESourceRows(args.First, networkID, snapshotID, args.Options, args.NoCache, "first")
+	if err != nil {
+		return nil, err
+	}
+	second, err := s.fetchNQESourceRows(args.Second, networkID, snapshotID, args.Options, args.NoCache, "second")
+	if err != nil {
+		return nil, err
+	}
+
+	combined, err := CombineNQERows(first, second, args.KeyColumn, secondKey, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("%s of %d and %d rows on %s: %d rows with %d distinct keys",
+		strings.ToUpper(mode[:1])+mode[1:], len(first), len(second), args.KeyColumn, len(combined.Items), len(combined.Keys))
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, combined.Keys, combined, args.Pretty))), nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func eolAndBGPRows() (eol, bgp []map[string]interface{}) {
+	eol = []map[string]interface{}{
+		{"device": "edge-1", "endOfLife": "2023-01-01"},
+		{"device": "core-1", "endOfLife": "2022-06-30"},
+	}
+	bgp = []map[string]interface{}{
+		{"deviceName": "edge-1", "neighbor": "10.0.0.1"},
+		{"deviceName": "edge-1", "neighbor": "10.0.0.2"},
+		{"deviceName": "edge-2", "neighbor": "10.0.0.3"},
+	}
+	return eol, bgp
+}
+
+func combinedKeys(t *testing.T, first, second []map[string]interface{}, mode string) []string {
+	t.Helper()
+	combined, err := CombineNQERows(first, second, "device", "deviceName", mode)
+	if err != nil {
+		t.Fatalf("CombineNQERows(%s) failed: %v", mode, err)
+	}
+	return combined.Keys
+}
+
+func TestCombineNQERowsModes(t *testing.T) {
+	eol, bgp := eolAndBGPRows()
+
+	if keys := combinedKeys(t, eol, bgp, CombineIntersection); !reflect.DeepEqual(keys, []string{"edge-1"}) {
+		t.Errorf("Expected edge-1 in both sets, got %v", keys)
+	}
+	if keys := combinedKeys(t, eol, bgp, CombineDifference); !reflect.DeepEqual(keys, []string{"core-1"}) {
+		t.Errorf("Expected only core-1 without BGP, got %v", keys)
+	}
+	combined, _ := CombineNQERows(eol, bgp, "device", "deviceName", CombineUnion)
+	if !reflect.DeepEqual(combined.Keys, []string{"edge-1", "core-1", "edge-2"}) || len(combined.Items) != 3 {
+		t.Errorf("Expected the union of both key sets with one row each, got %v (%d rows)", combined.Keys, len(combined.Items))
+	}
+
+	if _, err := CombineNQERows(eol, bgp, "device", "device", CombineIntersection); err == nil || !strings.Contains(err.Error(), `key column "device" not found in the second query's results (available: deviceName, neighbor)`) {
+		t.Errorf("Expected a missing key column error, got %v", err)
+	}
+}
+
+func TestCombineNQEResultsIntersection(t *testing.T) {
+	service := createTestService()
+	eol, bgp := eolAndBGPRows()
+	client := &recordingNQEClient{
+		MockForwardClient: NewMockForwardClient(),
+		respond:           resultsByQueryID(map[string]*forward.NQERunResult{"FQ_eol": {SnapshotID: "snap-1", Items: eol}}),
+	}
+	client.nqeResult = &forward.NQERunResult{SnapshotID: "snap-1", Items: bgp}
+	service.active().client = client
+
+	response, err := service.combineNQEResults(CombineNQEResultsArgs{
+		First:           NQEResultSource{QueryID: "FQ_eol"},
+		Second:          NQEResultSource{Query: "foreach device in network.devices select {deviceName: device.name}"},
+		KeyColumn:       "device",
+		SecondKeyColumn: "deviceName",
+		NoCache:         true,
+	})
+	if err != nil {
+		t.Fatalf("combineNQEResults failed: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.HasPrefix(text, "Intersection of 2 and 3 rows on device: 1 rows with 1 distinct keys") {
+		t.Errorf("Unexpected header:\n%s", text)
+	}
+	var combined NQECombineResult
+	if err := json.Unmarshal([]byte(text[strings.Index(text, "{"):]), &combined); err != nil {
+		t.Fatalf("Expected a JSON payload, got %v:\n%s", err, text)
+	}
+	if len(combined.Items) != 1 || combined.Items[0]["device"] != "edge-1" || combined.Items[0]["endOfLife"] != "2023-01-01" {
+		t.Errorf("Expected the edge-1 row from the first query, got %v", combined.Items)
+	}
+
+	if _, err := service.combineNQEResults(CombineNQEResultsArgs{First: NQEResultSource{QueryID: "FQ_eol"}, Second: NQEResultSource{}, KeyColumn: "device"}); err == nil {
+		t.Error("Expected an error when the second query has neither query_id nor query")
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// maxRuntimeSamples bounds how many recent executions are kept per query
+const maxRuntimeSamples = 10
+
+// Cost tier thresholds for observed runtimes
+const (
+	highCostRuntime   = 10 * time.Second
+	mediumCostRuntime = 2 * time.Second
+)
+
+// NQE cost tiers
+const (
+	QueryCostLow     = "low"
+	QueryCostMedium  = "medium"
+	QueryCostHigh    = "high"
+	QueryCostUnknown = "unknown"
+)
+
+// NQERuntimeTracker remembers recent execution times and the last run time per NQE query
+// ID. A nil tracker records nothing.
+type NQERuntimeTracker struct {
+	mutex   sync.RWMutex
+	samples map[string][]time.Duration
+	lastRun map[string]time.Time
+}
+
+// NewNQERuntimeTracker creates an empty runtime tracker
+func NewNQERuntimeTracker() *NQERuntimeTracker {
+	return &NQERuntimeTracker{samples: make(map[string][]time.Duration), lastRun: make(map[string]time.Time)}
+}
+
+// Record stores one execution time for a query, keeping the most recent samples
+func (t *NQERuntimeTracker) Record(queryID string, duration time.Duration) {
+	if t == nil || queryID == "" {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	samples := append(t.samples[queryID], duration)
+	if len(samples) > maxRuntimeSamples {
+		samples = samples[len(samples)-maxRuntimeSamples:]
+	}
+	t.samples[queryID] = samples
+	t.lastRun[queryID] = time.Now()
+}
+
+// Runtimes returns a copy of a query's recorded execution times, oldest first
+func (t *NQERuntimeTracker) Runtimes(queryID string) []time.Duration {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return append([]time.Duration(nil), t.samples[queryID]...)
+}
+
+// LastRun returns when a query last finished running, if it has been recorded
+func (t *NQERuntimeTracker) LastRun(queryID string) (time.Time, bool) {
+	if t == nil {
+		return time.Time{}, false
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	ran, ok := t.lastRun[queryID]
+	return ran, ok
+}
+
+// NQECostEstimate is a rough cost tier for running an NQE query
+type NQECostEstimate struct {
+	QueryID     string        `json:"query_id"`
+	Tier        string        `json:"tier"`
+	Reasons     []string      `json:"reasons"`
+	Runs        int           `json:"runs"`
+	LastRuntime time.Duration `json:"last_runtime,omitempty"`
+	AvgRuntime  time.Duration `json:"avg_runtime,omitempty"`
+}
+
+var (
+	nqeForeachPattern = regexp.MustCompile(`\bforeach\b`)
+	// Iterating a second top-level collection inside a loop joins them, which scales
+	// with the product of their sizes
+	nqeCrossJoinPattern = regexp.MustCompile(`\bforeach\s+\w+\s+in\s+network\.`)
+	nqeConfigPattern    = regexp.MustCompile(`\b(files\.config|patternMatch|blockMatch)\b`)
+)
+
+// estimateSourceCost scores NQE source by length, loop nesting, cross-joins, and config
+// parsing, returning a tier and the reasons behind it
+func estimateSourceCost(code string) (string, []string) {
+	var score int
+	var reasons []string
+
+	if length := len(code); length > 5000 {
+		score += 2
+		reasons = append(reasons, fmt.Sprintf("long query source (%d chars)", length))
+	} else if length > 2000 {
+		score++
+		reasons = append(reasons, fmt.Sprintf("moderately long query source (%d chars)", length))
+	}
+	if loops := len(nqeForeachPattern.FindAllString(code, -1)); loops >= 4 {
+		score += 2
+		reasons = append(reasons, fmt.Sprintf("%d nested loops", loops))
+	} else if loops >= 3 {
+		score++
+		reasons = append(reasons, fmt.Sprintf("%d nested loops", loops))
+	}
+	if joins := len(nqeCrossJoinPattern.FindAllString(code, -1)); joins >= 2 {
+		score += 2
+		reasons = append(reasons, fmt.Sprintf("cross-joins %d network collections", joins))
+	}
+	if nqeConfigPattern.MatchString(code) {
+		score++
+		reasons = append(reasons, "parses device configurations")
+	}
+
+	switch {
+	case score >= 3:
+		return QueryCostHigh, reasons
+	case score >= 1:
+		return QueryCostMedium, reasons
+	default:
+		return QueryCostLow, append(reasons, "short query without nested loops")
+	}
+}
+
+// EstimateQueryCost combines source heuristics with observed runtimes. Observed runtimes
+// take precedence because they reflect the actual network size.
+func EstimateQueryCost(queryID, code string, runtimes []time.Duration) NQECostEstimate {
+	estimate := NQECostEstimate{QueryID: queryID, Tier: QueryCostUnknown, Runs: len(runtimes)}
+
+	if code != "" {
+		estimate.Tier, estimate.Reasons = estimateSourceCost(code)
+	}
+
+	if len(runtimes) == 0 {
+		if code == "" {
+			estimate.Reasons = append(estimate.Reasons, "no query source or execution history available")
+		}
+		return estimate
+	}
+
+	var total, longest time.Duration
+	for _, runtime := range runtimes {
+		total += runtime
+		if runtime > longest {
+			longest = runtime
+		}
+	}
+	estimate.LastRuntime = runtimes[len(runtimes)-1]
+	estimate.AvgRuntime = total / time.Duration(len(runtimes))
+
+	switch {
+	case estimate.AvgRuntime >= highCostRuntime || longest >= 2*highCostRuntime:
+		estimate.Tier = QueryCostHigh
+	case estimate.AvgRuntime >= mediumCostRuntime:
+		estimate.Tier = QueryCostMedium
+	default:
+		estimate.Tier = QueryCostLow
+	}
+	estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("averaged %s over %d recent runs (last %s)",
+		estimate.AvgRuntime.Round(time.Millisecond), len(runtimes), estimate.LastRuntime.Round(time.Millisecond)))
+	return estimate
+}
+
+// estimateQueryCostFor estimates one query's cost from the index and recorded runtimes
+func (s *ForwardMCPService) estimateQueryCostFor(queryID string) NQECostEstimate {
+	var code string
+	if s.queryIndex != nil {
+		if entry, err := s.queryIndex.GetQueryByID(queryID); err == nil {
+			code = entry.Code
+		}
+	}
+	return EstimateQueryCost(queryID, code, s.active().queryRuntimes.Runtimes(queryID))
+}
+
+// formatCostSummary renders an estimate as "tier (last run Xs)"
+func formatCostSummary(estimate NQECostEstimate) string {
+	if estimate.LastRuntime > 0 {
+		return fmt.Sprintf("%s (last run %s)", estimate.Tier, estimate.LastRuntime.Round(time.Millisecond))
+	}
+	return estimate.Tier
+}
+
+// formatQueryHistory renders a query's recorded executions as "last run <time>, avg <runtime>
+// over N runs", or "no history" when it has not been run since the server started
+func (s *ForwardMCPService) formatQueryHistory(queryID string) string {
+	runtimeTracker := s.active().queryRuntimes
+	ran, ok := runtimeTracker.LastRun(queryID)
+	runtimes := runtimeTracker.Runtimes(queryID)
+	if !ok || len(runtimes) == 0 {
+		return "no history"
+	}
+	estimate := EstimateQueryCost(queryID, "", runtimes)
+	return fmt.Sprintf("last run %s (%s ago), avg %s over %d runs",
+		ran.UTC().Format(time.RFC3339), time.Since(ran).Round(time.Second), estimate.AvgRuntime.Round(time.Millisecond), estimate.Runs)
+}
+
+// estimateQueryCost reports a rough cost tier for an NQE query before it is run
+func (s *ForwardMCPService) estimateQueryCost(args EstimateQueryCostArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("estimate_query_cost", args, nil)
+
+	if args.QueryID == "" {
+		return nil, fmt.Errorf("query_id is required")
+	}
+
+	estimate := s.estimateQueryCostFor(args.QueryID)
+	response := fmt.Sprintf("Estimated cost for %s: %s\n", args.QueryID, strings.ToUpper(estimate.Tier))
+	if estimate.LastRuntime > 0 {
+		response += fmt.Sprintf("Last observed runtime: %s\n", estimate.LastRuntime.Round(time.Millisecond))
+	}
+	if len(estimate.Reasons) > 0 {
+		response += "\nBasis:\n• " + strings.Join(estimate.Reasons, "\n• ") + "\n"
+	}
+	if estimate.Tier == QueryCostHigh {
+		response += "\n💡 Consider a lower limit, a specific snapshot, or off-peak execution for this query.\n"
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateQueryCostFromSource(t *testing.T) {
+	simple := "foreach device in network.devices select {name: device.name}"
+	if estimate := EstimateQueryCost("FQ_simple", simple, nil); estimate.Tier != QueryCostLow {
+		t.Errorf("Expected a short single-loop query to be low cost, got %s (%v)", estimate.Tier, estimate.Reasons)
+	}
+
+	joined := `foreach device in network.devices
+foreach iface in device.interfaces
+foreach other in network.devices
+foreach peer in other.interfaces
+where iface.name == peer.name
+select {a: device.name, b: other.name}`
+	if estimate := EstimateQueryCost("FQ_joined", joined, nil); estimate.Tier != QueryCostHigh {
+		t.Errorf("Expected a cross-joined nested query to be high cost, got %s (%v)", estimate.Tier, estimate.Reasons)
+	}
+
+	if estimate := EstimateQueryCost("FQ_missing", "", nil); estimate.Tier != QueryCostUnknown {
+		t.Errorf("Expected unknown cost without source or history, got %s", estimate.Tier)
+	}
+}
+
+func TestEstimateQueryCostUsesRecordedRuntimes(t *testing.T) {
+	service := createTestService()
+	service.active().queryRuntimes = NewNQERuntimeTracker()
+	service.queryIndex = NewNQEQueryIndex(NewKeywordEmbeddingService(), createTestLogger())
+
+	// History outweighs a simple-looking source
+	seedQueryIndex(service.queryIndex, "/L3/BGP/BGP Neighbor State")
+	service.queryIndex.queries[0].Code = "foreach device in network.devices select {name: device.name}"
+	service.active().queryRuntimes.Record("FQ_test_0", 12*time.Second)
+	service.active().queryRuntimes.Record("FQ_test_0", 15*time.Second)
+
+	response, err := service.estimateQueryCost(EstimateQueryCostArgs{QueryID: "FQ_test_0"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "HIGH") || !strings.Contains(text, "Last observed runtime: 15s") {
+		t.Errorf("Expected a high cost tier with the last runtime, got:\n%s", text)
+	}
+
+	// Executions through run_nqe_query_by_id are recorded
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "FQ_recorded"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if runs := service.active().queryRuntimes.Runtimes("FQ_recorded"); len(runs) != 1 {
+		t.Errorf("Expected one recorded runtime, got %d", len(runs))
+	}
+}
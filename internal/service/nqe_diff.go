@@ -0,0 +1,167 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// NQEColumnChange is one column whose value differs between two runs
+type NQEColumnChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// NQERowChange is a row present in both runs whose non-key columns differ
+type NQERowChange struct {
+	Key     string                     `json:"key"`
+	Changes map[string]NQEColumnChange `json:"changes"`
+}
+
+// NQERowDiff is the row-level difference between two runs of the same NQE query
+type NQERowDiff struct {
+	KeyColumn string                   `json:"key_column,omitempty"`
+	Added     []map[string]interface{} `json:"added"`
+	Removed   []map[string]interface{} `json:"removed"`
+	Changed   []NQERowChange           `json:"changed"`
+	Unchanged int                      `json:"unchanged"`
+}
+
+// nqeRowKey identifies a row by keyColumn, or by its full content when keyColumn is empty
+func nqeRowKey(row map[string]interface{}, keyColumn string) string {
+	if keyColumn == "" {
+		data, _ := json.Marshal(row)
+		return string(data)
+	}
+	if value, ok := row[keyColumn].(string); ok {
+		return value
+	}
+	data, _ := json.Marshal(row[keyColumn])
+	return string(data)
+}
+
+// indexNQERows maps each row by its key, rejecting keys that do not identify a single row
+func indexNQERows(rows []map[string]interface{}, keyColumn, label string) (map[string]map[string]interface{}, []string, error) {
+	index := make(map[string]map[string]interface{}, len(rows))
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		key := nqeRowKey(row, keyColumn)
+		if _, exists := index[key]; exists {
+			if keyColumn == "" {
+				continue
+			}
+			return nil, nil, fmt.Errorf("key column %q is not unique in the %s run (%s appears more than once); choose a column that identifies each row", keyColumn, label, key)
+		}
+		index[key] = row
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return index, keys, nil
+}
+
+// DiffNQERows classifies rows as added, removed, or changed between two runs, matching
+// rows by keyColumn. Without a key column rows are compared whole, so a modified row
+// shows up as one removed and one added row.
+func DiffNQERows(before, after []map[string]interface{}, keyColumn string) (NQERowDiff, error) {
+	diff := NQERowDiff{KeyColumn: keyColumn}
+
+	beforeRows, beforeKeys, err := indexNQERows(before, keyColumn, "before")
+	if err != nil {
+		return diff, err
+	}
+	afterRows, afterKeys, err := indexNQERows(after, keyColumn, "after")
+	if err != nil {
+		return diff, err
+	}
+
+	for _, key := range beforeKeys {
+		if _, ok := afterRows[key]; !ok {
+			diff.Removed = append(diff.Removed, beforeRows[key])
+		}
+	}
+
+	for _, key := range afterKeys {
+		afterRow := afterRows[key]
+		beforeRow, ok := beforeRows[key]
+		if !ok {
+			diff.Added = append(diff.Added, afterRow)
+			continue
+		}
+
+		changes := make(map[string]NQEColumnChange)
+		columns := nqeResultColumns([]map[string]interface{}{beforeRow, afterRow})
+		for _, column := range columns {
+			if !reflect.DeepEqual(beforeRow[column], afterRow[column]) {
+				changes[column] = NQEColumnChange{Before: beforeRow[column], After: afterRow[column]}
+			}
+		}
+		if len(changes) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changed = append(diff.Changed, NQERowChange{Key: key, Changes: changes})
+	}
+
+	return diff, nil
+}
+
+// diffNQERuns runs one NQE query against two snapshots and reports the row-level diff
+func (s *ForwardMCPService) diffNQERuns(args DiffNQERunsArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("diff_nqe_runs", args, nil)
+
+	if args.QueryID == "" {
+		return nil, fmt.Errorf("query_id is required")
+	}
+	if args.BeforeSnapshot == "" || args.AfterSnapshot == "" {
+		return nil, fmt.Errorf("both before_snapshot and after_snapshot are required")
+	}
+
+	runArgs := RunNQEQueryByIDArgs{
+		NetworkID:  args.NetworkID,
+		QueryID:    args.QueryID,
+		Parameters: args.Parameters,
+		Options:    args.Options,
+		NoCache:    args.NoCache,
+	}
+
+	runArgs.SnapshotID = args.BeforeSnapshot
+	_, before, _, err := s.fetchNQEResult(runArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query on before snapshot %s: %w", args.BeforeSnapshot, err)
+	}
+
+	runArgs.SnapshotID = args.AfterSnapshot
+	_, after, _, err := s.fetchNQEResult(runArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query on after snapshot %s: %w", args.AfterSnapshot, err)
+	}
+
+	diff, err := DiffNQERows(before.Items, after.Items, args.KeyColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("NQE diff for %s (%s -> %s): %d added, %d removed, %d changed, %d unchanged",
+		args.QueryID, args.BeforeSnapshot, args.AfterSnapshot, len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged)
+	if args.NoCache {
+		header += "; cache bypassed"
+	}
+
+	var identifiers []string
+	if args.KeyColumn != "" {
+		for _, row := range diff.Added {
+			identifiers = append(identifiers, "+"+nqeRowKey(row, args.KeyColumn))
+		}
+		for _, row := range diff.Removed {
+			identifiers = append(identifiers, "-"+nqeRowKey(row, args.KeyColumn))
+		}
+		for _, change := range diff.Changed {
+			identifiers = append(identifiers, "~"+change.Key)
+		}
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, identifiers, diff, args.Pretty))), nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func bgpNeighborRuns() (before, after []map[string]interface{}) {
+	before = []map[string]interface{}{
+		{"neighbor": "10.0.0.1", "device": "edge-1", "state": "established"},
+		{"neighbor": "10.0.0.2", "device": "edge-1", "state": "established"},
+		{"neighbor": "10.0.0.3", "device": "edge-2", "state": "established"},
+	}
+	after = []map[string]interface{}{
+		{"neighbor": "10.0.0.1", "device": "edge-1", "state": "established"},
+		{"neighbor": "10.0.0.3", "device": "edge-2", "state": "idle"},
+		{"neighbor": "10.0.0.4", "device": "edge-2", "state": "established"},
+	}
+	return before, after
+}
+
+func TestDiffNQERowsByKeyColumn(t *testing.T) {
+	before, after := bgpNeighborRuns()
+
+	diff, err := DiffNQERows(before, after, "neighbor")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(diff.Added) != 1 || diff.Added[0]["neighbor"] != "10.0.0.4" {
+		t.Errorf("Expected 10.0.0.4 to be added, got %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0]["neighbor"] != "10.0.0.2" {
+		t.Errorf("Expected 10.0.0.2 to be removed, got %v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Key != "10.0.0.3" {
+		t.Fatalf("Expected 10.0.0.3 to be changed, got %v", diff.Changed)
+	}
+	if change := diff.Changed[0].Changes["state"]; change.Before != "established" || change.After != "idle" || len(diff.Changed[0].Changes) != 1 {
+		t.Errorf("Expected only state to change from established to idle, got %v", diff.Changed[0].Changes)
+	}
+	if diff.Unchanged != 1 {
+		t.Errorf("Expected 1 unchanged row, got %d", diff.Unchanged)
+	}
+}
+
+func TestDiffNQERowsWithoutKeyColumn(t *testing.T) {
+	before, after := bgpNeighborRuns()
+
+	diff, err := DiffNQERows(before, after, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Whole-row comparison reports the modified 10.0.0.3 row as removed and re-added
+	if len(diff.Added) != 2 || len(diff.Removed) != 2 || len(diff.Changed) != 0 || diff.Unchanged != 1 {
+		t.Errorf("Expected 2 added, 2 removed, 0 changed, 1 unchanged, got %d/%d/%d/%d",
+			len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged)
+	}
+}
+
+func TestDiffNQERowsRejectsNonUniqueKey(t *testing.T) {
+	before, after := bgpNeighborRuns()
+	if _, err := DiffNQERows(before, after, "device"); err == nil {
+		t.Error("Expected error when key column does not identify each row")
+	}
+}
+
+func TestDiffNQERunsTool(t *testing.T) {
+	before, after := bgpNeighborRuns()
+	service := createTestService()
+	service.active().client = &recordingNQEClient{
+		MockForwardClient: NewMockForwardClient(),
+		respond: resultsBySnapshot(map[string]*forward.NQERunResult{
+			"snap-1": {SnapshotID: "snap-1", Items: before},
+			"snap-2": {SnapshotID: "snap-2", Items: after},
+		}),
+	}
+	service.defaults.ResponseDetail = ResponseDetailSummary
+
+	response, err := service.diffNQERuns(DiffNQERunsArgs{
+		QueryID:        "FQ_bgp_neighbors",
+		BeforeSnapshot: "snap-1",
+		AfterSnapshot:  "snap-2",
+		KeyColumn:      "neighbor",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := "NQE diff for FQ_bgp_neighbors (snap-1 -> snap-2): 1 added, 1 removed, 1 changed, 1 unchanged; top: +10.0.0.4, -10.0.0.2, ~10.0.0.3"
+	if content := response.Content[0].TextContent.Text; content != expected {
+		t.Errorf("Expected %q, got %q", expected, content)
+	}
+}
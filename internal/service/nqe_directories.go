@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// defaultDirectoryDepth is how many directory levels list_nqe_directories shows by default
+const defaultDirectoryDepth = 2
+
+// NQEDirectory is one directory of the NQE library with the number of queries beneath it
+type NQEDirectory struct {
+	Path     string          `json:"path"`
+	Count    int             `json:"count"`
+	Children []*NQEDirectory `json:"children,omitempty"`
+}
+
+// BuildNQEDirectoryTree derives the directory tree from query paths such as
+// "/L3/BGP/BGP Neighbor State", counting every query under each directory. The returned
+// root has path "/".
+func BuildNQEDirectoryTree(paths []string) *NQEDirectory {
+	root := &NQEDirectory{Path: "/"}
+	index := map[string]*NQEDirectory{"/": root}
+
+	for _, path := range paths {
+		segments := strings.Split(strings.Trim(path, "/"), "/")
+		if len(segments) == 0 || segments[0] == "" {
+			continue
+		}
+		root.Count++
+
+		parent := root
+		prefix := "/"
+		for _, segment := range segments[:len(segments)-1] {
+			prefix += segment + "/"
+			node, ok := index[prefix]
+			if !ok {
+				node = &NQEDirectory{Path: prefix}
+				index[prefix] = node
+				parent.Children = append(parent.Children, node)
+			}
+			node.Count++
+			parent = node
+		}
+	}
+
+	for _, node := range index {
+		sort.Slice(node.Children, func(i, j int) bool { return node.Children[i].Path < node.Children[j].Path })
+	}
+	return root
+}
+
+// find returns the directory with the given path, or nil
+func (d *NQEDirectory) find(path string) *NQEDirectory {
+	if d.Path == path {
+		return d
+	}
+	for _, child := range d.Children {
+		if strings.HasPrefix(path, child.Path) {
+			return child.find(path)
+		}
+	}
+	return nil
+}
+
+// format renders the directory's children as an indented tree down to depth levels
+func (d *NQEDirectory) format(depth int, indent string) string {
+	if depth <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, child := range d.Children {
+		fmt.Fprintf(&b, "%s%s (%d)", indent, child.Path, child.Count)
+		if depth == 1 && len(child.Children) > 0 {
+			fmt.Fprintf(&b, " +%d subdirectories", len(child.Children))
+		}
+		b.WriteString("\n")
+		b.WriteString(child.format(depth-1, indent+"  "))
+	}
+	return b.String()
+}
+
+// listNQEDirectories shows the NQE library's directory tree from the local query index
+func (s *ForwardMCPService) listNQEDirectories(args ListNQEDirectoriesArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("list_nqe_directories", args, nil)
+
+	depth := args.Depth
+	if depth <= 0 {
+		depth = defaultDirectoryDepth
+	}
+
+	// Initialize query index if needed
+	if s.queryIndex.GetStatistics()["total_queries"].(int) == 0 {
+		s.logger.Info("Query index empty, initializing...")
+		if err := s.queryIndex.LoadFromSpec(); err != nil {
+			return s.failureResponse(fmt.Sprintf("Failed to initialize query index: %v\n\n**Manual Fix:** Run `initialize_query_index` and try again.", err)), nil
+		}
+	}
+
+	queries := s.queryIndex.Queries()
+	paths := make([]string, 0, len(queries))
+	for _, query := range queries {
+		paths = append(paths, query.Path)
+	}
+	tree := BuildNQEDirectoryTree(paths)
+
+	directory := "/"
+	if args.Directory != "" {
+		directory = "/" + strings.Trim(args.Directory, "/") + "/"
+	}
+	node := tree.find(directory)
+	if node == nil {
+		top := make([]string, 0, len(tree.Children))
+		for _, child := range tree.Children {
+			top = append(top, child.Path)
+		}
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Directory '%s' not found in the NQE library.\n\n**Top-level directories:** %s", directory, strings.Join(top, ", ")))), nil
+	}
+
+	response := fmt.Sprintf("📂 **NQE Directories under %s** (%d queries)\n\n", node.Path, node.Count)
+	if len(node.Children) == 0 {
+		response += "No subdirectories; use list_nqe_queries with this directory to see its queries.\n"
+		return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+	}
+	response += node.format(depth, "")
+	response += "\n💡 Drill down with `directory`, or pass a directory to list_nqe_queries to see its queries.\n"
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListNQEDirectoriesTree(t *testing.T) {
+	service := setupSmartSearchTestService()
+	seedQueryIndex(service.queryIndex,
+		"/L3/BGP/BGP Neighbor State",
+		"/L3/BGP/BGP Route Count",
+		"/L3/OSPF/OSPF Adjacencies",
+		"/L3/Routing/Default Routes",
+		"/Security/ACL/Permit Any Rules",
+		"/Security/STIGs/Cisco/CISC-RT-000400",
+	)
+
+	response, err := service.listNQEDirectories(ListNQEDirectoriesArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	for _, expected := range []string{"(6 queries)", "/L3/ (4)", "  /L3/BGP/ (2)", "/Security/ (2)", "  /Security/STIGs/ (1) +1 subdirectories"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected tree to contain %q, got:\n%s", expected, text)
+		}
+	}
+
+	response, err = service.listNQEDirectories(ListNQEDirectoriesArgs{Directory: "L3"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text = response.Content[0].TextContent.Text
+	if !strings.Contains(text, "under /L3/** (4 queries)") || strings.Contains(text, "/Security/") {
+		t.Errorf("Expected drill-down to show only /L3/, got:\n%s", text)
+	}
+
+	response, _ = service.listNQEDirectories(ListNQEDirectoriesArgs{Directory: "/Missing/"})
+	if text := response.Content[0].TextContent.Text; !strings.Contains(text, "not found") {
+		t.Errorf("Expected unknown directory to be reported, got:\n%s", text)
+	}
+}
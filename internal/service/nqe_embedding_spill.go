@@ -0,0 +1,93 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// SetMaxResidentEmbeddings caps how many query embeddings are held in memory; the rest
+// are spilled to the embeddings cache file and read back on demand during search.
+// Zero or less means no cap.
+func (idx *NQEQueryIndex) SetMaxResidentEmbeddings(max int) error {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	idx.maxResidentEmbeddings = max
+	return idx.enforceEmbeddingCap()
+}
+
+// isSpilled reports whether a query's embedding lives only in the cache file
+func (idx *NQEQueryIndex) isSpilled(query *NQEQueryIndexEntry) bool {
+	return len(query.Embedding) == 0 && idx.spilled[query.Path]
+}
+
+// enforceEmbeddingCap spills the least recently used resident embeddings to disk until
+// at most maxResidentEmbeddings remain. The cache file is written first so no spilled
+// embedding is lost. Callers must hold the write lock.
+func (idx *NQEQueryIndex) enforceEmbeddingCap() error {
+	if idx.maxResidentEmbeddings <= 0 {
+		return nil
+	}
+
+	var resident []*NQEQueryIndexEntry
+	for _, query := range idx.queries {
+		if len(query.Embedding) > 0 {
+			resident = append(resident, query)
+		}
+	}
+	excess := len(resident) - idx.maxResidentEmbeddings
+	if excess <= 0 {
+		return nil
+	}
+
+	if err := idx.saveEmbeddingsToCache(); err != nil {
+		return fmt.Errorf("failed to persist embeddings before spilling: %w", err)
+	}
+
+	idx.usageMutex.Lock()
+	sort.SliceStable(resident, func(i, j int) bool {
+		return idx.lastUsed[resident[i].Path] < idx.lastUsed[resident[j].Path]
+	})
+	idx.usageMutex.Unlock()
+
+	if idx.spilled == nil {
+		idx.spilled = make(map[string]bool)
+	}
+	for _, query := range resident[:excess] {
+		query.Embedding = nil
+		delete(idx.embeddings, query.QueryID)
+		idx.spilled[query.Path] = true
+	}
+	idx.logger.Debug("Spilled %d query embeddings to %s (%d resident)", excess, idx.embeddingsCachePath, idx.maxResidentEmbeddings)
+	return nil
+}
+
+// readSpilledEmbeddings reads the embeddings cache file, keyed by query path
+func (idx *NQEQueryIndex) readSpilledEmbeddings() (map[string][]float32, error) {
+	data, err := os.ReadFile(idx.embeddingsCachePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embeddings cache: %w", err)
+	}
+	var embeddings map[string][]float32
+	if err := json.Unmarshal(data, &embeddings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal embeddings cache: %w", err)
+	}
+	return embeddings, nil
+}
+
+// touchEmbeddings marks the queries behind search results as recently used, so they are
+// the last to be spilled
+func (idx *NQEQueryIndex) touchEmbeddings(results []*QuerySearchResult) {
+	idx.usageMutex.Lock()
+	defer idx.usageMutex.Unlock()
+
+	if idx.lastUsed == nil {
+		idx.lastUsed = make(map[string]uint64)
+	}
+	for _, result := range results {
+		idx.usageClock++
+		idx.lastUsed[result.Path] = idx.usageClock
+	}
+}
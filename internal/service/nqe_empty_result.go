@@ -0,0 +1,46 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// emptyNQEResultGuidance explains what to check when an NQE query returns no items, so
+// a wrong query can be told apart from a network that genuinely has no matches
+func emptyNQEResultGuidance(params *forward.NQEQueryParams, result *forward.NQERunResult) string {
+	snapshotID := params.SnapshotID
+	if result != nil && result.SnapshotID != "" {
+		snapshotID = result.SnapshotID
+	}
+	if snapshotID == "" {
+		snapshotID = "latest processed snapshot"
+	}
+
+	guidance := "The query ran successfully but matched nothing. Before concluding there are no matches:\n"
+	guidance += fmt.Sprintf("• Confirm the snapshot: results came from network %s, snapshot %s. Use list_snapshots to pick a different one.\n", params.NetworkID, snapshotID)
+
+	if params.Options != nil && len(params.Options.Filters) > 0 {
+		var filters []string
+		for _, filter := range params.Options.Filters {
+			filters = append(filters, fmt.Sprintf("%s=%q", filter.ColumnName, filter.Value))
+		}
+		guidance += fmt.Sprintf("• Check the column filters (%s): values must match exactly, including device name case. Retry without them to see what is available.\n", strings.Join(filters, ", "))
+	} else {
+		guidance += "• Check any device filter or name you expected to match; use list_devices to confirm exact device names.\n"
+	}
+
+	if len(params.Parameters) > 0 {
+		names := make([]string, 0, len(params.Parameters))
+		for name := range params.Parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		guidance += fmt.Sprintf("• Check the query parameters (%s); a narrower or misspelled value returns nothing.\n", strings.Join(names, ", "))
+	}
+
+	guidance += "• The query may not cover what you are looking for: use search_nqe_queries to find alternatives.\n"
+	return guidance
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// Flattening limits for nested NQE columns
+const (
+	defaultFlattenDepth = 3
+	maxFlattenDepth     = 10
+)
+
+// flattenArraySeparator joins the elements of an array column
+const flattenArraySeparator = ", "
+
+// nqeFlattenDepth validates a requested flattening depth, defaulting to defaultFlattenDepth
+func nqeFlattenDepth(depth int) (int, error) {
+	switch {
+	case depth == 0:
+		return defaultFlattenDepth, nil
+	case depth < 0 || depth > maxFlattenDepth:
+		return 0, fmt.Errorf("invalid flatten_depth %d: must be between 1 and %d", depth, maxFlattenDepth)
+	default:
+		return depth, nil
+	}
+}
+
+// flattenScalar renders a leaf value as text for a joined array
+func flattenScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// flattenValue writes value into row under prefix, expanding objects into prefix.key
+// columns until depth runs out; deeper objects are kept as compact JSON
+func flattenValue(row map[string]interface{}, prefix string, value interface{}, depth int) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if depth == 0 || len(v) == 0 {
+			row[prefix] = flattenScalar(v)
+			return
+		}
+		for key, nested := range v {
+			flattenValue(row, prefix+"."+key, nested, depth-1)
+		}
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, element := range v {
+			parts[i] = flattenScalar(element)
+		}
+		row[prefix] = strings.Join(parts, flattenArraySeparator)
+	default:
+		row[prefix] = v
+	}
+}
+
+// FlattenNQEItems gives NQE rows a flat, tabular shape: nested objects become dotted columns
+// (e.g. interface.ip) down to depth levels and arrays become one delimited string. The input
+// rows are not modified.
+func FlattenNQEItems(items []map[string]interface{}, depth int) []map[string]interface{} {
+	flattened := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		row := make(map[string]interface{}, len(item))
+		for column, value := range item {
+			flattenValue(row, column, value, depth)
+		}
+		flattened[i] = row
+	}
+	return flattened
+}
+
+// flattenNQEResult flattens a result's rows when the call asked for it
+func flattenNQEResult(result *forward.NQERunResult, flatten bool, depth int) (*forward.NQERunResult, error) {
+	if !flatten {
+		return result, nil
+	}
+	depth, err := nqeFlattenDepth(depth)
+	if err != nil {
+		return nil, err
+	}
+	return &forward.NQERunResult{SnapshotID: result.SnapshotID, Items: FlattenNQEItems(result.Items, depth)}, nil
+}
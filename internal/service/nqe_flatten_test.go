@@ -0,0 +1,73 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestFlattenNQEItemsExpandsObjectsAndJoinsArrays(t *testing.T) {
+	items := []map[string]interface{}{{
+		"device": "edge-1",
+		"interface": map[string]interface{}{
+			"name": "eth0",
+			"ip":   "10.0.0.1",
+			"vlan": map[string]interface{}{"id": float64(10)},
+		},
+		"tags": []interface{}{"core", float64(42), true},
+	}}
+
+	got := FlattenNQEItems(items, defaultFlattenDepth)
+	want := []map[string]interface{}{{
+		"device":            "edge-1",
+		"interface.name":    "eth0",
+		"interface.ip":      "10.0.0.1",
+		"interface.vlan.id": float64(10),
+		"tags":              "core, 42, true",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected flattened rows:\n got %v\nwant %v", got, want)
+	}
+	if _, ok := items[0]["interface"].(map[string]interface{}); !ok {
+		t.Error("Expected the input rows to be left unchanged")
+	}
+
+	// Objects below the depth limit stay compact JSON
+	shallow := FlattenNQEItems(items, 1)
+	if shallow[0]["interface.vlan"] != `{"id":10}` || shallow[0]["interface.ip"] != "10.0.0.1" {
+		t.Errorf("Expected depth 1 to stop at interface.vlan, got %v", shallow[0])
+	}
+
+	if _, err := nqeFlattenDepth(maxFlattenDepth + 1); err == nil {
+		t.Error("Expected a depth above the maximum to be rejected")
+	}
+}
+
+func TestRunNQEQueryFlattensResult(t *testing.T) {
+	service := createTestService()
+	mock := service.client().(*MockForwardClient)
+	mock.nqeResult = &forward.NQERunResult{SnapshotID: "snap-1", Items: []map[string]interface{}{
+		{"device": "edge-1", "interface": map[string]interface{}{"ip": "10.0.0.1"}},
+		{"device": "edge-2", "interface": map[string]interface{}{"ip": "10.0.0.2"}},
+	}}
+
+	response, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{
+		QueryID:     "FQ_interfaces",
+		Flatten:     true,
+		NoCache:     true,
+		PostFilters: []NQEPostFilter{{ColumnName: "interface.ip", Operator: "=", Value: "10.0.0.2"}},
+	})
+	if err != nil {
+		t.Fatalf("runNQEQueryByID failed: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "• interface.ip: string") || !strings.Contains(text, "edge-2") || strings.Contains(text, "edge-1") {
+		t.Errorf("Expected the flattened column to be filterable, got:\n%s", text)
+	}
+
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "FQ_interfaces", Flatten: true, FlattenDepth: -1}); err == nil {
+		t.Error("Expected an invalid flatten_depth to be rejected")
+	}
+}
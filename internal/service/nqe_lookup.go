@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// defaultLookupLimit is how many candidates lookup_query returns by default
+const defaultLookupLimit = 10
+
+// How a query matched a lookup, best first
+const (
+	lookupExactID = iota
+	lookupIDPrefix
+	lookupPathSubstring
+	lookupPathWords
+)
+
+// lookupMatchNames describes each match kind in tool output
+var lookupMatchNames = map[int]string{
+	lookupExactID:       "exact ID",
+	lookupIDPrefix:      "ID prefix",
+	lookupPathSubstring: "path",
+	lookupPathWords:     "path words",
+}
+
+// QueryLookupMatch is a query found by LookupQueries and how it matched
+type QueryLookupMatch struct {
+	Query *NQEQueryIndexEntry
+	Kind  int
+}
+
+// LookupQueries finds queries by exact Query ID, Query ID prefix, or path: the term as a
+// case-insensitive substring of the path, or failing that every word of the term somewhere
+// in the path. Matches are ordered by kind, then by path, and capped at limit.
+func (idx *NQEQueryIndex) LookupQueries(term string, limit int) []*QueryLookupMatch {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil
+	}
+	lowerTerm := strings.ToLower(term)
+	words := strings.Fields(lowerTerm)
+
+	var matches []*QueryLookupMatch
+	for _, query := range idx.Queries() {
+		path := strings.ToLower(query.Path)
+		kind := -1
+		switch {
+		case query.QueryID == term:
+			kind = lookupExactID
+		case strings.HasPrefix(query.QueryID, term):
+			kind = lookupIDPrefix
+		case strings.Contains(path, lowerTerm):
+			kind = lookupPathSubstring
+		case containsAllWords(path, words):
+			kind = lookupPathWords
+		}
+		if kind >= 0 {
+			matches = append(matches, &QueryLookupMatch{Query: query, Kind: kind})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Kind != matches[j].Kind {
+			return matches[i].Kind < matches[j].Kind
+		}
+		return matches[i].Query.Path < matches[j].Query.Path
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// containsAllWords reports whether text contains every word
+func containsAllWords(text string, words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupQuery finds queries by a partial Query ID or a remembered path fragment
+func (s *ForwardMCPService) lookupQuery(args LookupQueryArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("lookup_query", args, nil)
+
+	if strings.TrimSpace(args.Term) == "" {
+		return nil, fmt.Errorf("term is required: a Query ID, Query ID prefix, or part of a query path")
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultLookupLimit
+	}
+
+	// Initialize query index if needed
+	if s.queryIndex.GetStatistics()["total_queries"].(int) == 0 {
+		s.logger.Info("Query index empty, initializing...")
+		if err := s.queryIndex.LoadFromSpec(); err != nil {
+			return s.failureResponse(fmt.Sprintf("Failed to initialize query index: %v\n\n**Manual Fix:** Run `initialize_query_index` and try again.", err)), nil
+		}
+	}
+
+	matches := s.queryIndex.LookupQueries(args.Term, limit)
+	if len(matches) == 0 {
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("No queries match '%s' by Query ID or path.\n\n💡 Try search_nqe_queries to search by what the query does.", args.Term))), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔎 **%d queries match '%s'**\n\n", len(matches), args.Term)
+	for i, match := range matches {
+		query := match.Query
+		fmt.Fprintf(&b, "%d. **%s** (%s match)\n", i+1, query.Path, lookupMatchNames[match.Kind])
+		fmt.Fprintf(&b, "   - Query ID: `%s`\n", query.QueryID)
+		if query.Category != "" {
+			category := query.Category
+			if query.Subcategory != "" {
+				category += " / " + query.Subcategory
+			}
+			fmt.Fprintf(&b, "   - Category: %s\n", category)
+		}
+		if query.Intent != "" {
+			fmt.Fprintf(&b, "   - Intent: %s\n", query.Intent)
+		}
+	}
+	b.WriteString("\n💡 Run one with run_nqe_query_by_id using its Query ID.\n")
+	return mcp.NewToolResponse(mcp.NewTextContent(b.String())), nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookupQueryByPathAndIDPrefix(t *testing.T) {
+	service := setupSmartSearchTestService()
+	seedQueryIndex(service.queryIndex,
+		"/L3/BGP/BGP Neighbor State",
+		"/L3/OSPF/OSPF Adjacencies",
+		"/Security/ACL/Permit Any Rules",
+	)
+
+	matches := service.queryIndex.LookupQueries("neighbor state", 10)
+	if len(matches) != 1 || matches[0].Query.Path != "/L3/BGP/BGP Neighbor State" || matches[0].Kind != lookupPathSubstring {
+		t.Fatalf("Expected the BGP query by path substring, got %+v", matches)
+	}
+
+	matches = service.queryIndex.LookupQueries("acl permit", 10)
+	if len(matches) != 1 || matches[0].Query.Path != "/Security/ACL/Permit Any Rules" || matches[0].Kind != lookupPathWords {
+		t.Fatalf("Expected the ACL query by path words, got %+v", matches)
+	}
+
+	matches = service.queryIndex.LookupQueries("FQ_test_", 10)
+	if len(matches) != 3 || matches[0].Kind != lookupIDPrefix {
+		t.Fatalf("Expected every query by ID prefix, got %d matches", len(matches))
+	}
+
+	response, err := service.lookupQuery(LookupQueryArgs{Term: "FQ_test_1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "/L3/OSPF/OSPF Adjacencies** (exact ID match)") || !strings.Contains(text, "`FQ_test_1`") {
+		t.Errorf("Expected the OSPF query by exact ID, got:\n%s", text)
+	}
+
+	response, err = service.lookupQuery(LookupQueryArgs{Term: "interface counters"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; !strings.Contains(text, "No queries match") {
+		t.Errorf("Expected no matches, got:\n%s", text)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// ValidateNQEOptionColumns checks the filter and sort columns in options against the
+// columns a query actually returns, describing each mismatch
+func ValidateNQEOptionColumns(options *NQEQueryOptions, columns []string) []string {
+	if options == nil {
+		return nil
+	}
+
+	known := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		known[column] = true
+	}
+	check := func(kind, column string) string {
+		if known[column] {
+			return ""
+		}
+		for _, candidate := range columns {
+			if strings.EqualFold(candidate, column) {
+				return fmt.Sprintf("%s column %q does not exist; did you mean %q? (column names are case-sensitive)", kind, column, candidate)
+			}
+		}
+		return fmt.Sprintf("%s column %q does not exist", kind, column)
+	}
+
+	var problems []string
+	for _, filter := range options.Filters {
+		if problem := check("filter", filter.ColumnName); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	for _, sort := range options.SortBy {
+		if problem := check("sort_by", sort.ColumnName); problem != "" {
+			problems = append(problems, problem)
+		}
+		if order := strings.ToUpper(sort.Order); order != "ASC" && order != "DESC" {
+			problems = append(problems, fmt.Sprintf("sort_by order %q for column %q must be ASC or DESC", sort.Order, sort.ColumnName))
+		}
+	}
+	return problems
+}
+
+// previewNQEOptions runs a query for a single row to discover its columns, then checks
+// the requested filters and sort columns against them before a full run
+func (s *ForwardMCPService) previewNQEOptions(args PreviewNQEOptionsArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("preview_nqe_options", args, nil)
+
+	if args.QueryID == "" {
+		return nil, fmt.Errorf("query_id is required")
+	}
+	if err := s.checkNQEPolicy(args.QueryID); err != nil {
+		return nil, err
+	}
+
+	networkID := s.getNetworkID(args.NetworkID)
+	snapshotID, err := s.resolveSnapshotID(networkID, s.getSnapshotID(args.SnapshotID))
+	if err != nil {
+		return nil, err
+	}
+
+	// The probe runs without filters or sorting so a bad column cannot fail it
+	params := &forward.NQEQueryParams{
+		NetworkID:  networkID,
+		QueryID:    args.QueryID,
+		SnapshotID: snapshotID,
+		Parameters: args.Parameters,
+		Options:    &forward.NQEQueryOptions{Limit: 1},
+	}
+	result, err := s.client().RunNQEQueryByID(params)
+	if err != nil {
+		return nil, describeNQERunError(err)
+	}
+	if len(result.Items) == 0 {
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Query %s returned no rows, so its columns could not be discovered; the options cannot be checked in advance.", args.QueryID))), nil
+	}
+
+	columns := nqeResultColumns(result.Items)
+	problems := ValidateNQEOptionColumns(args.Options, columns)
+
+	var response string
+	if len(problems) == 0 {
+		response = fmt.Sprintf("✅ Options are valid for %s; run it with run_nqe_query_by_id.\n", args.QueryID)
+	} else {
+		response = fmt.Sprintf("⚠️  %d problem(s) with the options for %s:\n• %s\n", len(problems), args.QueryID, strings.Join(problems, "\n• "))
+	}
+	response += fmt.Sprintf("\nAvailable columns: %s\n", strings.Join(columns, ", "))
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestPreviewNQEOptionsFlagsMissingColumns(t *testing.T) {
+	service := createTestService()
+	service.active().client = &recordingNQEClient{
+		MockForwardClient: service.client().(*MockForwardClient),
+		respond: resultsBySnapshot(map[string]*forward.NQERunResult{
+			"": {Items: []map[string]interface{}{{"device_name": "router-1", "platform": "cisco_ios"}}},
+		}),
+	}
+
+	response, err := service.previewNQEOptions(PreviewNQEOptionsArgs{
+		QueryID: "FQ_test",
+		Options: &NQEQueryOptions{
+			Filters: []NQEColumnFilter{{ColumnName: "device_name", Value: "router-1"}, {ColumnName: "vendor", Value: "cisco"}},
+			SortBy:  []NQESortBy{{ColumnName: "Platform", Order: "ASC"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	text := response.Content[0].TextContent.Text
+	for _, expected := range []string{
+		"2 problem(s)",
+		`filter column "vendor" does not exist`,
+		`did you mean "platform"`,
+		"Available columns: device_name, platform",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, `"device_name" does not exist`) {
+		t.Errorf("Expected the existing column to pass, got:\n%s", text)
+	}
+}
+
+func TestPreviewNQEOptionsAcceptsValidOptions(t *testing.T) {
+	service := createTestService()
+
+	response, err := service.previewNQEOptions(PreviewNQEOptionsArgs{
+		QueryID: "FQ_test",
+		Options: &NQEQueryOptions{SortBy: []NQESortBy{{ColumnName: "device_name", Order: "desc"}}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; !strings.Contains(text, "Options are valid") {
+		t.Errorf("Expected options to be valid, got:\n%s", text)
+	}
+}
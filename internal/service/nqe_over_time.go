@@ -0,0 +1,145 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// Defaults for running one NQE query across recent snapshots
+const (
+	defaultOverTimeSnapshots = 5
+	maxOverTimeSnapshots     = 50
+	overTimeConcurrency      = 4
+)
+
+// NQETimeSeriesPoint is the query's metric for one snapshot; Error is set when the run failed
+type NQETimeSeriesPoint struct {
+	SnapshotID string   `json:"snapshot_id"`
+	Date       string   `json:"date"`
+	Value      *float64 `json:"value,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// nqeMetricValue reduces a result to one number: the row count, or the sum of a numeric column
+func nqeMetricValue(items []map[string]interface{}, valueColumn string) float64 {
+	if valueColumn == "" {
+		return float64(len(items))
+	}
+	var total float64
+	for _, item := range items {
+		switch value := item[valueColumn].(type) {
+		case float64:
+			total += value
+		case int:
+			total += float64(value)
+		case int64:
+			total += float64(value)
+		case string:
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				total += parsed
+			}
+		}
+	}
+	return total
+}
+
+// runNQEQueryOverTime runs one query against the most recent snapshots and returns a time
+// series of its metric, oldest first. Runs are bounded to overTimeConcurrency at once and a
+// failed snapshot becomes a point with an error rather than failing the whole series.
+func (s *ForwardMCPService) runNQEQueryOverTime(args RunNQEQueryOverTimeArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("run_nqe_query_over_time", args, nil)
+
+	if args.QueryID == "" {
+		return nil, fmt.Errorf("query_id is required")
+	}
+	networkID := s.getNetworkID(args.NetworkID)
+	if networkID == "" {
+		return nil, fmt.Errorf("network_id is required (no default network is set)")
+	}
+
+	count := args.Snapshots
+	if count <= 0 {
+		count = defaultOverTimeSnapshots
+	}
+	if count > maxOverTimeSnapshots {
+		count = maxOverTimeSnapshots
+	}
+
+	snapshots, err := s.client().GetSnapshots(networkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	recent := filterSnapshots(snapshots, "", false)
+	if len(recent) > count {
+		recent = recent[:count]
+	}
+	if len(recent) == 0 {
+		return nil, fmt.Errorf("no snapshots found for network %s", networkID)
+	}
+
+	// recent is newest-first; fill the series from the end so it reads oldest-first
+	series := make([]NQETimeSeriesPoint, len(recent))
+	sem := make(chan struct{}, overTimeConcurrency)
+	var wg sync.WaitGroup
+	for i, snapshot := range recent {
+		point := &series[len(recent)-1-i]
+		point.SnapshotID = snapshot.ID
+		if taken := snapshotTime(&recent[i]); !taken.IsZero() {
+			point.Date = taken.UTC().Format(time.RFC3339)
+		}
+
+		wg.Add(1)
+		go func(snapshotID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			_, result, _, err := s.fetchNQEResult(RunNQEQueryByIDArgs{
+				NetworkID:  networkID,
+				QueryID:    args.QueryID,
+				SnapshotID: snapshotID,
+				Parameters: args.Parameters,
+				Options:    args.Options,
+				NoCache:    args.NoCache,
+			})
+			if err != nil {
+				point.Error = err.Error()
+				return
+			}
+			value := nqeMetricValue(result.Items, args.ValueColumn)
+			point.Value = &value
+		}(snapshot.ID)
+	}
+	wg.Wait()
+
+	metric := "row count"
+	if args.ValueColumn != "" {
+		metric = "sum of " + args.ValueColumn
+	}
+	failed := 0
+	identifiers := make([]string, 0, len(series))
+	for _, point := range series {
+		if point.Value == nil {
+			failed++
+			identifiers = append(identifiers, fmt.Sprintf("%s=error", point.SnapshotID))
+			continue
+		}
+		identifiers = append(identifiers, fmt.Sprintf("%s=%s", point.SnapshotID, strconv.FormatFloat(*point.Value, 'f', -1, 64)))
+	}
+	if failed == len(series) {
+		return nil, fmt.Errorf("query %s failed on all %d snapshots: %s", args.QueryID, len(series), series[0].Error)
+	}
+
+	header := fmt.Sprintf("%s over %d snapshots of %s (%s, oldest first)", args.QueryID, len(series), networkID, metric)
+	if failed > 0 {
+		header += fmt.Sprintf(", %d failed", failed)
+	}
+	if args.NoCache {
+		header += "; cache bypassed"
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, identifiers, series, args.Pretty))), nil
+}
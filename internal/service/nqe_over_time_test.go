@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestRunNQEQueryOverTime(t *testing.T) {
+	service := createTestService()
+	mock := service.client().(*MockForwardClient)
+	mock.snapshots = []forward.Snapshot{
+		{ID: "snap-1", State: "PROCESSED", CreationDateMillis: 1767225600000},
+		{ID: "snap-3", State: "PROCESSED", CreationDateMillis: 1767398400000},
+		{ID: "snap-2", State: "PROCESSED", CreationDateMillis: 1767312000000},
+		{ID: "snap-draft", State: "PROCESSED", IsDraft: true, CreationDateMillis: 1767484800000},
+	}
+
+	devices := func(n int) *forward.NQERunResult {
+		items := make([]map[string]interface{}, n)
+		for i := range items {
+			items[i] = map[string]interface{}{"name": fmt.Sprintf("device-%d", i)}
+		}
+		return &forward.NQERunResult{Items: items}
+	}
+	service.active().client = &recordingNQEClient{
+		MockForwardClient: mock,
+		respond: resultsBySnapshot(map[string]*forward.NQERunResult{
+			"snap-1": devices(10),
+			"snap-2": devices(12),
+			"snap-3": devices(15),
+		}),
+	}
+
+	response, err := service.runNQEQueryOverTime(RunNQEQueryOverTimeArgs{NetworkID: "162112", QueryID: "FQ_devices", Snapshots: 5})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	if !contains(content, "FQ_devices over 3 snapshots of 162112 (row count, oldest first)") {
+		t.Errorf("Expected series header, got: %s", content)
+	}
+
+	var series []NQETimeSeriesPoint
+	if err := json.Unmarshal([]byte(content[strings.Index(content, "\n")+1:]), &series); err != nil {
+		t.Fatalf("Failed to parse series: %v", err)
+	}
+	expected := []struct {
+		id    string
+		date  string
+		value float64
+	}{
+		{"snap-1", "2026-01-01T00:00:00Z", 10},
+		{"snap-2", "2026-01-02T00:00:00Z", 12},
+		{"snap-3", "2026-01-03T00:00:00Z", 15},
+	}
+	if len(series) != len(expected) {
+		t.Fatalf("Expected one point per snapshot, got %+v", series)
+	}
+	for i, point := range series {
+		if point.SnapshotID != expected[i].id || point.Date != expected[i].date || point.Value == nil || *point.Value != expected[i].value {
+			t.Errorf("Point %d: expected %+v, got %+v", i, expected[i], point)
+		}
+	}
+}
+
+func TestRunNQEQueryOverTimeToleratesSnapshotFailures(t *testing.T) {
+	service := createTestService()
+	mock := service.client().(*MockForwardClient)
+	mock.snapshots = []forward.Snapshot{
+		{ID: "snap-1", State: "PROCESSED", CreationDateMillis: 1000},
+		{ID: "snap-2", State: "PROCESSED", CreationDateMillis: 2000},
+	}
+	service.active().client = &recordingNQEClient{
+		MockForwardClient: mock,
+		respond: resultsBySnapshot(map[string]*forward.NQERunResult{
+			"snap-2": {Items: []map[string]interface{}{{"bytes": 40.0}, {"bytes": "2"}}},
+		}),
+	}
+
+	response, err := service.runNQEQueryOverTime(RunNQEQueryOverTimeArgs{NetworkID: "162112", QueryID: "FQ_usage", ValueColumn: "bytes"})
+	if err != nil {
+		t.Fatalf("Expected partial series, got error: %v", err)
+	}
+	content := response.Content[0].TextContent.Text
+	if !contains(content, "(sum of bytes, oldest first), 1 failed") {
+		t.Errorf("Expected one failed snapshot in header, got: %s", content)
+	}
+	if !contains(content, `"value": 42`) {
+		t.Errorf("Expected summed column value, got: %s", content)
+	}
+}
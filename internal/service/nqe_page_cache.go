@@ -0,0 +1,211 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// maxNQEPageCacheEntries bounds how many query results the page cache holds
+const maxNQEPageCacheEntries = 50
+
+// nqePageEntry holds the rows retrieved so far for one query result, by row index
+type nqePageEntry struct {
+	rows       map[int]map[string]interface{}
+	total      int // number of rows in the full result, or -1 until a short page is seen
+	sizeBytes  int // JSON bytes of the rows
+	snapshotID string
+	storedAt   time.Time
+	expiresAt  time.Time
+}
+
+// NQEPageCache keeps the rows of NQE results retrieved page by page, keyed by network,
+// snapshot, query, parameters, filters, and sorting but not by limit or offset, so paging
+// back or re-reading any window inside rows already fetched is served from memory. Entries
+// live for the TTL from their first page and share the semantic cache's row and byte
+// limits. A nil cache serves nothing.
+type NQEPageCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]*nqePageEntry
+	now     func() time.Time
+
+	// Limits on one result's rows and JSON bytes and on all results' bytes (0 = off)
+	maxEntryRows  int
+	maxEntryBytes int
+	maxTotalBytes int
+	totalBytes    int
+}
+
+// NewNQEPageCache creates a page cache whose entries live for ttl
+func NewNQEPageCache(ttl time.Duration) *NQEPageCache {
+	return &NQEPageCache{ttl: ttl, entries: make(map[string]*nqePageEntry), now: time.Now}
+}
+
+// SetEntryLimits caps the rows and JSON bytes kept for a single result and the JSON bytes
+// of all results together; 0 leaves a limit off
+func (c *NQEPageCache) SetEntryLimits(maxRows, maxBytes, maxTotalBytes int) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.maxEntryRows = maxRows
+	c.maxEntryBytes = maxBytes
+	c.maxTotalBytes = maxTotalBytes
+	for c.maxTotalBytes > 0 && c.totalBytes > c.maxTotalBytes && c.removeOldest("") {
+	}
+}
+
+// nqePageKey identifies a query result independent of the page requested
+func nqePageKey(params *forward.NQEQueryParams) string {
+	var sortBy []forward.NQESortBy
+	var filters []forward.NQEColumnFilter
+	format := ""
+	if params.Options != nil {
+		sortBy, filters, format = params.Options.SortBy, params.Options.Filters, params.Options.Format
+	}
+	parametersJSON, _ := json.Marshal(params.Parameters)
+	sortJSON, _ := json.Marshal(sortBy)
+	filtersJSON, _ := json.Marshal(filters)
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s", params.NetworkID, params.SnapshotID, params.QueryID, parametersJSON, sortJSON, filtersJSON, format)
+}
+
+// nqePageWindow returns the offset and limit a query requests
+func nqePageWindow(params *forward.NQEQueryParams) (int, int) {
+	if params.Options == nil {
+		return 0, 0
+	}
+	return params.Options.Offset, params.Options.Limit
+}
+
+// Get returns the requested page when every row in it has been retrieved, with the time
+// the result's first page was stored
+func (c *NQEPageCache) Get(params *forward.NQEQueryParams) (*forward.NQERunResult, time.Time, bool) {
+	offset, limit := nqePageWindow(params)
+	if c == nil || limit <= 0 {
+		return nil, time.Time{}, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := nqePageKey(params)
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(key)
+		return nil, time.Time{}, false
+	}
+
+	end := offset + limit
+	if entry.total >= 0 && end > entry.total {
+		end = entry.total
+	}
+	items := make([]map[string]interface{}, 0, max(end-offset, 0))
+	for i := offset; i < end; i++ {
+		row, ok := entry.rows[i]
+		if !ok {
+			return nil, time.Time{}, false
+		}
+		items = append(items, row)
+	}
+	return &forward.NQERunResult{SnapshotID: entry.snapshotID, Items: items}, entry.storedAt, true
+}
+
+// Store records the rows of a retrieved page. A page shorter than its limit marks the end
+// of the result.
+func (c *NQEPageCache) Store(params *forward.NQEQueryParams, result *forward.NQERunResult) {
+	offset, limit := nqePageWindow(params)
+	if c == nil || result == nil || limit <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	key := nqePageKey(params)
+	entry, ok := c.entries[key]
+	if ok && (!now.Before(entry.expiresAt) || entry.snapshotID != result.SnapshotID) {
+		c.remove(key)
+		ok = false
+	}
+	if !ok {
+		if len(c.entries) >= maxNQEPageCacheEntries {
+			c.removeOldest("")
+		}
+		entry = &nqePageEntry{
+			rows:       make(map[int]map[string]interface{}),
+			total:      -1,
+			snapshotID: result.SnapshotID,
+			storedAt:   now,
+			expiresAt:  now.Add(c.ttl),
+		}
+		c.entries[key] = entry
+	}
+
+	added := make(map[int]map[string]interface{}, len(result.Items))
+	addedBytes := 0
+	for i, item := range result.Items {
+		if _, stored := entry.rows[offset+i]; stored {
+			continue
+		}
+		added[offset+i] = item
+		if data, err := json.Marshal(item); err == nil {
+			addedBytes += len(data)
+		}
+	}
+
+	// A result over the limits is dropped whole rather than kept in part, so its pages
+	// come from the API
+	size := entry.sizeBytes + addedBytes
+	if (c.maxEntryRows > 0 && len(entry.rows)+len(added) > c.maxEntryRows) ||
+		(c.maxEntryBytes > 0 && size > c.maxEntryBytes) ||
+		(c.maxTotalBytes > 0 && size > c.maxTotalBytes) {
+		c.remove(key)
+		return
+	}
+	for c.maxTotalBytes > 0 && c.totalBytes+addedBytes > c.maxTotalBytes && c.removeOldest(key) {
+	}
+
+	for index, row := range added {
+		entry.rows[index] = row
+	}
+	entry.sizeBytes = size
+	c.totalBytes += addedBytes
+	if len(result.Items) < limit {
+		entry.total = offset + len(result.Items)
+	}
+}
+
+// remove drops the entry under key and its bytes
+func (c *NQEPageCache) remove(key string) {
+	if entry, ok := c.entries[key]; ok {
+		c.totalBytes -= entry.sizeBytes
+		delete(c.entries, key)
+	}
+}
+
+// removeOldest drops the entry stored first other than keep, reporting whether there was one
+func (c *NQEPageCache) removeOldest(keep string) bool {
+	oldestKey := ""
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if key != keep && (oldestKey == "" || entry.storedAt.Before(oldest)) {
+			oldestKey, oldest = key, entry.storedAt
+		}
+	}
+	if oldestKey == "" {
+		return false
+	}
+	c.remove(oldestKey)
+	return true
+}
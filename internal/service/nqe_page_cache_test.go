@@ -0,0 +1,138 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// fiveRowPages serves a five-row result by offset and limit
+func fiveRowPages(params *forward.NQEQueryParams) (*forward.NQERunResult, error) {
+	result := &forward.NQERunResult{SnapshotID: "snapshot-123"}
+	for i := params.Options.Offset; i < params.Options.Offset+params.Options.Limit && i < 5; i++ {
+		result.Items = append(result.Items, map[string]interface{}{"device": fmt.Sprintf("router-%d", i)})
+	}
+	return result, nil
+}
+
+func TestNQEPagingServesRevisitedPagesFromMemory(t *testing.T) {
+	service := createTestService()
+	service.semanticCache = nil // serve repeats from the page cache only
+	service.active().nqePages = NewNQEPageCache(time.Minute)
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient), respond: fiveRowPages}
+	service.active().client = client
+
+	page := func(offset, limit int) []map[string]interface{} {
+		t.Helper()
+		_, result, _, err := service.fetchNQEResult(RunNQEQueryByIDArgs{
+			NetworkID: "162112", QueryID: "FQ_test", SnapshotID: "snapshot-123",
+			Options: &NQEQueryOptions{Offset: offset, Limit: limit},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return result.Items
+	}
+
+	page(0, 2)
+	page(2, 2)
+	if back := page(0, 2); len(client.queryIDs) != 2 || len(back) != 2 || back[0]["device"] != "router-0" {
+		t.Errorf("Expected paging back to be served from memory, got %d calls and %v", len(client.queryIDs), back)
+	}
+	if overlap := page(1, 2); len(client.queryIDs) != 2 || overlap[0]["device"] != "router-1" || overlap[1]["device"] != "router-2" {
+		t.Errorf("Expected a window inside fetched rows to be served from memory, got %d calls and %v", len(client.queryIDs), overlap)
+	}
+
+	// The short last page marks the end, so windows reaching past it need no call
+	page(4, 2)
+	if tail := page(3, 5); len(client.queryIDs) != 3 || len(tail) != 2 {
+		t.Errorf("Expected the tail to be served from memory, got %d calls and %v", len(client.queryIDs), tail)
+	}
+
+	if _, _, _, err := service.fetchNQEResult(RunNQEQueryByIDArgs{
+		NetworkID: "162112", QueryID: "FQ_test", SnapshotID: "snapshot-123",
+		Options: &NQEQueryOptions{Offset: 0, Limit: 2}, NoCache: true,
+	}); err != nil || len(client.queryIDs) != 4 {
+		t.Errorf("Expected no_cache to bypass the page cache, got %d calls (err: %v)", len(client.queryIDs), err)
+	}
+}
+
+func TestNQEPagesOfLatestSnapshotAreKeyedBySnapshotID(t *testing.T) {
+	service := createTestService()
+	service.semanticCache = nil
+	service.active().nqePages = NewNQEPageCache(time.Minute)
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	client.respond = func(params *forward.NQEQueryParams) (*forward.NQERunResult, error) {
+		latest := client.snapshots[0].ID
+		return &forward.NQERunResult{SnapshotID: latest, Items: []map[string]interface{}{{"device": latest + "-router"}}}, nil
+	}
+	service.active().client = client
+	client.snapshots = []forward.Snapshot{{ID: "snap-1"}}
+
+	args := RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_test", Options: &NQEQueryOptions{Limit: 2}}
+	for i := 0; i < 2; i++ {
+		if _, _, _, err := service.fetchNQEResult(args); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if len(client.queryIDs) != 1 {
+		t.Fatalf("Expected the repeated page to be served from memory, got %d runs", len(client.queryIDs))
+	}
+
+	// Once a new snapshot is processed the old snapshot's rows are no longer served
+	client.snapshots = []forward.Snapshot{{ID: "snap-2"}}
+	_, result, _, err := service.fetchNQEResult(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(client.queryIDs) != 2 || result.Items[0]["device"] != "snap-2-router" {
+		t.Errorf("Expected a live run against the new snapshot, got %d runs and %v", len(client.queryIDs), result.Items)
+	}
+}
+
+func TestNQEPageCacheOffWithoutCaching(t *testing.T) {
+	forwardConfig := createTestService().config.Forward
+	forwardConfig.NQEPageCacheTTL = 300
+	if state := newInstanceState("", NewMockForwardClient(), &forwardConfig, createTestLogger()); state.nqePages == nil {
+		t.Error("Expected a page cache while caching is enabled")
+	}
+	forwardConfig.SemanticCache.Enabled = false
+	if state := newInstanceState("", NewMockForwardClient(), &forwardConfig, createTestLogger()); state.nqePages != nil {
+		t.Error("Expected no page cache while caching is disabled")
+	}
+}
+
+func TestNQEPageCacheEntryLimits(t *testing.T) {
+	cache := NewNQEPageCache(time.Minute)
+	cache.SetEntryLimits(3, 0, 0)
+	pageParams := func(queryID string, offset int) *forward.NQEQueryParams {
+		return &forward.NQEQueryParams{NetworkID: "162112", SnapshotID: "snap-1", QueryID: queryID, Options: &forward.NQEQueryOptions{Offset: offset, Limit: 2}}
+	}
+	twoRows := func() *forward.NQERunResult {
+		return &forward.NQERunResult{SnapshotID: "snap-1", Items: []map[string]interface{}{{"device": "r1"}, {"device": "r2"}}}
+	}
+
+	// A result that grows past the row limit is dropped whole
+	cache.Store(pageParams("FQ_a", 0), twoRows())
+	if _, _, found := cache.Get(pageParams("FQ_a", 0)); !found {
+		t.Fatal("Expected the first page to be cached")
+	}
+	cache.Store(pageParams("FQ_a", 2), twoRows())
+	if _, _, found := cache.Get(pageParams("FQ_a", 0)); found {
+		t.Error("Expected a result over the row limit not to be cached")
+	}
+
+	// The total byte budget evicts the oldest result to make room
+	size := len(`{"device":"r1"}`) + len(`{"device":"r2"}`)
+	cache.SetEntryLimits(0, 0, size)
+	cache.Store(pageParams("FQ_b", 0), twoRows())
+	cache.Store(pageParams("FQ_c", 0), twoRows())
+	if _, _, found := cache.Get(pageParams("FQ_b", 0)); found {
+		t.Error("Expected the oldest result to be evicted to stay within the byte budget")
+	}
+	if _, _, found := cache.Get(pageParams("FQ_c", 0)); !found || cache.totalBytes != size {
+		t.Errorf("Expected only the newest result cached with %d bytes, got %d", size, cache.totalBytes)
+	}
+}
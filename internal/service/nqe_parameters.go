@@ -0,0 +1,175 @@
+package service
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// nqeQuerySignature matches the parameter list of an annotated NQE query, e.g.
+//
+//	@query
+//	lowMtu(mtuThreshold: Integer, ntpServers: List<IpAddress>) = ...
+var nqeQuerySignature = regexp.MustCompile(`@query\s+\w+\s*\(([^)]*)\)`)
+
+// ParseNQEParameters returns the declared parameter types of an NQE query by name, or nil
+// when the source declares no parameters
+func ParseNQEParameters(source string) map[string]string {
+	match := nqeQuerySignature.FindStringSubmatch(source)
+	if match == nil {
+		return nil
+	}
+
+	declared := make(map[string]string)
+	depth, start := 0, 0
+	list := match[1] + ","
+	for i, r := range list {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth > 0 {
+				continue
+			}
+			if name, typ, ok := strings.Cut(list[start:i], ":"); ok {
+				declared[strings.TrimSpace(name)] = strings.TrimSpace(typ)
+			}
+			start = i + 1
+		}
+	}
+	if len(declared) == 0 {
+		return nil
+	}
+	return declared
+}
+
+// coerceNQEValue converts a JSON value to the declared NQE type, accepting numeric and
+// boolean strings. Types it does not know are passed through for the server to check.
+func coerceNQEValue(typ string, value interface{}) (interface{}, error) {
+	if inner, ok := strings.CutPrefix(typ, "List<"); ok && strings.HasSuffix(inner, ">") {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expects %s, got %v", typ, value)
+		}
+		coerced := make([]interface{}, len(items))
+		for i, item := range items {
+			converted, err := coerceNQEValue(strings.TrimSuffix(inner, ">"), item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d %w", i, err)
+			}
+			coerced[i] = converted
+		}
+		return coerced, nil
+	}
+
+	text, isString := value.(string)
+	text = strings.TrimSpace(text)
+	switch typ {
+	case "Integer", "Number":
+		switch v := value.(type) {
+		case float64:
+			if v == float64(int64(v)) {
+				return int64(v), nil
+			}
+		case int, int64:
+			return v, nil
+		case string:
+			if parsed, err := strconv.ParseInt(text, 10, 64); err == nil {
+				return parsed, nil
+			}
+		}
+	case "Float":
+		switch v := value.(type) {
+		case float64, int, int64:
+			return v, nil
+		case string:
+			if parsed, err := strconv.ParseFloat(text, 64); err == nil {
+				return parsed, nil
+			}
+		}
+	case "Bool":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if parsed, err := strconv.ParseBool(text); err == nil {
+				return parsed, nil
+			}
+		}
+	case "String":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64, int, int64, bool:
+			return fmt.Sprint(v), nil
+		}
+	case "IpAddress":
+		if addr, err := netip.ParseAddr(text); isString && err == nil {
+			return addr.String(), nil
+		}
+	case "IpSubnet":
+		if prefix, err := netip.ParsePrefix(text); isString && err == nil {
+			return prefix.String(), nil
+		}
+	default:
+		return value, nil
+	}
+	return nil, fmt.Errorf("expects %s, got %v", typ, value)
+}
+
+// CoerceNQEParameters checks parameters against the types the query source declares and
+// returns a copy with values converted to those types. Unknown and missing parameters are
+// reported; a source that declares no parameters leaves parameters unchecked.
+func CoerceNQEParameters(source string, parameters map[string]interface{}) (map[string]interface{}, error) {
+	declared := ParseNQEParameters(source)
+	if declared == nil {
+		return parameters, nil
+	}
+
+	coerced := make(map[string]interface{}, len(parameters))
+	for _, name := range sortedKeys(parameters) {
+		typ, ok := declared[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown parameter %q (declared: %s)", name, strings.Join(sortedKeys(declared), ", "))
+		}
+		value, err := coerceNQEValue(typ, parameters[name])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q %w", name, err)
+		}
+		coerced[name] = value
+	}
+
+	var missing []string
+	for name, typ := range declared {
+		if _, ok := parameters[name]; !ok {
+			missing = append(missing, fmt.Sprintf("%s (%s)", name, typ))
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing parameters: %s", strings.Join(missing, ", "))
+	}
+	return coerced, nil
+}
+
+// typedNQEParameters coerces parameters to the types declared in the query's indexed
+// source, so type mismatches fail with a precise message before reaching the API
+func (s *ForwardMCPService) typedNQEParameters(queryID string, parameters map[string]interface{}) (map[string]interface{}, error) {
+	if s.queryIndex == nil {
+		return parameters, nil
+	}
+	entry, err := s.queryIndex.GetQueryByID(queryID)
+	if err != nil || entry.Code == "" {
+		return parameters, nil
+	}
+	coerced, err := CoerceNQEParameters(entry.Code, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for NQE query %s: %w", queryID, err)
+	}
+	return coerced, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+const lowMtuQuery = `@query
+lowMtu(mtuThreshold: Integer, ntpServers: List<IpAddress>) =
+foreach device in network.devices
+foreach iface in device.interfaces
+where iface.mtu < mtuThreshold
+select {device: device.name, interface: iface.name}`
+
+func lowMtuService() (*ForwardMCPService, *recordingNQEClient) {
+	service := createTestService()
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+	service.queryIndex = NewNQEQueryIndex(NewKeywordEmbeddingService(), createTestLogger())
+	service.queryIndex.queries = []*NQEQueryIndexEntry{{QueryID: "FQ_low_mtu", Path: "/Interfaces/Low MTU", Code: lowMtuQuery}}
+	return service, client
+}
+
+func TestTypedNQEParametersCoerceNumericStrings(t *testing.T) {
+	service, client := lowMtuService()
+
+	_, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{
+		NetworkID:  "162112",
+		QueryID:    "FQ_low_mtu",
+		Parameters: map[string]interface{}{"mtuThreshold": "1500", "ntpServers": []interface{}{"10.0.0.1"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected the numeric string to be coerced, got: %v", err)
+	}
+	if len(client.params) != 1 || client.params[0].Parameters["mtuThreshold"] != int64(1500) {
+		t.Errorf("Expected mtuThreshold to be sent as an integer, got %#v", client.params)
+	}
+}
+
+func TestTypedNQEParametersRejectMismatches(t *testing.T) {
+	service, client := lowMtuService()
+
+	testCases := map[string]struct {
+		parameters map[string]interface{}
+		expected   string
+	}{
+		"non-numeric integer": {
+			map[string]interface{}{"mtuThreshold": "large", "ntpServers": []interface{}{}},
+			`parameter "mtuThreshold" expects Integer, got large`,
+		},
+		"invalid list item": {
+			map[string]interface{}{"mtuThreshold": 1500.0, "ntpServers": []interface{}{"10.0.0.1", "ntp.example.com"}},
+			`parameter "ntpServers" item 1 expects IpAddress, got ntp.example.com`,
+		},
+		"unknown parameter": {
+			map[string]interface{}{"mtuThreshold": 1500.0, "ntpServers": []interface{}{}, "site": "NYC"},
+			`unknown parameter "site" (declared: mtuThreshold, ntpServers)`,
+		},
+		"missing parameter": {
+			map[string]interface{}{"mtuThreshold": 1500.0},
+			"missing parameters: ntpServers (List<IpAddress>)",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_low_mtu", Parameters: tc.parameters})
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error containing %q, got: %v", tc.expected, err)
+			}
+		})
+	}
+	if len(client.params) != 0 {
+		t.Errorf("Expected rejected parameters never to reach the API, got %d runs", len(client.params))
+	}
+}
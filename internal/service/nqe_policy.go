@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NQEDirectoryPolicy restricts which NQE library directories the server exposes. A query
+// is allowed when its path is under an allowed prefix (or no allow prefixes are set) and
+// under no denied prefix; deny wins when both match.
+type NQEDirectoryPolicy struct {
+	allow []string
+	deny  []string
+}
+
+// NewNQEDirectoryPolicy builds a policy from directory prefixes such as "/Security/Secrets".
+// It returns nil when both lists are empty, which allows every query.
+func NewNQEDirectoryPolicy(allow, deny []string) *NQEDirectoryPolicy {
+	policy := &NQEDirectoryPolicy{allow: normalizeDirectoryPrefixes(allow), deny: normalizeDirectoryPrefixes(deny)}
+	if len(policy.allow) == 0 && len(policy.deny) == 0 {
+		return nil
+	}
+	return policy
+}
+
+// normalizeDirectoryPrefixes lowercases prefixes and gives them a leading and trailing
+// slash, so "/L3/BGP" matches "/L3/BGP/Neighbors" but not "/L3/BGPv6/Peers"
+func normalizeDirectoryPrefixes(prefixes []string) []string {
+	var normalized []string
+	for _, prefix := range prefixes {
+		prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+		if prefix == "" {
+			continue
+		}
+		normalized = append(normalized, "/"+strings.ToLower(prefix)+"/")
+	}
+	return normalized
+}
+
+// underAny reports whether path lies under one of the normalized prefixes
+func underAny(path string, prefixes []string) bool {
+	path = "/" + strings.ToLower(strings.Trim(path, "/")) + "/"
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the query at path may be listed and run. A nil policy allows all.
+func (p *NQEDirectoryPolicy) Allows(path string) bool {
+	if p == nil {
+		return true
+	}
+	if underAny(path, p.deny) {
+		return false
+	}
+	return len(p.allow) == 0 || underAny(path, p.allow)
+}
+
+// RequiresKnownPath reports whether queries whose path cannot be determined must be
+// refused, which is the case when only allowed directories may be used
+func (p *NQEDirectoryPolicy) RequiresKnownPath() bool {
+	return p != nil && len(p.allow) > 0
+}
+
+// nqeQueryPath finds the library path of a query ID, first in the local index and then
+// in the Forward NQE library
+func (s *ForwardMCPService) nqeQueryPath(queryID string) (string, bool) {
+	if s.queryIndex != nil {
+		if entry, err := s.queryIndex.GetQueryByID(queryID); err == nil {
+			return entry.Path, true
+		}
+	}
+	queries, err := s.client().GetNQEQueries("")
+	if err != nil {
+		s.logger.Warn("Could not look up the directory of NQE query %s: %v", queryID, err)
+		return "", false
+	}
+	for _, query := range queries {
+		if query.QueryID == queryID {
+			return query.Path, true
+		}
+	}
+	return "", false
+}
+
+// checkNQEPolicy refuses to run a query that the directory policy does not allow
+func (s *ForwardMCPService) checkNQEPolicy(queryID string) error {
+	if s.nqePolicy == nil {
+		return nil
+	}
+	path, found := s.nqeQueryPath(queryID)
+	if !found {
+		if s.nqePolicy.RequiresKnownPath() {
+			return fmt.Errorf("NQE query %s is not permitted: its directory could not be determined and this server only allows queries from approved directories", queryID)
+		}
+		return nil
+	}
+	if !s.nqePolicy.Allows(path) {
+		return fmt.Errorf("NQE query %s (%s) is not permitted: its directory is blocked by this server's NQE directory policy", queryID, path)
+	}
+	return nil
+}
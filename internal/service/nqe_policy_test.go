@@ -0,0 +1,85 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// secretsPolicyService denies /Security/Secrets, with one allowed and one denied query
+// in both the Forward library and the local index
+func secretsPolicyService() *ForwardMCPService {
+	service := createTestService()
+	client := service.client().(*MockForwardClient)
+	client.nqeQueries = []forward.NQEQuery{
+		{QueryID: "FQ_devices", Path: "/L3/Basic/All Devices"},
+		{QueryID: "FQ_secrets", Path: "/Security/Secrets/SNMP Communities"},
+	}
+	client.nqeResult = &forward.NQERunResult{Items: []map[string]interface{}{{"name": "router-1"}}}
+
+	service.nqePolicy = NewNQEDirectoryPolicy(nil, []string{"/Security/Secrets"})
+	service.queryIndex = NewNQEQueryIndex(NewKeywordEmbeddingService(), createTestLogger())
+	service.queryIndex.SetDirectoryPolicy(service.nqePolicy)
+	seedQueryIndex(service.queryIndex, "/L3/Basic/All Devices", "/Security/Secrets/SNMP Communities")
+	return service
+}
+
+func TestNQEPolicyHidesDeniedQueries(t *testing.T) {
+	service := secretsPolicyService()
+
+	response, err := service.listNQEQueries(ListNQEQueriesArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "Found 1 NQE queries") || strings.Contains(text, "FQ_secrets") {
+		t.Errorf("Expected the denied query to be hidden from listing, got:\n%s", text)
+	}
+
+	results, err := service.queryIndex.SearchByKeywords("snmp communities secrets", 5)
+	if err != nil {
+		t.Fatalf("Expected search to succeed, got: %v", err)
+	}
+	for _, result := range results {
+		if strings.HasPrefix(result.Path, "/Security/Secrets/") {
+			t.Errorf("Expected the denied query to be hidden from search, got %s", result.Path)
+		}
+	}
+	if queries := service.queryIndex.Queries(); len(queries) != 1 || queries[0].Path != "/L3/Basic/All Devices" {
+		t.Errorf("Expected only the allowed query in the index listing, got %d queries", len(queries))
+	}
+}
+
+func TestNQEPolicyRejectsDeniedExecution(t *testing.T) {
+	service := secretsPolicyService()
+
+	_, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_secrets"})
+	if err == nil || !strings.Contains(err.Error(), "blocked by this server's NQE directory policy") {
+		t.Errorf("Expected a policy error for the denied query, got: %v", err)
+	}
+
+	if _, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_devices"}); err != nil {
+		t.Errorf("Expected the allowed query to run, got: %v", err)
+	}
+}
+
+func TestNQEDirectoryPolicyAllows(t *testing.T) {
+	policy := NewNQEDirectoryPolicy([]string{"/L3"}, []string{"/L3/Secrets/"})
+
+	testCases := map[string]bool{
+		"/L3/BGP/Neighbors":     true,
+		"/l3/bgp/neighbors":     true,
+		"/L3/Secrets/Passwords": false,
+		"/L3v6/Peers":           false,
+		"/Interfaces/Errors":    false,
+	}
+	for path, expected := range testCases {
+		if allowed := policy.Allows(path); allowed != expected {
+			t.Errorf("Allows(%q) = %v, expected %v", path, allowed, expected)
+		}
+	}
+	if NewNQEDirectoryPolicy(nil, []string{" "}) != nil {
+		t.Error("Expected an empty policy to be nil")
+	}
+}
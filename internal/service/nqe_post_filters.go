@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// nqePostFilterOperators lists the supported post-filter operators
+var nqePostFilterOperators = []string{"=", "!=", ">", "<", ">=", "<=", "contains"}
+
+// nqePostFilter is a validated post-filter; number is the value parsed for numeric operators
+type nqePostFilter struct {
+	NQEPostFilter
+	number float64
+}
+
+// compileNQEPostFilters validates post-filters, so a bad operator or a non-numeric
+// comparison value fails before the query runs
+func compileNQEPostFilters(filters []NQEPostFilter) ([]nqePostFilter, error) {
+	compiled := make([]nqePostFilter, 0, len(filters))
+	for i, filter := range filters {
+		filter.Operator = strings.ToLower(strings.TrimSpace(filter.Operator))
+		if filter.ColumnName == "" {
+			return nil, fmt.Errorf("post_filters[%d]: column_name is required", i)
+		}
+		known := false
+		for _, op := range nqePostFilterOperators {
+			known = known || op == filter.Operator
+		}
+		if !known {
+			return nil, fmt.Errorf("post_filters[%d]: unknown operator %q (supported: %s)", i, filter.Operator, strings.Join(nqePostFilterOperators, ", "))
+		}
+
+		entry := nqePostFilter{NQEPostFilter: filter}
+		switch filter.Operator {
+		case ">", "<", ">=", "<=":
+			number, err := strconv.ParseFloat(strings.TrimSpace(filter.Value), 64)
+			if err != nil {
+				return nil, fmt.Errorf("post_filters[%d]: operator %s needs a numeric value, got %q", i, filter.Operator, filter.Value)
+			}
+			entry.number = number
+		}
+		compiled = append(compiled, entry)
+	}
+	return compiled, nil
+}
+
+// nqeCellText renders a scalar NQE value for string comparison
+func nqeCellText(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return fmt.Sprint(value)
+}
+
+// matchesValue reports whether one scalar value satisfies the filter
+func (f nqePostFilter) matchesValue(value interface{}) bool {
+	switch f.Operator {
+	case "contains":
+		return strings.Contains(strings.ToLower(nqeCellText(value)), strings.ToLower(f.Value))
+	case "=", "!=":
+		equal := nqeCellText(value) == f.Value
+		if number, ok := nqeNumber(value); ok {
+			if want, err := strconv.ParseFloat(strings.TrimSpace(f.Value), 64); err == nil {
+				equal = number == want
+			}
+		}
+		return equal == (f.Operator == "=")
+	}
+
+	number, ok := nqeNumber(value)
+	if !ok {
+		return false
+	}
+	switch f.Operator {
+	case ">":
+		return number > f.number
+	case "<":
+		return number < f.number
+	case ">=":
+		return number >= f.number
+	default:
+		return number <= f.number
+	}
+}
+
+// matches reports whether a row satisfies the filter. A missing or null value only
+// satisfies !=, and a list matches when any of its elements does (all, for !=).
+func (f nqePostFilter) matches(row map[string]interface{}) bool {
+	value, ok := row[f.ColumnName]
+	if !ok || value == nil {
+		return f.Operator == "!="
+	}
+	list, isList := value.([]interface{})
+	if !isList {
+		return f.matchesValue(value)
+	}
+	for _, item := range list {
+		matched := f.matchesValue(item)
+		if f.Operator == "!=" && !matched {
+			return false
+		}
+		if f.Operator != "!=" && matched {
+			return true
+		}
+	}
+	return f.Operator == "!="
+}
+
+// FilterNQEItems keeps the rows that satisfy every post-filter (AND semantics). A filter
+// naming a column absent from every row is reported rather than silently matching nothing.
+func FilterNQEItems(items []map[string]interface{}, filters []NQEPostFilter) ([]map[string]interface{}, error) {
+	compiled, err := compileNQEPostFilters(filters)
+	if err != nil || len(compiled) == 0 {
+		return items, err
+	}
+
+	if len(items) > 0 {
+		columns := nqeResultColumns(items)
+		for _, filter := range compiled {
+			found := false
+			for _, column := range columns {
+				found = found || column == filter.ColumnName
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown post_filters column %q (available: %s)", filter.ColumnName, strings.Join(columns, ", "))
+			}
+		}
+	}
+
+	filtered := make([]map[string]interface{}, 0, len(items))
+	for _, row := range items {
+		keep := true
+		for _, filter := range compiled {
+			if !filter.matches(row) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered, nil
+}
+
+// applyNQEPostFilters returns a copy of result holding only the rows that satisfy filters,
+// with a note of how many rows of the page were kept. The cached result is not modified.
+func applyNQEPostFilters(result *forward.NQERunResult, filters []NQEPostFilter) (*forward.NQERunResult, string, error) {
+	if len(filters) == 0 {
+		return result, "", nil
+	}
+	items, err := FilterNQEItems(result.Items, filters)
+	if err != nil {
+		return nil, "", err
+	}
+	filtered := *result
+	filtered.Items = items
+	return &filtered, fmt.Sprintf("Post-filters kept %d of %d rows on this page.\n\n", len(items), len(result.Items)), nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// postFilterTestItems mixes numeric values and numbers encoded as strings, as NQE returns both
+func postFilterTestItems() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"name": "router-1", "platform": "cisco_ios", "mtu": 1500.0},
+		{"name": "switch-1", "platform": "cisco_nxos", "mtu": "9216"},
+		{"name": "edge-1", "platform": "cisco_ios_xr", "mtu": 9000.0},
+		{"name": "fw-1", "platform": "paloalto_panos", "mtu": 1400.0},
+	}
+}
+
+// filteredNames returns the name column of filtered rows
+func filteredNames(items []map[string]interface{}) []string {
+	var names []string
+	for _, item := range items {
+		names = append(names, item["name"].(string))
+	}
+	return names
+}
+
+func TestFilterNQEItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []NQEPostFilter
+		want    string
+	}{
+		{"contains is case-insensitive", []NQEPostFilter{{ColumnName: "platform", Operator: "contains", Value: "IOS"}}, "router-1,edge-1"},
+		{"numeric threshold", []NQEPostFilter{{ColumnName: "mtu", Operator: ">=", Value: "9000"}}, "switch-1,edge-1"},
+		{"filters combine with AND", []NQEPostFilter{
+			{ColumnName: "platform", Operator: "contains", Value: "IOS"},
+			{ColumnName: "mtu", Operator: "<", Value: "9000"},
+		}, "router-1"},
+		{"equality compares numbers numerically", []NQEPostFilter{{ColumnName: "mtu", Operator: "=", Value: "9216.0"}}, "switch-1"},
+		{"not equal", []NQEPostFilter{{ColumnName: "platform", Operator: "!=", Value: "cisco_ios"}}, "switch-1,edge-1,fw-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := FilterNQEItems(postFilterTestItems(), tt.filters)
+			if err != nil {
+				t.Fatalf("FilterNQEItems failed: %v", err)
+			}
+			if got := strings.Join(filteredNames(items), ","); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterNQEItemsRejectsInvalidFilters(t *testing.T) {
+	tests := []struct {
+		filter NQEPostFilter
+		want   string
+	}{
+		{NQEPostFilter{ColumnName: "mtu", Operator: "~", Value: "1500"}, `unknown operator "~"`},
+		{NQEPostFilter{ColumnName: "mtu", Operator: ">", Value: "large"}, "needs a numeric value"},
+		{NQEPostFilter{ColumnName: "vendor", Operator: "=", Value: "CISCO"}, `unknown post_filters column "vendor"`},
+	}
+	for _, tt := range tests {
+		if _, err := FilterNQEItems(postFilterTestItems(), []NQEPostFilter{tt.filter}); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected error containing %q for %+v, got %v", tt.want, tt.filter, err)
+		}
+	}
+}
+
+func TestRunNQEQueryAppliesPostFiltersWithoutChangingCache(t *testing.T) {
+	service := createTestService()
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	client.nqeResult = &forward.NQERunResult{SnapshotID: "snap-1", Items: postFilterTestItems()}
+	service.active().client = client
+
+	args := RunNQEQueryByIDArgs{
+		NetworkID:   "162112",
+		QueryID:     "FQ_test",
+		PostFilters: []NQEPostFilter{{ColumnName: "platform", Operator: "contains", Value: "IOS"}},
+	}
+	response, err := service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("runNQEQueryByID failed: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "Post-filters kept 2 of 4 rows") || strings.Contains(text, "fw-1") {
+		t.Errorf("Expected only the IOS rows, got:\n%s", text)
+	}
+
+	// The cached result keeps every row for later calls with other filters
+	args.PostFilters = nil
+	response, err = service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("runNQEQueryByID failed: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; len(client.queryIDs) != 1 || !strings.Contains(text, "fw-1") {
+		t.Errorf("Expected the unfiltered rows from the cache, got %d runs:\n%s", len(client.queryIDs), text)
+	}
+}
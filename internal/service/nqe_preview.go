@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// nqePreviewRows is the row limit of a preview run
+const nqePreviewRows = 3
+
+// previewNQEQuery runs a query for a few rows and returns their inferred schema and the
+// rows themselves, so the shape of a result can be checked before paying for a full run
+func (s *ForwardMCPService) previewNQEQuery(args RunNQEQueryByIDArgs) (*mcp.ToolResponse, error) {
+	requested := 0
+	options := NQEQueryOptions{}
+	if args.Options != nil {
+		options = *args.Options
+		requested = options.Limit
+	}
+	options.Limit = nqePreviewRows
+	args.Options = &options
+
+	_, result, _, err := s.fetchNQEResult(args)
+	if err != nil {
+		return nil, err
+	}
+	if result, err = flattenNQEResult(result, args.Flatten, args.FlattenDepth); err != nil {
+		return nil, err
+	}
+	result, filterNote, err := applyNQEPostFilters(result, args.PostFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	fullLimit, err := s.nqeRowLimit(requested)
+	if err != nil {
+		return nil, err
+	}
+	response := fmt.Sprintf("🔍 PREVIEW of %s: %d sample row(s) from a %d-row run. This is not the full result.\n\n", args.QueryID, len(result.Items), nqePreviewRows)
+	response += filterNote
+	if len(result.Items) == 0 {
+		response += "The preview returned no rows, so no schema could be inferred.\n\n"
+	} else {
+		items := result.Items
+		var payload interface{} = items
+		if len(args.Columns) > 0 {
+			var columns []string
+			items, columns, _ = projectNQEColumns(items, args.Columns)
+			rows := make([]orderedNQERow, len(items))
+			for i, item := range items {
+				rows[i] = orderedNQERow{columns: columns, values: item}
+			}
+			payload = rows
+		}
+		response += formatNQESchema(InferNQESchema(items))
+		response += fmt.Sprintf("Sample rows:\n%s\n\n", s.toJSON(payload, args.Pretty))
+	}
+	response += fmt.Sprintf("To run the full query, repeat this call without preview (up to %d rows); page with options.offset or narrow with columns / post_filters.", fullLimit)
+	if strings.TrimSpace(args.SnapshotID) == "" && result.SnapshotID != "" {
+		response += fmt.Sprintf(" Pass snapshot_id %s to get the same snapshot.", result.SnapshotID)
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
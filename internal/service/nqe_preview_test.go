@@ -0,0 +1,44 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestNQEPreviewRequestsSmallLimit(t *testing.T) {
+	service := createTestService()
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	client.nqeResult = &forward.NQERunResult{SnapshotID: "snap-1", Items: []map[string]interface{}{
+		{"name": "router-1", "platform": "ios", "uptime": float64(100)},
+		{"name": "switch-1", "platform": "eos", "uptime": nil},
+	}}
+	service.active().client = client
+
+	response, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{QueryID: "FQ_devices", Preview: true, Options: &NQEQueryOptions{Limit: 500}, NoCache: true})
+	if err != nil {
+		t.Fatalf("runNQEQueryByID failed: %v", err)
+	}
+	if len(client.limits()) != 1 || client.limits()[0] != nqePreviewRows {
+		t.Fatalf("Expected one run with limit %d, got %v", nqePreviewRows, client.limits())
+	}
+	text := response.Content[0].TextContent.Text
+	for _, want := range []string{"PREVIEW of FQ_devices", "not the full result", "• uptime: number (nullable)", "router-1", "repeat this call without preview (up to 500 rows)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the preview, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "NQE query completed") {
+		t.Errorf("Expected a preview rather than a full result, got:\n%s", text)
+	}
+
+	// First-class tools pass the flag through, skipping the lifecycle summary
+	response, err = service.getOSSupport(GetOSSupportArgs{NetworkID: "162112", Preview: true, NoCache: true})
+	if err != nil {
+		t.Fatalf("getOSSupport failed: %v", err)
+	}
+	if client.limits()[1] != nqePreviewRows || !strings.Contains(response.Content[0].TextContent.Text, "PREVIEW of ") {
+		t.Errorf("Expected get_os_support to preview with limit %d, got %v:\n%s", nqePreviewRows, client.limits(), response.Content[0].TextContent.Text)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// orderedNQERow is an NQE result row that serializes its columns in a fixed order
+type orderedNQERow struct {
+	columns []string
+	values  map[string]interface{}
+}
+
+// MarshalJSON writes the row's columns in the projected order rather than sorted
+func (r orderedNQERow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	written := 0
+	for _, column := range r.columns {
+		value, ok := r.values[column]
+		if !ok {
+			continue
+		}
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
+		written++
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// projectedNQEResult mirrors forward.NQERunResult with rows limited to the projected columns
+type projectedNQEResult struct {
+	SnapshotID string          `json:"snapshotId"`
+	Items      []orderedNQERow `json:"items"`
+}
+
+// projectNQEColumns keeps only the named columns of each row, in the given order. Columns
+// that appear in no row are returned as unknown and otherwise ignored. The input rows are
+// not modified, so cached results stay intact.
+func projectNQEColumns(items []map[string]interface{}, columns []string) ([]map[string]interface{}, []string, []string) {
+	present := make(map[string]bool)
+	for _, item := range items {
+		for column := range item {
+			present[column] = true
+		}
+	}
+
+	var known, unknown []string
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if seen[column] {
+			continue
+		}
+		seen[column] = true
+		if present[column] {
+			known = append(known, column)
+		} else {
+			unknown = append(unknown, column)
+		}
+	}
+
+	projected := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		row := make(map[string]interface{}, len(known))
+		for _, column := range known {
+			if value, ok := item[column]; ok {
+				row[column] = value
+			}
+		}
+		projected[i] = row
+	}
+	return projected, known, unknown
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunNQEQueryProjectsColumns(t *testing.T) {
+	service := createTestService()
+
+	response, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{
+		NetworkID: "162112",
+		QueryID:   "FQ_test",
+		Columns:   []string{"device_name"},
+		Pretty:    new(bool),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	var result struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	start := strings.Index(content, `{"snapshotId"`)
+	if start < 0 {
+		t.Fatalf("Expected compact result JSON, got: %s", content)
+	}
+	if err := json.NewDecoder(strings.NewReader(content[start:])).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(result.Items) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(result.Items))
+	}
+	for _, item := range result.Items {
+		if len(item) != 1 || item["device_name"] == nil {
+			t.Errorf("Expected only device_name to remain, got %v", item)
+		}
+	}
+
+	// The cached result keeps every column for later calls
+	full, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_test"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !contains(full.Content[0].TextContent.Text, "Cisco NX-OS") {
+		t.Error("Expected an unprojected run to include all columns")
+	}
+}
+
+func TestRunNQEQueryWarnsOnUnknownColumns(t *testing.T) {
+	service := createTestService()
+
+	response, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{
+		NetworkID: "162112",
+		QueryID:   "FQ_test",
+		Columns:   []string{"platform", "serial", "device_name"},
+	})
+	if err != nil {
+		t.Fatalf("Expected unknown columns not to fail, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	if !contains(content, "Unknown columns ignored: serial (available: device_name, platform)") {
+		t.Errorf("Expected unknown column warning, got: %s", content)
+	}
+	if strings.Index(content, `"platform"`) > strings.Index(content, `"device_name"`) {
+		t.Errorf("Expected columns in the requested order, got: %s", content)
+	}
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -25,7 +27,10 @@ type NQEQueryIndexEntry struct {
 	LastUpdated time.Time `json:"lastUpdated"`
 }
 
-// NQEQueryIndex manages the searchable index of NQE queries
+// NQEQueryIndex manages the searchable index of NQE queries.
+// The queries slice, per-query embeddings, and the embeddings map are guarded by mutex:
+// searches and statistics take the read lock; loads take the write lock, and embedding generation
+// takes it only to install each result.
 type NQEQueryIndex struct {
 	queries             []*NQEQueryIndexEntry
 	embeddings          map[string][]float32
@@ -34,31 +39,93 @@ type NQEQueryIndex struct {
 	mutex               sync.RWMutex
 	indexPath           string
 	embeddingsCachePath string // Path to save/load embeddings
+	specDir             string // Configured spec directory; empty searches the default locations
 	offlineMode         bool   // Whether to work with cached embeddings only
+
+	// Embedding generation checkpoints every checkpointInterval new embeddings and
+	// backs off from rateLimitBackoff (doubling) when the provider is rate limited
+	checkpointInterval int
+	rateLimitBackoff   time.Duration
+	sleep              func(time.Duration)
+
+	// At most maxResidentEmbeddings embeddings stay in memory (0 = no cap); spilled holds
+	// the paths of queries whose embedding is only in the cache file. lastUsed orders
+	// spilling by when a query last appeared in search results.
+	maxResidentEmbeddings int
+	spilled               map[string]bool
+	usageMutex            sync.Mutex
+	lastUsed              map[string]uint64
+	usageClock            uint64
+
+	// policy hides queries outside the allowed NQE directories from search and listing
+	policy *NQEDirectoryPolicy
+
+	// regenerateEmbeddings is set when the embeddings cache file was corrupt and set aside,
+	// until embeddings are generated and saved again
+	regenerateEmbeddings bool
+
+	// embeddingTag is the vector space of every embedding in the index. Embeddings served
+	// by a fallback provider are never stored, so they all share it.
+	embeddingTag EmbeddingTag
+
+	// generating is set while GenerateEmbeddingsWithProgress runs. Embeddings are requested
+	// without holding mutex, so it keeps a second run from embedding the same queries.
+	generating bool
 }
 
+// Spec directory file names
+const (
+	specLibraryFile     = "NQELibrary.json"
+	embeddingsCacheFile = "nqe-embeddings.json"
+)
+
+// embeddingTagSuffix names the file next to the embeddings cache that records the provider
+// and dimension of the cached embeddings
+const embeddingTagSuffix = ".provider"
+
+// errCorruptEmbeddingsCache marks an embeddings cache file that exists but cannot be parsed
+var errCorruptEmbeddingsCache = errors.New("embeddings cache is corrupt")
+
+// Embedding generation defaults
+const (
+	defaultEmbeddingCheckpointInterval = 100
+	defaultEmbeddingRateLimitBackoff   = 2 * time.Second
+	maxEmbeddingRateLimitRetries       = 5
+	maxConsecutiveEmbeddingFailures    = 5
+)
+
 // QuerySearchResult represents a search result with similarity score
 type QuerySearchResult struct {
 	*NQEQueryIndexEntry
 	SimilarityScore float64 `json:"similarityScore"`
-	MatchType       string  `json:"matchType"` // "intent", "path", "code"
+	MatchType       string  `json:"matchType"` // "semantic", "keyword", or "keyword-fallback"
+}
+
+// MatchTypeKeywordFallback marks results produced by keyword matching because the
+// embedding service failed during a semantic search
+const MatchTypeKeywordFallback = "keyword-fallback"
+
+// IsDegradedSearch reports whether the results came from the keyword fallback
+// used when the embedding service is unavailable
+func IsDegradedSearch(results []*QuerySearchResult) bool {
+	return len(results) > 0 && results[0].MatchType == MatchTypeKeywordFallback
 }
 
 // NewNQEQueryIndex creates a new query index
 func NewNQEQueryIndex(embeddingService EmbeddingService, logger *logger.Logger) *NQEQueryIndex {
 	// Try to find the spec file using robust path resolution
-	specPath, err := findSpecFile("NQELibrary.json")
+	specPath, err := findSpecFile(specLibraryFile)
 	if err != nil {
 		logger.Debug("Could not locate spec file during initialization: %v", err)
-		specPath = "spec/NQELibrary.json" // fallback to relative path
+		specPath = filepath.Join("spec", specLibraryFile) // fallback to relative path
 	}
 
 	// Find embeddings cache path in the same directory as spec file
-	embeddingsCachePath := "spec/nqe-embeddings.json"
-	if specPath != "spec/NQELibrary.json" {
+	embeddingsCachePath := filepath.Join("spec", embeddingsCacheFile)
+	if specPath != filepath.Join("spec", specLibraryFile) {
 		// Use the same directory as the spec file for embeddings cache
 		specDir := filepath.Dir(specPath)
-		embeddingsCachePath = filepath.Join(specDir, "nqe-embeddings.json")
+		embeddingsCachePath = filepath.Join(specDir, embeddingsCacheFile)
 	}
 
 	return &NQEQueryIndex{
@@ -69,6 +136,9 @@ func NewNQEQueryIndex(embeddingService EmbeddingService, logger *logger.Logger)
 		indexPath:           specPath,
 		embeddingsCachePath: embeddingsCachePath,
 		offlineMode:         false,
+		checkpointInterval:  defaultEmbeddingCheckpointInterval,
+		rateLimitBackoff:    defaultEmbeddingRateLimitBackoff,
+		sleep:               time.Sleep,
 	}
 }
 
@@ -77,17 +147,87 @@ func (idx *NQEQueryIndex) LoadFromSpec() error {
 	idx.mutex.Lock()
 	defer idx.mutex.Unlock()
 
+	queries, err := idx.readSpecFile()
+	if err != nil {
+		return err
+	}
+
+	idx.queries = queries
+	idx.spilled = nil
+	idx.embeddingTag = EmbeddingTag{}
+	idx.logger.Info("Loaded %d NQE queries into search index", len(queries))
+
+	// Try to load pre-generated embeddings. A corrupt cache (e.g. from an interrupted
+	// write) is set aside so search falls back to keywords instead of failing.
+	if err := idx.loadEmbeddingsFromCache(); errors.Is(err, errCorruptEmbeddingsCache) {
+		idx.logger.Warn("Ignoring %v; search uses keywords until embeddings are regenerated with 'initialize_query_index' and 'generate_embeddings: true'", err)
+		idx.setAsideCorruptEmbeddingsCache()
+	} else if err != nil {
+		idx.logger.Debug("Could not load cached embeddings: %v", err)
+		idx.logger.Debug("Run 'initialize_query_index' with 'generate_embeddings: true' to create embeddings cache")
+	} else {
+		embeddedCount := 0
+		for _, query := range idx.queries {
+			if len(query.Embedding) > 0 {
+				embeddedCount++
+			}
+		}
+		idx.logger.Info("Loaded %d cached embeddings for offline AI search", embeddedCount)
+		if err := idx.enforceEmbeddingCap(); err != nil {
+			idx.logger.Warn("Keeping all embeddings in memory: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// SetSpecDir reads the spec file and keeps the embeddings cache in dir instead of searching
+// the default locations, so the server works from any working directory. The directory is
+// resolved to an absolute path; an empty dir keeps the default search.
+func (idx *NQEQueryIndex) SetSpecDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid spec directory %q: %w", dir, err)
+	}
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	idx.specDir = absDir
+	idx.indexPath = filepath.Join(absDir, specLibraryFile)
+	idx.embeddingsCachePath = filepath.Join(absDir, embeddingsCacheFile)
+	return nil
+}
+
+// specFilePath returns the spec file the index was created with, or locates it again
+// when that path no longer exists. A configured spec directory is never searched past.
+func (idx *NQEQueryIndex) specFilePath() (string, error) {
+	if idx.indexPath != "" {
+		if _, err := os.Stat(idx.indexPath); err == nil {
+			return idx.indexPath, nil
+		}
+	}
+	if idx.specDir != "" {
+		return "", fmt.Errorf("spec file %s not found in spec directory %s", specLibraryFile, idx.specDir)
+	}
+	return findSpecFile(specLibraryFile)
+}
+
+// readSpecFile parses the spec file into index entries with path metadata applied
+func (idx *NQEQueryIndex) readSpecFile() ([]*NQEQueryIndexEntry, error) {
 	// Try to find the spec file using robust path resolution
-	specPath, err := findSpecFile("NQELibrary.json")
+	specPath, err := idx.specFilePath()
 	if err != nil {
-		return fmt.Errorf("failed to open spec file: %w", err)
+		return nil, fmt.Errorf("failed to open spec file: %w", err)
 	}
 
 	idx.logger.Debug("Loading NQE query index from spec file: %s", specPath)
 
 	file, err := os.Open(specPath)
 	if err != nil {
-		return fmt.Errorf("failed to open spec file: %w", err)
+		return nil, fmt.Errorf("failed to open spec file: %w", err)
 	}
 	defer file.Close()
 
@@ -98,46 +238,31 @@ func (idx *NQEQueryIndex) LoadFromSpec() error {
 
 	decoder := json.NewDecoder(file)
 	if err := decoder.Decode(&nqeLibrary); err != nil {
-		return fmt.Errorf("failed to parse JSON file: %w", err)
+		return nil, fmt.Errorf("failed to parse JSON file: %w", err)
 	}
 
 	if len(nqeLibrary.Queries) == 0 {
 		idx.logger.Warn("No queries loaded from spec file")
-		return fmt.Errorf("no queries found in spec file")
+		return nil, fmt.Errorf("no queries found in spec file")
 	}
 
 	// Parse path into category, subcategory, and intent for each query
 	for _, query := range nqeLibrary.Queries {
-		segments := strings.Split(strings.Trim(query.Path, "/"), "/")
-		if len(segments) > 0 {
-			query.Category = segments[0]
-		}
-		if len(segments) > 1 {
-			query.Subcategory = segments[1]
-		}
-		if len(segments) > 0 {
-			query.Intent = segments[len(segments)-1]
-		}
+		applyPathMetadata(query)
 	}
+	return nqeLibrary.Queries, nil
+}
 
-	idx.queries = nqeLibrary.Queries
-	idx.logger.Info("Loaded %d NQE queries into search index", len(nqeLibrary.Queries))
-
-	// Try to load pre-generated embeddings
-	if err := idx.loadEmbeddingsFromCache(); err != nil {
-		idx.logger.Debug("Could not load cached embeddings: %v", err)
-		idx.logger.Debug("Run 'initialize_query_index' with 'generate_embeddings: true' to create embeddings cache")
-	} else {
-		embeddedCount := 0
-		for _, query := range idx.queries {
-			if len(query.Embedding) > 0 {
-				embeddedCount++
-			}
-		}
-		idx.logger.Info("Loaded %d cached embeddings for offline AI search", embeddedCount)
+// applyPathMetadata derives category, subcategory, and intent from the query path
+func applyPathMetadata(query *NQEQueryIndexEntry) {
+	segments := strings.Split(strings.Trim(query.Path, "/"), "/")
+	if len(segments) > 0 {
+		query.Category = segments[0]
+		query.Intent = segments[len(segments)-1]
+	}
+	if len(segments) > 1 {
+		query.Subcategory = segments[1]
 	}
-
-	return nil
 }
 
 // loadEmbeddingsFromCache loads pre-generated embeddings from disk
@@ -149,23 +274,76 @@ func (idx *NQEQueryIndex) loadEmbeddingsFromCache() error {
 
 	var embeddingsCache map[string][]float32
 	if err := json.Unmarshal(data, &embeddingsCache); err != nil {
-		return fmt.Errorf("failed to unmarshal embeddings cache: %w", err)
+		return fmt.Errorf("%w: %s: %v", errCorruptEmbeddingsCache, idx.embeddingsCachePath, err)
+	}
+
+	// Caches written before tags were recorded have no tag file; their dimension is taken
+	// from the first embedding
+	tag, err := idx.readEmbeddingTag()
+	if err != nil {
+		idx.logger.Debug("Cached embeddings have no provider tag: %v", err)
+	}
+	hashes, err := idx.readTextHashes()
+	if err != nil {
+		idx.logger.Debug("Cached embeddings have no text hashes: %v", err)
 	}
 
-	// Match embeddings to queries by path (more reliable than generated IDs)
-	embeddingsLoaded := 0
+	// Match embeddings to queries by path (more reliable than generated IDs). Corrupt
+	// embeddings, embeddings of another dimension, and embeddings of text that has since
+	// changed are dropped so the next generation run replaces them.
+	embeddingsLoaded, embeddingsRejected := 0, 0
 	for _, query := range idx.queries {
 		if embedding, exists := embeddingsCache[query.Path]; exists {
+			if err := validateEmbedding(embedding); err != nil {
+				idx.logger.Warn("Discarding cached embedding for %s: %v", query.Path, err)
+				embeddingsRejected++
+				continue
+			}
+			if staleText(hashes, query) {
+				idx.logger.Debug("Discarding cached embedding for %s: its text changed", query.Path)
+				embeddingsRejected++
+				continue
+			}
+			if tag.Dimensions == 0 {
+				tag.Dimensions = len(embedding)
+			} else if len(embedding) != tag.Dimensions {
+				idx.logger.Warn("Discarding cached embedding for %s: %d dimensions, expected %d", query.Path, len(embedding), tag.Dimensions)
+				embeddingsRejected++
+				continue
+			}
 			query.Embedding = embedding
 			idx.embeddings[query.QueryID] = embedding
 			embeddingsLoaded++
 		}
 	}
 
-	idx.logger.Debug("Loaded %d embeddings from cache file", embeddingsLoaded)
+	idx.embeddingTag = tag
+	idx.logger.Debug("Loaded %d embeddings from cache file (%d discarded for regeneration)", embeddingsLoaded, embeddingsRejected)
 	return nil
 }
 
+// readEmbeddingTag reads the provider tag recorded next to the embeddings cache
+func (idx *NQEQueryIndex) readEmbeddingTag() (EmbeddingTag, error) {
+	data, err := os.ReadFile(idx.embeddingsCachePath + embeddingTagSuffix)
+	if err != nil {
+		return EmbeddingTag{}, err
+	}
+	return parseEmbeddingTag(string(data))
+}
+
+// setAsideCorruptEmbeddingsCache renames a corrupt embeddings cache so the next save
+// writes a fresh file, keeping the corrupt one for inspection, and marks the embeddings
+// for regeneration
+func (idx *NQEQueryIndex) setAsideCorruptEmbeddingsCache() {
+	idx.regenerateEmbeddings = true
+	corruptPath := idx.embeddingsCachePath + ".corrupt"
+	if err := os.Rename(idx.embeddingsCachePath, corruptPath); err != nil {
+		idx.logger.Warn("Failed to set aside corrupt embeddings cache: %v", err)
+		return
+	}
+	idx.logger.Warn("Moved corrupt embeddings cache to %s", corruptPath)
+}
+
 // saveEmbeddingsToCache saves generated embeddings to disk for offline use
 func (idx *NQEQueryIndex) saveEmbeddingsToCache() error {
 	// Create a map of path -> embedding for reliable lookup
@@ -177,70 +355,122 @@ func (idx *NQEQueryIndex) saveEmbeddingsToCache() error {
 		}
 	}
 
+	// Spilled embeddings are only on disk, so carry them over from the current file
+	if len(idx.spilled) > 0 {
+		onDisk, err := idx.readSpilledEmbeddings()
+		if err != nil {
+			return fmt.Errorf("failed to preserve spilled embeddings: %w", err)
+		}
+		for path := range idx.spilled {
+			if embedding, ok := onDisk[path]; ok {
+				if _, resident := embeddingsCache[path]; !resident {
+					embeddingsCache[path] = embedding
+				}
+			}
+		}
+	}
+
 	data, err := json.MarshalIndent(embeddingsCache, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal embeddings cache: %w", err)
 	}
 
-	if err := os.WriteFile(idx.embeddingsCachePath, data, 0644); err != nil {
+	if err := writeFileAtomic(idx.embeddingsCachePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write embeddings cache: %w", err)
 	}
+	if idx.embeddingTag.Provider != "" {
+		if err := writeFileAtomic(idx.embeddingsCachePath+embeddingTagSuffix, []byte(idx.embeddingTag.String()+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to record embedding provider: %w", err)
+		}
+	}
+	if err := idx.recordTextHashes(embeddingsCache); err != nil {
+		return err
+	}
 
+	idx.regenerateEmbeddings = false
 	idx.logger.Info("Saved %d embeddings to cache file: %s", len(embeddingsCache), idx.embeddingsCachePath)
 	return nil
 }
 
 // GenerateEmbeddings creates embeddings for all queries using the embedding service
 func (idx *NQEQueryIndex) GenerateEmbeddings() error {
-	idx.mutex.Lock()
-	defer idx.mutex.Unlock()
+	return idx.GenerateEmbeddingsWithProgress(nil)
+}
 
-	// Check if we can actually generate embeddings
-	if _, ok := idx.embeddingService.(*MockEmbeddingService); ok {
-		return fmt.Errorf("cannot generate real embeddings with mock service - set OPENAI_API_KEY")
+// GenerateEmbeddingsWithProgress generates embeddings like GenerateEmbeddings and calls
+// progress, if non-nil, after each query with the number processed and the total.
+// Queries that already have an embedding (e.g. loaded from the cache file) are skipped,
+// and progress is checkpointed to the cache file periodically, so a run that stops
+// partway resumes where it left off.
+func (idx *NQEQueryIndex) GenerateEmbeddingsWithProgress(progress func(processed, total int)) error {
+	pending, total, err := idx.beginEmbeddingRun()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		idx.mutex.Lock()
+		idx.generating = false
+		idx.mutex.Unlock()
+	}()
+
+	checkpointInterval := idx.checkpointInterval
+	if checkpointInterval <= 0 {
+		checkpointInterval = defaultEmbeddingCheckpointInterval
 	}
 
-	idx.logger.Info("Generating embeddings for %d NQE queries...", len(idx.queries))
+	idx.logger.Info("Generating embeddings for %d NQE queries...", total)
 
-	successCount := 0
-	for i, query := range idx.queries {
-		// Skip if embedding already exists (for resuming)
-		if len(query.Embedding) > 0 {
-			successCount++
-			continue
+	// Embeddings are requested without the lock, so searches keep running through slow
+	// provider calls and rate-limit backoff; the lock is only taken to install each result
+	successCount := total - len(pending)
+	generated := 0
+	consecutiveFailures := 0
+	for i, job := range pending {
+		if progress != nil {
+			progress(total-len(pending)+i, total)
 		}
 
-		// Use all parsed fields for richer context
-		searchText := fmt.Sprintf(
-			"Query Path: %s\nCategory: %s\nSubcategory: %s\nIntent: %s",
-			query.Path, query.Category, query.Subcategory, query.Intent,
-		)
-
-		embedding, err := idx.embeddingService.GenerateEmbedding(searchText)
+		embedding, tag, fallback, err := idx.generateEmbeddingWithBackoff(job.text)
+		if err == nil && fallback {
+			// Never store a fallback provider's vector; the query is embedded on a later run
+			err = fmt.Errorf("served by fallback provider %s", tag.Provider)
+		}
+		if err == nil {
+			err = idx.installEmbedding(job.query, embedding, tag)
+		}
+		if errors.Is(err, errIndexReloaded) {
+			return fmt.Errorf("embedding generation stopped with %d/%d queries embedded: %w", successCount, total, err)
+		}
 		if err != nil {
-			idx.logger.Debug("Failed to generate embedding for query %s: %v", query.Path, err)
+			idx.logger.Debug("Failed to generate embedding for query %s: %v", job.query.Path, err)
+			consecutiveFailures++
+			if consecutiveFailures >= maxConsecutiveEmbeddingFailures {
+				idx.mutex.Lock()
+				if saveErr := idx.saveEmbeddingsToCache(); saveErr != nil {
+					idx.logger.Error("Failed to save embeddings checkpoint: %v", saveErr)
+				}
+				idx.mutex.Unlock()
+				return fmt.Errorf("embedding generation stopped after %d consecutive failures with %d/%d queries embedded; progress was saved, re-run to resume: %w",
+					consecutiveFailures, successCount, total, err)
+			}
 			continue
 		}
-
-		// Convert []float64 to []float32
-		embedding32 := make([]float32, len(embedding))
-		for j, v := range embedding {
-			embedding32[j] = float32(v)
-		}
-
-		query.Embedding = embedding32
-		idx.embeddings[query.QueryID] = embedding32
+		consecutiveFailures = 0
 		successCount++
+		generated++
 
 		// Log progress every 50 queries (more frequent updates)
-		if (i+1)%50 == 0 {
-			idx.logger.Info("Generated embeddings for %d/%d queries (%.1f%%)", i+1, len(idx.queries), float64(i+1)/float64(len(idx.queries))*100)
+		if generated%50 == 0 {
+			idx.logger.Info("Generated embeddings for %d/%d queries (%.1f%%)", successCount, total, float64(successCount)/float64(total)*100)
 		}
 
-		// Save progress incrementally every 100 queries to avoid losing work
-		if successCount%100 == 0 {
+		// Checkpoint periodically so a failed run does not lose completed work
+		if generated%checkpointInterval == 0 {
 			idx.logger.Info("Saving incremental progress (%d embeddings)...", successCount)
-			if err := idx.saveEmbeddingsToCache(); err != nil {
+			idx.mutex.Lock()
+			err := idx.saveEmbeddingsToCache()
+			idx.mutex.Unlock()
+			if err != nil {
 				idx.logger.Error("Failed to save incremental cache: %v", err)
 			} else {
 				idx.logger.Info("Incremental cache saved successfully")
@@ -249,16 +479,138 @@ func (idx *NQEQueryIndex) GenerateEmbeddings() error {
 	}
 
 	idx.logger.Info("Successfully generated embeddings for %d queries", successCount)
+	if progress != nil {
+		progress(total, total)
+	}
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
 
 	// Save final embeddings to cache
 	if err := idx.saveEmbeddingsToCache(); err != nil {
 		idx.logger.Error("Failed to save embeddings cache: %v", err)
 		return err
 	}
+	if successCount == total {
+		if err := idx.recordSpecHash(); err != nil {
+			idx.logger.Debug("Embeddings freshness tracking unavailable: %v", err)
+		}
+	}
+
+	return idx.enforceEmbeddingCap()
+}
+
+// embeddingJob is a query still missing an embedding and the text to embed for it
+type embeddingJob struct {
+	query *NQEQueryIndexEntry
+	text  string
+}
+
+// beginEmbeddingRun checks that real embeddings can be generated, marks a run in progress,
+// and returns the queries without an embedding along with the number of queries
+func (idx *NQEQueryIndex) beginEmbeddingRun() ([]embeddingJob, int, error) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
 
+	// Check if we can actually generate embeddings
+	if !isRealEmbeddingProvider(idx.embeddingService) {
+		return nil, 0, fmt.Errorf("cannot generate real embeddings with mock service - set OPENAI_API_KEY")
+	}
+	if idx.generating {
+		return nil, 0, fmt.Errorf("embedding generation is already running")
+	}
+
+	// Embeddings from another provider live in another vector space, so they are all
+	// replaced rather than mixed with new ones
+	if primary := primaryEmbeddingProvider(idx.embeddingService); idx.embeddingTag.Provider != "" && idx.embeddingTag.Provider != primary {
+		idx.logger.Info("Replacing %s embeddings with embeddings from %s", idx.embeddingTag, primary)
+		for _, query := range idx.queries {
+			query.Embedding = nil
+		}
+		idx.embeddings = make(map[string][]float32)
+		idx.spilled = nil
+		idx.embeddingTag = EmbeddingTag{}
+	}
+
+	// Skip queries that already have an embedding (for resuming)
+	var pending []embeddingJob
+	for _, query := range idx.queries {
+		if len(query.Embedding) > 0 || idx.isSpilled(query) {
+			continue
+		}
+		pending = append(pending, embeddingJob{query: query, text: embeddingText(query)})
+	}
+	idx.generating = true
+	return pending, len(idx.queries), nil
+}
+
+// errIndexReloaded is returned when the queries were reloaded while their embeddings were
+// being generated
+var errIndexReloaded = errors.New("the query index was reloaded; re-run to embed the new queries")
+
+// installEmbedding stores a generated embedding on its query, unless it is in a different
+// vector space than the embeddings already in the index or the query was reloaded meanwhile
+func (idx *NQEQueryIndex) installEmbedding(query *NQEQueryIndexEntry, embedding []float64, tag EmbeddingTag) error {
+	// Convert []float64 to []float32
+	embedding32 := make([]float32, len(embedding))
+	for j, v := range embedding {
+		embedding32[j] = float32(v)
+	}
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	if !idx.containsEntry(query) {
+		return errIndexReloaded
+	}
+	if !tag.matches(idx.embeddingTag) {
+		return fmt.Errorf("%s embedding does not match the index's %s embeddings", tag, idx.embeddingTag)
+	}
+	if idx.embeddingTag.Provider == "" {
+		idx.embeddingTag = tag
+	}
+	query.Embedding = embedding32
+	idx.embeddings[query.QueryID] = embedding32
 	return nil
 }
 
+// containsEntry reports whether query is still one of the index's queries.
+// Callers must hold idx.mutex.
+func (idx *NQEQueryIndex) containsEntry(query *NQEQueryIndexEntry) bool {
+	for _, entry := range idx.queries {
+		if entry == query {
+			return true
+		}
+	}
+	return false
+}
+
+// generateEmbeddingWithBackoff generates one tagged embedding, waiting and retrying with
+// exponential backoff while the provider reports rate limiting
+func (idx *NQEQueryIndex) generateEmbeddingWithBackoff(text string) ([]float64, EmbeddingTag, bool, error) {
+	backoff := idx.rateLimitBackoff
+	if backoff <= 0 {
+		backoff = defaultEmbeddingRateLimitBackoff
+	}
+	sleep := idx.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	for attempt := 0; ; attempt++ {
+		embedding, tag, fallback, err := generateTaggedEmbedding(idx.embeddingService, text)
+		if err == nil {
+			return embedding, tag, fallback, validateEmbedding(embedding)
+		}
+		if !errors.Is(err, ErrEmbeddingRateLimited) || attempt == maxEmbeddingRateLimitRetries {
+			return embedding, tag, fallback, err
+		}
+		idx.logger.Warn("Embedding provider rate limited, retrying in %s", backoff)
+		sleep(backoff)
+		backoff *= 2
+	}
+}
+
 // calculateCosineSimilarity computes the cosine similarity between two vectors
 func calculateCosineSimilarity(a, b []float32) float64 {
 	if len(a) != len(b) {
@@ -289,10 +641,10 @@ func (idx *NQEQueryIndex) SearchQueries(searchText string, limit int) ([]*QueryS
 		return nil, fmt.Errorf("query index is empty - run LoadFromSpec() first")
 	}
 
-	// Count queries with embeddings
+	// Count queries with embeddings, resident or spilled to disk
 	embeddedCount := 0
 	for _, query := range idx.queries {
-		if len(query.Embedding) > 0 {
+		if len(query.Embedding) > 0 || idx.isSpilled(query) {
 			embeddedCount++
 		}
 	}
@@ -301,20 +653,30 @@ func (idx *NQEQueryIndex) SearchQueries(searchText string, limit int) ([]*QueryS
 	var searchEmbedding []float32
 
 	// Check if we should use keyword-based search directly
-	_, isMock := idx.embeddingService.(*MockEmbeddingService)
 	_, isKeyword := idx.embeddingService.(*KeywordEmbeddingService)
 
-	if isMock || isKeyword || embeddedCount == 0 {
+	if !isRealEmbeddingProvider(idx.embeddingService) || isKeyword || embeddedCount == 0 {
 		// Use keyword-based matching for better accuracy with these services
 		idx.logger.Debug("Using keyword-based search (service type: %T)", idx.embeddingService)
 		return idx.searchWithKeywords(searchText, limit)
 	}
 
 	// Try to generate embedding for search text
-	searchEmbedding64, err := idx.embeddingService.GenerateEmbedding(searchText)
+	searchEmbedding64, searchTag, _, err := generateTaggedEmbedding(idx.embeddingService, searchText)
+	if err == nil && !searchTag.matches(idx.embeddingTag) {
+		// A fallback provider's vector cannot be compared with the index's embeddings
+		err = fmt.Errorf("%s search embedding does not match the index's %s embeddings", searchTag, idx.embeddingTag)
+	}
 	if err != nil {
-		idx.logger.Debug("Failed to generate search embedding, falling back to keyword search: %v", err)
-		return idx.searchWithKeywords(searchText, limit)
+		idx.logger.Warn("Failed to generate search embedding, falling back to keyword search: %v", err)
+		results, err := idx.searchWithKeywords(searchText, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			result.MatchType = MatchTypeKeywordFallback
+		}
+		return results, nil
 	}
 
 	// Convert to float32
@@ -325,13 +687,28 @@ func (idx *NQEQueryIndex) SearchQueries(searchText string, limit int) ([]*QueryS
 
 	var results []*QuerySearchResult
 
+	// Spilled embeddings are read back from disk for this search only
+	var spilled map[string][]float32
+	if len(idx.spilled) > 0 {
+		if spilled, err = idx.readSpilledEmbeddings(); err != nil {
+			idx.logger.Warn("Searching resident embeddings only: %v", err)
+		}
+	}
+
 	// Calculate similarity scores using cached embeddings
 	for _, query := range idx.queries {
-		if len(query.Embedding) == 0 {
+		if !idx.policy.Allows(query.Path) {
+			continue
+		}
+		embedding := query.Embedding
+		if len(embedding) == 0 && idx.isSpilled(query) {
+			embedding = spilled[query.Path]
+		}
+		if len(embedding) != len(searchEmbedding) {
 			continue
 		}
 
-		similarity := calculateCosineSimilarity(searchEmbedding, query.Embedding)
+		similarity := calculateCosineSimilarity(searchEmbedding, embedding)
 
 		// Lower threshold to be more lenient (was 0.05)
 		if similarity > 0.01 {
@@ -368,16 +745,29 @@ func (idx *NQEQueryIndex) SearchQueries(searchText string, limit int) ([]*QueryS
 	if limit > 0 && len(results) > limit {
 		results = results[:limit]
 	}
+	idx.touchEmbeddings(results)
 
 	return results, nil
 }
 
-// searchWithKeywords provides keyword-based search as fallback when embeddings are not available
+// SearchByKeywords performs keyword-based search on the query index
+func (idx *NQEQueryIndex) SearchByKeywords(searchText string, limit int) ([]*QuerySearchResult, error) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	return idx.searchWithKeywords(searchText, limit)
+}
+
+// searchWithKeywords provides keyword-based search as fallback when embeddings are not available.
+// Callers must hold idx.mutex.
 func (idx *NQEQueryIndex) searchWithKeywords(searchText string, limit int) ([]*QuerySearchResult, error) {
 	searchTerms := strings.Fields(strings.ToLower(searchText))
 	var results []*QuerySearchResult
 
 	for _, query := range idx.queries {
+		if !idx.policy.Allows(query.Path) {
+			continue
+		}
 		score := idx.calculateKeywordScore(query, searchTerms)
 
 		if score > 0 {
@@ -522,7 +912,7 @@ func (idx *NQEQueryIndex) GetStatistics() map[string]interface{} {
 				subcategories[query.Category][query.Subcategory]++
 			}
 		}
-		if len(query.Embedding) > 0 {
+		if len(query.Embedding) > 0 || idx.isSpilled(query) {
 			embeddedCount++
 		}
 	}
@@ -541,9 +931,94 @@ func (idx *NQEQueryIndex) GetStatistics() map[string]interface{} {
 		"categories":         categories,
 		"subcategories":      subcategories,
 		"embedding_coverage": float64(embeddedCount) / float64(len(idx.queries)),
+		"spilled_embeddings": len(idx.spilled),
+		"embeddings_corrupt": idx.regenerateEmbeddings,
 	}
 }
 
+// NQECategorySummary describes one top-level category of the NQE library
+type NQECategorySummary struct {
+	Name          string         `json:"name"`
+	QueryCount    int            `json:"queryCount"`
+	Subcategories map[string]int `json:"subcategories,omitempty"`
+	ExamplePaths  []string       `json:"examplePaths"`
+}
+
+// GetCategorySummaries returns the categories in the index ordered by query count,
+// each with up to examplesPerCategory representative query paths. Examples are drawn
+// from distinct subcategories first so they show the breadth of the category.
+func (idx *NQEQueryIndex) GetCategorySummaries(examplesPerCategory int) []*NQECategorySummary {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	summaries := make(map[string]*NQECategorySummary)
+	var order []string
+	for _, query := range idx.queries {
+		if query.Category == "" || !idx.policy.Allows(query.Path) {
+			continue
+		}
+		summary, exists := summaries[query.Category]
+		if !exists {
+			summary = &NQECategorySummary{
+				Name:          query.Category,
+				Subcategories: make(map[string]int),
+			}
+			summaries[query.Category] = summary
+			order = append(order, query.Category)
+		}
+		summary.QueryCount++
+		if query.Subcategory != "" {
+			summary.Subcategories[query.Subcategory]++
+		}
+	}
+
+	// Pick examples: first one query per unseen subcategory, then fill with anything left
+	seenSubcategories := make(map[string]map[string]bool)
+	for _, pass := range []bool{true, false} {
+		for _, query := range idx.queries {
+			summary, exists := summaries[query.Category]
+			if !exists || len(summary.ExamplePaths) >= examplesPerCategory || !idx.policy.Allows(query.Path) {
+				continue
+			}
+			if seenSubcategories[query.Category] == nil {
+				seenSubcategories[query.Category] = make(map[string]bool)
+			}
+			if pass {
+				if seenSubcategories[query.Category][query.Subcategory] {
+					continue
+				}
+				seenSubcategories[query.Category][query.Subcategory] = true
+			} else if containsString(summary.ExamplePaths, query.Path) {
+				continue
+			}
+			summary.ExamplePaths = append(summary.ExamplePaths, query.Path)
+		}
+	}
+
+	result := make([]*NQECategorySummary, 0, len(order))
+	for _, name := range order {
+		result = append(result, summaries[name])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].QueryCount != result[j].QueryCount {
+			return result[i].QueryCount > result[j].QueryCount
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// containsString reports whether values contains target
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
 // SaveIndex saves the query index to a JSON file for faster loading
 func (idx *NQEQueryIndex) SaveIndex(filename string) error {
 	idx.mutex.RLock()
@@ -554,7 +1029,7 @@ func (idx *NQEQueryIndex) SaveIndex(filename string) error {
 		return fmt.Errorf("failed to marshal index: %w", err)
 	}
 
-	if err := os.WriteFile(filename, data, 0644); err != nil {
+	if err := writeFileAtomic(filename, data, 0644); err != nil {
 		return fmt.Errorf("failed to write index file: %w", err)
 	}
 
@@ -717,9 +1192,26 @@ func (idx *NQEQueryIndex) extractKeyTerms(searchTerms []string) []string {
 	return keyTerms
 }
 
-// Queries returns the list of NQE queries in the index (read-only)
+// Queries returns the list of NQE queries in the index that the directory policy
+// allows (read-only)
 func (idx *NQEQueryIndex) Queries() []*NQEQueryIndexEntry {
 	idx.mutex.RLock()
 	defer idx.mutex.RUnlock()
-	return idx.queries
+	if idx.policy == nil {
+		return idx.queries
+	}
+	queries := make([]*NQEQueryIndexEntry, 0, len(idx.queries))
+	for _, query := range idx.queries {
+		if idx.policy.Allows(query.Path) {
+			queries = append(queries, query)
+		}
+	}
+	return queries
+}
+
+// SetDirectoryPolicy hides queries the policy does not allow from search and listing
+func (idx *NQEQueryIndex) SetDirectoryPolicy(policy *NQEDirectoryPolicy) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	idx.policy = policy
 }
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/logger"
+)
+
+// scriptedEmbeddingService embeds text with the keyword service, after first returning
+// the scripted errors in order (nil entries succeed)
+type scriptedEmbeddingService struct {
+	keyword *KeywordEmbeddingService
+	errors  []error
+	calls   []string
+}
+
+func (s *scriptedEmbeddingService) GenerateEmbedding(text string) ([]float64, error) {
+	s.calls = append(s.calls, text)
+	if len(s.errors) > 0 {
+		err := s.errors[0]
+		s.errors = s.errors[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.keyword.GenerateEmbedding(text)
+}
+
+// newCheckpointTestIndex builds an index of n queries that checkpoints to cachePath
+func newCheckpointTestIndex(service EmbeddingService, cachePath string, n int) *NQEQueryIndex {
+	idx := NewNQEQueryIndex(service, logger.New())
+	idx.embeddingsCachePath = cachePath
+	idx.checkpointInterval = 2
+	idx.sleep = func(time.Duration) {}
+	for i := 0; i < n; i++ {
+		idx.queries = append(idx.queries, &NQEQueryIndexEntry{
+			QueryID: fmt.Sprintf("FQ_%d", i),
+			Path:    fmt.Sprintf("/Test/Query %d", i),
+			Intent:  fmt.Sprintf("Query %d", i),
+		})
+	}
+	return idx
+}
+
+func TestGenerateEmbeddingsResumesAfterMidRunFailure(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "nqe-embeddings.json")
+
+	// First run: 4 queries succeed, then the provider goes down
+	outage := make([]error, 4, 4+maxConsecutiveEmbeddingFailures)
+	for i := 0; i < maxConsecutiveEmbeddingFailures; i++ {
+		outage = append(outage, fmt.Errorf("connection reset"))
+	}
+	first := &scriptedEmbeddingService{keyword: NewKeywordEmbeddingService(), errors: outage}
+	if err := newCheckpointTestIndex(first, cachePath, 10).GenerateEmbeddings(); err == nil {
+		t.Fatal("Expected the first run to stop after repeated failures")
+	}
+
+	// Second run: a fresh index loads the checkpoint and embeds only the remainder
+	second := &scriptedEmbeddingService{keyword: NewKeywordEmbeddingService()}
+	idx := newCheckpointTestIndex(second, cachePath, 10)
+	if err := idx.loadEmbeddingsFromCache(); err != nil {
+		t.Fatalf("Expected checkpoint to be saved, got: %v", err)
+	}
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("Expected resumed run to succeed, got: %v", err)
+	}
+
+	if len(second.calls) != 6 {
+		t.Fatalf("Expected 6 remaining queries to be embedded, got %d calls", len(second.calls))
+	}
+	if !contains(second.calls[0], "Query 4") {
+		t.Errorf("Expected resume to start at query 4, first call was %q", second.calls[0])
+	}
+	for _, query := range idx.queries {
+		if len(query.Embedding) == 0 {
+			t.Errorf("Expected %s to have an embedding after resuming", query.QueryID)
+		}
+	}
+}
+
+func TestGenerateEmbeddingsBacksOffWhenRateLimited(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "nqe-embeddings.json")
+	rateLimited := fmt.Errorf("%w: 429 Too Many Requests", ErrEmbeddingRateLimited)
+	service := &scriptedEmbeddingService{keyword: NewKeywordEmbeddingService(), errors: []error{rateLimited, rateLimited}}
+
+	idx := newCheckpointTestIndex(service, cachePath, 3)
+	var waits []time.Duration
+	idx.rateLimitBackoff = time.Second
+	idx.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("Expected generation to continue after rate limiting, got: %v", err)
+	}
+	if len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Errorf("Expected exponential backoff of 1s then 2s, got %v", waits)
+	}
+	for _, query := range idx.queries {
+		if len(query.Embedding) == 0 {
+			t.Errorf("Expected %s to be embedded", query.QueryID)
+		}
+	}
+}
+
+func TestIndexStaysReadableWhileEmbeddingsGenerate(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "nqe-embeddings.json")
+	rateLimited := fmt.Errorf("%w: 429 Too Many Requests", ErrEmbeddingRateLimited)
+	service := &scriptedEmbeddingService{keyword: NewKeywordEmbeddingService(), errors: []error{rateLimited}}
+
+	idx := newCheckpointTestIndex(service, cachePath, 3)
+	backingOff := make(chan struct{})
+	resume := make(chan struct{})
+	idx.sleep = func(time.Duration) {
+		close(backingOff)
+		<-resume
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- idx.GenerateEmbeddings() }()
+
+	<-backingOff
+	read := make(chan error, 1)
+	go func() {
+		_, err := idx.GetQueryByID("FQ_1")
+		read <- err
+	}()
+	select {
+	case err := <-read:
+		if err != nil {
+			t.Errorf("GetQueryByID failed during generation: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected lookups not to wait for the rate limit backoff")
+	}
+
+	close(resume)
+	if err := <-done; err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+	if stats := idx.GetStatistics(); stats["embedded_queries"] != 3 {
+		t.Errorf("Expected all 3 queries embedded, got %v", stats["embedded_queries"])
+	}
+}
+
+func TestSearchFindsSpilledEmbeddings(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "nqe-embeddings.json")
+	service := &scriptedEmbeddingService{keyword: NewKeywordEmbeddingService()}
+	idx := newCheckpointTestIndex(service, cachePath, 0)
+	for i, path := range []string{"/L3/BGP/BGP Neighbor State", "/L3/OSPF/OSPF Adjacencies", "/Security/ACL/Permit Any Rules"} {
+		query := &NQEQueryIndexEntry{QueryID: fmt.Sprintf("FQ_%d", i), Path: path}
+		applyPathMetadata(query)
+		idx.queries = append(idx.queries, query)
+	}
+	idx.maxResidentEmbeddings = 1
+
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("Expected embeddings to be generated, got: %v", err)
+	}
+	bgp := idx.queries[0]
+	if len(bgp.Embedding) != 0 || !idx.isSpilled(bgp) {
+		t.Fatal("Expected the BGP query's embedding to be spilled to disk")
+	}
+	if stats := idx.GetStatistics(); stats["embedded_queries"] != 3 || stats["spilled_embeddings"] != 2 {
+		t.Errorf("Expected 3 embedded queries with 2 spilled, got %v and %v", stats["embedded_queries"], stats["spilled_embeddings"])
+	}
+
+	results, err := idx.SearchQueries("BGP neighbor state", 1)
+	if err != nil {
+		t.Fatalf("Expected search to succeed, got: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != bgp.Path {
+		t.Fatalf("Expected the spilled BGP query to be found, got %v", results)
+	}
+
+	// The hit is now the most recently used, so reloading keeps it resident
+	idx.mutex.Lock()
+	idx.spilled = nil
+	err = idx.loadEmbeddingsFromCache()
+	if err == nil {
+		err = idx.enforceEmbeddingCap()
+	}
+	idx.mutex.Unlock()
+	if err != nil {
+		t.Fatalf("Expected embeddings to reload under the cap, got: %v", err)
+	}
+	if len(bgp.Embedding) == 0 || len(idx.queries[1].Embedding) != 0 || len(idx.queries[2].Embedding) != 0 {
+		t.Error("Expected only the recently searched query to stay resident")
+	}
+}
+
+func TestLoadEmbeddingsDiscardsZeroVectors(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "nqe-embeddings.json")
+	cache := `{"/Test/Query 0": [0.6, 0.8], "/Test/Query 1": [0, 0]}`
+	if err := os.WriteFile(cachePath, []byte(cache), 0644); err != nil {
+		t.Fatalf("Failed to write embeddings cache: %v", err)
+	}
+
+	idx := newCheckpointTestIndex(NewKeywordEmbeddingService(), cachePath, 2)
+	if err := idx.loadEmbeddingsFromCache(); err != nil {
+		t.Fatalf("Expected cache to load, got: %v", err)
+	}
+	if _, ok := idx.embeddings["FQ_0"]; !ok {
+		t.Error("Expected the valid embedding to be loaded")
+	}
+	if _, ok := idx.embeddings["FQ_1"]; ok || len(idx.queries[1].Embedding) > 0 {
+		t.Error("Expected the zero-norm embedding to be discarded")
+	}
+}
+
+func TestLoadFromSpecToleratesCorruptEmbeddingsCache(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "NQELibrary.json")
+	writeTestSpec(t, specPath, map[string]string{
+		"FQ_bgp":  "/L3/BGP/BGP Neighbor State",
+		"FQ_ospf": "/L3/OSPF/OSPF Adjacencies",
+	})
+	cachePath := filepath.Join(dir, "nqe-embeddings.json")
+	if err := os.WriteFile(cachePath, []byte(`{"/L3/BGP/BGP Neighbor State": [0.6, 0.`), 0644); err != nil {
+		t.Fatalf("Failed to write embeddings cache: %v", err)
+	}
+
+	idx := NewNQEQueryIndex(NewKeywordEmbeddingService(), logger.New())
+	idx.indexPath = specPath
+	idx.embeddingsCachePath = cachePath
+	if err := idx.LoadFromSpec(); err != nil {
+		t.Fatalf("Expected the index to load despite the corrupt cache, got: %v", err)
+	}
+
+	results, err := idx.SearchQueries("bgp neighbor", 5)
+	if err != nil || len(results) == 0 || results[0].QueryID != "FQ_bgp" {
+		t.Fatalf("Expected keyword search to find the BGP query, got %v (err: %v)", results, err)
+	}
+
+	stats := idx.GetStatistics()
+	if stats["embedded_queries"].(int) != 0 || stats["embeddings_corrupt"] != true {
+		t.Errorf("Expected no embeddings and the cache marked corrupt, got %v", stats)
+	}
+	if _, err := os.Stat(cachePath + ".corrupt"); err != nil {
+		t.Errorf("Expected the corrupt cache to be set aside: %v", err)
+	}
+
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("Failed to regenerate embeddings: %v", err)
+	}
+	if idx.GetStatistics()["embeddings_corrupt"] != false {
+		t.Error("Expected regeneration to clear the corrupt marker")
+	}
+}
+
+func TestSetSpecDirUsedForSpecAndEmbeddingsCache(t *testing.T) {
+	workDir := t.TempDir()
+	specDir := filepath.Join(workDir, "custom-spec")
+	if err := os.Mkdir(specDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestSpec(t, filepath.Join(specDir, "NQELibrary.json"), map[string]string{
+		"FQ_bgp":  "/L3/BGP/BGP Neighbor State",
+		"FQ_ospf": "/L3/OSPF/OSPF Adjacencies",
+	})
+	t.Chdir(workDir)
+
+	idx := NewNQEQueryIndex(NewKeywordEmbeddingService(), logger.New())
+	if err := idx.SetSpecDir("custom-spec"); err != nil {
+		t.Fatalf("SetSpecDir failed: %v", err)
+	}
+	if !filepath.IsAbs(idx.indexPath) || !filepath.IsAbs(idx.embeddingsCachePath) {
+		t.Errorf("Expected absolute spec paths, got %s and %s", idx.indexPath, idx.embeddingsCachePath)
+	}
+	if err := idx.LoadFromSpec(); err != nil {
+		t.Fatalf("Expected the spec to load from the custom directory: %v", err)
+	}
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("GenerateEmbeddings failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(specDir, "nqe-embeddings.json")); err != nil {
+		t.Fatalf("Expected the embeddings cache in the custom directory: %v", err)
+	}
+
+	// A fresh index started elsewhere reads the cache back from the same directory
+	t.Chdir(t.TempDir())
+	reloaded := NewNQEQueryIndex(NewKeywordEmbeddingService(), logger.New())
+	if err := reloaded.SetSpecDir(specDir); err != nil {
+		t.Fatalf("SetSpecDir failed: %v", err)
+	}
+	if err := reloaded.LoadFromSpec(); err != nil {
+		t.Fatalf("Failed to reload from the custom directory: %v", err)
+	}
+	if embedded := reloaded.GetStatistics()["embedded_queries"].(int); embedded != 2 {
+		t.Errorf("Expected 2 embeddings read from the custom cache, got %d", embedded)
+	}
+}
+
+func TestSetSpecDirDoesNotSearchDefaultLocations(t *testing.T) {
+	idx := NewNQEQueryIndex(NewKeywordEmbeddingService(), logger.New())
+	if err := idx.SetSpecDir(t.TempDir()); err != nil {
+		t.Fatalf("SetSpecDir failed: %v", err)
+	}
+	if err := idx.LoadFromSpec(); err == nil {
+		t.Error("Expected a missing spec file in the configured directory to fail the load")
+	}
+}
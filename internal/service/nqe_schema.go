@@ -0,0 +1,74 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// NQEColumnSchema describes one column of an NQE result
+type NQEColumnSchema struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // string, number, bool, object, array, or unknown when always null
+	Nullable bool   `json:"nullable"`
+}
+
+// InferNQESchema derives column names and types from NQE result items. Each column's
+// type comes from its first non-null value; a column is nullable when any item has a
+// null value for it or omits it entirely.
+func InferNQESchema(items []map[string]interface{}) []NQEColumnSchema {
+	columns := nqeResultColumns(items)
+	schema := make([]NQEColumnSchema, 0, len(columns))
+
+	for _, column := range columns {
+		columnSchema := NQEColumnSchema{Name: column, Type: "unknown"}
+		typed := false
+		for _, item := range items {
+			value, present := item[column]
+			if !present || value == nil {
+				columnSchema.Nullable = true
+				continue
+			}
+			if !typed {
+				columnSchema.Type = nqeValueType(value)
+				typed = true
+			}
+		}
+		schema = append(schema, columnSchema)
+	}
+	return schema
+}
+
+// nqeValueType maps a decoded JSON value to a simple type name
+func nqeValueType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64, float32, int, int32, int64, json.Number:
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// formatNQESchema renders the inferred schema as a compact block for tool responses
+func formatNQESchema(schema []NQEColumnSchema) string {
+	if len(schema) == 0 {
+		return ""
+	}
+
+	block := fmt.Sprintf("Result schema (%d columns):\n", len(schema))
+	for _, column := range schema {
+		block += fmt.Sprintf("• %s: %s", column.Name, column.Type)
+		if column.Nullable {
+			block += " (nullable)"
+		}
+		block += "\n"
+	}
+	return block + "\n"
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestInferNQESchema(t *testing.T) {
+	items := []map[string]interface{}{
+		{"name": "router-1", "cpu": 42.5, "up": true, "tags": []interface{}{"core"}, "site": nil, "meta": map[string]interface{}{"rack": "A1"}},
+		{"name": "switch-1", "cpu": 10.0, "up": false, "tags": []interface{}{}, "site": "dc-1", "meta": map[string]interface{}{}},
+		{"name": "fw-1", "up": true, "tags": []interface{}{}, "site": "dc-2", "meta": nil},
+	}
+
+	expected := map[string]NQEColumnSchema{
+		"name": {Name: "name", Type: "string", Nullable: false},
+		"cpu":  {Name: "cpu", Type: "number", Nullable: true},
+		"up":   {Name: "up", Type: "bool", Nullable: false},
+		"tags": {Name: "tags", Type: "array", Nullable: false},
+		"site": {Name: "site", Type: "string", Nullable: true},
+		"meta": {Name: "meta", Type: "object", Nullable: true},
+	}
+
+	schema := InferNQESchema(items)
+	if len(schema) != len(expected) {
+		t.Fatalf("Expected %d columns, got %d: %+v", len(expected), len(schema), schema)
+	}
+	for _, column := range schema {
+		if want := expected[column.Name]; column != want {
+			t.Errorf("Column %s: expected %+v, got %+v", column.Name, want, column)
+		}
+	}
+}
+
+func TestRunNQEQueryIncludesSchema(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).nqeResult = &forward.NQERunResult{
+		Items: []map[string]interface{}{
+			{"name": "router-1", "site": nil},
+			{"name": "switch-1", "site": "dc-1"},
+		},
+	}
+	args := RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_test"}
+
+	response, err := service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	content := response.Content[0].TextContent.Text
+	if !contains(content, "Result schema (2 columns)") || !contains(content, "• site: string (nullable)") {
+		t.Errorf("Expected schema block in NQE response, got: %s", content)
+	}
+
+	hide := false
+	if _, err := service.setDefaultSettings(SetDefaultSettingsArgs{IncludeNQESchema: &hide}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	response, err = service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if content := response.Content[0].TextContent.Text; contains(content, "Result schema") {
+		t.Errorf("Expected schema block to be hidden, got: %s", content)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import "fmt"
+
+// maxCategoryHints bounds how many categories an empty search suggests
+const maxCategoryHints = 5
+
+// nqeExampleSearches are broad phrases that match well-populated parts of the library
+var nqeExampleSearches = []string{
+	"device inventory",
+	"interface errors",
+	"bgp neighbors",
+	"security vulnerabilities",
+	"cloud",
+}
+
+// CategoryHint is a library category and how many queries it holds
+type CategoryHint struct {
+	Category string `json:"category"`
+	Queries  int    `json:"queries"`
+}
+
+// NQESearchGuidance is the next-step guidance returned when search_nqe_queries finds nothing
+type NQESearchGuidance struct {
+	Query           string         `json:"query"`
+	Filters         []string       `json:"filters,omitempty"`
+	TopCategories   []CategoryHint `json:"top_categories"`
+	ExampleSearches []string       `json:"example_searches"`
+	NextSteps       []string       `json:"next_steps"`
+}
+
+// nqeSearchGuidance builds the guidance for a search that matched nothing, from the
+// index's query counts per category
+func nqeSearchGuidance(query, category, subcategory string, categories map[string]int) NQESearchGuidance {
+	guidance := NQESearchGuidance{Query: query, ExampleSearches: nqeExampleSearches}
+	if category != "" {
+		guidance.Filters = append(guidance.Filters, "category="+category)
+	}
+	if subcategory != "" {
+		guidance.Filters = append(guidance.Filters, "subcategory="+subcategory)
+	}
+
+	for _, name := range keysByCount(categories) {
+		if name == "" || len(guidance.TopCategories) == maxCategoryHints {
+			continue
+		}
+		guidance.TopCategories = append(guidance.TopCategories, CategoryHint{Category: name, Queries: categories[name]})
+	}
+
+	if len(guidance.Filters) > 0 {
+		guidance.NextSteps = append(guidance.NextSteps, "Remove the category/subcategory filters or pick one of top_categories")
+	}
+	guidance.NextSteps = append(guidance.NextSteps,
+		"Use broader or related terms (e.g. 'routing' for 'BGP'), or try one of example_searches",
+		"Run get_query_index_stats to see every category and subcategory",
+		"Try find_executable_query or browse with list_nqe_queries",
+	)
+	return guidance
+}
+
+// formatNQESearchGuidance renders the guidance for an empty search result
+func (s *ForwardMCPService) formatNQESearchGuidance(guidance NQESearchGuidance) string {
+	return fmt.Sprintf("No matches found for: '%s'. Guidance for a next search:\n%s", guidance.Query, s.toJSON(guidance, nil))
+}
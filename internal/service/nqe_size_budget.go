@@ -0,0 +1,139 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// maxRowSizeSamples bounds how many recent runs' row sizes are kept per network and query
+const maxRowSizeSamples = 10
+
+// NQEResultSizeTracker remembers the average serialized row size of recent runs per network
+// and NQE query, so the size of a response can be predicted from its row limit. A nil
+// tracker records nothing.
+type NQEResultSizeTracker struct {
+	mutex   sync.RWMutex
+	samples map[string][]int
+}
+
+// NewNQEResultSizeTracker creates an empty result size tracker
+func NewNQEResultSizeTracker() *NQEResultSizeTracker {
+	return &NQEResultSizeTracker{samples: make(map[string][]int)}
+}
+
+// resultSizeKey identifies a query's results on one network
+func resultSizeKey(networkID, queryID string) string {
+	return networkID + "/" + queryID
+}
+
+// Record stores the average row size of one run; runs without rows are ignored
+func (t *NQEResultSizeTracker) Record(networkID, queryID string, items []map[string]interface{}) {
+	if t == nil || queryID == "" || len(items) == 0 {
+		return
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := resultSizeKey(networkID, queryID)
+	samples := append(t.samples[key], max(len(data)/len(items), 1))
+	if len(samples) > maxRowSizeSamples {
+		samples = samples[len(samples)-maxRowSizeSamples:]
+	}
+	t.samples[key] = samples
+}
+
+// AvgRowBytes returns the average row size over a query's recorded runs on a network, and
+// how many runs it is based on
+func (t *NQEResultSizeTracker) AvgRowBytes(networkID, queryID string) (int, int) {
+	if t == nil {
+		return 0, 0
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	samples := t.samples[resultSizeKey(networkID, queryID)]
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	total := 0
+	for _, size := range samples {
+		total += size
+	}
+	return total / len(samples), len(samples)
+}
+
+// NQESizeAdvice is the predicted response size for a row limit and, when it exceeds the
+// budget, the largest limit that fits
+type NQESizeAdvice struct {
+	RequestedLimit int `json:"requested_limit"`
+	SuggestedLimit int `json:"suggested_limit"`
+	AvgRowBytes    int `json:"avg_row_bytes"`
+	PredictedBytes int `json:"predicted_bytes"`
+	BudgetBytes    int `json:"budget_bytes"`
+}
+
+// AdviseNQELimit predicts the response size of limit rows of avgRowBytes each and reports
+// whether it exceeds budget. The suggested limit is at least one row.
+func AdviseNQELimit(limit, avgRowBytes, budget int) (NQESizeAdvice, bool) {
+	advice := NQESizeAdvice{
+		RequestedLimit: limit,
+		SuggestedLimit: limit,
+		AvgRowBytes:    avgRowBytes,
+		PredictedBytes: limit * avgRowBytes,
+		BudgetBytes:    budget,
+	}
+	if budget <= 0 || avgRowBytes <= 0 || advice.PredictedBytes <= budget {
+		return advice, false
+	}
+	advice.SuggestedLimit = max(budget/avgRowBytes, 1)
+	return advice, true
+}
+
+// nqeSizeAdvice checks the row limit of an NQE call against the response size budget using
+// the sizes of earlier runs. When the limit is predicted to overflow the budget it returns
+// a note suggesting a smaller limit; with auto-limiting on, a call that did not set a limit
+// explicitly is run with the smaller limit instead.
+func (s *ForwardMCPService) nqeSizeAdvice(args RunNQEQueryByIDArgs) (RunNQEQueryByIDArgs, string, error) {
+	if s.defaults == nil || s.defaults.NQEResponseBudgetBytes <= 0 {
+		return args, "", nil
+	}
+	avgRowBytes, runs := s.active().resultSizes.AvgRowBytes(s.getNetworkID(args.NetworkID), args.QueryID)
+	if runs == 0 {
+		return args, "", nil
+	}
+
+	requested := 0
+	if args.Options != nil {
+		requested = args.Options.Limit
+	}
+	limit, err := s.nqeRowLimit(requested)
+	if err != nil {
+		return args, "", err
+	}
+	advice, oversized := AdviseNQELimit(limit, avgRowBytes, s.defaults.NQEResponseBudgetBytes)
+	if !oversized {
+		return args, "", nil
+	}
+
+	reason := fmt.Sprintf("%d rows at about %s per row (average of the last %d runs) is about %s, over the %s response budget",
+		advice.RequestedLimit, formatBytes(float64(advice.AvgRowBytes)), runs, formatBytes(float64(advice.PredictedBytes)), formatBytes(float64(advice.BudgetBytes)))
+	if !s.defaults.NQEAutoLimit || requested != 0 {
+		return args, fmt.Sprintf("⚠️ Large response expected: %s. Consider limit %d with offset paging, or columns / post_filters to trim rows.\n\n", reason, advice.SuggestedLimit), nil
+	}
+
+	options := NQEQueryOptions{}
+	if args.Options != nil {
+		options = *args.Options
+	}
+	options.Limit = advice.SuggestedLimit
+	args.Options = &options
+	s.logger.Info("Auto-limited NQE query %s to %d rows: %s", args.QueryID, advice.SuggestedLimit, reason)
+	return args, fmt.Sprintf("Auto-limited to %d rows: %s. Page on with offset %d, or pass an explicit limit to override.\n\n", advice.SuggestedLimit, reason, options.Offset+advice.SuggestedLimit), nil
+}
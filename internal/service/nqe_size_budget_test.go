@@ -0,0 +1,88 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// wideRows returns n rows of roughly 1 KiB each
+func wideRows(n int) []map[string]interface{} {
+	rows := make([]map[string]interface{}, n)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"name": "router", "config": strings.Repeat("x", 1000)}
+	}
+	return rows
+}
+
+func TestAdviseNQELimit(t *testing.T) {
+	advice, oversized := AdviseNQELimit(1000, 1024, 100*1024)
+	if !oversized || advice.SuggestedLimit != 100 || advice.PredictedBytes != 1000*1024 {
+		t.Errorf("Expected a 100-row suggestion, got %+v (oversized %v)", advice, oversized)
+	}
+	if _, oversized := AdviseNQELimit(50, 1024, 100*1024); oversized {
+		t.Error("Expected a limit within the budget to pass")
+	}
+	if advice, _ := AdviseNQELimit(10, 1<<20, 1024); advice.SuggestedLimit != 1 {
+		t.Errorf("Expected at least one row suggested, got %d", advice.SuggestedLimit)
+	}
+}
+
+func TestRunNQEQuerySuggestsSmallerLimitForLargeRows(t *testing.T) {
+	service := createTestService()
+	service.active().resultSizes = NewNQEResultSizeTracker()
+	service.defaults.NQEResponseBudgetBytes = 50 * 1024
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	client.nqeResult = &forward.NQERunResult{SnapshotID: "snap-1", Items: wideRows(5)}
+	service.active().client = client
+
+	// The first run has no size history, so nothing is predicted
+	args := RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_configs", NoCache: true}
+	response, err := service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("runNQEQueryByID failed: %v", err)
+	}
+	if strings.Contains(response.Content[0].TextContent.Text, "Large response expected") {
+		t.Error("Expected no suggestion without size history")
+	}
+
+	response, err = service.runNQEQueryByID(args)
+	if err != nil {
+		t.Fatalf("runNQEQueryByID failed: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "Large response expected: 100 rows") || !strings.Contains(text, "Consider limit 49 ") {
+		t.Errorf("Expected a down-sizing suggestion for the default 100-row limit, got:\n%s", text)
+	}
+
+	// The history is per network
+	args.NetworkID = "other"
+	if response, err = service.runNQEQueryByID(args); err != nil || strings.Contains(response.Content[0].TextContent.Text, "Large response expected") {
+		t.Errorf("Expected no suggestion on a network without history (err: %v)", err)
+	}
+}
+
+func TestRunNQEQueryAutoLimitsWhenConfigured(t *testing.T) {
+	service := createTestService()
+	service.active().resultSizes = NewNQEResultSizeTracker()
+	service.active().resultSizes.Record("162112", "FQ_configs", wideRows(5))
+	service.defaults.NQEResponseBudgetBytes = 50 * 1024
+	service.defaults.NQEAutoLimit = true
+	client := &recordingNQEClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+
+	response, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_configs", NoCache: true})
+	if err != nil {
+		t.Fatalf("runNQEQueryByID failed: %v", err)
+	}
+	if client.limits()[0] != 49 || !strings.Contains(response.Content[0].TextContent.Text, "Auto-limited to 49 rows") {
+		t.Errorf("Expected the query run with limit 49, got %v:\n%s", client.limits(), response.Content[0].TextContent.Text)
+	}
+
+	// An explicit limit is kept and only gets a suggestion
+	_, err = service.runNQEQueryByID(RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_configs", NoCache: true, Options: &NQEQueryOptions{Limit: 80}})
+	if err != nil || client.limits()[1] != 80 {
+		t.Errorf("Expected the explicit limit to be kept, got %v (err: %v)", client.limits(), err)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// nqeDisplaySuffix names the column holding a normalized value next to its raw column
+const nqeDisplaySuffix = "_display"
+
+// columnWords splits a snake_case or camelCase column name into lowercase words
+func columnWords(column string) []string {
+	var words []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, strings.ToLower(current.String()))
+			current.Reset()
+		}
+	}
+	for i, r := range column {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+		case unicode.IsUpper(r) && i > 0:
+			flush()
+			current.WriteRune(r)
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return words
+}
+
+// nqeUnitFormatter returns how to render a column's numeric values, recognized from the
+// last word of its name, or nil when the column has no known unit
+func nqeUnitFormatter(column string) func(float64) string {
+	words := columnWords(column)
+	if len(words) == 0 {
+		return nil
+	}
+	isTimestamp := false
+	for _, word := range words[:len(words)-1] {
+		if word == "date" || word == "time" || word == "timestamp" {
+			isTimestamp = true
+		}
+	}
+
+	switch words[len(words)-1] {
+	case "bytes":
+		return formatBytes
+	case "percent", "pct":
+		return func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) + "%" }
+	case "fraction", "ratio":
+		return func(v float64) string { return strconv.FormatFloat(v*100, 'f', 1, 64) + "%" }
+	case "millis", "ms":
+		if isTimestamp {
+			return func(v float64) string { return time.UnixMilli(int64(v)).UTC().Format(time.RFC3339) }
+		}
+		return func(v float64) string { return (time.Duration(v) * time.Millisecond).String() }
+	case "seconds", "secs":
+		return func(v float64) string { return (time.Duration(v * float64(time.Second))).String() }
+	}
+	return nil
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. 1536 as "1.5 KiB"
+func formatBytes(v float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	unit := 0
+	for v >= 1024 && unit < len(units)-1 {
+		v /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", v, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", v, units[unit])
+}
+
+// nqeNumber reads a numeric NQE value, including numbers encoded as strings
+func nqeNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
+// NormalizeNQEUnits annotates columns whose names carry a unit (e.g. *_bytes, *_percent,
+// creationDateMillis) with a human-friendly <column>_display value, leaving the raw value
+// in place. It returns annotated copies of the rows and the columns in order, with each
+// display column following its source; rows are returned unchanged when nothing matched.
+func NormalizeNQEUnits(items []map[string]interface{}, columns []string) ([]map[string]interface{}, []string) {
+	formatters := make(map[string]func(float64) string)
+	for _, column := range columns {
+		if formatter := nqeUnitFormatter(column); formatter != nil {
+			formatters[column] = formatter
+		}
+	}
+	if len(formatters) == 0 {
+		return items, columns
+	}
+
+	normalized := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		row := make(map[string]interface{}, len(item)+len(formatters))
+		for column, value := range item {
+			row[column] = value
+			if formatter, ok := formatters[column]; ok {
+				if number, ok := nqeNumber(value); ok {
+					row[column+nqeDisplaySuffix] = formatter(number)
+				}
+			}
+		}
+		normalized[i] = row
+	}
+
+	withDisplay := make([]string, 0, len(columns)+len(formatters))
+	for _, column := range columns {
+		withDisplay = append(withDisplay, column)
+		if _, ok := formatters[column]; ok {
+			withDisplay = append(withDisplay, column+nqeDisplaySuffix)
+		}
+	}
+	return normalized, withDisplay
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestNormalizeNQEUnits(t *testing.T) {
+	items := []map[string]interface{}{
+		{"device": "router-1", "creationDateMillis": float64(1767225600000), "cpu_percent": 42.5, "memory_bytes": "1610612736", "uptimeMillis": float64(90000)},
+	}
+	columns := nqeResultColumns(items)
+
+	normalized, withDisplay := NormalizeNQEUnits(items, columns)
+	expected := map[string]string{
+		"creationDateMillis_display": "2026-01-01T00:00:00Z",
+		"cpu_percent_display":        "42.5%",
+		"memory_bytes_display":       "1.5 GiB",
+		"uptimeMillis_display":       "1m30s",
+	}
+	for column, value := range expected {
+		if normalized[0][column] != value {
+			t.Errorf("Expected %s = %q, got %v", column, value, normalized[0][column])
+		}
+	}
+	if normalized[0]["creationDateMillis"] != float64(1767225600000) || normalized[0]["cpu_percent"] != 42.5 {
+		t.Errorf("Expected raw values to be kept, got %v", normalized[0])
+	}
+	if _, ok := items[0]["cpu_percent_display"]; ok {
+		t.Error("Expected the original rows to be left unmodified")
+	}
+	if len(withDisplay) != len(columns)+4 || withDisplay[1] != "cpu_percent_display" {
+		t.Errorf("Expected display columns after their sources, got %v", withDisplay)
+	}
+	if _, ok := normalized[0]["device_display"]; ok {
+		t.Error("Expected columns without a unit to be left alone")
+	}
+}
+
+func TestNQEResultUnitNormalizationSetting(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).nqeResult = &forward.NQERunResult{
+		Items: []map[string]interface{}{{"device": "router-1", "cpu_percent": 87.0}},
+	}
+
+	run := func() string {
+		response, err := service.runNQEQueryByID(RunNQEQueryByIDArgs{NetworkID: "162112", QueryID: "FQ_cpu", Columns: []string{"device", "cpu_percent"}})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return response.Content[0].TextContent.Text
+	}
+
+	if text := run(); strings.Contains(text, "cpu_percent_display") {
+		t.Errorf("Expected no normalization by default, got:\n%s", text)
+	}
+
+	normalize := true
+	if _, err := service.setDefaultSettings(SetDefaultSettingsArgs{NormalizeUnits: &normalize}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := run(); !strings.Contains(text, `"cpu_percent_display": "87.0%"`) {
+		t.Errorf("Expected a normalized cpu_percent column, got:\n%s", text)
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// formatNQEValidation renders compile errors with the offending source line and a caret
+// under the reported column
+func formatNQEValidation(query string, result *forward.NQEValidationResult) string {
+	if result.Valid {
+		return "✅ NQE query is valid"
+	}
+
+	lines := strings.Split(query, "\n")
+	var b strings.Builder
+	fmt.Fprintf(&b, "❌ NQE query has %d error(s):\n", len(result.Errors))
+	for _, queryErr := range result.Errors {
+		if queryErr.Line == 0 {
+			fmt.Fprintf(&b, "\n• %s\n", queryErr.Message)
+			continue
+		}
+		fmt.Fprintf(&b, "\n• Line %d, column %d: %s\n", queryErr.Line, queryErr.Column, queryErr.Message)
+		if queryErr.Line <= len(lines) {
+			fmt.Fprintf(&b, "    %s\n", lines[queryErr.Line-1])
+			if queryErr.Column > 0 {
+				fmt.Fprintf(&b, "    %s^\n", strings.Repeat(" ", queryErr.Column-1))
+			}
+		}
+	}
+	return b.String()
+}
+
+// validateNQEQuery checks inline NQE source for compile errors, running it for at most one row
+func (s *ForwardMCPService) validateNQEQuery(args ValidateNQEQueryArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("validate_nqe_query", args, nil)
+
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	networkID := s.getNetworkID(args.NetworkID)
+	if networkID == "" {
+		return nil, fmt.Errorf("network_id is required (no default network is set)")
+	}
+
+	result, err := s.client().ValidateNQEQuery(networkID, args.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate NQE query: %w", err)
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(formatNQEValidation(args.Query, result))), nil
+}
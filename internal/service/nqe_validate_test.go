@@ -0,0 +1,38 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestValidateNQEQuery(t *testing.T) {
+	service := createTestService()
+	query := "foreach device in network.devices\nselect {name: device.nmae}"
+
+	response, err := service.validateNQEQuery(ValidateNQEQueryArgs{NetworkID: "162112", Query: query})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; text != "✅ NQE query is valid" {
+		t.Errorf("Expected a valid query, got:\n%s", text)
+	}
+
+	service.client().(*MockForwardClient).nqeValidation = &forward.NQEValidationResult{
+		Errors: []forward.NQEValidationError{{Message: "Unknown field nmae", Line: 2, Column: 23}},
+	}
+	response, err = service.validateNQEQuery(ValidateNQEQueryArgs{NetworkID: "162112", Query: query})
+	if err != nil {
+		t.Fatalf("Expected errors in the response, got error: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	expected := "• Line 2, column 23: Unknown field nmae\n    select {name: device.nmae}\n                          ^"
+	if !strings.Contains(text, "1 error(s)") || !strings.Contains(text, expected) {
+		t.Errorf("Expected the error with its location, got:\n%s", text)
+	}
+
+	if _, err := service.validateNQEQuery(ValidateNQEQueryArgs{NetworkID: "162112", Query: " "}); err == nil {
+		t.Error("Expected an error for an empty query")
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/forward-mcp/internal/cursor"
+)
+
+// PageInfo describes one page of a list response so callers know whether and how to
+// fetch the next page. NextCursor is set when there is a next page; passing it back as
+// cursor fetches that page and fails if the network, snapshot, or filters changed.
+type PageInfo struct {
+	Returned   int    `json:"returned"`
+	Offset     int    `json:"offset"`
+	HasMore    bool   `json:"has_more"`
+	NextOffset int    `json:"next_offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// newPageInfo computes page metadata from the request's offset and limit and the number
+// of items returned. A full page (returned == limit) is assumed to have more behind it.
+func newPageInfo(offset, limit, returned int) PageInfo {
+	return PageInfo{
+		Returned:   returned,
+		Offset:     offset,
+		HasMore:    limit > 0 && returned >= limit,
+		NextOffset: offset + returned,
+	}
+}
+
+// newPageInfoOfTotal computes page metadata for a page cut from a list whose full length
+// is known, so has_more is exact rather than inferred from a full page
+func newPageInfoOfTotal(offset, returned, total int) PageInfo {
+	return PageInfo{
+		Returned:   returned,
+		Offset:     offset,
+		HasMore:    offset+returned < total,
+		NextOffset: offset + returned,
+	}
+}
+
+// pageStart returns the offset a list request starts at: its cursor's, after checking the
+// cursor was issued for the same network, snapshot, and filters, or else its offset argument
+func pageStart(token string, offset int, networkID, snapshotID, filterHash string) (int, error) {
+	if token == "" {
+		return offset, nil
+	}
+	position, err := cursor.Parse(token, networkID, snapshotID, filterHash)
+	if err != nil {
+		return 0, err
+	}
+	return position.Offset, nil
+}
+
+// withNextCursor sets the cursor of the next page when there is one
+func withNextCursor(page PageInfo, networkID, snapshotID, filterHash string) PageInfo {
+	if page.HasMore {
+		page.NextCursor = cursor.Encode(cursor.Cursor{
+			NetworkID:  networkID,
+			SnapshotID: snapshotID,
+			Offset:     page.NextOffset,
+			FilterHash: filterHash,
+		})
+	}
+	return page
+}
+
+// withPageTrailer appends the compact {"page": ...} block to a list response
+func withPageTrailer(text string, page PageInfo) string {
+	data, _ := json.Marshal(map[string]PageInfo{"page": page})
+	return text + "\n" + string(data)
+}
+
+// paginate returns the [offset, offset+limit) window of n items as slice bounds;
+// a limit of zero or less means no limit
+func paginate(n, offset, limit int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > n {
+		offset = n
+	}
+	end := n
+	if limit > 0 && offset+limit < n {
+		end = offset + limit
+	}
+	return offset, end
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/cursor"
+	"github.com/forward-mcp/internal/forward"
+)
+
+// pageTrailer parses the {"page": ...} block from the last line of a list response
+func pageTrailer(t *testing.T, content string) PageInfo {
+	t.Helper()
+	var trailer struct {
+		Page PageInfo `json:"page"`
+	}
+	if err := json.Unmarshal([]byte(content[strings.LastIndex(content, "\n")+1:]), &trailer); err != nil {
+		t.Fatalf("Expected a page trailer, got: %s", content)
+	}
+	return trailer.Page
+}
+
+func TestListDevicesPageTrailer(t *testing.T) {
+	service := createTestService()
+	mockClient := service.client().(*MockForwardClient)
+	for i := 0; i < 5; i++ {
+		mockClient.devices = append(mockClient.devices, forward.Device{Name: fmt.Sprintf("edge-%d", i)})
+	}
+
+	testCases := []struct {
+		name     string
+		args     ListDevicesArgs
+		expected PageInfo
+	}{
+		{"full page has more", ListDevicesArgs{Limit: 2, Offset: 4}, PageInfo{Returned: 2, Offset: 4, HasMore: true, NextOffset: 6}},
+		{"short page is the last", ListDevicesArgs{Limit: 10}, PageInfo{Returned: 7, Offset: 0, HasMore: false, NextOffset: 7}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.args.NetworkID = "162112"
+			response, err := service.listDevices(tc.args)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			page := pageTrailer(t, response.Content[0].TextContent.Text)
+			if (page.NextCursor != "") != page.HasMore {
+				t.Errorf("Expected a next cursor exactly when there are more pages, got %+v", page)
+			}
+			page.NextCursor = ""
+			if page != tc.expected {
+				t.Errorf("Expected page %+v, got %+v", tc.expected, page)
+			}
+		})
+	}
+}
+
+func TestListSnapshotsPageTrailer(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).snapshots = []forward.Snapshot{
+		{ID: "snap-1", State: "PROCESSED", CreationDateMillis: 1000},
+		{ID: "snap-2", State: "PROCESSED", CreationDateMillis: 2000},
+		{ID: "snap-3", State: "PROCESSED", CreationDateMillis: 3000},
+	}
+
+	testCases := []struct {
+		name     string
+		args     ListSnapshotsArgs
+		expected PageInfo
+	}{
+		{"full page has more", ListSnapshotsArgs{Limit: 2}, PageInfo{Returned: 2, Offset: 0, HasMore: true, NextOffset: 2}},
+		{"short page is the last", ListSnapshotsArgs{Limit: 2, Offset: 2}, PageInfo{Returned: 1, Offset: 2, HasMore: false, NextOffset: 3}},
+		{"full last page has no more", ListSnapshotsArgs{Limit: 3}, PageInfo{Returned: 3, Offset: 0, HasMore: false, NextOffset: 3}},
+		{"no limit returns everything", ListSnapshotsArgs{}, PageInfo{Returned: 3, Offset: 0, HasMore: false, NextOffset: 3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.args.NetworkID = "162112"
+			response, err := service.listSnapshots(tc.args)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			page := pageTrailer(t, response.Content[0].TextContent.Text)
+			if (page.NextCursor != "") != page.HasMore {
+				t.Errorf("Expected a next cursor exactly when there are more pages, got %+v", page)
+			}
+			page.NextCursor = ""
+			if page != tc.expected {
+				t.Errorf("Expected page %+v, got %+v", tc.expected, page)
+			}
+		})
+	}
+}
+
+func TestListSnapshotsFollowsCursor(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).snapshots = []forward.Snapshot{
+		{ID: "snap-1", State: "PROCESSED", CreationDateMillis: 1000},
+		{ID: "snap-2", State: "PROCESSED", CreationDateMillis: 2000},
+		{ID: "snap-3", State: "PROCESSED", CreationDateMillis: 3000},
+	}
+
+	first, err := service.listSnapshots(ListSnapshotsArgs{NetworkID: "162112", State: "processed", Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	next := pageTrailer(t, first.Content[0].TextContent.Text).NextCursor
+	if next == "" {
+		t.Fatal("Expected a next cursor after a full page")
+	}
+
+	second, err := service.listSnapshots(ListSnapshotsArgs{NetworkID: "162112", State: "PROCESSED", Limit: 2, Cursor: next})
+	if err != nil {
+		t.Fatalf("Expected the cursor to be accepted, got: %v", err)
+	}
+	text := second.Content[0].TextContent.Text
+	if page := pageTrailer(t, text); page.Offset != 2 || page.Returned != 1 || page.NextCursor != "" {
+		t.Errorf("Expected the last page at offset 2, got %+v", page)
+	}
+	if !contains(text, "snap-1") || contains(text, "snap-3") {
+		t.Errorf("Expected only the oldest snapshot on the second page, got: %s", text)
+	}
+
+	includeDrafts := false
+	_, err = service.listSnapshots(ListSnapshotsArgs{NetworkID: "162112", State: "PROCESSED", IncludeDrafts: &includeDrafts, Limit: 2, Cursor: next})
+	if !errors.Is(err, cursor.ErrStaleCursor) {
+		t.Errorf("Expected a stale cursor error after the filters changed, got: %v", err)
+	}
+}
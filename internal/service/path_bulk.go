@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// maxBulkPathFlows caps the flows sent in one paths-bulk request
+const maxBulkPathFlows = 100
+
+// BulkPathResult is the outcome of one flow in a bulk path search, aligned to the request
+// by index. Error is set when the API returned no response or an error for the flow.
+type BulkPathResult struct {
+	SrcIP    string   `json:"src_ip,omitempty"`
+	DstIP    string   `json:"dst_ip"`
+	Paths    int      `json:"paths"`
+	Outcomes []string `json:"outcomes,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// flow names a bulk result by its source and destination
+func (r BulkPathResult) flow() string {
+	source := r.SrcIP
+	if source == "" {
+		source = "any"
+	}
+	return source + " -> " + r.DstIP
+}
+
+// AlignBulkPathResponses pairs each request with the response at the same index. The
+// paths-bulk API answers in request order, so a request past the end of responses, or a
+// response carrying an error marker, is reported as a failed flow.
+func AlignBulkPathResponses(requests []forward.PathSearchParams, responses []forward.PathSearchResponse) []BulkPathResult {
+	results := make([]BulkPathResult, len(requests))
+	for i, request := range requests {
+		result := &results[i]
+		result.SrcIP = request.SrcIP
+		result.DstIP = request.DstIP
+		if i >= len(responses) {
+			result.Error = fmt.Sprintf("no response returned (API answered %d of %d flows)", len(responses), len(requests))
+			continue
+		}
+		if responses[i].Error != "" {
+			result.Error = responses[i].Error
+			continue
+		}
+		result.Paths = len(responses[i].Paths)
+		for _, path := range responses[i].Paths {
+			result.Outcomes = append(result.Outcomes, string(ClassifyPath(path).Class))
+		}
+	}
+	return results
+}
+
+// searchPathsBulk traces several flows in one paths-bulk request and reports which
+// flows succeeded and which failed
+func (s *ForwardMCPService) searchPathsBulk(args SearchPathsBulkArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("search_paths_bulk", args, nil)
+
+	if len(args.Flows) == 0 {
+		return nil, fmt.Errorf("flows must contain at least one flow")
+	}
+	if len(args.Flows) > maxBulkPathFlows {
+		return nil, fmt.Errorf("too many flows: %d (max %d per bulk search)", len(args.Flows), maxBulkPathFlows)
+	}
+
+	networkID := s.getNetworkID(args.NetworkID)
+	snapshotID, err := s.pathSearchSnapshot(networkID, args.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]forward.PathSearchParams, len(args.Flows))
+	for i, flow := range args.Flows {
+		search := SearchPathsArgs{SrcIP: flow.SrcIP, DstIP: flow.DstIP, IPProto: flow.IPProto, SrcPort: flow.SrcPort, DstPort: flow.DstPort, Intent: args.Intent}
+		if err := validateSearchPathsArgs(&search); err != nil {
+			return nil, fmt.Errorf("invalid flow %d: %w", i+1, err)
+		}
+		requests[i] = forward.PathSearchParams{
+			SrcIP:      search.SrcIP,
+			DstIP:      search.DstIP,
+			Intent:     search.Intent,
+			SrcPort:    search.SrcPort,
+			DstPort:    search.DstPort,
+			SnapshotID: snapshotID,
+		}
+		if search.IPProto != 0 {
+			requests[i].IPProto = &search.IPProto
+		}
+		s.applyPathSearchDefaults(&requests[i])
+	}
+
+	responses, err := s.client().SearchPathsBulk(networkID, requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search paths: %w", err)
+	}
+	if len(responses) != len(requests) {
+		s.logger.Warn("Bulk path search returned %d responses for %d flows", len(responses), len(requests))
+	}
+
+	results := AlignBulkPathResponses(requests, responses)
+	var failed []string
+	identifiers := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Error != "" {
+			failed = append(failed, result.flow())
+			identifiers = append(identifiers, result.flow()+"=FAILED")
+			continue
+		}
+		identifiers = append(identifiers, fmt.Sprintf("%s=%d paths", result.flow(), result.Paths))
+	}
+	if len(failed) == len(results) {
+		return nil, fmt.Errorf("all %d flows failed: %s", len(results), results[0].Error)
+	}
+
+	header := fmt.Sprintf("Bulk path search on %s (snapshot %s): %d of %d flows succeeded", networkID, snapshotID, len(results)-len(failed), len(results))
+	if len(failed) > 0 {
+		header += fmt.Sprintf("; failed: %s", summarizeIdentifiers(failed))
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, identifiers, results, args.Pretty))), nil
+}
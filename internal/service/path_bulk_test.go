@@ -0,0 +1,64 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// shortBulkClient answers paths-bulk requests with one fewer response than requested
+type shortBulkClient struct {
+	*MockForwardClient
+}
+
+func (c *shortBulkClient) SearchPathsBulk(networkID string, requests []forward.PathSearchParams) ([]forward.PathSearchResponse, error) {
+	responses, err := c.MockForwardClient.SearchPathsBulk(networkID, requests)
+	if err != nil {
+		return nil, err
+	}
+	return responses[:len(responses)-1], nil
+}
+
+func TestSearchPathsBulkFlagsMissingResponse(t *testing.T) {
+	service := createTestService()
+	mock := service.client().(*MockForwardClient)
+	mock.pathResponse = &forward.PathSearchResponse{Paths: []forward.Path{{Outcome: "DELIVERED", Hops: []forward.Hop{{Device: "router-1"}}}}}
+	service.active().client = &shortBulkClient{MockForwardClient: mock}
+
+	response, err := service.searchPathsBulk(SearchPathsBulkArgs{
+		NetworkID:  "162112",
+		SnapshotID: "snap-1",
+		Flows: []BulkPathFlow{
+			{SrcIP: "10.0.0.1", DstIP: "10.0.1.1"},
+			{SrcIP: "10.0.0.2", DstIP: "10.0.1.2"},
+			{SrcIP: "10.0.0.3", DstIP: "10.0.1.3"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected a partial result, got error: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "2 of 3 flows succeeded; failed: 10.0.0.3 -> 10.0.1.3") {
+		t.Errorf("Expected the missing flow to be flagged, got:\n%s", text)
+	}
+	if !strings.Contains(text, "no response returned (API answered 2 of 3 flows)") {
+		t.Errorf("Expected the failure reason in the results, got:\n%s", text)
+	}
+}
+
+func TestAlignBulkPathResponsesReportsErrorMarkers(t *testing.T) {
+	requests := []forward.PathSearchParams{{DstIP: "10.0.1.1"}, {SrcIP: "10.0.0.2", DstIP: "10.0.1.2"}}
+	responses := []forward.PathSearchResponse{
+		{Paths: []forward.Path{{Outcome: "DELIVERED"}}},
+		{Error: "search timed out"},
+	}
+
+	results := AlignBulkPathResponses(requests, responses)
+	if results[0].Error != "" || results[0].Paths != 1 || results[0].flow() != "any -> 10.0.1.1" {
+		t.Errorf("Expected the first flow to succeed, got %+v", results[0])
+	}
+	if results[1].Error != "search timed out" {
+		t.Errorf("Expected the error marker on the second flow, got %+v", results[1])
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// reasonDetailHints select hop details worth quoting when explaining a drop
+var reasonDetailHints = []string{"reason", "acl", "rule", "policy", "route"}
+
+// ExplainPath narrates one path in plain language, e.g. "Traffic from 10.0.0.1 to
+// 10.0.1.1 enters at router-1, is forwarded out interface Gi0/1, reaches switch-1, and
+// is delivered." Drops and loops name the responsible device and reason.
+func ExplainPath(path forward.Path, source, destination string) string {
+	classification := ClassifyPath(path)
+	if source == "" {
+		source = "the source"
+	}
+
+	hops := path.Hops
+	if classification.BlockingHopIndex >= 0 {
+		hops = hops[:classification.BlockingHopIndex+1]
+	}
+
+	var steps []string
+	for i, hop := range hops {
+		if i == 0 {
+			steps = append(steps, "enters at "+hop.Device)
+		} else {
+			steps = append(steps, "reaches "+hop.Device)
+		}
+		blocking := classification.BlockingHop != nil && i == classification.BlockingHopIndex
+		if hop.Interface != "" && !blocking && i < len(path.Hops)-1 {
+			steps = append(steps, "is forwarded out interface "+hop.Interface)
+		}
+	}
+	steps = append(steps, explainOutcome(classification))
+
+	narrative := fmt.Sprintf("Traffic from %s to %s ", source, destination)
+	switch len(steps) {
+	case 1:
+		narrative += steps[0]
+	case 2:
+		narrative += steps[0] + " and " + steps[1]
+	default:
+		narrative += strings.Join(steps[:len(steps)-1], ", ") + ", and " + steps[len(steps)-1]
+	}
+	return narrative + "."
+}
+
+// explainOutcome renders the final clause of a path narrative
+func explainOutcome(classification PathClassification) string {
+	hop := classification.BlockingHop
+	location := ""
+	if hop != nil {
+		location = " at " + hop.Device
+		if hop.Interface != "" {
+			location += " on " + hop.Interface
+		}
+	}
+
+	switch classification.Class {
+	case OutcomeDelivered:
+		return "is delivered"
+	case OutcomeDroppedACL:
+		return fmt.Sprintf("is dropped%s because %s%s", location, classification.Reason, hopReasonDetails(hop))
+	case OutcomeDroppedNoRoute:
+		return fmt.Sprintf("is dropped%s because there is %s%s", location, classification.Reason, hopReasonDetails(hop))
+	case OutcomeLooped:
+		return fmt.Sprintf("loops%s because %s", location, classification.Reason)
+	case OutcomeUnreachable:
+		return fmt.Sprintf("stops%s because the %s", location, classification.Reason)
+	default:
+		return fmt.Sprintf("ends%s with %s", location, classification.Reason)
+	}
+}
+
+// hopReasonDetails quotes the hop details that explain a drop, such as the matching ACL
+func hopReasonDetails(hop *forward.Hop) string {
+	if hop == nil {
+		return ""
+	}
+	var parts []string
+	for _, key := range sortedKeys(hop.Details) {
+		if containsAny(strings.ToLower(key), reasonDetailHints) {
+			parts = append(parts, fmt.Sprintf("%s %v", key, hop.Details[key]))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// formatPathExplanations renders a narrative per path for search_paths with explain: true
+func formatPathExplanations(paths []forward.Path, source, destination string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	explanation := "\nExplanation:\n"
+	for i, path := range paths {
+		explanation += fmt.Sprintf("• Path %d: %s\n", i+1, ExplainPath(path, source, destination))
+	}
+	return explanation
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestExplainPath(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     forward.Path
+		expected string
+	}{
+		{
+			name: "delivered",
+			path: forward.Path{
+				Outcome: "DELIVERED",
+				Hops: []forward.Hop{
+					{Device: "router-1", Interface: "Gi0/1", Action: "FORWARD"},
+					{Device: "switch-1", Action: "DELIVER"},
+				},
+			},
+			expected: "Traffic from 10.0.0.1 to 10.0.1.1 enters at router-1, is forwarded out interface Gi0/1, reaches switch-1, and is delivered.",
+		},
+		{
+			name: "ACL drop names the device and rule",
+			path: forward.Path{
+				Outcome: "DROPPED",
+				Hops: []forward.Hop{
+					{Device: "router-1", Interface: "Gi0/1", Action: "FORWARD"},
+					{Device: "fw-1", Interface: "ethernet1/1", Action: "DROP", Details: map[string]interface{}{"acl": "OUTSIDE_IN", "bytes": 0}},
+					{Device: "server-1", Action: "DELIVER"},
+				},
+			},
+			expected: "Traffic from 10.0.0.1 to 10.0.1.1 enters at router-1, is forwarded out interface Gi0/1, reaches fw-1, and is dropped at fw-1 on ethernet1/1 because traffic is denied by an ACL or security policy (acl OUTSIDE_IN).",
+		},
+		{
+			name: "single hop without a route",
+			path: forward.Path{
+				Outcome: "BLACKHOLE",
+				Hops:    []forward.Hop{{Device: "router-1", Action: "DROP"}},
+			},
+			expected: "Traffic from 10.0.0.1 to 10.0.1.1 enters at router-1 and is dropped at router-1 because there is no route to the destination.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ExplainPath(tc.path, "10.0.0.1", "10.0.1.1"); got != tc.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSearchPathsExplain(t *testing.T) {
+	service := createTestService()
+
+	response, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", SrcIP: "10.0.0.1", DstIP: "10.0.1.1", SnapshotID: "snapshot-123", Explain: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	router := strings.Index(content, "enters at router-1")
+	switchHop := strings.Index(content, "reaches switch-1")
+	delivered := strings.Index(content, "and is delivered.")
+	if router < 0 || switchHop < router || delivered < switchHop {
+		t.Errorf("Expected narrative to name devices in order and end with the outcome, got: %s", content)
+	}
+
+	response, err = service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.1.1", SnapshotID: "snapshot-123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if contains(response.Content[0].TextContent.Text, "Explanation:") {
+		t.Error("Expected no narrative unless explain is set")
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// Normalized hop detail keys (lowercase, without separators) for common network functions,
+// most specific first. Nested details are flattened, so {"acl": {"name": ...}} is "aclname".
+var (
+	aclNameKeys        = []string{"aclname", "acl", "accesslistname", "accesslist", "filtername", "filter"}
+	aclLineKeys        = []string{"aclline", "acllinenumber", "aclentry", "aclrule", "aclrulenumber", "linenumber", "line"}
+	natOriginalKeys    = []string{"natoriginal", "natoriginalip", "originalip", "prenatip", "natfrom"}
+	natTranslatedKeys  = []string{"nattranslated", "nattranslatedip", "translatedip", "postnatip", "natto"}
+	natTranslationKeys = []string{"nattranslation", "nat", "sourcenat", "snat", "destinationnat", "dnat"}
+	policyKeys         = []string{"policyname", "appliedpolicy", "securitypolicy", "securityrule", "policy", "routemap", "pbr"}
+)
+
+// flattenHopDetails flattens nested hop details into normalized keys
+func flattenHopDetails(prefix string, details map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range details {
+		normalized := prefix + strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key))
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenHopDetails(normalized, nested, flat)
+			continue
+		}
+		flat[normalized] = value
+	}
+}
+
+// firstHopDetail returns the first present, non-empty detail among keys
+func firstHopDetail(flat map[string]interface{}, keys []string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := flat[key]; ok && value != nil {
+			if text := strings.TrimSpace(fmt.Sprint(value)); text != "" {
+				return text, true
+			}
+		}
+	}
+	return "", false
+}
+
+// hopVerdict describes what a hop did to the traffic, for ACL and policy annotations
+func hopVerdict(hop forward.Hop) string {
+	action := strings.ToLower(hop.Action)
+	switch {
+	case strings.Contains(action, "drop") || strings.Contains(action, "deny") || strings.Contains(action, "discard"):
+		return "dropped"
+	case action != "":
+		return "permitted"
+	}
+	return "matched"
+}
+
+// DescribeHopFunctions summarizes the network functions applied at a hop, e.g.
+// "dropped by ACL INBOUND line 30" or "NAT 10.0.0.5 -> 203.0.113.5", from its details
+func DescribeHopFunctions(hop forward.Hop) []string {
+	if len(hop.Details) == 0 {
+		return nil
+	}
+	flat := make(map[string]interface{})
+	flattenHopDetails("", hop.Details, flat)
+
+	var functions []string
+	if name, ok := firstHopDetail(flat, aclNameKeys); ok {
+		acl := fmt.Sprintf("%s by ACL %s", hopVerdict(hop), name)
+		if line, ok := firstHopDetail(flat, aclLineKeys); ok {
+			acl += " line " + line
+		}
+		functions = append(functions, acl)
+	}
+
+	original, hasOriginal := firstHopDetail(flat, natOriginalKeys)
+	translated, hasTranslated := firstHopDetail(flat, natTranslatedKeys)
+	switch {
+	case hasOriginal && hasTranslated:
+		functions = append(functions, fmt.Sprintf("NAT %s -> %s", original, translated))
+	case hasTranslated:
+		functions = append(functions, "NAT to "+translated)
+	default:
+		if translation, ok := firstHopDetail(flat, natTranslationKeys); ok {
+			functions = append(functions, "NAT "+translation)
+		}
+	}
+
+	if policy, ok := firstHopDetail(flat, policyKeys); ok {
+		functions = append(functions, fmt.Sprintf("%s by policy %s", hopVerdict(hop), policy))
+	}
+	return functions
+}
+
+// formatHopFunctions renders one annotation line per hop that applied a network function,
+// for search_paths with include_network_functions: true
+func formatHopFunctions(paths []forward.Path) string {
+	var lines []string
+	for i, path := range paths {
+		for j, hop := range path.Hops {
+			functions := DescribeHopFunctions(hop)
+			if len(functions) == 0 {
+				continue
+			}
+			location := hop.Device
+			if hop.Interface != "" {
+				location += " on " + hop.Interface
+			}
+			lines = append(lines, fmt.Sprintf("• Path %d hop %d (%s): %s\n", i+1, j+1, location, strings.Join(functions, "; ")))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\nNetwork functions:\n" + strings.Join(lines, "")
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestDescribeHopFunctions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		hop      forward.Hop
+		expected []string
+	}{
+		{
+			name:     "flat ACL details",
+			hop:      forward.Hop{Device: "fw-1", Action: "DROP", Details: map[string]interface{}{"aclName": "INBOUND", "aclLine": 30}},
+			expected: []string{"dropped by ACL INBOUND line 30"},
+		},
+		{
+			name:     "nested ACL details",
+			hop:      forward.Hop{Device: "fw-1", Action: "FORWARD", Details: map[string]interface{}{"acl": map[string]interface{}{"name": "OUTSIDE_IN", "line_number": 10}}},
+			expected: []string{"permitted by ACL OUTSIDE_IN line 10"},
+		},
+		{
+			name: "NAT and policy",
+			hop: forward.Hop{Device: "fw-1", Action: "FORWARD", Details: map[string]interface{}{
+				"nat":    map[string]interface{}{"original": "10.0.0.5", "translated": "203.0.113.5"},
+				"policy": "allow-web",
+			}},
+			expected: []string{"NAT 10.0.0.5 -> 203.0.113.5", "permitted by policy allow-web"},
+		},
+		{
+			name: "unrelated details",
+			hop:  forward.Hop{Device: "router-1", Action: "FORWARD", Details: map[string]interface{}{"bytes": 0}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DescribeHopFunctions(tc.hop); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSearchPathsAnnotatesNetworkFunctions(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).pathResponse = &forward.PathSearchResponse{
+		SnapshotID:   "snapshot-123",
+		SearchTimeMs: 5,
+		Paths: []forward.Path{{
+			Outcome: "DROPPED",
+			Hops: []forward.Hop{
+				{Device: "router-1", Interface: "Gi0/1", Action: "FORWARD"},
+				{Device: "fw-1", Interface: "ethernet1/1", Action: "DROP", Details: map[string]interface{}{"aclName": "INBOUND", "aclLine": 30}},
+			},
+		}},
+	}
+
+	args := SearchPathsArgs{NetworkID: "162112", SrcIP: "10.0.0.1", DstIP: "10.0.1.1", SnapshotID: "snapshot-123", IncludeNetworkFunctions: true}
+	response, err := service.searchPaths(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	content := response.Content[0].TextContent.Text
+	if !strings.Contains(content, "• Path 1 hop 2 (fw-1 on ethernet1/1): dropped by ACL INBOUND line 30") {
+		t.Errorf("Expected the ACL annotation, got:\n%s", content)
+	}
+
+	args.IncludeNetworkFunctions = false
+	response, err = service.searchPaths(args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.Contains(response.Content[0].TextContent.Text, "Network functions:") {
+		t.Error("Expected no annotations unless include_network_functions is set")
+	}
+}
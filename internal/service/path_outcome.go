@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// PathOutcomeClass is a normalized category for a path search outcome
+type PathOutcomeClass string
+
+const (
+	OutcomeDelivered      PathOutcomeClass = "DELIVERED"
+	OutcomeDroppedACL     PathOutcomeClass = "DROPPED_ACL"
+	OutcomeDroppedNoRoute PathOutcomeClass = "DROPPED_NO_ROUTE"
+	OutcomeLooped         PathOutcomeClass = "LOOPED"
+	OutcomeUnreachable    PathOutcomeClass = "UNREACHABLE"
+	OutcomeOther          PathOutcomeClass = "OTHER"
+)
+
+// PathClassification is the structured interpretation of one path's outcome
+type PathClassification struct {
+	Class            PathOutcomeClass `json:"class"`
+	BlockingHop      *forward.Hop     `json:"blocking_hop,omitempty"`
+	BlockingHopIndex int              `json:"blocking_hop_index"` // -1 when no hop blocked the traffic
+	Reason           string           `json:"reason"`
+}
+
+var (
+	aclHints     = []string{"acl", "access-list", "access list", "filter", "firewall", "security policy", "denied", "deny"}
+	noRouteHints = []string{"no route", "no_route", "noroute", "blackhole", "black_hole", "black hole", "null route", "null0", "missing route"}
+)
+
+// ClassifyPath maps a path's free-form outcome, hop actions, and hop details onto a
+// PathOutcomeClass and identifies the hop most likely responsible for blocking traffic
+func ClassifyPath(path forward.Path) PathClassification {
+	outcome := strings.ToLower(path.Outcome + " " + path.OutcomeType)
+	blockingIndex := findBlockingHop(path.Hops)
+
+	classification := PathClassification{BlockingHopIndex: -1}
+	setBlockingHop := func(index int) {
+		if index >= 0 && index < len(path.Hops) {
+			classification.BlockingHop = &path.Hops[index]
+			classification.BlockingHopIndex = index
+		}
+	}
+
+	switch {
+	case strings.Contains(outcome, "loop"):
+		classification.Class = OutcomeLooped
+		classification.Reason = "traffic loops between devices"
+		setBlockingHop(len(path.Hops) - 1)
+	case containsAny(outcome, aclHints) || (blockingIndex >= 0 && containsAny(hopText(path.Hops[blockingIndex]), aclHints)):
+		classification.Class = OutcomeDroppedACL
+		classification.Reason = "traffic is denied by an ACL or security policy"
+		setBlockingHop(blockingIndex)
+	case strings.Contains(outcome, "deliver") && !strings.Contains(outcome, "not deliver") && !strings.Contains(outcome, "undeliver"):
+		classification.Class = OutcomeDelivered
+		classification.Reason = "traffic reaches the destination"
+	case containsAny(outcome, noRouteHints) || (blockingIndex >= 0 && containsAny(hopText(path.Hops[blockingIndex]), noRouteHints)):
+		classification.Class = OutcomeDroppedNoRoute
+		classification.Reason = "no route to the destination"
+		setBlockingHop(blockingIndex)
+		if classification.BlockingHop == nil {
+			setBlockingHop(len(path.Hops) - 1)
+		}
+	case strings.Contains(outcome, "unreachable") || strings.Contains(outcome, "inadmissible"):
+		classification.Class = OutcomeUnreachable
+		classification.Reason = "destination is unreachable"
+		setBlockingHop(len(path.Hops) - 1)
+	default:
+		classification.Class = OutcomeOther
+		classification.Reason = fmt.Sprintf("unrecognized outcome %q", strings.TrimSpace(path.Outcome+" "+path.OutcomeType))
+		setBlockingHop(blockingIndex)
+	}
+
+	return classification
+}
+
+// findBlockingHop returns the index of the first hop that drops or denies traffic, or -1
+func findBlockingHop(hops []forward.Hop) int {
+	for i, hop := range hops {
+		action := strings.ToLower(hop.Action)
+		if strings.Contains(action, "drop") || strings.Contains(action, "deny") || strings.Contains(action, "discard") {
+			return i
+		}
+	}
+	return -1
+}
+
+// hopText flattens a hop's action and details for keyword matching
+func hopText(hop forward.Hop) string {
+	var builder strings.Builder
+	builder.WriteString(strings.ToLower(hop.Action))
+	for key, value := range hop.Details {
+		builder.WriteString(" ")
+		builder.WriteString(strings.ToLower(fmt.Sprintf("%s %v", key, value)))
+	}
+	return builder.String()
+}
+
+// formatPathClassifications renders a one-line outcome summary per path
+func formatPathClassifications(paths []forward.Path) string {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	summary := "\nPath outcomes:\n"
+	for i, path := range paths {
+		classification := ClassifyPath(path)
+		summary += fmt.Sprintf("• Path %d: %s - %s", i+1, classification.Class, classification.Reason)
+		if hop := classification.BlockingHop; hop != nil {
+			location := hop.Device
+			if hop.Interface != "" {
+				location += " (" + hop.Interface + ")"
+			}
+			summary += fmt.Sprintf("; likely blocking hop %d: %s", classification.BlockingHopIndex+1, location)
+		}
+		summary += "\n"
+	}
+	return summary
+}
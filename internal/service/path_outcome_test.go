@@ -0,0 +1,124 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestClassifyPath(t *testing.T) {
+	testCases := []struct {
+		name             string
+		path             forward.Path
+		expectedClass    PathOutcomeClass
+		expectedHopIndex int
+	}{
+		{
+			name: "delivered",
+			path: forward.Path{
+				Outcome:     "DELIVERED",
+				OutcomeType: "PERMITTED",
+				Hops: []forward.Hop{
+					{Device: "router-1", Action: "FORWARD"},
+					{Device: "server-1", Action: "DELIVER"},
+				},
+			},
+			expectedClass:    OutcomeDelivered,
+			expectedHopIndex: -1,
+		},
+		{
+			name: "ACL drop identified from hop details",
+			path: forward.Path{
+				Outcome: "DROPPED",
+				Hops: []forward.Hop{
+					{Device: "router-1", Action: "FORWARD"},
+					{Device: "fw-1", Interface: "ethernet1/1", Action: "DROP", Details: map[string]interface{}{"acl": "OUTSIDE_IN", "rule": "deny ip any any"}},
+				},
+			},
+			expectedClass:    OutcomeDroppedACL,
+			expectedHopIndex: 1,
+		},
+		{
+			name: "security outcome denied",
+			path: forward.Path{
+				Outcome:     "DELIVERED",
+				OutcomeType: "DENIED",
+				Hops: []forward.Hop{
+					{Device: "fw-1", Action: "DENY"},
+					{Device: "server-1", Action: "DELIVER"},
+				},
+			},
+			expectedClass:    OutcomeDroppedACL,
+			expectedHopIndex: 0,
+		},
+		{
+			name: "blackhole without route",
+			path: forward.Path{
+				Outcome: "BLACKHOLE",
+				Hops: []forward.Hop{
+					{Device: "router-1", Action: "FORWARD"},
+					{Device: "router-2", Action: "DROP", Details: map[string]interface{}{"reason": "no route"}},
+				},
+			},
+			expectedClass:    OutcomeDroppedNoRoute,
+			expectedHopIndex: 1,
+		},
+		{
+			name:             "loop",
+			path:             forward.Path{Outcome: "LOOP", Hops: []forward.Hop{{Device: "router-1"}, {Device: "router-2"}}},
+			expectedClass:    OutcomeLooped,
+			expectedHopIndex: 1,
+		},
+		{
+			name:             "unreachable",
+			path:             forward.Path{Outcome: "UNREACHABLE", Hops: []forward.Hop{{Device: "router-1"}}},
+			expectedClass:    OutcomeUnreachable,
+			expectedHopIndex: 0,
+		},
+		{
+			name:             "unknown outcome",
+			path:             forward.Path{Outcome: "TIMEOUT"},
+			expectedClass:    OutcomeOther,
+			expectedHopIndex: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			classification := ClassifyPath(tc.path)
+			if classification.Class != tc.expectedClass {
+				t.Errorf("Expected class %s, got %s (%s)", tc.expectedClass, classification.Class, classification.Reason)
+			}
+			if classification.BlockingHopIndex != tc.expectedHopIndex {
+				t.Errorf("Expected blocking hop %d, got %d", tc.expectedHopIndex, classification.BlockingHopIndex)
+			}
+		})
+	}
+}
+
+func TestSearchPathsIncludesOutcomeClassification(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).pathResponse = &forward.PathSearchResponse{
+		SnapshotID:   "snapshot-123",
+		SearchTimeMs: 10,
+		Paths: []forward.Path{
+			{
+				Outcome: "DROPPED",
+				Hops: []forward.Hop{
+					{Device: "router-1", Action: "FORWARD"},
+					{Device: "fw-1", Interface: "ethernet1/1", Action: "DROP", Details: map[string]interface{}{"acl": "OUTSIDE_IN"}},
+				},
+			},
+		},
+	}
+
+	response, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	if !contains(content, "Path 1: DROPPED_ACL") || !contains(content, "likely blocking hop 2: fw-1 (ethernet1/1)") {
+		t.Errorf("Expected outcome classification in response, got: %s", content)
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// PathAsymmetry lists the devices that only one direction of a flow traverses
+type PathAsymmetry struct {
+	ForwardOnly []string `json:"forward_only,omitempty"`
+	ReturnOnly  []string `json:"return_only,omitempty"`
+}
+
+// Asymmetric reports whether the forward and return paths traverse different devices
+func (a PathAsymmetry) Asymmetric() bool {
+	return len(a.ForwardOnly) > 0 || len(a.ReturnOnly) > 0
+}
+
+// pathDevices returns the distinct devices a path traverses, in hop order
+func pathDevices(path forward.Path) []string {
+	var devices []string
+	seen := make(map[string]bool, len(path.Hops))
+	for _, hop := range path.Hops {
+		if hop.Device == "" || seen[hop.Device] {
+			continue
+		}
+		seen[hop.Device] = true
+		devices = append(devices, hop.Device)
+	}
+	return devices
+}
+
+// CompareReturnPath compares the devices traversed by a forward path and its return
+// path. Hop order is ignored, since the return path visits devices in reverse.
+func CompareReturnPath(forwardPath, returnPath forward.Path) PathAsymmetry {
+	var asymmetry PathAsymmetry
+	forwardDevices := pathDevices(forwardPath)
+	returnDevices := pathDevices(returnPath)
+
+	onReturn := make(map[string]bool, len(returnDevices))
+	for _, device := range returnDevices {
+		onReturn[device] = true
+	}
+	onForward := make(map[string]bool, len(forwardDevices))
+	for _, device := range forwardDevices {
+		onForward[device] = true
+		if !onReturn[device] {
+			asymmetry.ForwardOnly = append(asymmetry.ForwardOnly, device)
+		}
+	}
+	for _, device := range returnDevices {
+		if !onForward[device] {
+			asymmetry.ReturnOnly = append(asymmetry.ReturnOnly, device)
+		}
+	}
+	return asymmetry
+}
+
+// formatReturnPaths renders each return path's hops and outcome for search_paths with
+// include_return_path: true, flagging return paths that diverge from the forward path
+// at the same position (or the first forward path when there are more return paths)
+func formatReturnPaths(paths, returnPaths []forward.Path) string {
+	if len(returnPaths) == 0 {
+		return "\nReturn paths: none found\n"
+	}
+
+	summary := "\nReturn paths:\n"
+	for i, returnPath := range returnPaths {
+		classification := ClassifyPath(returnPath)
+		summary += fmt.Sprintf("• Return path %d: %s - %s", i+1, strings.Join(pathDevices(returnPath), " -> "), classification.Class)
+		if len(paths) > 0 {
+			forwardIndex := i
+			if forwardIndex >= len(paths) {
+				forwardIndex = 0
+			}
+			if asymmetry := CompareReturnPath(paths[forwardIndex], returnPath); asymmetry.Asymmetric() {
+				summary += fmt.Sprintf("\n  ⚠️  Asymmetric routing vs path %d:", forwardIndex+1)
+				if len(asymmetry.ForwardOnly) > 0 {
+					summary += " forward only via " + strings.Join(asymmetry.ForwardOnly, ", ") + ";"
+				}
+				if len(asymmetry.ReturnOnly) > 0 {
+					summary += " return only via " + strings.Join(asymmetry.ReturnOnly, ", ") + ";"
+				}
+				summary = strings.TrimSuffix(summary, ";")
+			}
+		}
+		summary += "\n"
+	}
+	return summary
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestSearchPathsRendersReturnPathAsymmetry(t *testing.T) {
+	service := createTestService()
+	client := &recordingPathClient{MockForwardClient: service.client().(*MockForwardClient)}
+	client.pathResponse = &forward.PathSearchResponse{
+		SnapshotID:         "snapshot-123",
+		SearchTimeMs:       12,
+		NumCandidatesFound: 1,
+		Paths: []forward.Path{{
+			Outcome: "DELIVERED",
+			Hops: []forward.Hop{
+				{Device: "router-1", Interface: "Gi0/1", Action: "FORWARD"},
+				{Device: "fw-1", Interface: "ethernet1/1", Action: "FORWARD"},
+				{Device: "switch-1", Action: "DELIVER"},
+			},
+		}},
+		ReturnPaths: []forward.Path{{
+			Outcome: "DELIVERED",
+			Hops: []forward.Hop{
+				{Device: "switch-1", Interface: "Gi0/2", Action: "FORWARD"},
+				{Device: "fw-2", Interface: "ethernet1/2", Action: "FORWARD"},
+				{Device: "router-1", Action: "DELIVER"},
+			},
+		}},
+	}
+	service.active().client = client
+
+	response, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.1.1", SnapshotID: "snapshot-123", IncludeReturnPath: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if client.params.MaxReturnPathResults != defaultPathMaxResults {
+		t.Errorf("Expected a return path to be requested per result, got %d", client.params.MaxReturnPathResults)
+	}
+
+	text := response.Content[0].TextContent.Text
+	if !strings.Contains(text, "• Return path 1: switch-1 -> fw-2 -> router-1 - DELIVERED") {
+		t.Errorf("Expected the return path to be rendered, got:\n%s", text)
+	}
+	if !strings.Contains(text, "Asymmetric routing vs path 1: forward only via fw-1; return only via fw-2") {
+		t.Errorf("Expected asymmetry to be flagged, got:\n%s", text)
+	}
+}
+
+func TestCompareReturnPathIgnoresHopOrder(t *testing.T) {
+	forwardPath := forward.Path{Hops: []forward.Hop{{Device: "router-1"}, {Device: "switch-1"}}}
+	returnPath := forward.Path{Hops: []forward.Hop{{Device: "switch-1"}, {Device: "router-1"}}}
+
+	if asymmetry := CompareReturnPath(forwardPath, returnPath); asymmetry.Asymmetric() {
+		t.Errorf("Expected a reversed path to be symmetric, got %+v", asymmetry)
+	}
+	if text := formatReturnPaths([]forward.Path{forwardPath}, []forward.Path{returnPath}); strings.Contains(text, "Asymmetric") {
+		t.Errorf("Expected no asymmetry warning, got:\n%s", text)
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// defaultPathSearchHistorySize bounds how many path searches are retained in memory
+const defaultPathSearchHistorySize = 500
+
+// defaultPathSearchHistoryLimit is how many searches get_path_search_history returns by default
+const defaultPathSearchHistoryLimit = 20
+
+// PathSearchRecord is one tracked path search and its classified outcomes
+type PathSearchRecord struct {
+	Key        string             `json:"key"`
+	NetworkID  string             `json:"network_id"`
+	SnapshotID string             `json:"snapshot_id"`
+	Source     string             `json:"source"`
+	DstIP      string             `json:"dst_ip"`
+	DstPort    string             `json:"dst_port,omitempty"`
+	PathCount  int                `json:"path_count"`
+	Outcomes   []PathOutcomeClass `json:"outcomes"`
+	Timestamp  time.Time          `json:"timestamp"`
+}
+
+// PathSearchTracker keeps a bounded history of path searches. Every search is stored as
+// its own record, so repeated searches between the same endpoints accumulate rather
+// than overwrite. A nil tracker records nothing.
+type PathSearchTracker struct {
+	mutex      sync.RWMutex
+	records    []PathSearchRecord
+	maxRecords int
+	sequence   uint64
+	now        func() time.Time
+}
+
+// NewPathSearchTracker creates a tracker that retains up to maxRecords searches
+func NewPathSearchTracker(maxRecords int) *PathSearchTracker {
+	if maxRecords <= 0 {
+		maxRecords = defaultPathSearchHistorySize
+	}
+	return &PathSearchTracker{
+		maxRecords: maxRecords,
+		now:        time.Now,
+	}
+}
+
+// pathSearchKey builds a distinct key per search from the endpoints, snapshot, and time.
+// The sequence number keeps keys unique even when two searches share a timestamp.
+func pathSearchKey(source, dstIP, snapshotID string, timestamp time.Time, sequence uint64) string {
+	return fmt.Sprintf("path_search_%s_to_%s_%s_%d_%d", source, dstIP, snapshotID, timestamp.UnixMilli(), sequence)
+}
+
+// Track records a completed path search and returns the stored record
+func (t *PathSearchTracker) Track(networkID, snapshotID string, args SearchPathsArgs, response *forward.PathSearchResponse) PathSearchRecord {
+	if t == nil {
+		return PathSearchRecord{}
+	}
+
+	source := args.SrcIP
+	if source == "" {
+		source = args.From
+	}
+	if source == "" {
+		source = "any"
+	}
+	if response != nil && response.SnapshotID != "" {
+		snapshotID = response.SnapshotID
+	}
+
+	record := PathSearchRecord{
+		NetworkID:  networkID,
+		SnapshotID: snapshotID,
+		Source:     source,
+		DstIP:      args.DstIP,
+		DstPort:    args.DstPort,
+	}
+	if response != nil {
+		record.PathCount = len(response.Paths)
+		for _, path := range response.Paths {
+			record.Outcomes = append(record.Outcomes, ClassifyPath(path).Class)
+		}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.sequence++
+	record.Timestamp = t.now()
+	record.Key = pathSearchKey(source, args.DstIP, snapshotID, record.Timestamp, t.sequence)
+
+	t.records = append(t.records, record)
+	if len(t.records) > t.maxRecords {
+		t.records = t.records[len(t.records)-t.maxRecords:]
+	}
+	return record
+}
+
+// Records returns tracked searches for a network (all networks when empty), newest-first
+func (t *PathSearchTracker) Records(networkID string) []PathSearchRecord {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	// Walk backwards so searches sharing a timestamp still come out newest-first
+	records := make([]PathSearchRecord, 0, len(t.records))
+	for i := len(t.records) - 1; i >= 0; i-- {
+		if record := t.records[i]; networkID == "" || record.NetworkID == networkID {
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.After(records[j].Timestamp)
+	})
+	return records
+}
+
+// describe renders a record as "src -> dst:port @ snapshot [OUTCOMES]"
+func (r PathSearchRecord) describe() string {
+	destination := r.DstIP
+	if r.DstPort != "" {
+		destination += ":" + r.DstPort
+	}
+
+	outcomes := make([]string, 0, len(r.Outcomes))
+	for _, outcome := range r.Outcomes {
+		outcomes = append(outcomes, string(outcome))
+	}
+	if len(outcomes) == 0 {
+		outcomes = append(outcomes, "NO_PATHS")
+	}
+
+	return fmt.Sprintf("%s -> %s @ %s [%s]", r.Source, destination, r.SnapshotID, strings.Join(outcomes, ", "))
+}
+
+// getPathSearchHistory lists tracked path searches for a network, newest first
+func (s *ForwardMCPService) getPathSearchHistory(args GetPathSearchHistoryArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("get_path_search_history", args, nil)
+
+	pathSearches := s.active().pathSearches
+	if pathSearches == nil {
+		return mcp.NewToolResponse(mcp.NewTextContent("Path search history is not available: search tracking is disabled.")), nil
+	}
+
+	networkID := args.NetworkID
+	if networkID == "all" {
+		networkID = ""
+	} else {
+		networkID = s.getNetworkID(networkID)
+	}
+	scope := "all networks"
+	if networkID != "" {
+		scope = "network " + networkID
+	}
+
+	records := pathSearches.Records(networkID)
+	if len(records) == 0 {
+		return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("No path searches have been run for %s yet. Use search_paths to check reachability.", scope))), nil
+	}
+
+	total := len(records)
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultPathSearchHistoryLimit
+	}
+	if len(records) > limit {
+		records = records[:limit]
+	}
+
+	if s.summaryMode() {
+		identifiers := make([]string, 0, len(records))
+		for _, record := range records {
+			identifiers = append(identifiers, record.describe())
+		}
+		header := fmt.Sprintf("Found %d path searches for %s (showing %d)", total, scope, len(records))
+		return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, identifiers, records, args.Pretty))), nil
+	}
+
+	response := fmt.Sprintf("Found %d path searches for %s (showing %d, newest first):\n", total, scope, len(records))
+	for _, record := range records {
+		response += fmt.Sprintf("• %s  %s (%d paths)\n", record.Timestamp.Format(time.RFC3339), record.describe(), record.PathCount)
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestPathSearchTracker_RepeatedSearchesAreDistinct(t *testing.T) {
+	service := createTestService()
+	service.active().pathSearches = NewPathSearchTracker(10)
+
+	args := SearchPathsArgs{NetworkID: "162112", SrcIP: "10.0.0.1", DstIP: "10.0.0.2", SnapshotID: "snapshot-123"}
+	for i := 0; i < 2; i++ {
+		if _, err := service.searchPaths(args); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	records := service.active().pathSearches.Records("162112")
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 tracked path searches, got %d", len(records))
+	}
+	if records[0].Key == records[1].Key {
+		t.Errorf("Expected distinct keys for repeated searches, both were %q", records[0].Key)
+	}
+	for _, record := range records {
+		if !contains(record.Key, "path_search_10.0.0.1_to_10.0.0.2_") {
+			t.Errorf("Expected key to identify endpoints, got %q", record.Key)
+		}
+	}
+}
+
+func TestPathSearchTracker_NewestFirstAndBounded(t *testing.T) {
+	tracker := NewPathSearchTracker(2)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := 0
+	tracker.now = func() time.Time {
+		tick++
+		return base.Add(time.Duration(tick) * time.Minute)
+	}
+
+	response := &forward.PathSearchResponse{Paths: []forward.Path{{Outcome: "DELIVERED"}}}
+	tracker.Track("net-1", "snap-1", SearchPathsArgs{DstIP: "10.0.0.1"}, response)
+	tracker.Track("net-2", "snap-2", SearchPathsArgs{DstIP: "10.0.0.2"}, response)
+	tracker.Track("net-1", "snap-3", SearchPathsArgs{DstIP: "10.0.0.3"}, response)
+
+	all := tracker.Records("")
+	if len(all) != 2 {
+		t.Fatalf("Expected history capped at 2 records, got %d", len(all))
+	}
+	if all[0].DstIP != "10.0.0.3" || all[1].DstIP != "10.0.0.2" {
+		t.Errorf("Expected newest-first order, got %s then %s", all[0].DstIP, all[1].DstIP)
+	}
+
+	net1 := tracker.Records("net-1")
+	if len(net1) != 1 || net1[0].Outcomes[0] != OutcomeDelivered || net1[0].Source != "any" {
+		t.Errorf("Expected one classified net-1 record with source 'any', got %+v", net1)
+	}
+}
+
+func TestGetPathSearchHistoryListsTrackedSearches(t *testing.T) {
+	service := createTestService()
+	service.active().pathSearches = NewPathSearchTracker(10)
+
+	searches := []SearchPathsArgs{
+		{NetworkID: "162112", SrcIP: "10.0.0.1", DstIP: "10.0.0.2", DstPort: "443"},
+		{NetworkID: "162112", SrcIP: "10.0.0.5", DstIP: "10.0.0.9"},
+	}
+	for _, args := range searches {
+		if _, err := service.searchPaths(args); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	response, err := service.getPathSearchHistory(GetPathSearchHistoryArgs{NetworkID: "162112"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	content := response.Content[0].TextContent.Text
+
+	if !contains(content, "Found 2 path searches for network 162112") {
+		t.Errorf("Expected both searches to be counted, got:\n%s", content)
+	}
+	newer := strings.Index(content, "10.0.0.5 -> 10.0.0.9")
+	older := strings.Index(content, "10.0.0.1 -> 10.0.0.2:443")
+	if newer < 0 || older < 0 || newer > older {
+		t.Errorf("Expected both searches listed newest-first, got:\n%s", content)
+	}
+	for _, record := range service.active().pathSearches.Records("162112") {
+		if !contains(content, record.describe()) {
+			t.Errorf("Expected outcomes %v for %s, got:\n%s", record.Outcomes, record.DstIP, content)
+		}
+	}
+}
+
+func TestGetPathSearchHistoryWithoutTracker(t *testing.T) {
+	service := createTestService()
+	service.active().pathSearches = nil
+
+	response, err := service.getPathSearchHistory(GetPathSearchHistoryArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error with a nil tracker, got: %v", err)
+	}
+	if !contains(response.Content[0].TextContent.Text, "not available") {
+		t.Errorf("Expected an explanation that history is unavailable, got: %s", response.Content[0].TextContent.Text)
+	}
+}
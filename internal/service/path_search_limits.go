@@ -0,0 +1,63 @@
+package service
+
+import "github.com/forward-mcp/internal/forward"
+
+// Built-in path search limits, matching the Forward API's own defaults
+const (
+	defaultPathMaxCandidates        = 5000
+	defaultPathMaxResults           = 1
+	defaultPathMaxReturnPathResults = 0
+	defaultPathMaxSeconds           = 30
+)
+
+// PathSearchLimits are the limits applied to a path search when the call leaves them unset
+type PathSearchLimits struct {
+	MaxCandidates        int `json:"max_candidates"`
+	MaxResults           int `json:"max_results"`
+	MaxReturnPathResults int `json:"max_return_path_results"`
+	MaxSeconds           int `json:"max_seconds"`
+}
+
+// pathSearchDefaults returns the service's path search limits, falling back to the
+// built-in defaults for any that are not configured
+func (s *ForwardMCPService) pathSearchDefaults() PathSearchLimits {
+	limits := PathSearchLimits{
+		MaxCandidates:        defaultPathMaxCandidates,
+		MaxResults:           defaultPathMaxResults,
+		MaxReturnPathResults: defaultPathMaxReturnPathResults,
+		MaxSeconds:           defaultPathMaxSeconds,
+	}
+	if s.defaults == nil {
+		return limits
+	}
+	if s.defaults.PathMaxCandidates > 0 {
+		limits.MaxCandidates = s.defaults.PathMaxCandidates
+	}
+	if s.defaults.PathMaxResults > 0 {
+		limits.MaxResults = s.defaults.PathMaxResults
+	}
+	if s.defaults.PathMaxReturnPathResults > 0 {
+		limits.MaxReturnPathResults = s.defaults.PathMaxReturnPathResults
+	}
+	if s.defaults.PathMaxSeconds > 0 {
+		limits.MaxSeconds = s.defaults.PathMaxSeconds
+	}
+	return limits
+}
+
+// applyPathSearchDefaults fills the limits a path search request leaves unset
+func (s *ForwardMCPService) applyPathSearchDefaults(params *forward.PathSearchParams) {
+	limits := s.pathSearchDefaults()
+	if params.MaxCandidates == 0 {
+		params.MaxCandidates = limits.MaxCandidates
+	}
+	if params.MaxResults == 0 {
+		params.MaxResults = limits.MaxResults
+	}
+	if params.MaxReturnPathResults == 0 {
+		params.MaxReturnPathResults = limits.MaxReturnPathResults
+	}
+	if params.MaxSeconds == 0 {
+		params.MaxSeconds = limits.MaxSeconds
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// recordingPathClient records the parameters of the last path search
+type recordingPathClient struct {
+	*MockForwardClient
+	params *forward.PathSearchParams
+}
+
+func (c *recordingPathClient) SearchPaths(networkID string, params *forward.PathSearchParams) (*forward.PathSearchResponse, error) {
+	c.params = params
+	return c.MockForwardClient.SearchPaths(networkID, params)
+}
+
+func TestSearchPathsForwardsLimits(t *testing.T) {
+	service := createTestService()
+	client := &recordingPathClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+
+	_, err := service.searchPaths(SearchPathsArgs{
+		NetworkID:            "162112",
+		DstIP:                "10.0.1.1",
+		SnapshotID:           "snapshot-123",
+		MaxResults:           3,
+		MaxCandidates:        20000,
+		MaxReturnPathResults: 2,
+		MaxSeconds:           90,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if p := client.params; p.MaxResults != 3 || p.MaxCandidates != 20000 || p.MaxReturnPathResults != 2 || p.MaxSeconds != 90 {
+		t.Errorf("Expected explicit limits to be forwarded, got %+v", p)
+	}
+}
+
+func TestSearchPathsAppliesDefaultLimits(t *testing.T) {
+	service := createTestService()
+	client := &recordingPathClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+
+	if _, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.1.1", SnapshotID: "snapshot-123"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if p := client.params; p.MaxResults != defaultPathMaxResults || p.MaxCandidates != defaultPathMaxCandidates || p.MaxSeconds != defaultPathMaxSeconds {
+		t.Errorf("Expected built-in defaults when unset, got %+v", p)
+	}
+
+	service.defaults.PathMaxCandidates = 10000
+	service.defaults.PathMaxSeconds = 60
+	if _, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.1.1", SnapshotID: "snapshot-123", MaxSeconds: 45}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if p := client.params; p.MaxCandidates != 10000 || p.MaxSeconds != 45 || p.MaxResults != defaultPathMaxResults {
+		t.Errorf("Expected service defaults for unset limits and the explicit max_seconds, got %+v", p)
+	}
+
+	if _, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.1.1", MaxCandidates: -1}); err == nil {
+		t.Error("Expected error for a negative max_candidates")
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// resolveDeviceName maps a user-supplied device reference to the device name the path
+// search API expects. An exact name passes through unchanged; otherwise the reference
+// is matched case-insensitively against names, hostnames (with or without domain), and
+// management IPs.
+func resolveDeviceName(devices []forward.Device, reference string) (string, error) {
+	for _, device := range devices {
+		if device.Name == reference {
+			return reference, nil
+		}
+	}
+
+	var matches []string
+	for _, device := range devices {
+		if deviceMatchesReference(device, reference) {
+			matches = append(matches, device.Name)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", fmt.Errorf("device %q not found; use list_devices to see device names", reference)
+	default:
+		return "", fmt.Errorf("device %q is ambiguous, it matches %s; use the exact device name", reference, strings.Join(matches, ", "))
+	}
+}
+
+// deviceMatchesReference reports whether reference names device by name, hostname, or
+// management IP
+func deviceMatchesReference(device forward.Device, reference string) bool {
+	if strings.EqualFold(device.Name, reference) || strings.EqualFold(device.Hostname, reference) {
+		return true
+	}
+	if short, _, found := strings.Cut(device.Hostname, "."); found && strings.EqualFold(short, reference) {
+		return true
+	}
+	for _, ip := range device.ManagementIPs {
+		if ip == reference {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePathSource resolves search_paths' from argument against the network's devices.
+// If the inventory cannot be fetched the value is passed through so the API can judge it.
+func (s *ForwardMCPService) resolvePathSource(networkID, snapshotID, from string) (string, error) {
+	devices, err := s.fetchAllDevices(networkID, snapshotID)
+	if err != nil {
+		s.logger.Warn("Could not resolve path search source %q, passing it through: %v", from, err)
+		return from, nil
+	}
+
+	name, err := resolveDeviceName(devices, from)
+	if err != nil {
+		return "", fmt.Errorf("invalid from in network %s: %w", networkID, err)
+	}
+	if name != from {
+		s.logger.Debug("Resolved path search source %q to device %s", from, name)
+	}
+	return name, nil
+}
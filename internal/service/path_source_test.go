@@ -0,0 +1,33 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchPathsResolvesFromDevice(t *testing.T) {
+	service := createTestService()
+	client := &recordingPathClient{MockForwardClient: service.client().(*MockForwardClient)}
+	service.active().client = client
+
+	for _, from := range []string{"router-1", "ROUTER-1", "rtr1.example.com", "rtr1", "192.168.1.1"} {
+		if _, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.0.1", From: from}); err != nil {
+			t.Fatalf("Expected from %q to resolve, got: %v", from, err)
+		}
+		if client.params.From != "router-1" {
+			t.Errorf("Expected from %q to be sent as router-1, got %q", from, client.params.From)
+		}
+	}
+}
+
+func TestSearchPathsRejectsUnknownFromDevice(t *testing.T) {
+	service := createTestService()
+
+	_, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "10.0.0.1", From: "core-99"})
+	if err == nil {
+		t.Fatal("Expected an error for an unknown source device")
+	}
+	if !strings.Contains(err.Error(), `device "core-99" not found`) || !strings.Contains(err.Error(), "list_devices") {
+		t.Errorf("Expected a clear not-found error, got: %v", err)
+	}
+}
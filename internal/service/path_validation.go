@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// maxIPProto is the largest valid IP protocol number
+const maxIPProto = 255
+
+// validateSearchPathsArgs checks and normalizes path search arguments so malformed
+// IPs, ports, and protocols are rejected before reaching the Forward API
+func validateSearchPathsArgs(args *SearchPathsArgs) error {
+	var err error
+
+	args.DstIP = strings.TrimSpace(args.DstIP)
+	if args.DstIP == "" {
+		return fmt.Errorf("dst_ip is required")
+	}
+	if args.DstIP, err = normalizeIPOrCIDR("dst_ip", args.DstIP); err != nil {
+		return err
+	}
+
+	if args.SrcIP = strings.TrimSpace(args.SrcIP); args.SrcIP != "" {
+		if args.SrcIP, err = normalizeIPOrCIDR("src_ip", args.SrcIP); err != nil {
+			return err
+		}
+	}
+
+	if args.SrcPort, err = normalizePortSpec("src_port", args.SrcPort); err != nil {
+		return err
+	}
+	if args.DstPort, err = normalizePortSpec("dst_port", args.DstPort); err != nil {
+		return err
+	}
+
+	if args.IPProto < 0 || args.IPProto > maxIPProto {
+		return fmt.Errorf("invalid ip_proto %d: must be between 0 and %d", args.IPProto, maxIPProto)
+	}
+
+	limits := []struct {
+		field string
+		value int
+	}{
+		{"max_results", args.MaxResults},
+		{"max_candidates", args.MaxCandidates},
+		{"max_return_path_results", args.MaxReturnPathResults},
+		{"max_seconds", args.MaxSeconds},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return fmt.Errorf("invalid %s %d: must not be negative", limit.field, limit.value)
+		}
+	}
+
+	return nil
+}
+
+// normalizeIPOrCIDR validates a single IP address or CIDR subnet and returns its canonical form
+func normalizeIPOrCIDR(field, value string) (string, error) {
+	if strings.Contains(value, "/") {
+		ip, subnet, err := net.ParseCIDR(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s %q: not a valid IP address or CIDR subnet", field, value)
+		}
+		prefix, _ := subnet.Mask.Size()
+		return fmt.Sprintf("%s/%d", ip.String(), prefix), nil
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return "", fmt.Errorf("invalid %s %q: not a valid IP address or CIDR subnet", field, value)
+	}
+	return ip.String(), nil
+}
+
+// normalizePortSpec validates a single port ("80") or an ascending range ("8080-8088")
+func normalizePortSpec(field, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	low, high, isRange := strings.Cut(value, "-")
+	lowPort, err := parsePort(field, value, low)
+	if err != nil {
+		return "", err
+	}
+	if !isRange {
+		return strconv.Itoa(lowPort), nil
+	}
+
+	highPort, err := parsePort(field, value, high)
+	if err != nil {
+		return "", err
+	}
+	if lowPort > highPort {
+		return "", fmt.Errorf("invalid %s %q: port range must be ascending (got %d-%d)", field, value, lowPort, highPort)
+	}
+	if lowPort == highPort {
+		return strconv.Itoa(lowPort), nil
+	}
+	return fmt.Sprintf("%d-%d", lowPort, highPort), nil
+}
+
+// parsePort parses one port number and checks it is within 0-65535
+func parsePort(field, spec, part string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(part))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: expected a port number or range like 8080-8088", field, spec)
+	}
+	if port < 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid %s %q: port %d is out of range 0-65535", field, spec, port)
+	}
+	return port, nil
+}
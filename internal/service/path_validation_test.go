@@ -0,0 +1,90 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSearchPathsArgs(t *testing.T) {
+	testCases := []struct {
+		name        string
+		args        SearchPathsArgs
+		expectedErr string
+	}{
+		{
+			name: "valid IPs and ports",
+			args: SearchPathsArgs{SrcIP: "10.0.0.1", DstIP: "10.1.0.0/16", SrcPort: "1024-2048", DstPort: "443", IPProto: 6},
+		},
+		{
+			name:        "invalid destination IP",
+			args:        SearchPathsArgs{DstIP: "8.8.8"},
+			expectedErr: `invalid dst_ip "8.8.8"`,
+		},
+		{
+			name:        "invalid source CIDR",
+			args:        SearchPathsArgs{SrcIP: "10.0.0.0/33", DstIP: "8.8.8.8"},
+			expectedErr: `invalid src_ip "10.0.0.0/33"`,
+		},
+		{
+			name:        "reversed port range",
+			args:        SearchPathsArgs{DstIP: "8.8.8.8", DstPort: "80-70"},
+			expectedErr: "port range must be ascending",
+		},
+		{
+			name:        "port out of range",
+			args:        SearchPathsArgs{DstIP: "8.8.8.8", SrcPort: "70000"},
+			expectedErr: "out of range 0-65535",
+		},
+		{
+			name:        "non-numeric port",
+			args:        SearchPathsArgs{DstIP: "8.8.8.8", DstPort: "http"},
+			expectedErr: `invalid dst_port "http"`,
+		},
+		{
+			name:        "protocol out of range",
+			args:        SearchPathsArgs{DstIP: "8.8.8.8", IPProto: 300},
+			expectedErr: "invalid ip_proto 300",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSearchPathsArgs(&tc.args)
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateSearchPathsArgsNormalizes(t *testing.T) {
+	args := SearchPathsArgs{DstIP: " 10.1.2.3/16 ", SrcIP: "10.0.0.1", DstPort: " 8080 - 8088 ", SrcPort: "53-53"}
+	if err := validateSearchPathsArgs(&args); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if args.DstIP != "10.1.2.3/16" {
+		t.Errorf("Expected trimmed destination, got %q", args.DstIP)
+	}
+	if args.DstPort != "8080-8088" {
+		t.Errorf("Expected normalized port range, got %q", args.DstPort)
+	}
+	if args.SrcPort != "53" {
+		t.Errorf("Expected single-port range to collapse to 53, got %q", args.SrcPort)
+	}
+}
+
+func TestSearchPathsRejectsInvalidArgsBeforeAPICall(t *testing.T) {
+	service := createTestService()
+
+	_, err := service.searchPaths(SearchPathsArgs{NetworkID: "162112", DstIP: "8.8.8", SnapshotID: "snap-1"})
+	if err == nil || !strings.Contains(err.Error(), "invalid path search arguments") {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// IndexReloadResult summarizes how a spec reload changed the query index
+type IndexReloadResult struct {
+	Total               int `json:"total"`
+	Added               int `json:"added"`
+	Removed             int `json:"removed"`
+	Changed             int `json:"changed"`
+	EmbeddingsPreserved int `json:"embeddings_preserved"`
+	EmbeddingsFromCache int `json:"embeddings_from_cache"`
+	MissingEmbeddings   int `json:"missing_embeddings"`
+}
+
+// ReloadFromSpec re-reads the spec file under the write lock. Queries are matched by ID:
+// a query whose embedding text is unchanged keeps its embedding, while added and changed
+// queries take an embedding from the cache file when one was generated from their current
+// text. Searches see either the old or the new index, never a mix.
+func (idx *NQEQueryIndex) ReloadFromSpec() (IndexReloadResult, error) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	var result IndexReloadResult
+	queries, err := idx.readSpecFile()
+	if err != nil {
+		return result, err
+	}
+
+	previous := make(map[string]*NQEQueryIndexEntry, len(idx.queries))
+	for _, query := range idx.queries {
+		previous[query.QueryID] = query
+	}
+
+	var cached map[string][]float32
+	if data, err := os.ReadFile(idx.embeddingsCachePath); err == nil {
+		if err := json.Unmarshal(data, &cached); err != nil {
+			idx.logger.Debug("Ignoring unreadable embeddings cache during reload: %v", err)
+		}
+	}
+	hashes, _ := idx.readTextHashes()
+
+	embeddings := make(map[string][]float32, len(queries))
+	seen := make(map[string]bool, len(queries))
+	for _, query := range queries {
+		seen[query.QueryID] = true
+		old, existed := previous[query.QueryID]
+		switch {
+		case !existed:
+			result.Added++
+		case old.Path != query.Path || old.Code != query.Code:
+			result.Changed++
+		}
+
+		if existed && embeddingText(old) == embeddingText(query) && len(old.Embedding) > 0 {
+			query.Embedding = old.Embedding
+			result.EmbeddingsPreserved++
+		} else if embedding, ok := cached[query.Path]; ok && !staleText(hashes, query) &&
+			(idx.embeddingTag.Dimensions == 0 || len(embedding) == idx.embeddingTag.Dimensions) {
+			query.Embedding = embedding
+			result.EmbeddingsFromCache++
+		} else {
+			result.MissingEmbeddings++
+		}
+		if len(query.Embedding) > 0 {
+			embeddings[query.QueryID] = query.Embedding
+		}
+	}
+	for id := range previous {
+		if !seen[id] {
+			result.Removed++
+		}
+	}
+
+	idx.queries = queries
+	idx.embeddings = embeddings
+	idx.spilled = nil
+	result.Total = len(queries)
+	if err := idx.enforceEmbeddingCap(); err != nil {
+		idx.logger.Warn("Keeping all embeddings in memory: %v", err)
+	}
+
+	idx.logger.Info("Reloaded NQE query index: %d queries (%d added, %d removed, %d changed)",
+		result.Total, result.Added, result.Removed, result.Changed)
+	return result, nil
+}
+
+// reloadQueryIndex refreshes the query index from the spec file without a restart
+func (s *ForwardMCPService) reloadQueryIndex(args ReloadQueryIndexArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("reload_query_index", args, nil)
+
+	if s.queryIndex == nil {
+		return nil, fmt.Errorf("query index is not available")
+	}
+
+	result, err := s.queryIndex.ReloadFromSpec()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload query index: %w", err)
+	}
+
+	response := fmt.Sprintf("🔄 **Query index reloaded**: %d queries\n", result.Total)
+	response += fmt.Sprintf("• Added: %d\n• Removed: %d\n• Changed: %d\n", result.Added, result.Removed, result.Changed)
+	response += fmt.Sprintf("• Embeddings: %d preserved, %d loaded from cache, %d missing\n",
+		result.EmbeddingsPreserved, result.EmbeddingsFromCache, result.MissingEmbeddings)
+	if result.MissingEmbeddings > 0 {
+		response += "\n💡 Missing embeddings fall back to keyword search. Run `initialize_query_index` with `generate_embeddings: true` to fill them in.\n"
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forward-mcp/internal/logger"
+)
+
+// writeTestSpec writes an NQE library spec with the given queryId -> path entries
+func writeTestSpec(t *testing.T, path string, queries map[string]string) {
+	t.Helper()
+	var library struct {
+		Queries []NQEQueryIndexEntry `json:"queries"`
+	}
+	for id, queryPath := range queries {
+		library.Queries = append(library.Queries, NQEQueryIndexEntry{QueryID: id, Path: queryPath})
+	}
+	data, err := json.Marshal(library)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadQueryIndexKeepsEmbeddings(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "NQELibrary.json")
+	writeTestSpec(t, specPath, map[string]string{
+		"FQ_bgp":  "/L3/BGP/BGP Neighbor State",
+		"FQ_ospf": "/L3/OSPF/OSPF Adjacencies",
+	})
+
+	idx := NewNQEQueryIndex(NewKeywordEmbeddingService(), logger.New())
+	idx.indexPath = specPath
+	idx.embeddingsCachePath = filepath.Join(dir, "nqe-embeddings.json")
+	if err := idx.LoadFromSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	if err := idx.GenerateEmbeddings(); err != nil {
+		t.Fatalf("Failed to generate embeddings: %v", err)
+	}
+	before := idx.embeddings["FQ_bgp"]
+
+	writeTestSpec(t, specPath, map[string]string{
+		"FQ_bgp":  "/L3/BGP/BGP Neighbor State",
+		"FQ_ospf": "/L3/OSPF/OSPF Adjacencies",
+		"FQ_acl":  "/Security/ACL/Permit Any Rules",
+	})
+	service := &ForwardMCPService{queryIndex: idx, logger: logger.New()}
+	response, err := service.reloadQueryIndex(ReloadQueryIndexArgs{})
+	if err != nil {
+		t.Fatalf("Expected reload to succeed, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	for _, expected := range []string{"3 queries", "Added: 1", "Removed: 0", "Changed: 0", "2 preserved", "1 missing"} {
+		if !contains(content, expected) {
+			t.Errorf("Expected reload summary to contain %q, got:\n%s", expected, content)
+		}
+	}
+	if len(idx.Queries()) != 3 {
+		t.Errorf("Expected 3 queries after reload, got %d", len(idx.Queries()))
+	}
+	after, ok := idx.embeddings["FQ_bgp"]
+	if !ok || len(after) != len(before) || &after[0] != &before[0] {
+		t.Error("Expected the unchanged query to keep its embedding")
+	}
+	if _, ok := idx.embeddings["FQ_acl"]; ok {
+		t.Error("Expected the new query to have no embedding until generation runs")
+	}
+}
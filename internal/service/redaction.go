@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// redactedMarker replaces sensitive values in redacted output
+const redactedMarker = "[REDACTED]"
+
+// DefaultRedactionPatterns match common secrets found in device configs and API payloads.
+// When a pattern has capture groups, the first group is kept as context and the rest of
+// the match is replaced; otherwise the whole match is replaced.
+var DefaultRedactionPatterns = []string{
+	`(?i)(\bsnmp-server\s+community\s+)\S+`,
+	`(?i)(\bsnmp-community\s+)\S+`,
+	`(?i)(\b(?:password|passwd|secret|key-string|pre-shared-key|passphrase)\s+(?:\d\s+)?)\S+`,
+	`(?i)("(?:password|passwd|secret|apiKey|api_key|apiSecret|api_secret|token|community)"\s*:\s*")[^"]*`,
+	`(?i)(\b(?:Basic|Bearer)\s+)[A-Za-z0-9+/=._-]{8,}`,
+}
+
+// Redactor scrubs sensitive values from text using a list of regular expressions
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles the given patterns into a redactor
+func NewRedactor(patterns []string) (*Redactor, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &Redactor{patterns: compiled}, nil
+}
+
+// Redact returns text with every pattern match masked. A nil redactor returns text unchanged.
+func (r *Redactor) Redact(text string) string {
+	if r == nil {
+		return text
+	}
+	for _, re := range r.patterns {
+		if re.NumSubexp() > 0 {
+			text = re.ReplaceAllString(text, "${1}"+redactedMarker)
+		} else {
+			text = re.ReplaceAllString(text, redactedMarker)
+		}
+	}
+	return text
+}
+
+// RedactResponse masks sensitive values in every text item of a tool response
+func (r *Redactor) RedactResponse(response *mcp.ToolResponse) {
+	if r == nil || response == nil {
+		return
+	}
+	for _, content := range response.Content {
+		if content != nil && content.TextContent != nil {
+			content.TextContent.Text = r.Redact(content.TextContent.Text)
+		}
+	}
+}
+
+// newRedactorFromConfig builds the redactor for the service, or nil when redaction is off
+func newRedactorFromConfig(enabled bool, extraPatterns []string) (*Redactor, error) {
+	if !enabled {
+		return nil, nil
+	}
+	patterns := append(append([]string{}, DefaultRedactionPatterns...), extraPatterns...)
+	return NewRedactor(patterns)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// Test that the default patterns scrub common device secrets
+func TestRedactorDefaultPatterns(t *testing.T) {
+	redactor, err := NewRedactor(DefaultRedactionPatterns)
+	if err != nil {
+		t.Fatalf("Failed to compile default patterns: %v", err)
+	}
+
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"snmp-community public", "snmp-community [REDACTED]"},
+		{"snmp-server community s3cr3t RO", "snmp-server community [REDACTED] RO"},
+		{"username admin password 7 0822455D0A16", "username admin password 7 [REDACTED]"},
+		{"enable secret 5 $1$mERr$hx5rVt7rPNoS4wqbXKX7m0", "enable secret 5 [REDACTED]"},
+		{`{"apiSecret": "abc123", "name": "core"}`, `{"apiSecret": "[REDACTED]", "name": "core"}`},
+		{"Authorization: Basic dGVzdDpzZWNyZXQ=", "Authorization: Basic [REDACTED]"},
+		{"interface Ethernet1 description uplink", "interface Ethernet1 description uplink"},
+	}
+
+	for _, tc := range testCases {
+		if got := redactor.Redact(tc.input); got != tc.expected {
+			t.Errorf("Redact(%q) = %q, expected %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
+// Test that custom patterns are applied and invalid ones are rejected
+func TestRedactorCustomPatterns(t *testing.T) {
+	redactor, err := newRedactorFromConfig(true, []string{`(?i)(tacacs-server key\s+)\S+`, `internal-\d+`})
+	if err != nil {
+		t.Fatalf("Failed to compile patterns: %v", err)
+	}
+
+	got := redactor.Redact("tacacs-server key hunter2 on internal-42")
+	if expected := "tacacs-server key [REDACTED] on [REDACTED]"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	if _, err := NewRedactor([]string{"("}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+
+	if redactor, _ := newRedactorFromConfig(false, nil); redactor != nil {
+		t.Error("Expected no redactor when redaction is disabled")
+	}
+}
+
+// Test that tool responses pass through redaction in the middleware
+func TestToolMiddlewareRedactsResponses(t *testing.T) {
+	service := createTestService()
+	handler := func(_ *ForwardMCPService, args ListNetworksArgs) (*mcp.ToolResponse, error) {
+		return mcp.NewToolResponse(mcp.NewTextContent("snmp-server community public RO\nsnmp-community public")), nil
+	}
+
+	// Redaction off: response is untouched
+	response, _ := withToolMiddleware(service, "test_tool", handler)(ListNetworksArgs{})
+	if !contains(response.Content[0].TextContent.Text, "community public") {
+		t.Errorf("Expected unredacted response, got: %s", response.Content[0].TextContent.Text)
+	}
+
+	// Redaction on: secrets are masked
+	service.redactor, _ = newRedactorFromConfig(true, nil)
+	response, _ = withToolMiddleware(service, "test_tool", handler)(ListNetworksArgs{})
+	text := response.Content[0].TextContent.Text
+	if contains(text, "public") {
+		t.Errorf("Expected community strings to be redacted, got: %s", text)
+	}
+	if !contains(text, "snmp-community [REDACTED]") {
+		t.Errorf("Expected redaction marker, got: %s", text)
+	}
+}
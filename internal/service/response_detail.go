@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// Response detail levels for tool output
+const (
+	// ResponseDetailFull returns complete JSON payloads (the default)
+	ResponseDetailFull = "full"
+	// ResponseDetailSummary returns counts and key identifiers only, to conserve tokens
+	ResponseDetailSummary = "summary"
+)
+
+// summaryIdentifierLimit caps how many identifiers a summary response names
+const summaryIdentifierLimit = 5
+
+// summaryMode reports whether tools should return compact summaries instead of full JSON
+func (s *ForwardMCPService) summaryMode() bool {
+	return s.defaults != nil && s.defaults.ResponseDetail == ResponseDetailSummary
+}
+
+// responseDetail returns the effective response detail level
+func (s *ForwardMCPService) responseDetail() string {
+	if s.summaryMode() {
+		return ResponseDetailSummary
+	}
+	return ResponseDetailFull
+}
+
+// summarizeIdentifiers renders the first few identifiers, noting how many were omitted
+func summarizeIdentifiers(identifiers []string) string {
+	if len(identifiers) <= summaryIdentifierLimit {
+		return strings.Join(identifiers, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(identifiers[:summaryIdentifierLimit], ", "), len(identifiers)-summaryIdentifierLimit)
+}
+
+// formatDetail renders header followed by payload as JSON in full mode, or header
+// followed by the top identifiers in summary mode. pretty overrides the JSON format.
+func (s *ForwardMCPService) formatDetail(header string, identifiers []string, payload interface{}, pretty *bool) string {
+	if s.summaryMode() {
+		if len(identifiers) == 0 {
+			return header
+		}
+		return fmt.Sprintf("%s; top: %s", header, summarizeIdentifiers(identifiers))
+	}
+
+	return fmt.Sprintf("%s:\n%s", header, s.toJSON(payload, pretty))
+}
+
+// nqeResultColumns returns the sorted column names present in NQE result items
+func nqeResultColumns(items []map[string]interface{}) []string {
+	columns := make(map[string]bool)
+	for _, item := range items {
+		for column := range item {
+			columns[column] = true
+		}
+	}
+	return sortedKeys(columns)
+}
+
+// setDefaultSettings updates session-wide defaults such as the response detail level
+// and whether NQE results include an inferred schema
+func (s *ForwardMCPService) setDefaultSettings(args SetDefaultSettingsArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("set_default_settings", args, nil)
+
+	var changes []string
+	if args.ResponseDetail != "" {
+		detail := strings.ToLower(strings.TrimSpace(args.ResponseDetail))
+		if detail != ResponseDetailFull && detail != ResponseDetailSummary {
+			return nil, fmt.Errorf("invalid response_detail %q: must be %q or %q", args.ResponseDetail, ResponseDetailFull, ResponseDetailSummary)
+		}
+		s.defaults.ResponseDetail = detail
+		changes = append(changes, fmt.Sprintf("response_detail = %s", detail))
+	}
+
+	if args.IncludeNQESchema != nil {
+		s.defaults.HideNQESchema = !*args.IncludeNQESchema
+		changes = append(changes, fmt.Sprintf("include_nqe_schema = %v", *args.IncludeNQESchema))
+	}
+
+	if args.NormalizeUnits != nil {
+		s.defaults.NormalizeNQEUnits = *args.NormalizeUnits
+		changes = append(changes, fmt.Sprintf("normalize_units = %v", *args.NormalizeUnits))
+	}
+
+	if args.DefaultFormat != nil {
+		s.defaults.DefaultFormat = strings.TrimSpace(*args.DefaultFormat)
+		changes = append(changes, fmt.Sprintf("default_format = %q", s.defaults.DefaultFormat))
+	}
+
+	if len(changes) == 0 {
+		return mcp.NewToolResponse(mcp.NewTextContent("No settings changed. Provide at least one setting, e.g. response_detail: summary.")), nil
+	}
+
+	sort.Strings(changes)
+	response := "Default settings updated for this session:\n"
+	for _, change := range changes {
+		response += fmt.Sprintf("• %s\n", change)
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
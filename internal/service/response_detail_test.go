@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+)
+
+func TestListDevicesSummaryMode(t *testing.T) {
+	service := createTestService()
+
+	// Settings made through the middleware outlive the call's copy of the service
+	setDefaultSettings := withToolMiddleware(service, "set_default_settings", (*ForwardMCPService).setDefaultSettings)
+	if _, err := setDefaultSettings(SetDefaultSettingsArgs{ResponseDetail: "summary"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	response, err := service.listDevices(ListDevicesArgs{NetworkID: "162112"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	content := response.Content[0].TextContent.Text
+	if content != "Found 2 devices (total: 2); top: router-1, switch-1\n"+`{"page":{"returned":2,"offset":0,"has_more":false,"next_offset":2}}` {
+		t.Errorf("Expected compact device summary, got: %s", content)
+	}
+	if contains(content, "\"devices\"") || contains(content, "managementIps") {
+		t.Errorf("Expected summary mode to omit the JSON payload, got: %s", content)
+	}
+
+	// Switching back restores the full JSON output
+	if _, err := setDefaultSettings(SetDefaultSettingsArgs{ResponseDetail: "full"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	response, err = service.listDevices(ListDevicesArgs{NetworkID: "162112"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if content := response.Content[0].TextContent.Text; !contains(content, "\"devices\"") {
+		t.Errorf("Expected full JSON in full mode, got: %s", content)
+	}
+}
+
+func TestSetDefaultSettingsRejectsUnknownDetail(t *testing.T) {
+	service := createTestService()
+
+	if _, err := service.setDefaultSettings(SetDefaultSettingsArgs{ResponseDetail: "verbose"}); err == nil {
+		t.Error("Expected an error for an unknown response_detail")
+	}
+	if service.responseDetail() != ResponseDetailFull {
+		t.Errorf("Expected response detail to stay full, got %s", service.responseDetail())
+	}
+}
+
+func TestSummarizeIdentifiers(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e", "f", "g"}
+	if got := summarizeIdentifiers(ids); got != "a, b, c, d, e (+2 more)" {
+		t.Errorf("Expected truncated identifier list, got %q", got)
+	}
+	if got := summarizeIdentifiers(ids[:2]); got != "a, b" {
+		t.Errorf("Expected full identifier list, got %q", got)
+	}
+}
+
+func TestDefaultFormatAppliedWhenOmitted(t *testing.T) {
+	service := createTestService()
+
+	csv := "csv"
+	if _, err := service.setDefaultSettings(SetDefaultSettingsArgs{DefaultFormat: &csv}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	options, err := service.convertNQEQueryOptions(nil)
+	if err != nil || options.Format != "csv" {
+		t.Errorf("Expected the default format for a call without one, got %q (err: %v)", options.Format, err)
+	}
+
+	options, err = service.convertNQEQueryOptions(&NQEQueryOptions{Format: "markdown"})
+	if err != nil || options.Format != "markdown" {
+		t.Errorf("Expected the per-call format to win, got %q (err: %v)", options.Format, err)
+	}
+
+	// An empty default restores the API default
+	none := ""
+	if _, err := service.setDefaultSettings(SetDefaultSettingsArgs{DefaultFormat: &none}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if options, _ := service.convertNQEQueryOptions(nil); options.Format != "" {
+		t.Errorf("Expected no format after clearing the default, got %q", options.Format)
+	}
+}
@@ -3,9 +3,11 @@ package service
 import (
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,12 +26,21 @@ type CacheEntry struct {
 	NetworkID       string                `json:"network_id"`
 	SnapshotID      string                `json:"snapshot_id"`
 	Embedding       []float64             `json:"embedding"`
+	EmbeddingTag    EmbeddingTag          `json:"embedding_tag"`
 	Result          *forward.NQERunResult `json:"result"`
 	Timestamp       time.Time             `json:"timestamp"`
 	AccessCount     int                   `json:"access_count"`
 	LastAccessed    time.Time             `json:"last_accessed"`
 	Hash            string                `json:"hash"`
 	SimilarityScore float64               `json:"-"` // Used for search results
+
+	// NQE execution details, set for entries cached by NQE tools so they can be replayed
+	QueryID    string                   `json:"query_id,omitempty"`
+	Parameters map[string]interface{}   `json:"parameters,omitempty"`
+	Options    *forward.NQEQueryOptions `json:"options,omitempty"`
+
+	// sizeBytes is the JSON size of Result, counted against the cache's byte budget
+	sizeBytes int
 }
 
 // SemanticCache provides intelligent caching with embedding-based similarity
@@ -43,12 +54,22 @@ type SemanticCache struct {
 	// Configuration
 	maxEntries          int
 	ttl                 time.Duration
+	networkTTLs         map[string]time.Duration // per-network overrides of ttl
 	similarityThreshold float64
+	suggestionFloor     float64 // minimum similarity for FindSimilarQueries suggestions
+
+	// Size limits (0 = unlimited): results above maxEntryRows or maxEntryBytes are not
+	// cached, and entries are evicted to keep totalBytes within maxTotalBytes
+	maxEntryRows  int
+	maxEntryBytes int
+	maxTotalBytes int
+	totalBytes    int
 
 	// Metrics
-	hitCount     int64
-	missCount    int64
-	totalQueries int64
+	hitCount       int64
+	missCount      int64
+	totalQueries   int64
+	oversizedSkips int64
 }
 
 // truncateString safely truncates a string for logging
@@ -68,14 +89,26 @@ func NewSemanticCache(embeddingService EmbeddingService, logger *logger.Logger)
 		logger:              logger,
 		maxEntries:          1000,
 		ttl:                 24 * time.Hour,
+		networkTTLs:         make(map[string]time.Duration),
 		similarityThreshold: 0.85, // 85% similarity threshold
+		suggestionFloor:     defaultSuggestionFloor,
 	}
 }
 
-// generateCacheKey creates a consistent cache key
+// defaultSuggestionFloor is the similarity a cached query needs to be suggested
+const defaultSuggestionFloor = 0.5
+
+// normalizeCacheQuery trims the query and collapses whitespace runs, so queries that
+// differ only in formatting share a cache entry
+func normalizeCacheQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// generateCacheKey creates a consistent cache key from the normalized query text,
+// partitioned by network and snapshot
 func (sc *SemanticCache) generateCacheKey(query, networkID, snapshotID string) string {
 	hasher := md5.New()
-	hasher.Write([]byte(fmt.Sprintf("%s|%s|%s", query, networkID, snapshotID)))
+	hasher.Write([]byte(fmt.Sprintf("%s|%s|%s", normalizeCacheQuery(query), networkID, snapshotID)))
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
@@ -101,8 +134,9 @@ func (sc *SemanticCache) cosineSimilarity(a, b []float64) float64 {
 
 // Get attempts to retrieve a cached result using semantic similarity
 func (sc *SemanticCache) Get(query, networkID, snapshotID string) (*forward.NQERunResult, bool) {
-	sc.mutex.RLock()
-	defer sc.mutex.RUnlock()
+	// Get updates hit counters and access times, so it needs the write lock
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
 
 	sc.totalQueries++
 
@@ -117,15 +151,15 @@ func (sc *SemanticCache) Get(query, networkID, snapshotID string) (*forward.NQER
 	}
 
 	// Generate embedding for semantic search
-	embedding, err := sc.embeddingService.GenerateEmbedding(query)
+	embedding, tag, err := sc.validEmbedding(query)
 	if err != nil {
-		sc.logger.Error("CACHE ERROR: Failed to generate embedding: %v", err)
+		sc.logger.Error("CACHE ERROR: %v", err)
 		sc.missCount++
 		return nil, false
 	}
 
 	// Search for semantically similar queries
-	bestMatch := sc.findBestMatch(embedding, networkID, snapshotID)
+	bestMatch := sc.findBestMatch(embedding, tag, networkID, snapshotID)
 	if bestMatch != nil && bestMatch.SimilarityScore >= sc.similarityThreshold {
 		bestMatch.AccessCount++
 		bestMatch.LastAccessed = time.Now()
@@ -139,50 +173,204 @@ func (sc *SemanticCache) Get(query, networkID, snapshotID string) (*forward.NQER
 	return nil, false
 }
 
+// validEmbedding generates the embedding for query, tagged with the provider that served
+// it, regenerating once if the provider returns a NaN, Inf, or zero-norm vector, which
+// would poison similarity scores
+func (sc *SemanticCache) validEmbedding(query string) ([]float64, EmbeddingTag, error) {
+	for attempt := 0; ; attempt++ {
+		embedding, tag, _, err := generateTaggedEmbedding(sc.embeddingService, query)
+		if err != nil {
+			return nil, EmbeddingTag{}, fmt.Errorf("failed to generate embedding: %w", err)
+		}
+		err = validateEmbedding(embedding)
+		if err == nil {
+			return embedding, tag, nil
+		}
+		if attempt > 0 {
+			return nil, EmbeddingTag{}, fmt.Errorf("embedding for query %q rejected: %w", truncateString(query, 50), err)
+		}
+		sc.logger.Warn("Regenerating embedding for query %q: %v", truncateString(query, 50), err)
+	}
+}
+
 // Put stores a query result in the cache with its embedding
 func (sc *SemanticCache) Put(query, networkID, snapshotID string, result *forward.NQERunResult) error {
 	sc.mutex.Lock()
 	defer sc.mutex.Unlock()
 
-	// Generate embedding
-	embedding, err := sc.embeddingService.GenerateEmbedding(query)
+	embedding, tag, err := sc.validEmbedding(query)
 	if err != nil {
-		return fmt.Errorf("failed to generate embedding: %w", err)
+		return err
 	}
 
 	key := sc.generateCacheKey(query, networkID, snapshotID)
+	now := time.Now()
+
+	size, ok := sc.admit(result, truncateString(query, 50))
+	if !ok {
+		// Never serve the previous result of a query whose latest result was too large
+		sc.removeEntry(key)
+		return nil
+	}
+
+	// A formatting variant of a cached query refreshes that entry instead of duplicating it
+	if existing, exists := sc.entries[key]; exists {
+		existing.Result = result
+		existing.Embedding = embedding
+		existing.EmbeddingTag = tag
+		existing.Timestamp = now
+		existing.LastAccessed = now
+		sc.resize(existing, size)
+		sc.logger.Debug("CACHE PUT: Refreshed existing entry for query: %s", truncateString(query, 50))
+		return nil
+	}
+
 	entry := &CacheEntry{
 		Query:        query,
 		NetworkID:    networkID,
 		SnapshotID:   snapshotID,
 		Embedding:    embedding,
+		EmbeddingTag: tag,
 		Result:       result,
-		Timestamp:    time.Now(),
+		Timestamp:    now,
 		AccessCount:  1,
-		LastAccessed: time.Now(),
+		LastAccessed: now,
 		Hash:         key,
+		sizeBytes:    size,
 	}
 
 	// Check if we need to evict entries
-	if len(sc.entries) >= sc.maxEntries {
-		sc.evictOldest()
-	}
+	sc.makeRoom(size)
 
 	sc.entries[key] = entry
 	sc.embeddingIndex = append(sc.embeddingIndex, entry)
+	sc.totalBytes += size
 
 	sc.logger.Debug("CACHE PUT: Stored result for query: %s", truncateString(query, 50))
 	return nil
 }
 
-// findBestMatch finds the most similar cached query
-func (sc *SemanticCache) findBestMatch(embedding []float64, networkID, snapshotID string) *CacheEntry {
+// NQECacheQuery builds the canonical cache text for an NQE execution. Map keys are
+// marshaled in sorted order, so equal parameters always produce the same text.
+func NQECacheQuery(queryID string, parameters map[string]interface{}, options *forward.NQEQueryOptions) string {
+	paramsJSON, _ := json.Marshal(parameters)
+	optionsJSON, _ := json.Marshal(options)
+	return fmt.Sprintf("nqe:%s params=%s options=%s", queryID, paramsJSON, optionsJSON)
+}
+
+// GetNQEResult looks up a cached NQE execution by exact key. Unlike Get it never falls
+// back to semantic matching, since a similar query ID is a different query.
+func (sc *SemanticCache) GetNQEResult(queryID string, parameters map[string]interface{}, options *forward.NQEQueryOptions, networkID, snapshotID string) (*CacheEntry, bool) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.totalQueries++
+
+	key := sc.generateCacheKey(NQECacheQuery(queryID, parameters, options), networkID, snapshotID)
+	entry, exists := sc.entries[key]
+	if !exists || sc.isExpired(entry) {
+		sc.missCount++
+		return nil, false
+	}
+
+	entry.AccessCount++
+	entry.LastAccessed = time.Now()
+	sc.hitCount++
+	sc.logger.Debug("CACHE HIT: NQE query %s on network %s", queryID, networkID)
+
+	entryCopy := *entry
+	return &entryCopy, true
+}
+
+// PutNQEResult caches an NQE execution under its exact key. NQE entries carry no
+// embedding because they are only ever matched exactly.
+func (sc *SemanticCache) PutNQEResult(queryID string, parameters map[string]interface{}, options *forward.NQEQueryOptions, networkID, snapshotID string, result *forward.NQERunResult) {
+	sc.storeNQEEntry(CacheEntry{
+		NetworkID:   networkID,
+		SnapshotID:  snapshotID,
+		Result:      result,
+		AccessCount: 1,
+		QueryID:     queryID,
+		Parameters:  parameters,
+		Options:     options,
+	})
+}
+
+// storeNQEEntry inserts an NQE entry, or refreshes the result of an existing one while
+// keeping its access count so popular queries stay popular across refreshes
+func (sc *SemanticCache) storeNQEEntry(entry CacheEntry) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	entry.Query = NQECacheQuery(entry.QueryID, entry.Parameters, entry.Options)
+	key := sc.generateCacheKey(entry.Query, entry.NetworkID, entry.SnapshotID)
+	now := time.Now()
+
+	size, ok := sc.admit(entry.Result, "NQE query "+entry.QueryID)
+	if !ok {
+		sc.removeEntry(key)
+		return
+	}
+
+	if existing, exists := sc.entries[key]; exists {
+		existing.Result = entry.Result
+		existing.Timestamp = now
+		sc.resize(existing, size)
+		return
+	}
+
+	sc.makeRoom(size)
+
+	if entry.AccessCount < 1 {
+		entry.AccessCount = 1
+	}
+	entry.Timestamp = now
+	entry.LastAccessed = now
+	entry.Hash = key
+	entry.Embedding = nil
+	entry.sizeBytes = size
+
+	sc.entries[key] = &entry
+	sc.embeddingIndex = append(sc.embeddingIndex, &entry)
+	sc.totalBytes += size
+
+	sc.logger.Debug("CACHE PUT: Stored NQE query %s on network %s", entry.QueryID, entry.NetworkID)
+}
+
+// TopNQEEntries returns copies of up to n cached NQE executions, most-accessed first
+func (sc *SemanticCache) TopNQEEntries(n int) []CacheEntry {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+
+	var entries []CacheEntry
+	for _, entry := range sc.entries {
+		if entry.QueryID != "" {
+			entries = append(entries, *entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].AccessCount != entries[j].AccessCount {
+			return entries[i].AccessCount > entries[j].AccessCount
+		}
+		return entries[i].LastAccessed.After(entries[j].LastAccessed)
+	})
+
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// findBestMatch finds the most similar cached query among entries whose embedding is in
+// the same vector space as embedding
+func (sc *SemanticCache) findBestMatch(embedding []float64, tag EmbeddingTag, networkID, snapshotID string) *CacheEntry {
 	var bestMatch *CacheEntry
 	var bestSimilarity float64
 
 	for _, entry := range sc.embeddingIndex {
-		// Skip expired entries and different networks/snapshots
-		if sc.isExpired(entry) ||
+		// Skip expired entries, other embedding providers, and different networks/snapshots
+		if sc.isExpired(entry) || !entry.EmbeddingTag.matches(tag) ||
 			(networkID != "" && entry.NetworkID != networkID) ||
 			(snapshotID != "" && entry.SnapshotID != snapshotID) {
 			continue
@@ -204,10 +392,138 @@ func (sc *SemanticCache) findBestMatch(embedding []float64, networkID, snapshotI
 
 // isExpired checks if a cache entry has expired
 func (sc *SemanticCache) isExpired(entry *CacheEntry) bool {
-	return time.Since(entry.Timestamp) > sc.ttl
+	return time.Since(entry.Timestamp) > sc.ttlFor(entry.NetworkID)
 }
 
-// evictOldest removes the oldest cache entry
+// ttlFor returns the TTL for a network, honoring per-network overrides
+func (sc *SemanticCache) ttlFor(networkID string) time.Duration {
+	if ttl, exists := sc.networkTTLs[networkID]; exists {
+		return ttl
+	}
+	return sc.ttl
+}
+
+// SetNetworkTTL overrides the cache TTL for entries of one network
+func (sc *SemanticCache) SetNetworkTTL(networkID string, ttl time.Duration) {
+	if sc == nil {
+		return
+	}
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.networkTTLs[networkID] = ttl
+	sc.logger.Debug("CACHE TTL: Network %s TTL set to %s", networkID, ttl)
+}
+
+// SetSuggestionFloor sets the minimum similarity (0-1) for query suggestions
+func (sc *SemanticCache) SetSuggestionFloor(floor float64) {
+	if sc == nil {
+		return
+	}
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.suggestionFloor = floor
+}
+
+// SuggestionFloor returns the minimum similarity for query suggestions
+func (sc *SemanticCache) SuggestionFloor() float64 {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+
+	return sc.suggestionFloor
+}
+
+// NetworkTTL returns the effective cache TTL for a network
+func (sc *SemanticCache) NetworkTTL(networkID string) time.Duration {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+
+	return sc.ttlFor(networkID)
+}
+
+// SetEntryLimits caps the rows and JSON bytes of a single cached result and the JSON bytes
+// of all cached results together; 0 leaves a limit off
+func (sc *SemanticCache) SetEntryLimits(maxRows, maxBytes, maxTotalBytes int) {
+	if sc == nil {
+		return
+	}
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	sc.maxEntryRows = maxRows
+	sc.maxEntryBytes = maxBytes
+	sc.maxTotalBytes = maxTotalBytes
+	for sc.maxTotalBytes > 0 && sc.totalBytes > sc.maxTotalBytes {
+		sc.evictOldest()
+	}
+}
+
+// admit measures result and reports whether it fits the per-entry limits. Results too
+// large for the entry or total budget are counted and not cached, since a truncated
+// result served from the cache would look complete.
+func (sc *SemanticCache) admit(result *forward.NQERunResult, label string) (int, bool) {
+	size := 0
+	if result != nil {
+		if data, err := json.Marshal(result); err == nil {
+			size = len(data)
+		}
+		if sc.maxEntryRows > 0 && len(result.Items) > sc.maxEntryRows {
+			sc.oversizedSkips++
+			sc.logger.Debug("CACHE SKIP: %s returned %d rows, above the %d-row entry limit", label, len(result.Items), sc.maxEntryRows)
+			return 0, false
+		}
+	}
+	if (sc.maxEntryBytes > 0 && size > sc.maxEntryBytes) || (sc.maxTotalBytes > 0 && size > sc.maxTotalBytes) {
+		sc.oversizedSkips++
+		sc.logger.Debug("CACHE SKIP: %s result is %d bytes, above the cache byte limit", label, size)
+		return 0, false
+	}
+	return size, true
+}
+
+// resize updates the byte accounting of a refreshed entry and evicts others if the
+// cache went over its byte budget
+func (sc *SemanticCache) resize(entry *CacheEntry, size int) {
+	sc.totalBytes += size - entry.sizeBytes
+	entry.sizeBytes = size
+	for sc.maxTotalBytes > 0 && sc.totalBytes > sc.maxTotalBytes && len(sc.entries) > 1 {
+		sc.evictOldest()
+	}
+}
+
+// makeRoom evicts the oldest entries until an entry of size bytes fits both the entry
+// count and the byte budget
+func (sc *SemanticCache) makeRoom(size int) {
+	for len(sc.entries) > 0 &&
+		(len(sc.entries) >= sc.maxEntries || (sc.maxTotalBytes > 0 && sc.totalBytes+size > sc.maxTotalBytes)) {
+		sc.evictOldest()
+	}
+}
+
+// removeEntry deletes the entry under key from the map, the embedding index, and the byte
+// accounting, returning whether there was one
+func (sc *SemanticCache) removeEntry(key string) (*CacheEntry, bool) {
+	entry, exists := sc.entries[key]
+	if !exists {
+		return nil, false
+	}
+	delete(sc.entries, key)
+	sc.totalBytes -= entry.sizeBytes
+
+	for i, indexEntry := range sc.embeddingIndex {
+		if indexEntry.Hash == key {
+			sc.embeddingIndex = append(sc.embeddingIndex[:i], sc.embeddingIndex[i+1:]...)
+			break
+		}
+	}
+	return entry, true
+}
+
+// evictOldest removes the least recently accessed cache entry
 func (sc *SemanticCache) evictOldest() {
 	if len(sc.entries) == 0 {
 		return
@@ -218,24 +534,13 @@ func (sc *SemanticCache) evictOldest() {
 	var oldestTime time.Time = time.Now()
 
 	for key, entry := range sc.entries {
-		if entry.LastAccessed.Before(oldestTime) {
+		if oldestKey == "" || entry.LastAccessed.Before(oldestTime) {
 			oldestTime = entry.LastAccessed
 			oldestKey = key
 		}
 	}
 
-	// Remove from both maps
-	if entry, exists := sc.entries[oldestKey]; exists {
-		delete(sc.entries, oldestKey)
-
-		// Remove from embedding index
-		for i, indexEntry := range sc.embeddingIndex {
-			if indexEntry.Hash == oldestKey {
-				sc.embeddingIndex = append(sc.embeddingIndex[:i], sc.embeddingIndex[i+1:]...)
-				break
-			}
-		}
-
+	if entry, removed := sc.removeEntry(oldestKey); removed {
 		sc.logger.Debug("CACHE EVICT: Removed entry for query: %s", truncateString(entry.Query, 50))
 	}
 }
@@ -259,15 +564,33 @@ func (sc *SemanticCache) GetStats() map[string]interface{} {
 		"threshold":        sc.similarityThreshold,
 		"max_entries":      sc.maxEntries,
 		"ttl_hours":        sc.ttl.Hours(),
+		"total_bytes":      sc.totalBytes,
+		"max_total_bytes":  sc.maxTotalBytes,
+		"oversized_skips":  sc.oversizedSkips,
 	}
 }
 
-// FindSimilarQueries returns similar cached queries for query suggestion
+// Counters returns the raw hit, miss, and entry counts for metrics export
+func (sc *SemanticCache) Counters() (hits, misses, entries int64) {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+
+	return sc.hitCount, sc.missCount, int64(len(sc.entries))
+}
+
+// FindSimilarQueries returns similar cached queries for query suggestion, using the
+// cache's suggestion floor
 func (sc *SemanticCache) FindSimilarQueries(query string, limit int) ([]*CacheEntry, error) {
+	return sc.FindSimilarQueriesAbove(query, limit, sc.SuggestionFloor())
+}
+
+// FindSimilarQueriesAbove returns up to limit cached queries whose similarity to query
+// exceeds floor, most similar first
+func (sc *SemanticCache) FindSimilarQueriesAbove(query string, limit int, floor float64) ([]*CacheEntry, error) {
 	sc.mutex.RLock()
 	defer sc.mutex.RUnlock()
 
-	embedding, err := sc.embeddingService.GenerateEmbedding(query)
+	embedding, tag, _, err := generateTaggedEmbedding(sc.embeddingService, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
@@ -275,12 +598,12 @@ func (sc *SemanticCache) FindSimilarQueries(query string, limit int) ([]*CacheEn
 	var similarEntries []*CacheEntry
 
 	for _, entry := range sc.embeddingIndex {
-		if sc.isExpired(entry) {
+		if sc.isExpired(entry) || !entry.EmbeddingTag.matches(tag) {
 			continue
 		}
 
 		similarity := sc.cosineSimilarity(embedding, entry.Embedding)
-		if similarity > 0.5 { // Lower threshold for suggestions
+		if similarity > floor {
 			entryCopy := *entry
 			entryCopy.SimilarityScore = similarity
 			similarEntries = append(similarEntries, &entryCopy)
@@ -300,6 +623,20 @@ func (sc *SemanticCache) FindSimilarQueries(query string, limit int) ([]*CacheEn
 	return similarEntries, nil
 }
 
+// Clear removes every entry and resets the hit and miss counters, keeping the cache's
+// configuration. It returns the number of entries removed.
+func (sc *SemanticCache) Clear() int {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	removed := len(sc.entries)
+	sc.entries = make(map[string]*CacheEntry)
+	sc.embeddingIndex = make([]*CacheEntry, 0)
+	sc.totalBytes = 0
+	sc.hitCount, sc.missCount, sc.totalQueries = 0, 0, 0
+	return removed
+}
+
 // ClearExpired removes all expired entries
 func (sc *SemanticCache) ClearExpired() int {
 	sc.mutex.Lock()
@@ -311,6 +648,7 @@ func (sc *SemanticCache) ClearExpired() int {
 	for key, entry := range sc.entries {
 		if sc.isExpired(entry) {
 			delete(sc.entries, key)
+			sc.totalBytes -= entry.sizeBytes
 			removed++
 		} else {
 			validEntries = append(validEntries, entry)
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -267,6 +270,75 @@ func TestSemanticCacheSimilarQueries(t *testing.T) {
 	t.Logf("Found %d similar queries for unrelated query", len(similarQueries))
 }
 
+func TestSemanticCacheSuggestionFloor(t *testing.T) {
+	cache := NewSemanticCache(NewKeywordEmbeddingService(), createTestLogger())
+	result := &forward.NQERunResult{Items: []map[string]interface{}{{"test": "data"}}}
+	for _, query := range []string{"show bgp neighbors", "show bgp neighbor state", "list interfaces", "device inventory"} {
+		if err := cache.Put(query, "162112", "latest", result); err != nil {
+			t.Fatalf("Failed to put query: %v", err)
+		}
+	}
+
+	if cache.SuggestionFloor() != defaultSuggestionFloor {
+		t.Errorf("Expected default floor %.2f, got %.2f", defaultSuggestionFloor, cache.SuggestionFloor())
+	}
+
+	all, err := cache.FindSimilarQueriesAbove("bgp neighbors", 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to find similar queries: %v", err)
+	}
+	if len(all) < 2 {
+		t.Fatalf("Expected at least 2 suggestions with no floor, got %d", len(all))
+	}
+
+	// Raising the floor to the runner-up's score leaves only closer matches
+	cache.SetSuggestionFloor(all[1].SimilarityScore)
+	strict, err := cache.FindSimilarQueries("bgp neighbors", 10)
+	if err != nil {
+		t.Fatalf("Failed to find similar queries: %v", err)
+	}
+	if len(strict) >= len(all) {
+		t.Errorf("Expected a higher floor to return fewer suggestions, got %d of %d", len(strict), len(all))
+	}
+	for _, entry := range strict {
+		if entry.SimilarityScore <= all[1].SimilarityScore {
+			t.Errorf("Expected only suggestions above the floor, got %.3f", entry.SimilarityScore)
+		}
+	}
+}
+
+func TestSemanticCacheMergesWhitespaceVariants(t *testing.T) {
+	cache := NewSemanticCache(NewMockEmbeddingService(), createTestLogger())
+	first := &forward.NQERunResult{Items: []map[string]interface{}{{"name": "router-1"}}}
+	second := &forward.NQERunResult{Items: []map[string]interface{}{{"name": "switch-1"}}}
+
+	if err := cache.Put("foreach device in network.devices\nselect {name: device.name}", "162112", "latest", first); err != nil {
+		t.Fatalf("Failed to put query: %v", err)
+	}
+	if err := cache.Put("  foreach device in   network.devices select {name: device.name} ", "162112", "latest", second); err != nil {
+		t.Fatalf("Failed to put query: %v", err)
+	}
+	if len(cache.entries) != 1 || len(cache.embeddingIndex) != 1 {
+		t.Fatalf("Expected whitespace variants to share one entry, got %d entries and %d indexed", len(cache.entries), len(cache.embeddingIndex))
+	}
+
+	result, found := cache.Get("foreach device in network.devices select {name: device.name}", "162112", "latest")
+	if !found || result.Items[0]["name"] != "switch-1" {
+		t.Errorf("Expected a hit on the refreshed entry, got found=%v result=%v", found, result)
+	}
+	if cache.hitCount != 1 {
+		t.Errorf("Expected one exact hit, got %d", cache.hitCount)
+	}
+
+	// Partitioning by network and snapshot is preserved
+	if err := cache.Put("foreach device in network.devices select {name: device.name}", "999", "latest", first); err != nil {
+		t.Fatalf("Failed to put query: %v", err)
+	}
+	if len(cache.entries) != 2 {
+		t.Errorf("Expected the same query on another network to get its own entry, got %d entries", len(cache.entries))
+	}
+}
+
 func TestSemanticCacheClearExpired(t *testing.T) {
 	embeddingService := NewMockEmbeddingService()
 	cache := NewSemanticCache(embeddingService, createTestLogger())
@@ -366,3 +438,115 @@ func TestMockEmbeddingService(t *testing.T) {
 func createTestLogger() *logger.Logger {
 	return logger.New()
 }
+
+// fixedEmbeddingService returns the same embedding for every text
+type fixedEmbeddingService struct {
+	embedding []float64
+	calls     int
+}
+
+func (f *fixedEmbeddingService) GenerateEmbedding(text string) ([]float64, error) {
+	f.calls++
+	return f.embedding, nil
+}
+
+func TestSemanticCacheRejectsInvalidEmbeddings(t *testing.T) {
+	result := &forward.NQERunResult{Items: []map[string]interface{}{{"test": "data"}}}
+
+	testCases := map[string][]float64{
+		"zero vector": {0, 0, 0},
+		"NaN value":   {0.5, math.NaN(), 0.5},
+	}
+	for name, embedding := range testCases {
+		t.Run(name, func(t *testing.T) {
+			embeddingService := &fixedEmbeddingService{embedding: embedding}
+			cache := NewSemanticCache(embeddingService, createTestLogger())
+
+			err := cache.Put("foreach device in network.devices", "162112", "latest", result)
+			if !errors.Is(err, ErrInvalidEmbedding) {
+				t.Fatalf("Expected ErrInvalidEmbedding, got: %v", err)
+			}
+			if embeddingService.calls != 2 {
+				t.Errorf("Expected the embedding to be regenerated once, got %d calls", embeddingService.calls)
+			}
+			if len(cache.entries) != 0 || len(cache.embeddingIndex) != 0 {
+				t.Errorf("Expected nothing to be indexed, got %d entries", len(cache.entries))
+			}
+		})
+	}
+}
+
+// nqeRows builds an NQE result of n rows
+func nqeRows(n int) *forward.NQERunResult {
+	result := &forward.NQERunResult{SnapshotID: "snap-1"}
+	for i := 0; i < n; i++ {
+		result.Items = append(result.Items, map[string]interface{}{"device": fmt.Sprintf("device-%03d", i)})
+	}
+	return result
+}
+
+func TestSemanticCacheSizeLimits(t *testing.T) {
+	t.Run("oversized_result_not_cached", func(t *testing.T) {
+		cache := NewSemanticCache(NewMockEmbeddingService(), createTestLogger())
+		cache.SetEntryLimits(10, 0, 0)
+
+		cache.PutNQEResult("FQ_small", nil, nil, "162112", "", nqeRows(10))
+		cache.PutNQEResult("FQ_large", nil, nil, "162112", "", nqeRows(11))
+		if _, found := cache.GetNQEResult("FQ_small", nil, nil, "162112", ""); !found {
+			t.Error("Expected a result at the row limit to be cached")
+		}
+		if _, found := cache.GetNQEResult("FQ_large", nil, nil, "162112", ""); found {
+			t.Error("Expected a result above the row limit not to be cached")
+		}
+
+		// A refresh that grows past the limit drops the stale entry
+		cache.PutNQEResult("FQ_small", nil, nil, "162112", "", nqeRows(20))
+		if _, found := cache.GetNQEResult("FQ_small", nil, nil, "162112", ""); found {
+			t.Error("Expected an oversized refresh to drop the previous result")
+		}
+		stats := cache.GetStats()
+		if stats["oversized_skips"] != int64(2) || stats["total_bytes"] != 0 {
+			t.Errorf("Expected 2 oversized skips and no cached bytes, got %v", stats)
+		}
+	})
+
+	t.Run("byte_budget_evicts_oldest", func(t *testing.T) {
+		cache := NewSemanticCache(NewMockEmbeddingService(), createTestLogger())
+		entryBytes := len(mustJSON(t, nqeRows(5)))
+		cache.SetEntryLimits(0, 0, 2*entryBytes)
+
+		cache.PutNQEResult("FQ_1", nil, nil, "162112", "", nqeRows(5))
+		time.Sleep(time.Millisecond)
+		cache.PutNQEResult("FQ_2", nil, nil, "162112", "", nqeRows(5))
+		time.Sleep(time.Millisecond)
+		cache.PutNQEResult("FQ_3", nil, nil, "162112", "", nqeRows(5))
+
+		if _, found := cache.GetNQEResult("FQ_1", nil, nil, "162112", ""); found {
+			t.Error("Expected the oldest entry to be evicted to stay within the byte budget")
+		}
+		for _, id := range []string{"FQ_2", "FQ_3"} {
+			if _, found := cache.GetNQEResult(id, nil, nil, "162112", ""); !found {
+				t.Errorf("Expected %s to be cached", id)
+			}
+		}
+		if stats := cache.GetStats(); stats["total_entries"] != 2 || stats["total_bytes"] != 2*entryBytes {
+			t.Errorf("Expected 2 entries of %d bytes each, got %v", entryBytes, stats)
+		}
+
+		// A result larger than the whole budget is never cached
+		cache.PutNQEResult("FQ_huge", nil, nil, "162112", "", nqeRows(50))
+		if _, found := cache.GetNQEResult("FQ_huge", nil, nil, "162112", ""); found || cache.GetStats()["total_entries"] != 2 {
+			t.Error("Expected a result above the total budget to be skipped without evicting others")
+		}
+	})
+}
+
+// mustJSON marshals v or fails the test
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// sessionProfilesMu serializes reads and writes of the profile files
+var sessionProfilesMu sync.Mutex
+
+// SessionProfile is a named bundle of session defaults that load_profile restores in one call
+type SessionProfile struct {
+	NetworkID      string    `json:"network_id,omitempty"`
+	SnapshotID     string    `json:"snapshot_id,omitempty"`
+	QueryLimit     int       `json:"query_limit,omitempty"`
+	DefaultFormat  string    `json:"default_format,omitempty"`
+	ResponseDetail string    `json:"response_detail,omitempty"`
+	HideNQESchema  bool      `json:"hide_nqe_schema,omitempty"`
+	NormalizeUnits bool      `json:"normalize_units,omitempty"`
+	SavedAt        time.Time `json:"saved_at"`
+}
+
+// profileFromDefaults captures the current session defaults
+func profileFromDefaults(defaults *ServiceDefaults) SessionProfile {
+	networkID, snapshotID := defaults.Scope()
+	return SessionProfile{
+		NetworkID:      networkID,
+		SnapshotID:     snapshotID,
+		QueryLimit:     defaults.QueryLimit,
+		DefaultFormat:  defaults.DefaultFormat,
+		ResponseDetail: defaults.ResponseDetail,
+		HideNQESchema:  defaults.HideNQESchema,
+		NormalizeUnits: defaults.NormalizeNQEUnits,
+	}
+}
+
+// applyTo overwrites the session defaults the profile covers; limits and budgets that
+// come from the server configuration are left alone
+func (p SessionProfile) applyTo(defaults *ServiceDefaults) {
+	defaults.SetScope(p.NetworkID, p.SnapshotID)
+	defaults.QueryLimit = p.QueryLimit
+	defaults.DefaultFormat = p.DefaultFormat
+	defaults.ResponseDetail = p.ResponseDetail
+	defaults.HideNQESchema = p.HideNQESchema
+	defaults.NormalizeNQEUnits = p.NormalizeUnits
+}
+
+// profileName normalizes and validates a profile name
+func profileName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("a profile name is required")
+	}
+	return name, nil
+}
+
+// sessionProfilesPath returns the profile file of the active Forward instance, so profiles
+// saved against one instance never point another at its network IDs
+func (s *ForwardMCPService) sessionProfilesPath() string {
+	dir := ""
+	if s.config != nil {
+		dir = s.config.Forward.ProfilesDir
+	}
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			base = os.TempDir()
+		}
+		dir = filepath.Join(base, "forward-mcp", "profiles")
+	}
+	instanceID := s.activeInstanceID()
+	if instanceID == "" {
+		instanceID = defaultInstanceName
+	}
+	return filepath.Join(dir, instanceID+".json")
+}
+
+// loadSessionProfiles reads the saved profiles of the active instance; a missing file
+// means none have been saved yet. The caller holds sessionProfilesMu.
+func (s *ForwardMCPService) loadSessionProfiles() (map[string]SessionProfile, error) {
+	profiles := make(map[string]SessionProfile)
+	data, err := os.ReadFile(s.sessionProfilesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session profiles: %w", err)
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse session profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// storeSessionProfiles writes the profiles of the active instance. The caller holds
+// sessionProfilesMu.
+func (s *ForwardMCPService) storeSessionProfiles(profiles map[string]SessionProfile) error {
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session profiles: %w", err)
+	}
+	path := s.sessionProfilesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create session profile directory: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session profiles: %w", err)
+	}
+	return nil
+}
+
+// describeProfile renders a profile's settings on one line
+func describeProfile(p SessionProfile) string {
+	network := p.NetworkID
+	if network == "" {
+		network = "(none)"
+	}
+	snapshot := p.SnapshotID
+	if snapshot == "" {
+		snapshot = "latest"
+	}
+	format := p.DefaultFormat
+	if format == "" {
+		format = "API default"
+	}
+	detail := p.ResponseDetail
+	if detail == "" {
+		detail = ResponseDetailFull
+	}
+	return fmt.Sprintf("network %s, snapshot %s, limit %d, format %s, detail %s", network, snapshot, p.QueryLimit, format, detail)
+}
+
+// saveProfile stores the current session defaults under a name, replacing a profile of
+// the same name
+func (s *ForwardMCPService) saveProfile(args SaveProfileArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("save_profile", args, nil)
+
+	name, err := profileName(args.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionProfilesMu.Lock()
+	defer sessionProfilesMu.Unlock()
+
+	profiles, err := s.loadSessionProfiles()
+	if err != nil {
+		return nil, err
+	}
+	_, replaced := profiles[name]
+	profile := profileFromDefaults(s.defaults)
+	profile.SavedAt = time.Now().UTC()
+	profiles[name] = profile
+	if err := s.storeSessionProfiles(profiles); err != nil {
+		return nil, err
+	}
+
+	action := "saved"
+	if replaced {
+		action = "replaced"
+	}
+	response := fmt.Sprintf("Profile %q %s: %s\n", name, action, describeProfile(profile))
+	response += fmt.Sprintf("Switch back to it with load_profile name: %s", name)
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
+
+// loadProfile replaces the session defaults with a saved profile and names the
+// profile's network so the switch can be confirmed
+func (s *ForwardMCPService) loadProfile(args LoadProfileArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("load_profile", args, nil)
+
+	name, err := profileName(args.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionProfilesMu.Lock()
+	profiles, err := s.loadSessionProfiles()
+	sessionProfilesMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("no profile named %q for this instance; use list_profiles to see saved profiles", name)
+	}
+
+	profile.applyTo(s.defaults)
+
+	response := fmt.Sprintf("Profile %q loaded: %s\n", name, describeProfile(profile))
+	if profile.NetworkID != "" {
+		networkName := ""
+		if networks, err := s.client().GetNetworks(); err != nil {
+			s.logger.Warn("Could not resolve network %s of profile %q: %v", profile.NetworkID, name, err)
+		} else {
+			for _, network := range networks {
+				if network.ID == profile.NetworkID {
+					networkName = network.Name
+					break
+				}
+			}
+		}
+		if networkName != "" {
+			response += fmt.Sprintf("Default network: %s (ID: %s)\n", networkName, profile.NetworkID)
+		} else {
+			response += fmt.Sprintf("⚠️ Network %s was not found on this instance; use set_default_network to pick another.\n", profile.NetworkID)
+		}
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
+
+// listProfiles names the profiles saved for the active instance
+func (s *ForwardMCPService) listProfiles(args ListProfilesArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("list_profiles", args, nil)
+
+	sessionProfilesMu.Lock()
+	profiles, err := s.loadSessionProfiles()
+	sessionProfilesMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return mcp.NewToolResponse(mcp.NewTextContent("No profiles saved for this instance. Use save_profile to store the current defaults under a name.")), nil
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response := fmt.Sprintf("Saved profiles (%d):\n", len(names))
+	for _, name := range names {
+		profile := profiles[name]
+		response += fmt.Sprintf("• %s: %s (saved %s)\n", name, describeProfile(profile), profile.SavedAt.Format(time.RFC3339))
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(response)), nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+func TestSaveAndLoadProfileRestoresDefaults(t *testing.T) {
+	service := createTestService()
+	service.config.Forward.ProfilesDir = t.TempDir()
+	service.client().(*MockForwardClient).networks = []forward.Network{{ID: "162112", Name: "Campus"}, {ID: "200", Name: "Datacenter"}}
+
+	*service.defaults = ServiceDefaults{
+		NetworkID:         "162112",
+		SnapshotID:        "snap-42",
+		QueryLimit:        250,
+		DefaultFormat:     "csv",
+		ResponseDetail:    ResponseDetailSummary,
+		HideNQESchema:     true,
+		NormalizeNQEUnits: true,
+	}
+	saved := profileFromDefaults(service.defaults)
+	if _, err := service.saveProfile(SaveProfileArgs{Name: "campus-audit"}); err != nil {
+		t.Fatalf("saveProfile failed: %v", err)
+	}
+
+	*service.defaults = ServiceDefaults{NetworkID: "200", QueryLimit: 100}
+	response, err := service.loadProfile(LoadProfileArgs{Name: " campus-audit "})
+	if err != nil {
+		t.Fatalf("loadProfile failed: %v", err)
+	}
+	if restored := profileFromDefaults(service.defaults); !reflect.DeepEqual(restored, saved) {
+		t.Errorf("Expected the saved defaults to be restored:\n got %+v\nwant %+v", restored, saved)
+	}
+	if text := response.Content[0].TextContent.Text; !strings.Contains(text, "Default network: Campus (ID: 162112)") {
+		t.Errorf("Expected the network name to be resolved, got:\n%s", text)
+	}
+
+	response, err = service.listProfiles(ListProfilesArgs{})
+	if err != nil {
+		t.Fatalf("listProfiles failed: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; !strings.Contains(text, "• campus-audit: network 162112, snapshot snap-42, limit 250, format csv, detail summary") {
+		t.Errorf("Expected the profile to be listed, got:\n%s", text)
+	}
+
+	if _, err := service.loadProfile(LoadProfileArgs{Name: "missing"}); err == nil {
+		t.Error("Expected an unknown profile to be rejected")
+	}
+	if _, err := service.saveProfile(SaveProfileArgs{Name: "  "}); err == nil {
+		t.Error("Expected an empty profile name to be rejected")
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+)
+
+// flightCall is one in-flight lookup and the result its callers share
+type flightCall struct {
+	done    sync.WaitGroup
+	value   interface{}
+	err     error
+	waiters int
+}
+
+// FlightGroup coalesces concurrent lookups with the same key into a single call whose
+// result every caller shares, so a burst of identical lookups reaches the API once.
+// Results are not kept after the call returns; caching is left to the caller. A nil
+// group runs every call.
+type FlightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+// NewFlightGroup creates an empty group
+func NewFlightGroup() *FlightGroup {
+	return &FlightGroup{calls: make(map[string]*flightCall)}
+}
+
+// Do runs fn for key, or waits for the call already in flight for key and returns its
+// result. shared reports whether the result came from another caller's call.
+func (g *FlightGroup) Do(key string, fn func() (interface{}, error)) (value interface{}, err error, shared bool) {
+	if g == nil {
+		value, err = fn()
+		return value, err, false
+	}
+
+	g.mutex.Lock()
+	if call, ok := g.calls[key]; ok {
+		call.waiters++
+		g.mutex.Unlock()
+		call.done.Wait()
+		return call.value, call.err, true
+	}
+	call := &flightCall{}
+	call.done.Add(1)
+	g.calls[key] = call
+	g.mutex.Unlock()
+
+	// Release the key and the waiters even when fn panics, so later lookups never block
+	// on a call that will not finish; waiters then get an error and the panic continues
+	completed := false
+	defer func() {
+		if !completed {
+			call.value, call.err = nil, fmt.Errorf("lookup %s did not complete", key)
+		}
+		g.mutex.Lock()
+		delete(g.calls, key)
+		g.mutex.Unlock()
+		call.done.Done()
+	}()
+
+	call.value, call.err = fn()
+	completed = true
+	return call.value, call.err, false
+}
+
+// waiting returns how many callers are waiting on the call in flight for key
+func (g *FlightGroup) waiting(key string) int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if call, ok := g.calls[key]; ok {
+		return call.waiters
+	}
+	return 0
+}
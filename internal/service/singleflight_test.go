@@ -0,0 +1,144 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// blockingLookupClient counts latest-snapshot and network list calls, holding each call
+// until release is closed
+type blockingLookupClient struct {
+	*MockForwardClient
+	release       chan struct{}
+	mutex         sync.Mutex
+	snapshotCalls int
+	networkCalls  int
+}
+
+func (c *blockingLookupClient) GetLatestSnapshot(networkID string) (*forward.Snapshot, error) {
+	c.mutex.Lock()
+	c.snapshotCalls++
+	c.mutex.Unlock()
+	<-c.release
+	return c.MockForwardClient.GetLatestSnapshot(networkID)
+}
+
+func (c *blockingLookupClient) GetNetworks() ([]forward.Network, error) {
+	c.mutex.Lock()
+	c.networkCalls++
+	c.mutex.Unlock()
+	<-c.release
+	return c.MockForwardClient.GetNetworks()
+}
+
+// waitForWaiters blocks until n callers are waiting on the flight for key
+func waitForWaiters(t *testing.T, group *FlightGroup, key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for group.waiting(key) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %d callers on %s, have %d", n, key, group.waiting(key))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConcurrentLookupsShareOneAPICall(t *testing.T) {
+	const callers = 20
+	service := createTestService()
+	service.lookups = NewFlightGroup()
+	client := &blockingLookupClient{MockForwardClient: service.client().(*MockForwardClient), release: make(chan struct{})}
+	service.active().client = client
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := service.latestSnapshot("162112"); err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if networks, err := service.cachedNetworks(false); err != nil || len(networks) == 0 {
+				errs <- err
+			}
+		}()
+	}
+	waitForWaiters(t, service.lookups, service.active().lookupKey(latestSnapshotLookupKey("162112")), callers-1)
+	waitForWaiters(t, service.lookups, service.active().lookupKey(networksListKey), callers-1)
+	close(client.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Expected every caller to get the shared result, got: %v", err)
+	}
+	if client.snapshotCalls != 1 || client.networkCalls != 1 {
+		t.Errorf("Expected one latest-snapshot and one network call, got %d and %d", client.snapshotCalls, client.networkCalls)
+	}
+}
+
+func TestNilFlightGroupRunsEveryCall(t *testing.T) {
+	var group *FlightGroup
+	calls := 0
+	for i := 0; i < 2; i++ {
+		if _, _, shared := group.Do("key", func() (interface{}, error) { calls++; return nil, nil }); shared {
+			t.Error("Expected no shared result without a group")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected both calls to run, got %d", calls)
+	}
+}
+
+func TestFlightGroupReleasesKeyWhenCallPanics(t *testing.T) {
+	group := NewFlightGroup()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	panicked := make(chan interface{}, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		group.Do("key", func() (interface{}, error) {
+			close(started)
+			<-release
+			panic("lookup failed")
+		})
+	}()
+	<-started
+
+	// A waiter on the panicking call gets an error instead of blocking forever
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err, _ := group.Do("key", func() (interface{}, error) { return "fresh", nil })
+		waiterErr <- err
+	}()
+	waitForWaiters(t, group, "key", 1)
+	close(release)
+
+	if recovered := <-panicked; recovered != "lookup failed" {
+		t.Errorf("Expected the panic to reach the caller, got %v", recovered)
+	}
+	if err := <-waiterErr; err == nil {
+		t.Error("Expected the waiter to get an error")
+	}
+
+	// The key is free again, so the next lookup runs
+	value, err, shared := group.Do("key", func() (interface{}, error) { return "fresh", nil })
+	if value != "fresh" || err != nil || shared {
+		t.Errorf("Expected a fresh call after the panic, got %v, %v, shared=%v", value, err, shared)
+	}
+}
+
+func TestLookupKeysAreScopedToTheInstance(t *testing.T) {
+	defaultInstance := &instanceState{}
+	lab := &instanceState{name: "lab"}
+	if defaultInstance.lookupKey(networksListKey) == lab.lookupKey(networksListKey) {
+		t.Error("Expected different instances to use different flight keys")
+	}
+}
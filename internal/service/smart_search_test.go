@@ -1,7 +1,14 @@
 package service
 
 import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/forward-mcp/internal/config"
 	"github.com/forward-mcp/internal/logger"
@@ -28,7 +35,7 @@ func setupSmartSearchTestService() *ForwardMCPService {
 	embeddingService := NewKeywordEmbeddingService()
 
 	service := &ForwardMCPService{
-		forwardClient:   NewMockForwardClient(),
+		instance:        newInstanceHolder(&instanceState{client: NewMockForwardClient()}),
 		config:          cfg,
 		logger:          testLogger,
 		defaults:        &ServiceDefaults{},
@@ -449,6 +456,275 @@ func TestGetQueryIndexStats_Detailed(t *testing.T) {
 	}
 }
 
+// seedQueryIndex replaces the index contents with queries built from the given paths
+func seedQueryIndex(idx *NQEQueryIndex, paths ...string) {
+	queries := make([]*NQEQueryIndexEntry, 0, len(paths))
+	for i, path := range paths {
+		query := &NQEQueryIndexEntry{
+			QueryID: fmt.Sprintf("FQ_test_%d", i),
+			Path:    path,
+		}
+		applyPathMetadata(query)
+		queries = append(queries, query)
+	}
+
+	idx.mutex.Lock()
+	idx.queries = queries
+	idx.mutex.Unlock()
+}
+
+// Test browsing the NQE library lists categories with example paths
+func TestBrowseNQELibrary(t *testing.T) {
+	service := setupSmartSearchTestService()
+	seedQueryIndex(service.queryIndex,
+		"/L3/BGP/BGP Neighbor State",
+		"/L3/BGP/BGP Route Count",
+		"/L3/OSPF/OSPF Adjacencies",
+		"/L3/Routing/Default Routes",
+		"/Security/ACL/Permit Any Rules",
+		"/Security/STIGs/Cisco/CISC-RT-000400",
+	)
+
+	response, err := service.browseNQELibrary(BrowseNQELibraryArgs{Examples: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	responseText := response.Content[0].TextContent.Text
+	for _, expected := range []string{
+		"**L3** (4 queries)",
+		"**Security** (2 queries)",
+		"- /L3/BGP/BGP Neighbor State",
+		"- /L3/OSPF/OSPF Adjacencies",
+		"- /Security/ACL/Permit Any Rules",
+		"- /Security/STIGs/Cisco/CISC-RT-000400",
+	} {
+		if !contains(responseText, expected) {
+			t.Errorf("Expected response to contain %q, got: %s", expected, responseText)
+		}
+	}
+
+	// Examples are capped per category and prefer distinct subcategories
+	if contains(responseText, "/L3/BGP/BGP Route Count") || contains(responseText, "/L3/Routing/Default Routes") {
+		t.Errorf("Expected at most 2 examples from distinct L3 subcategories, got: %s", responseText)
+	}
+
+	// Category filter narrows the output
+	response, err = service.browseNQELibrary(BrowseNQELibraryArgs{Category: "security"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	responseText = response.Content[0].TextContent.Text
+	if contains(responseText, "**L3**") || !contains(responseText, "**Security**") {
+		t.Errorf("Expected only the Security category, got: %s", responseText)
+	}
+}
+
+// Test concurrent searches while the index is reloaded and embeddings are generated.
+// Run with -race (make test-race) to detect unsynchronized access.
+func TestNQEQueryIndex_ConcurrentSearchDuringReload(t *testing.T) {
+	idx := NewNQEQueryIndex(NewKeywordEmbeddingService(), logger.New())
+	idx.embeddingsCachePath = filepath.Join(t.TempDir(), "nqe-embeddings.json")
+	seedQueryIndex(idx,
+		"/L3/BGP/BGP Neighbor State",
+		"/L3/OSPF/OSPF Adjacencies",
+		"/Security/ACL/Permit Any Rules",
+		"/Interfaces/Utilization/High Utilization",
+	)
+
+	indexFile := filepath.Join(t.TempDir(), "index.json")
+	if err := idx.SaveIndex(indexFile); err != nil {
+		t.Fatalf("Failed to save index: %v", err)
+	}
+
+	// Each reader exercises a single entry point so that lock operations in one
+	// call cannot mask missing synchronization in another
+	readers := []func() error{
+		func() error {
+			_, err := idx.SearchQueries("bgp neighbor", 5)
+			return err
+		},
+		func() error {
+			_, err := idx.SearchByKeywords("utilization", 5)
+			return err
+		},
+		func() error {
+			idx.GetStatistics()
+			return nil
+		},
+		func() error {
+			idx.GetCategorySummaries(2)
+			return nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	for _, read := range readers {
+		wg.Add(1)
+		go func(read func() error) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if err := read(); err != nil {
+					t.Errorf("Concurrent search failed: %v", err)
+					return
+				}
+			}
+		}(read)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 20; j++ {
+			if err := idx.LoadIndex(indexFile); err != nil {
+				t.Errorf("LoadIndex failed: %v", err)
+				return
+			}
+			if err := idx.GenerateEmbeddings(); err != nil {
+				t.Errorf("GenerateEmbeddings failed: %v", err)
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	if got := idx.GetStatistics()["total_queries"].(int); got != 4 {
+		t.Errorf("Expected 4 queries after reloads, got %d", got)
+	}
+}
+
+// failingEmbeddingService simulates an embedding provider outage
+type failingEmbeddingService struct{}
+
+func (f *failingEmbeddingService) GenerateEmbedding(text string) ([]float64, error) {
+	return nil, fmt.Errorf("embedding provider unavailable")
+}
+
+// Test that semantic search falls back to keyword matching when embeddings fail
+func TestSearchQueries_KeywordFallbackOnEmbeddingError(t *testing.T) {
+	service := setupSmartSearchTestService()
+	service.queryIndex = NewNQEQueryIndex(&failingEmbeddingService{}, service.logger)
+	seedQueryIndex(service.queryIndex,
+		"/L3/BGP/BGP Neighbor State",
+		"/Devices/Inventory/Device Basic Info",
+		"/Security/ACL/Permit Any Rules",
+	)
+	// Cached embeddings force the semantic path, which then fails at query time
+	for _, query := range service.queryIndex.Queries() {
+		query.Embedding = []float32{0.1, 0.2, 0.3}
+	}
+
+	results, err := service.queryIndex.SearchQueries("bgp neighbor", 5)
+	if err != nil {
+		t.Fatalf("Expected keyword fallback instead of error, got: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("Expected keyword results during embedding outage, got none")
+	}
+	if results[0].Path != "/L3/BGP/BGP Neighbor State" {
+		t.Errorf("Expected BGP query as top result, got %s", results[0].Path)
+	}
+	if !IsDegradedSearch(results) {
+		t.Errorf("Expected results to be marked as %s, got %s", MatchTypeKeywordFallback, results[0].MatchType)
+	}
+
+	response, err := service.findExecutableQuery(FindExecutableQueryArgs{Query: "device basic info"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	responseText := response.Content[0].TextContent.Text
+	if !contains(responseText, "Degraded matching") {
+		t.Errorf("Expected degraded matching note, got: %s", responseText)
+	}
+}
+
+// Test that weak executable mappings are filtered by min_confidence
+func TestFilterMappingsByConfidence(t *testing.T) {
+	results := []*QuerySearchResult{
+		{NQEQueryIndexEntry: &NQEQueryIndexEntry{Path: "/Devices/device_basic_info", Intent: "Show device inventory"}, SimilarityScore: 0.9},
+		{NQEQueryIndexEntry: &NQEQueryIndexEntry{Path: "/Misc/Hardware Notes"}, SimilarityScore: 0.4},
+	}
+
+	mappings := MapSemanticToExecutable(results)
+	if len(mappings) < 2 {
+		t.Fatalf("Expected strong and weak mappings before filtering, got %d", len(mappings))
+	}
+
+	filtered := FilterMappingsByConfidence(mappings, defaultMinMappingConfidence)
+	if len(filtered) != 1 || filtered[0].ExecutableQuery.Name != "Device Basic Info" {
+		t.Fatalf("Expected only Device Basic Info to pass the default threshold, got %d mappings", len(filtered))
+	}
+	if len(FilterMappingsByConfidence(mappings, 1.01)) != 0 {
+		t.Error("Expected a threshold above 1 to suppress every mapping")
+	}
+}
+
+// Test that find_executable_query honors min_confidence
+func TestFindExecutableQuery_MinConfidence(t *testing.T) {
+	service := setupSmartSearchTestService()
+	seedQueryIndex(service.queryIndex,
+		"/Devices/Inventory/Device Basic Info",
+		"/Misc/Hardware Notes",
+	)
+
+	response, err := service.findExecutableQuery(FindExecutableQueryArgs{Query: "device basic info"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	responseText := response.Content[0].TextContent.Text
+	if !contains(responseText, "Device Basic Info") || !contains(responseText, "Query ID:") {
+		t.Errorf("Expected an executable recommendation for a relevant query, got: %s", responseText)
+	}
+
+	response, err = service.findExecutableQuery(FindExecutableQueryArgs{Query: "hardware notes", MinConfidence: 0.95})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	responseText = response.Content[0].TextContent.Text
+	if contains(responseText, "Query ID:") {
+		t.Errorf("Expected weak mappings to be suppressed, got: %s", responseText)
+	}
+	if !contains(responseText, "confidence threshold") || !contains(responseText, "/Misc/Hardware Notes") {
+		t.Errorf("Expected raw semantic matches with a threshold explanation, got: %s", responseText)
+	}
+}
+
+// Test that include_history annotates recommendations with recorded runs
+func TestFindExecutableQuery_IncludeHistory(t *testing.T) {
+	service := setupSmartSearchTestService()
+	service.active().queryRuntimes = NewNQERuntimeTracker()
+	seedQueryIndex(service.queryIndex, "/Devices/Inventory/Device Basic Info")
+
+	response, err := service.findExecutableQuery(FindExecutableQueryArgs{Query: "device basic info", IncludeHistory: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if responseText := response.Content[0].TextContent.Text; !contains(responseText, "**History:** no history") {
+		t.Errorf("Expected a never-run query to show no history, got: %s", responseText)
+	}
+
+	service.active().queryRuntimes.Record("FQ_ac651cb2901b067fe7dbfb511613ab44776d8029", 2*time.Second)
+	service.active().queryRuntimes.Record("FQ_ac651cb2901b067fe7dbfb511613ab44776d8029", 4*time.Second)
+	response, err = service.findExecutableQuery(FindExecutableQueryArgs{Query: "device basic info", IncludeHistory: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	responseText := response.Content[0].TextContent.Text
+	if !contains(responseText, "**History:** last run ") || !contains(responseText, "avg 3s over 2 runs") {
+		t.Errorf("Expected the recorded history on the recommendation, got: %s", responseText)
+	}
+
+	// History is only shown on request
+	response, err = service.findExecutableQuery(FindExecutableQueryArgs{Query: "device basic info"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if contains(response.Content[0].TextContent.Text, "**History:**") {
+		t.Error("Expected no history without include_history")
+	}
+}
+
 // Test keyword embedding service used in smart search
 func TestKeywordEmbeddingService_SmartSearch(t *testing.T) {
 	service := NewKeywordEmbeddingService()
@@ -522,3 +798,46 @@ func BenchmarkFindExecutableQuery(b *testing.B) {
 		}
 	}
 }
+
+// Test that a search matching nothing returns category hints instead of an empty list
+func TestSearchNQEQueries_NoResultsGuidance(t *testing.T) {
+	service := setupSmartSearchTestService()
+	seedQueryIndex(service.queryIndex,
+		"/L3/BGP/BGP Neighbor State",
+		"/L3/OSPF/OSPF Adjacencies",
+		"/L3/Routes/Default Routes",
+		"/Security/ACL/Unused ACL Rules",
+	)
+
+	response, err := service.searchNQEQueries(SearchNQEQueriesArgs{Query: "zzqx unmatched phrase"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+
+	var guidance NQESearchGuidance
+	if err := json.Unmarshal([]byte(text[strings.Index(text, "{"):]), &guidance); err != nil {
+		t.Fatalf("Expected structured guidance, got %v:\n%s", err, text)
+	}
+	expected := []CategoryHint{{Category: "L3", Queries: 3}, {Category: "Security", Queries: 1}}
+	if !reflect.DeepEqual(guidance.TopCategories, expected) {
+		t.Errorf("Expected categories by size %v, got %v", expected, guidance.TopCategories)
+	}
+	if len(guidance.ExampleSearches) == 0 {
+		t.Error("Expected example search phrases")
+	}
+	if !contains(text, "get_query_index_stats") {
+		t.Errorf("Expected a pointer to get_query_index_stats, got:\n%s", text)
+	}
+}
+
+// Test that guidance for a filtered search suggests removing the filters
+func TestNQESearchGuidanceWithFilters(t *testing.T) {
+	guidance := nqeSearchGuidance("bgp", "Cloud", "", map[string]int{"L3": 2})
+	if !reflect.DeepEqual(guidance.Filters, []string{"category=Cloud"}) {
+		t.Errorf("Expected the category filter to be echoed, got %v", guidance.Filters)
+	}
+	if len(guidance.NextSteps) == 0 || !contains(guidance.NextSteps[0], "Remove the category/subcategory filters") {
+		t.Errorf("Expected removing filters as the first next step, got %v", guidance.NextSteps)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+const (
+	// cadenceSmoothingFactor weights the newest snapshot interval in the moving average
+	cadenceSmoothingFactor = 0.3
+	// minRecommendedTTL and maxRecommendedTTL bound the TTL recommendation
+	minRecommendedTTL = 15 * time.Minute
+	maxRecommendedTTL = 7 * 24 * time.Hour
+)
+
+// networkCadence tracks how often new snapshots appear for one network
+type networkCadence struct {
+	lastSnapshotID   string
+	lastSnapshotTime time.Time
+	smoothedInterval time.Duration
+	intervals        int
+}
+
+// SnapshotCadenceTracker observes latest-snapshot results over time and estimates each
+// network's snapshot interval with exponential smoothing. A nil tracker records nothing.
+type SnapshotCadenceTracker struct {
+	mutex    sync.RWMutex
+	networks map[string]*networkCadence
+}
+
+// CadenceRecommendation is the cache TTL suggested for a network's snapshot cadence
+type CadenceRecommendation struct {
+	NetworkID        string        `json:"network_id"`
+	SmoothedInterval time.Duration `json:"smoothed_interval"`
+	RecommendedTTL   time.Duration `json:"recommended_ttl"`
+	Intervals        int           `json:"observed_intervals"`
+}
+
+// NewSnapshotCadenceTracker creates an empty tracker
+func NewSnapshotCadenceTracker() *SnapshotCadenceTracker {
+	return &SnapshotCadenceTracker{
+		networks: make(map[string]*networkCadence),
+	}
+}
+
+// snapshotTime returns when the snapshot was taken, falling back to processing time
+func snapshotTime(snapshot *forward.Snapshot) time.Time {
+	if snapshot.CreationDateMillis > 0 {
+		return time.UnixMilli(snapshot.CreationDateMillis)
+	}
+	if snapshot.ProcessedAtMillis > 0 {
+		return time.UnixMilli(snapshot.ProcessedAtMillis)
+	}
+	return time.Time{}
+}
+
+// Observe records a latest-snapshot result. A new snapshot ID with a later timestamp
+// contributes one interval sample to the smoothed cadence.
+func (t *SnapshotCadenceTracker) Observe(networkID string, snapshot *forward.Snapshot) {
+	if t == nil || snapshot == nil || networkID == "" {
+		return
+	}
+	taken := snapshotTime(snapshot)
+	if taken.IsZero() {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	cadence, exists := t.networks[networkID]
+	if !exists {
+		t.networks[networkID] = &networkCadence{lastSnapshotID: snapshot.ID, lastSnapshotTime: taken}
+		return
+	}
+	if snapshot.ID == cadence.lastSnapshotID || !taken.After(cadence.lastSnapshotTime) {
+		return
+	}
+
+	interval := taken.Sub(cadence.lastSnapshotTime)
+	if cadence.intervals == 0 {
+		cadence.smoothedInterval = interval
+	} else {
+		cadence.smoothedInterval = time.Duration(cadenceSmoothingFactor*float64(interval) +
+			(1-cadenceSmoothingFactor)*float64(cadence.smoothedInterval))
+	}
+	cadence.intervals++
+	cadence.lastSnapshotID = snapshot.ID
+	cadence.lastSnapshotTime = taken
+}
+
+// RecommendedTTL returns a cache TTL aligned to the network's snapshot cadence, so cached
+// results expire around the time a new snapshot is expected
+func (t *SnapshotCadenceTracker) RecommendedTTL(networkID string) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	cadence, exists := t.networks[networkID]
+	if !exists || cadence.intervals == 0 {
+		return 0, false
+	}
+	return clampTTL(cadence.smoothedInterval), true
+}
+
+// Recommendations returns TTL recommendations for every network with enough observations
+func (t *SnapshotCadenceTracker) Recommendations() []CadenceRecommendation {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var recommendations []CadenceRecommendation
+	for _, networkID := range sortedKeys(t.networks) {
+		cadence := t.networks[networkID]
+		if cadence.intervals == 0 {
+			continue
+		}
+		recommendations = append(recommendations, CadenceRecommendation{
+			NetworkID:        networkID,
+			SmoothedInterval: cadence.smoothedInterval,
+			RecommendedTTL:   clampTTL(cadence.smoothedInterval),
+			Intervals:        cadence.intervals,
+		})
+	}
+	return recommendations
+}
+
+// clampTTL keeps a recommended TTL within sensible bounds
+func clampTTL(ttl time.Duration) time.Duration {
+	if ttl < minRecommendedTTL {
+		return minRecommendedTTL
+	}
+	if ttl > maxRecommendedTTL {
+		return maxRecommendedTTL
+	}
+	return ttl
+}
+
+// observeSnapshot feeds a latest-snapshot result to the cadence tracker and, when
+// auto-tuning is enabled, applies the recommended TTL to the network's cache entries
+func (s *ForwardMCPService) observeSnapshot(networkID string, snapshot *forward.Snapshot) {
+	cadence := s.active().snapshotCadence
+	cadence.Observe(networkID, snapshot)
+
+	if s.config == nil || !s.config.Forward.SemanticCache.AutoTuneTTL {
+		return
+	}
+	if ttl, ok := cadence.RecommendedTTL(networkID); ok {
+		s.semanticCache.SetNetworkTTL(networkID, ttl)
+	}
+}
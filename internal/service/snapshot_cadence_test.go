@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// observeSeries feeds the tracker one snapshot per interval starting at start
+func observeSeries(tracker *SnapshotCadenceTracker, networkID string, start time.Time, intervals ...time.Duration) {
+	taken := start
+	tracker.Observe(networkID, &forward.Snapshot{ID: "snap-0", CreationDateMillis: taken.UnixMilli()})
+	for i, interval := range intervals {
+		taken = taken.Add(interval)
+		tracker.Observe(networkID, &forward.Snapshot{ID: fmt.Sprintf("snap-%d", i+1), CreationDateMillis: taken.UnixMilli()})
+	}
+}
+
+func TestSnapshotCadenceRecommendedTTL(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		intervals []time.Duration
+		minTTL    time.Duration
+		maxTTL    time.Duration
+	}{
+		{"hourly snapshots", []time.Duration{time.Hour, time.Hour, time.Hour, time.Hour}, time.Hour, time.Hour},
+		{"daily with jitter", []time.Duration{22 * time.Hour, 26 * time.Hour, 24 * time.Hour}, 23 * time.Hour, 25 * time.Hour},
+		{"weekly snapshots", []time.Duration{7 * 24 * time.Hour, 7 * 24 * time.Hour}, maxRecommendedTTL, maxRecommendedTTL},
+		{"very frequent snapshots are clamped", []time.Duration{time.Minute, time.Minute}, minRecommendedTTL, minRecommendedTTL},
+		{"smoothing follows a cadence change", []time.Duration{24 * time.Hour, time.Hour, time.Hour, time.Hour, time.Hour, time.Hour, time.Hour, time.Hour, time.Hour}, time.Hour, 4 * time.Hour},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := NewSnapshotCadenceTracker()
+			observeSeries(tracker, "162112", start, tc.intervals...)
+
+			ttl, ok := tracker.RecommendedTTL("162112")
+			if !ok {
+				t.Fatal("Expected a TTL recommendation")
+			}
+			if ttl < tc.minTTL || ttl > tc.maxTTL {
+				t.Errorf("Expected TTL between %s and %s, got %s", tc.minTTL, tc.maxTTL, ttl)
+			}
+		})
+	}
+}
+
+func TestSnapshotCadenceIgnoresRepeatedSnapshots(t *testing.T) {
+	tracker := NewSnapshotCadenceTracker()
+	snapshot := &forward.Snapshot{ID: "snap-1", CreationDateMillis: time.Now().UnixMilli()}
+
+	tracker.Observe("162112", snapshot)
+	tracker.Observe("162112", snapshot)
+
+	if _, ok := tracker.RecommendedTTL("162112"); ok {
+		t.Error("Expected no recommendation from a single snapshot seen twice")
+	}
+}
+
+func TestSnapshotCadenceAutoTuneAndStats(t *testing.T) {
+	service := createTestService()
+	service.active().snapshotCadence = NewSnapshotCadenceTracker()
+	now := time.Now()
+	observeSeries(service.active().snapshotCadence, "162112", now.Add(-3*time.Hour), time.Hour, time.Hour)
+
+	// Recommendation only, auto-tuning is off by default
+	service.observeSnapshot("162112", &forward.Snapshot{ID: "snap-3", CreationDateMillis: now.UnixMilli()})
+	if ttl := service.semanticCache.NetworkTTL("162112"); ttl != 24*time.Hour {
+		t.Errorf("Expected default TTL without auto-tuning, got %s", ttl)
+	}
+
+	response, err := service.getCacheStats(GetCacheStatsArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	text := response.Content[0].TextContent.Text
+	if !contains(text, "Network 162112") || !contains(text, "recommended TTL 1h0m0s") {
+		t.Errorf("Expected TTL recommendation in cache stats, got: %s", text)
+	}
+
+	// Opt in: the recommendation is applied to the network's cache entries
+	service.config.Forward.SemanticCache.AutoTuneTTL = true
+	service.observeSnapshot("162112", &forward.Snapshot{ID: "snap-4", CreationDateMillis: now.Add(time.Hour).UnixMilli()})
+	if ttl := service.semanticCache.NetworkTTL("162112"); ttl != time.Hour {
+		t.Errorf("Expected auto-tuned TTL of 1h, got %s", ttl)
+	}
+	if ttl := service.semanticCache.NetworkTTL("other-network"); ttl != 24*time.Hour {
+		t.Errorf("Expected other networks to keep the default TTL, got %s", ttl)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// snapshotDateLayout is the date form accepted as a snapshot reference
+const snapshotDateLayout = "2006-01-02"
+
+// isSnapshotReference reports whether ref is a relative reference rather than a snapshot ID
+func isSnapshotReference(ref string) bool {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if ref == "latest" || ref == "today" || ref == "yesterday" || strings.HasPrefix(ref, "latest-") {
+		return true
+	}
+	_, err := time.Parse(snapshotDateLayout, ref)
+	return err == nil
+}
+
+// processedSnapshots returns the network's processed, non-draft snapshots newest-first
+func processedSnapshots(snapshots []forward.Snapshot) []forward.Snapshot {
+	var processed []forward.Snapshot
+	for _, snapshot := range filterSnapshots(snapshots, "", false) {
+		if snapshot.State == "" || strings.EqualFold(snapshot.State, "PROCESSED") {
+			processed = append(processed, snapshot)
+		}
+	}
+	return processed
+}
+
+// SelectSnapshot picks the snapshot a reference names from a network's snapshots:
+// "latest" is the newest processed snapshot, "latest-N" the Nth processed snapshot before
+// it, and a date ("2024-05-01", "today", "yesterday") the newest processed snapshot taken
+// on or before that day (UTC)
+func SelectSnapshot(snapshots []forward.Snapshot, ref string, now time.Time) (*forward.Snapshot, error) {
+	processed := processedSnapshots(snapshots)
+	if len(processed) == 0 {
+		return nil, fmt.Errorf("no processed snapshots to resolve %q against", ref)
+	}
+
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if ref == "latest" {
+		return &processed[0], nil
+	}
+	if offset, ok := strings.CutPrefix(ref, "latest-"); ok {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid snapshot reference %q: expected latest-N with N a non-negative number", ref)
+		}
+		if n >= len(processed) {
+			return nil, fmt.Errorf("snapshot reference %q is out of range: only %d processed snapshots exist", ref, len(processed))
+		}
+		return &processed[n], nil
+	}
+
+	var day time.Time
+	switch ref {
+	case "today":
+		day = now.UTC()
+	case "yesterday":
+		day = now.UTC().AddDate(0, 0, -1)
+	default:
+		parsed, err := time.Parse(snapshotDateLayout, ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot reference %q", ref)
+		}
+		day = parsed
+	}
+	endOfDay := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	for i := range processed {
+		if taken := snapshotTime(&processed[i]); !taken.IsZero() && taken.Before(endOfDay) {
+			return &processed[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no processed snapshot was taken on or before %s", endOfDay.AddDate(0, 0, -1).Format(snapshotDateLayout))
+}
+
+// resolveSnapshotID turns a snapshot reference (latest, latest-N, or a date) into a
+// snapshot ID. Snapshot IDs and an empty ref are returned unchanged without an API call.
+func (s *ForwardMCPService) resolveSnapshotID(networkID, ref string) (string, error) {
+	if !isSnapshotReference(ref) {
+		return ref, nil
+	}
+	snapshots, err := s.client().GetSnapshots(networkID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list snapshots to resolve %q: %w", ref, err)
+	}
+	snapshot, err := SelectSnapshot(snapshots, ref, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("network %s: %w", networkID, err)
+	}
+	s.logger.Debug("Resolved snapshot reference %q to %s for network %s", ref, snapshot.ID, networkID)
+	return snapshot.ID, nil
+}
+
+// resolveSnapshot shows which snapshot a relative reference selects
+func (s *ForwardMCPService) resolveSnapshot(args ResolveSnapshotArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("resolve_snapshot", args, nil)
+
+	networkID := s.getNetworkID(args.NetworkID)
+	if networkID == "" {
+		return nil, fmt.Errorf("network_id is required (no default network is set)")
+	}
+	if !isSnapshotReference(args.Reference) {
+		return nil, fmt.Errorf("invalid snapshot reference %q: use latest, latest-N, today, yesterday, or a date like 2024-05-01", args.Reference)
+	}
+
+	snapshots, err := s.client().GetSnapshots(networkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	snapshot, err := SelectSnapshot(snapshots, args.Reference, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("network %s: %w", networkID, err)
+	}
+
+	header := fmt.Sprintf("%s on network %s resolves to snapshot %s", args.Reference, networkID, snapshot.ID)
+	if taken := snapshotTime(snapshot); !taken.IsZero() {
+		header += " taken " + taken.UTC().Format(time.RFC3339)
+	}
+	return mcp.NewToolResponse(mcp.NewTextContent(s.formatDetail(header, []string{snapshot.ID}, snapshot, args.Pretty))), nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/forward-mcp/internal/forward"
+)
+
+// referenceSnapshots is a network's snapshots out of order, with a draft and a failed one
+func referenceSnapshots() []forward.Snapshot {
+	day := func(d int) int64 { return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC).UnixMilli() }
+	return []forward.Snapshot{
+		{ID: "snap-may-2", State: "PROCESSED", CreationDateMillis: day(2)},
+		{ID: "snap-may-5", State: "PROCESSED", CreationDateMillis: day(5)},
+		{ID: "snap-may-6-draft", State: "PROCESSED", CreationDateMillis: day(6), IsDraft: true},
+		{ID: "snap-may-6-failed", State: "FAILED", CreationDateMillis: day(6)},
+		{ID: "snap-may-4", State: "PROCESSED", CreationDateMillis: day(4)},
+	}
+}
+
+func TestSelectSnapshot(t *testing.T) {
+	now := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		ref      string
+		expected string
+	}{
+		{"latest", "snap-may-5"},
+		{"latest-1", "snap-may-4"},
+		{"LATEST-2", "snap-may-2"},
+		{"yesterday", "snap-may-5"},
+		{"2024-05-03", "snap-may-2"},
+	}
+	for _, tc := range testCases {
+		snapshot, err := SelectSnapshot(referenceSnapshots(), tc.ref, now)
+		if err != nil {
+			t.Errorf("%s: expected %s, got error: %v", tc.ref, tc.expected, err)
+			continue
+		}
+		if snapshot.ID != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.ref, tc.expected, snapshot.ID)
+		}
+	}
+
+	for _, ref := range []string{"latest-3", "latest-x", "2024-05-01"} {
+		if _, err := SelectSnapshot(referenceSnapshots(), ref, now); err == nil {
+			t.Errorf("%s: expected an error", ref)
+		}
+	}
+}
+
+func TestResolveSnapshotID(t *testing.T) {
+	service := createTestService()
+	mock := service.client().(*MockForwardClient)
+	mock.snapshots = referenceSnapshots()
+
+	if id, err := service.resolveSnapshotID("162112", "latest-1"); err != nil || id != "snap-may-4" {
+		t.Errorf("Expected latest-1 to select the second-newest snapshot, got %q (err: %v)", id, err)
+	}
+
+	// Snapshot IDs pass through without listing snapshots
+	mock.shouldError = true
+	if id, err := service.resolveSnapshotID("162112", "snapshot-123"); err != nil || id != "snapshot-123" {
+		t.Errorf("Expected a snapshot ID unchanged, got %q (err: %v)", id, err)
+	}
+}
+
+func TestResolveSnapshotTool(t *testing.T) {
+	service := createTestService()
+	service.client().(*MockForwardClient).snapshots = referenceSnapshots()
+
+	response, err := service.resolveSnapshot(ResolveSnapshotArgs{NetworkID: "162112", Reference: "latest-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := response.Content[0].TextContent.Text; !contains(text, "latest-1 on network 162112 resolves to snapshot snap-may-4") {
+		t.Errorf("Expected the resolved snapshot, got:\n%s", text)
+	}
+
+	if _, err := service.resolveSnapshot(ResolveSnapshotArgs{NetworkID: "162112", Reference: "snapshot-123"}); err == nil {
+		t.Error("Expected a plain snapshot ID to be rejected as a reference")
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/invopop/jsonschema"
+	mcp "github.com/metoro-io/mcp-golang"
+)
+
+// toolSchemaReflector mirrors the reflector mcp-golang uses for tools/list, so
+// describe_tools reports the same schemas clients receive
+var toolSchemaReflector = jsonschema.Reflector{
+	Anonymous:                  true,
+	AllowAdditionalProperties:  true,
+	RequiredFromJSONSchemaTags: true,
+	DoNotReference:             true,
+	ExpandedStruct:             true,
+}
+
+// ToolDescription is one registered tool with the JSON schema of its arguments
+type ToolDescription struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	InputSchema *jsonschema.Schema `json:"input_schema"`
+}
+
+// ToolCatalog records every tool as it is registered, so the catalog always matches
+// what RegisterTools exposes
+type ToolCatalog struct {
+	mutex sync.RWMutex
+	tools map[string]ToolDescription
+}
+
+// NewToolCatalog creates an empty tool catalog
+func NewToolCatalog() *ToolCatalog {
+	return &ToolCatalog{tools: make(map[string]ToolDescription)}
+}
+
+// Add records a tool, deriving its schema from the handler's argument type
+func (c *ToolCatalog) Add(name, description string, handler any) {
+	var schema *jsonschema.Schema
+	if handlerType := reflect.TypeOf(handler); handlerType != nil && handlerType.Kind() == reflect.Func && handlerType.NumIn() > 0 {
+		schema = toolSchemaReflector.ReflectFromType(handlerType.In(handlerType.NumIn() - 1))
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tools[name] = ToolDescription{Name: name, Description: description, InputSchema: schema}
+}
+
+// Tools returns the recorded tools sorted by name
+func (c *ToolCatalog) Tools() []ToolDescription {
+	if c == nil {
+		return nil
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	tools := make([]ToolDescription, 0, len(c.tools))
+	for _, tool := range c.tools {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// catalogingServer registers tools with the MCP server and records them in the catalog
+type catalogingServer struct {
+	server  *mcp.Server
+	catalog *ToolCatalog
+}
+
+// RegisterTool registers the tool and, once the server accepts it, adds it to the catalog
+func (c catalogingServer) RegisterTool(name, description string, handler any) error {
+	if err := c.server.RegisterTool(name, description, handler); err != nil {
+		return err
+	}
+	c.catalog.Add(name, description, handler)
+	return nil
+}
+
+// describeTools returns the name, description, and argument schema of registered tools
+func (s *ForwardMCPService) describeTools(args DescribeToolsArgs) (*mcp.ToolResponse, error) {
+	s.logToolCall("describe_tools", args, nil)
+
+	tools := s.toolCatalog.Tools()
+	if args.Tool != "" {
+		for _, tool := range tools {
+			if tool.Name == args.Tool {
+				return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("Tool %s:\n%s", tool.Name, s.toJSON(tool, args.Pretty)))), nil
+			}
+		}
+		return nil, fmt.Errorf("tool %q is not registered; call describe_tools without a tool name to list all tools", args.Tool)
+	}
+
+	return mcp.NewToolResponse(mcp.NewTextContent(fmt.Sprintf("%d registered tools:\n%s", len(tools), s.toJSON(tools, args.Pretty)))), nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport/stdio"
+)
+
+func TestDescribeToolsSearchPathsSchema(t *testing.T) {
+	service := createTestService()
+	if err := service.RegisterTools(mcp.NewServer(stdio.NewStdioServerTransport())); err != nil {
+		t.Fatalf("Failed to register tools: %v", err)
+	}
+
+	response, err := service.describeTools(DescribeToolsArgs{Tool: "search_paths"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	_, payload, _ := strings.Cut(response.Content[0].TextContent.Text, ":\n")
+
+	var tool struct {
+		Name        string `json:"name"`
+		InputSchema struct {
+			Required   []string `json:"required"`
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"input_schema"`
+	}
+	if err := json.Unmarshal([]byte(payload), &tool); err != nil {
+		t.Fatalf("Expected a JSON tool description, got %v:\n%s", err, payload)
+	}
+	if tool.Name != "search_paths" {
+		t.Errorf("Expected search_paths, got %q", tool.Name)
+	}
+	if !containsString(tool.InputSchema.Required, "dst_ip") {
+		t.Errorf("Expected dst_ip to be required, got %v", tool.InputSchema.Required)
+	}
+	intents := tool.InputSchema.Properties["intent"].Enum
+	if len(intents) != 3 || !containsString(intents, "PREFER_DELIVERED") {
+		t.Errorf("Expected the intent enum, got %v", intents)
+	}
+
+	// The catalog covers every registered tool, including itself
+	all, err := service.describeTools(DescribeToolsArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text := all.Content[0].TextContent.Text; !strings.Contains(text, `"describe_tools"`) || !strings.Contains(text, `"list_networks"`) {
+		t.Errorf("Expected all registered tools to be described, got:\n%.300s", text)
+	}
+	if _, err := service.describeTools(DescribeToolsArgs{Tool: "no_such_tool"}); err == nil {
+		t.Error("Expected an error for an unregistered tool")
+	}
+}
@@ -4,20 +4,29 @@ package service
 type ListNetworksArgs struct {
 	// Dummy parameter for MCP framework compatibility (the tool doesn't actually use this)
 	RandomString string `json:"random_string" jsonschema:"description=Dummy parameter for no-parameter tools"`
+	Refresh      bool   `json:"refresh,omitempty" jsonschema:"description=Bypass the short-lived list cache and fetch networks from the API"`
+	Pretty       *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type CreateNetworkArgs struct {
-	Name string `json:"name" jsonschema:"required,description=Name of the network to create"`
+	Name   string `json:"name" jsonschema:"required,description=Name of the network to create"`
+	Pretty *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type DeleteNetworkArgs struct {
 	NetworkID string `json:"network_id" jsonschema:"required,description=ID of the network to delete"`
+	Pretty    *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type UpdateNetworkArgs struct {
 	NetworkID   string `json:"network_id" jsonschema:"required,description=ID of the network to update"`
 	Name        string `json:"name,omitempty" jsonschema:"description=New name for the network"`
 	Description string `json:"description,omitempty" jsonschema:"description=New description for the network"`
+	Pretty      *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+}
+
+type GetNetworkSummaryArgs struct {
+	NetworkID string `json:"network_id,omitempty" jsonschema:"description=ID of the network to summarize (uses default network if omitted)"`
 }
 
 // Path Search Tool Arguments
@@ -25,39 +34,97 @@ type SearchPathsArgs struct {
 	NetworkID               string `json:"network_id" jsonschema:"required,description=ID of the network to search paths in"`
 	DstIP                   string `json:"dst_ip" jsonschema:"required,description=Destination IP address or subnet"`
 	SrcIP                   string `json:"src_ip,omitempty" jsonschema:"description=Source IP address or subnet"`
-	From                    string `json:"from,omitempty" jsonschema:"description=Device from which traffic originates"`
-	Intent                  string `json:"intent,omitempty" jsonschema:"description=Search intent,enum=PREFER_DELIVERED|PREFER_VIOLATIONS|VIOLATIONS_ONLY"`
+	From                    string `json:"from,omitempty" jsonschema:"description=Device from which traffic originates: a device name or hostname or management IP (resolved to the device name)"`
+	Intent                  string `json:"intent,omitempty" jsonschema:"description=Search intent,enum=PREFER_DELIVERED,enum=PREFER_VIOLATIONS,enum=VIOLATIONS_ONLY"`
 	IPProto                 int    `json:"ip_proto,omitempty" jsonschema:"description=IP protocol number"`
 	SrcPort                 string `json:"src_port,omitempty" jsonschema:"description=Source port (e.g. '80' or '8080-8088')"`
 	DstPort                 string `json:"dst_port,omitempty" jsonschema:"description=Destination port (e.g. '80' or '8080-8088')"`
-	MaxResults              int    `json:"max_results,omitempty" jsonschema:"description=Maximum number of results to return (default: 1)"`
-	IncludeNetworkFunctions bool   `json:"include_network_functions,omitempty" jsonschema:"description=Include detailed forwarding info for each hop"`
+	MaxResults              int    `json:"max_results,omitempty" jsonschema:"description=Maximum number of results to return (default: 1 or the server's configured default)"`
+	MaxCandidates           int    `json:"max_candidates,omitempty" jsonschema:"description=Maximum number of candidate paths to consider; raise for complex searches (default: 5000)"`
+	MaxReturnPathResults    int    `json:"max_return_path_results,omitempty" jsonschema:"description=Maximum number of return paths to include (default: 0)"`
+	MaxSeconds              int    `json:"max_seconds,omitempty" jsonschema:"description=Time limit for the search in seconds (default: 30)"`
+	IncludeNetworkFunctions bool   `json:"include_network_functions,omitempty" jsonschema:"description=Include detailed forwarding info for each hop and summarize the ACL/NAT/policy it matched"`
 	SnapshotID              string `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to use (optional)"`
+	Explain                 bool   `json:"explain,omitempty" jsonschema:"description=Add a plain-language narrative of each path naming the devices traversed and where and why traffic is dropped"`
+	IncludeReturnPath       bool   `json:"include_return_path,omitempty" jsonschema:"description=Also search the return path and flag asymmetric routing where the return traffic traverses different devices (requests one return path per result unless max_return_path_results is set)"`
+	Pretty                  *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+}
+
+// BulkPathFlow is one source/destination flow in a bulk path search
+type BulkPathFlow struct {
+	SrcIP   string `json:"src_ip,omitempty" jsonschema:"description=Source IP address or subnet"`
+	DstIP   string `json:"dst_ip" jsonschema:"required,description=Destination IP address or subnet"`
+	IPProto int    `json:"ip_proto,omitempty" jsonschema:"description=IP protocol number"`
+	SrcPort string `json:"src_port,omitempty" jsonschema:"description=Source port (e.g. '80' or '8080-8088')"`
+	DstPort string `json:"dst_port,omitempty" jsonschema:"description=Destination port (e.g. '80' or '8080-8088')"`
+}
+
+// SearchPathsBulkArgs represents arguments for tracing several flows in one request
+type SearchPathsBulkArgs struct {
+	NetworkID  string         `json:"network_id" jsonschema:"required,description=ID of the network to search paths in"`
+	Flows      []BulkPathFlow `json:"flows" jsonschema:"required,description=Flows to trace (max: 100)"`
+	Intent     string         `json:"intent,omitempty" jsonschema:"description=Search intent applied to every flow,enum=PREFER_DELIVERED,enum=PREFER_VIOLATIONS,enum=VIOLATIONS_ONLY"`
+	SnapshotID string         `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to use (optional)"`
+	Pretty     *bool          `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+}
+
+// DevicePair is one source/destination device pair in a reachability check
+type DevicePair struct {
+	Source      string `json:"source" jsonschema:"required,description=Source device name; hostname or management IP"`
+	Destination string `json:"destination" jsonschema:"required,description=Destination device name; hostname or management IP"`
+}
+
+// CheckDeviceReachabilityArgs represents arguments for checking reachability between device pairs
+type CheckDeviceReachabilityArgs struct {
+	NetworkID  string       `json:"network_id" jsonschema:"required,description=ID of the network to check reachability in"`
+	Pairs      []DevicePair `json:"pairs" jsonschema:"required,description=Source/destination device pairs to check (max: 100)"`
+	SnapshotID string       `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to use (optional)"`
+	Pretty     *bool        `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+}
+
+// GetPathSearchHistoryArgs represents arguments for listing recent path searches
+type GetPathSearchHistoryArgs struct {
+	NetworkID string `json:"network_id,omitempty" jsonschema:"description=Network ID to list searches for (defaults to the default network; use 'all' for every network)"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"description=Maximum number of searches to return (default 20)"`
+	Pretty    *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 // NQE Tool Arguments
 type RunNQEQueryByStringArgs struct {
 	NetworkID  string                 `json:"network_id" jsonschema:"required,description=ID of the network to query"`
 	Query      string                 `json:"query" jsonschema:"required,description=NQE query source code"`
-	SnapshotID string                 `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to query (optional)"`
+	SnapshotID string                 `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to query or a reference such as latest-1 / yesterday / 2024-05-01 (optional)"`
 	Parameters map[string]interface{} `json:"parameters,omitempty" jsonschema:"description=Query parameters to use"`
 	Options    *NQEQueryOptions       `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
 }
 
+// ValidateNQEQueryArgs represents arguments for checking NQE source for compile errors
+type ValidateNQEQueryArgs struct {
+	NetworkID string `json:"network_id,omitempty" jsonschema:"description=Network to compile the query against (defaults to the default network)"`
+	Query     string `json:"query" jsonschema:"required,description=NQE query source code to check"`
+}
+
 type RunNQEQueryByIDArgs struct {
-	NetworkID  string                 `json:"network_id" description:"Network ID to run the query against"`
-	QueryID    string                 `json:"query_id" description:"Query ID from NQE Library (use the 'queryId' field from list_nqe_queries response)"`
-	SnapshotID string                 `json:"snapshot_id,omitempty" description:"Specific snapshot ID to query (optional)"`
-	Parameters map[string]interface{} `json:"parameters,omitempty" description:"Optional parameters for the query"`
-	Options    *NQEQueryOptions       `json:"options,omitempty" description:"Optional query options for sorting and filtering"`
+	NetworkID    string                 `json:"network_id" description:"Network ID to run the query against"`
+	QueryID      string                 `json:"query_id" description:"Query ID from NQE Library (use the 'queryId' field from list_nqe_queries response)"`
+	SnapshotID   string                 `json:"snapshot_id,omitempty" description:"Specific snapshot ID to query or a reference such as latest-1 / yesterday / 2024-05-01 (optional)"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty" description:"Optional parameters for the query"`
+	Options      *NQEQueryOptions       `json:"options,omitempty" description:"Optional query options for sorting and filtering"`
+	Columns      []string               `json:"columns,omitempty" description:"Only return these result columns, in this order (optional; unknown columns are reported and ignored)"`
+	Pretty       *bool                  `json:"pretty,omitempty" description:"Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache      bool                   `json:"no_cache,omitempty" description:"Bypass the semantic cache for this call: always run the query live and do not store the result"`
+	PostFilters  []NQEPostFilter        `json:"post_filters,omitempty" description:"Client-side row predicates applied to the returned page after retrieval; all must match (AND). Use for columns the server cannot filter or for numeric comparisons"`
+	Preview      bool                   `json:"preview,omitempty" description:"Run for 3 rows only and return the inferred column schema with those sample rows instead of the full result; cheaper than a full run"`
+	Flatten      bool                   `json:"flatten,omitempty" description:"Expand nested objects into dotted columns (e.g. interface.ip) and join arrays into delimited strings for a flat, tabular result"`
+	FlattenDepth int                    `json:"flatten_depth,omitempty" description:"How many levels of nested objects flatten expands (default 3, max 10); deeper objects stay JSON"`
 }
 
 type NQEQueryOptions struct {
-	Limit   int               `json:"limit,omitempty" jsonschema:"description=Maximum number of rows to return"`
+	Limit   int               `json:"limit,omitempty" jsonschema:"description=Maximum number of rows to return (default: the server's default query limit; limits above the server's maximum are rejected)"`
 	Offset  int               `json:"offset,omitempty" jsonschema:"description=Number of rows to skip"`
 	SortBy  []NQESortBy       `json:"sort_by,omitempty" jsonschema:"description=Sorting criteria for results"`
 	Filters []NQEColumnFilter `json:"filters,omitempty" jsonschema:"description=Column filters to apply"`
-	Format  string            `json:"format,omitempty" jsonschema:"description=Output format for results"`
+	Format  string            `json:"format,omitempty" jsonschema:"description=Output format for results (default: the session default_format)"`
 }
 
 type NQESortBy struct {
@@ -70,8 +137,16 @@ type NQEColumnFilter struct {
 	Value      string `json:"value" jsonschema:"required,description=Value to filter by"`
 }
 
+// NQEPostFilter is a client-side predicate (column op value) applied to result rows
+type NQEPostFilter struct {
+	ColumnName string `json:"column_name" jsonschema:"required,description=Name of the column to test"`
+	Operator   string `json:"operator" jsonschema:"required,description=Comparison operator (contains is case-insensitive; >/</>=/<= compare numbers),enum==,enum=!=,enum=>,enum=<,enum=>=,enum=<=,enum=contains"`
+	Value      string `json:"value" jsonschema:"required,description=Value to compare against"`
+}
+
 type ListNQEQueriesArgs struct {
 	Directory string `json:"directory,omitempty" jsonschema:"description=Filter queries by directory (e.g. '/L3/Advanced/')"`
+	Pretty    *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 // Device Management Tool Arguments
@@ -80,24 +155,43 @@ type ListDevicesArgs struct {
 	SnapshotID string `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
 	Limit      int    `json:"limit,omitempty" jsonschema:"description=Maximum number of devices to return"`
 	Offset     int    `json:"offset,omitempty" jsonschema:"description=Number of devices to skip"`
+	Cursor     string `json:"cursor,omitempty" jsonschema:"description=next_cursor from the previous page; replaces offset and is rejected if the network or snapshot changed"`
+	Pretty     *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type GetDeviceLocationsArgs struct {
 	NetworkID string `json:"network_id" jsonschema:"required,description=ID of the network"`
+	Pretty    *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 // Snapshot Management Tool Arguments
 type ListSnapshotsArgs struct {
-	NetworkID string `json:"network_id" jsonschema:"required,description=ID of the network"`
+	NetworkID     string `json:"network_id" jsonschema:"required,description=ID of the network"`
+	State         string `json:"state,omitempty" jsonschema:"description=Only return snapshots in this state (e.g. 'PROCESSED'). Case-insensitive."`
+	IncludeDrafts *bool  `json:"include_drafts,omitempty" jsonschema:"description=Include draft snapshots (default: true); set false to list only non-draft snapshots"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"description=Maximum number of snapshots to return (newest first)"`
+	Offset        int    `json:"offset,omitempty" jsonschema:"description=Number of snapshots to skip"`
+	Cursor        string `json:"cursor,omitempty" jsonschema:"description=next_cursor from the previous page; replaces offset and is rejected if the filters changed"`
+	Pretty        *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+}
+
+// ResolveSnapshotArgs represents arguments for resolving a relative snapshot reference
+type ResolveSnapshotArgs struct {
+	NetworkID string `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	Reference string `json:"reference" jsonschema:"required,description=Snapshot reference: latest / latest-N (the Nth processed snapshot before the latest) / today / yesterday / a date like 2024-05-01"`
+	Pretty    *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type GetLatestSnapshotArgs struct {
 	NetworkID string `json:"network_id" jsonschema:"required,description=ID of the network"`
+	Pretty    *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 // Location Management Tool Arguments
 type ListLocationsArgs struct {
 	NetworkID string `json:"network_id" jsonschema:"required,description=ID of the network"`
+	Refresh   bool   `json:"refresh,omitempty" jsonschema:"description=Bypass the short-lived list cache and fetch locations from the API"`
+	Pretty    *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type CreateLocationArgs struct {
@@ -106,31 +200,60 @@ type CreateLocationArgs struct {
 	Description string   `json:"description,omitempty" jsonschema:"description=Description of the location"`
 	Latitude    *float64 `json:"latitude,omitempty" jsonschema:"description=Latitude coordinate"`
 	Longitude   *float64 `json:"longitude,omitempty" jsonschema:"description=Longitude coordinate"`
+	Pretty      *bool    `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 // First-Class Query Tool Arguments - Critical Network Operations
 type GetDeviceBasicInfoArgs struct {
-	NetworkID  string           `json:"network_id" jsonschema:"required,description=ID of the network"`
-	SnapshotID string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
-	Options    *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
+	NetworkID    string           `json:"network_id" jsonschema:"required,description=ID of the network"`
+	SnapshotID   string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
+	Options      *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
+	Columns      []string         `json:"columns,omitempty" jsonschema:"description=Only return these result columns in this order (unknown columns are reported and ignored)"`
+	Pretty       *bool            `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache      bool             `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+	PostFilters  []NQEPostFilter  `json:"post_filters,omitempty" jsonschema:"description=Client-side row predicates (column operator value) applied after retrieval; all must match"`
+	Preview      bool             `json:"preview,omitempty" jsonschema:"description=Run for 3 rows only and return the inferred column schema with those sample rows instead of the full result"`
+	Flatten      bool             `json:"flatten,omitempty" jsonschema:"description=Expand nested objects into dotted columns (e.g. interface.ip) and join arrays into delimited strings"`
+	FlattenDepth int              `json:"flatten_depth,omitempty" jsonschema:"description=Levels of nested objects flatten expands (default 3; max 10)"`
 }
 
 type GetDeviceHardwareArgs struct {
-	NetworkID  string           `json:"network_id" jsonschema:"required,description=ID of the network"`
-	SnapshotID string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
-	Options    *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
+	NetworkID    string           `json:"network_id" jsonschema:"required,description=ID of the network"`
+	SnapshotID   string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
+	Options      *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
+	Columns      []string         `json:"columns,omitempty" jsonschema:"description=Only return these result columns in this order (unknown columns are reported and ignored)"`
+	Pretty       *bool            `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache      bool             `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+	PostFilters  []NQEPostFilter  `json:"post_filters,omitempty" jsonschema:"description=Client-side row predicates (column operator value) applied after retrieval; all must match"`
+	Preview      bool             `json:"preview,omitempty" jsonschema:"description=Run for 3 rows only and return the inferred column schema with those sample rows instead of the full result"`
+	Flatten      bool             `json:"flatten,omitempty" jsonschema:"description=Expand nested objects into dotted columns (e.g. interface.ip) and join arrays into delimited strings"`
+	FlattenDepth int              `json:"flatten_depth,omitempty" jsonschema:"description=Levels of nested objects flatten expands (default 3; max 10)"`
 }
 
 type GetHardwareSupportArgs struct {
-	NetworkID  string           `json:"network_id" jsonschema:"required,description=ID of the network"`
-	SnapshotID string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
-	Options    *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
+	NetworkID    string           `json:"network_id" jsonschema:"required,description=ID of the network"`
+	SnapshotID   string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
+	Options      *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
+	Columns      []string         `json:"columns,omitempty" jsonschema:"description=Only return these result columns in this order (unknown columns are reported and ignored)"`
+	Pretty       *bool            `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache      bool             `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+	PostFilters  []NQEPostFilter  `json:"post_filters,omitempty" jsonschema:"description=Client-side row predicates (column operator value) applied after retrieval; all must match"`
+	Preview      bool             `json:"preview,omitempty" jsonschema:"description=Run for 3 rows only and return the inferred column schema with those sample rows instead of the full result"`
+	Flatten      bool             `json:"flatten,omitempty" jsonschema:"description=Expand nested objects into dotted columns (e.g. interface.ip) and join arrays into delimited strings"`
+	FlattenDepth int              `json:"flatten_depth,omitempty" jsonschema:"description=Levels of nested objects flatten expands (default 3; max 10)"`
 }
 
 type GetOSSupportArgs struct {
-	NetworkID  string           `json:"network_id" jsonschema:"required,description=ID of the network"`
-	SnapshotID string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
-	Options    *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
+	NetworkID    string           `json:"network_id" jsonschema:"required,description=ID of the network"`
+	SnapshotID   string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID (optional)"`
+	Options      *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options like limit, offset, sorting, etc."`
+	Columns      []string         `json:"columns,omitempty" jsonschema:"description=Only return these result columns in this order (unknown columns are reported and ignored)"`
+	Pretty       *bool            `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache      bool             `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+	PostFilters  []NQEPostFilter  `json:"post_filters,omitempty" jsonschema:"description=Client-side row predicates (column operator value) applied after retrieval; all must match"`
+	Preview      bool             `json:"preview,omitempty" jsonschema:"description=Run for 3 rows only and return the inferred column schema with those sample rows instead of the full result"`
+	Flatten      bool             `json:"flatten,omitempty" jsonschema:"description=Expand nested objects into dotted columns (e.g. interface.ip) and join arrays into delimited strings"`
+	FlattenDepth int              `json:"flatten_depth,omitempty" jsonschema:"description=Levels of nested objects flatten expands (default 3; max 10)"`
 }
 
 // SearchConfigsArgs represents arguments for configuration search
@@ -141,21 +264,148 @@ type SearchConfigsArgs struct {
 	DeviceFilter string                 `json:"device_filter,omitempty" jsonschema:"description=Optional device name pattern to filter results"`
 	Parameters   map[string]interface{} `json:"parameters,omitempty" jsonschema:"description=Additional query parameters"`
 	Options      *NQEQueryOptions       `json:"options,omitempty" jsonschema:"description=Query options (limit, offset, etc.)"`
+	Columns      []string               `json:"columns,omitempty" jsonschema:"description=Only return these result columns in this order (unknown columns are reported and ignored)"`
+	Pretty       *bool                  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache      bool                   `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+	PostFilters  []NQEPostFilter        `json:"post_filters,omitempty" jsonschema:"description=Client-side row predicates (column operator value) applied after retrieval; all must match"`
+	Preview      bool                   `json:"preview,omitempty" jsonschema:"description=Run for 3 rows only and return the inferred column schema with those sample rows instead of the full result"`
+	Flatten      bool                   `json:"flatten,omitempty" jsonschema:"description=Expand nested objects into dotted columns (e.g. interface.ip) and join arrays into delimited strings"`
+	FlattenDepth int                    `json:"flatten_depth,omitempty" jsonschema:"description=Levels of nested objects flatten expands (default 3; max 10)"`
+}
+
+// GetDeviceConfigArgs represents arguments for fetching one device's running configuration
+type GetDeviceConfigArgs struct {
+	NetworkID  string `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	SnapshotID string `json:"snapshot_id,omitempty" jsonschema:"description=Snapshot ID (optional, uses latest if not specified)"`
+	DeviceName string `json:"device_name" jsonschema:"required,description=Device name (a hostname or management IP is also accepted)"`
+	OutputFile string `json:"output_file,omitempty" jsonschema:"description=Write the configuration to this file path instead of returning it (recommended for large configs)"`
+}
+
+// FindDevicesByConfigArgs represents arguments for finding devices by configuration presence
+type FindDevicesByConfigArgs struct {
+	NetworkID  string `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	SnapshotID string `json:"snapshot_id,omitempty" jsonschema:"description=Snapshot ID (optional, uses latest if not specified)"`
+	Pattern    string `json:"pattern" jsonschema:"required,description=Config pattern to look for (plain text or a block pattern as accepted by search_configs)"`
+	Inverse    bool   `json:"inverse,omitempty" jsonschema:"description=Return the devices whose configuration does NOT match the pattern"`
+	Pretty     *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 // GetConfigDiffArgs represents arguments for configuration comparison
 type GetConfigDiffArgs struct {
 	NetworkID      string                 `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
-	BeforeSnapshot string                 `json:"before_snapshot" jsonschema:"required,description=Earlier snapshot ID for comparison"`
-	AfterSnapshot  string                 `json:"after_snapshot" jsonschema:"required,description=Later snapshot ID for comparison"`
+	BeforeSnapshot string                 `json:"before_snapshot" jsonschema:"required,description=Earlier snapshot ID for comparison (or a reference such as latest-1 or 2024-05-01)"`
+	AfterSnapshot  string                 `json:"after_snapshot" jsonschema:"required,description=Later snapshot ID for comparison (or a reference such as latest or yesterday)"`
 	DeviceFilter   string                 `json:"device_filter,omitempty" jsonschema:"description=Optional device name pattern to filter results"`
 	Parameters     map[string]interface{} `json:"parameters,omitempty" jsonschema:"description=Additional query parameters"`
 	Options        *NQEQueryOptions       `json:"options,omitempty" jsonschema:"description=Query options (limit, offset, etc.)"`
+	Columns        []string               `json:"columns,omitempty" jsonschema:"description=Only return these result columns in this order (unknown columns are reported and ignored)"`
+	Pretty         *bool                  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache        bool                   `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+	PostFilters    []NQEPostFilter        `json:"post_filters,omitempty" jsonschema:"description=Client-side row predicates (column operator value) applied after retrieval; all must match"`
+	Preview        bool                   `json:"preview,omitempty" jsonschema:"description=Run for 3 rows only and return the inferred column schema with those sample rows instead of the full result"`
+	Flatten        bool                   `json:"flatten,omitempty" jsonschema:"description=Expand nested objects into dotted columns (e.g. interface.ip) and join arrays into delimited strings"`
+	FlattenDepth   int                    `json:"flatten_depth,omitempty" jsonschema:"description=Levels of nested objects flatten expands (default 3; max 10)"`
+}
+
+// SummarizeChangesArgs represents arguments for summarizing network changes between snapshots
+type SummarizeChangesArgs struct {
+	NetworkID      string `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	BeforeSnapshot string `json:"before_snapshot" jsonschema:"required,description=Earlier snapshot ID for comparison (or a reference such as latest-1 or 2024-05-01)"`
+	AfterSnapshot  string `json:"after_snapshot" jsonschema:"required,description=Later snapshot ID for comparison (or a reference such as latest or yesterday)"`
+	Pretty         *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+}
+
+// DiffNQERunsArgs represents arguments for diffing one NQE query's output across two snapshots
+type DiffNQERunsArgs struct {
+	NetworkID      string                 `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	QueryID        string                 `json:"query_id" jsonschema:"required,description=Query ID to run against both snapshots (e.g. FQ_ac651cb2901b067fe7dbfb511613ab44776d8029)"`
+	BeforeSnapshot string                 `json:"before_snapshot" jsonschema:"required,description=Earlier snapshot ID for comparison (or a reference such as latest-1 or 2024-05-01)"`
+	AfterSnapshot  string                 `json:"after_snapshot" jsonschema:"required,description=Later snapshot ID for comparison (or a reference such as latest or yesterday)"`
+	KeyColumn      string                 `json:"key_column,omitempty" jsonschema:"description=Column that identifies a row across snapshots (e.g. device or neighborAddress). Without it rows are compared whole and only added/removed are reported"`
+	Parameters     map[string]interface{} `json:"parameters,omitempty" jsonschema:"description=Query parameters applied to both runs"`
+	Options        *NQEQueryOptions       `json:"options,omitempty" jsonschema:"description=Query options such as limit and filters applied to both runs"`
+	Pretty         *bool                  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache        bool                   `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+}
+
+// NQEResultSource is one query combined by combine_nqe_results: a library query or inline NQE source
+type NQEResultSource struct {
+	QueryID    string                 `json:"query_id,omitempty" jsonschema:"description=Query ID from the NQE library (set this or query)"`
+	Query      string                 `json:"query,omitempty" jsonschema:"description=Inline NQE source code (set this or query_id)"`
+	Parameters map[string]interface{} `json:"parameters,omitempty" jsonschema:"description=Query parameters"`
+}
+
+// CombineNQEResultsArgs represents arguments for combining two NQE result sets on a key column
+type CombineNQEResultsArgs struct {
+	NetworkID       string           `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	SnapshotID      string           `json:"snapshot_id,omitempty" jsonschema:"description=Snapshot both queries run against (optional; a reference such as latest-1 is accepted)"`
+	First           NQEResultSource  `json:"first" jsonschema:"required,description=First query"`
+	Second          NQEResultSource  `json:"second" jsonschema:"required,description=Second query"`
+	KeyColumn       string           `json:"key_column" jsonschema:"required,description=Column joining the two result sets (e.g. deviceName)"`
+	SecondKeyColumn string           `json:"second_key_column,omitempty" jsonschema:"description=Key column in the second query's results when it is named differently (default: key_column)"`
+	Mode            string           `json:"mode,omitempty" jsonschema:"description=intersection: first rows whose key is also in second; union: all first rows plus second rows with new keys; difference: first rows whose key is not in second (default: intersection),enum=intersection,enum=union,enum=difference"`
+	Options         *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options such as limit applied to both queries"`
+	Pretty          *bool            `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache         bool             `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the queries live and do not store the results"`
+}
+
+// PreviewNQEOptionsArgs represents arguments for checking NQE filters and sorting before a full run
+type PreviewNQEOptionsArgs struct {
+	NetworkID  string                 `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	QueryID    string                 `json:"query_id" jsonschema:"required,description=Query ID to check the options against (e.g. FQ_ac651cb2901b067fe7dbfb511613ab44776d8029)"`
+	SnapshotID string                 `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to query or a reference such as latest-1 / yesterday / 2024-05-01 (optional)"`
+	Parameters map[string]interface{} `json:"parameters,omitempty" jsonschema:"description=Query parameters to use"`
+	Options    *NQEQueryOptions       `json:"options,omitempty" jsonschema:"description=The filters and sort_by you plan to run with; their column names are checked against the query's real columns"`
+}
+
+// RunNQEQueryOverTimeArgs represents arguments for running one NQE query across recent snapshots
+type RunNQEQueryOverTimeArgs struct {
+	NetworkID   string                 `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	QueryID     string                 `json:"query_id" jsonschema:"required,description=Query ID to run against each snapshot (e.g. FQ_ac651cb2901b067fe7dbfb511613ab44776d8029)"`
+	Snapshots   int                    `json:"snapshots,omitempty" jsonschema:"description=Number of most recent snapshots to include (default: 5; max: 50)"`
+	ValueColumn string                 `json:"value_column,omitempty" jsonschema:"description=Numeric column to sum per snapshot. Without it the metric is the row count (e.g. number of devices)"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty" jsonschema:"description=Query parameters applied to every run"`
+	Options     *NQEQueryOptions       `json:"options,omitempty" jsonschema:"description=Query options such as limit and filters applied to every run"`
+	Pretty      *bool                  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache     bool                   `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+}
+
+// RunNQEQueryBatchArgs represents arguments for running one NQE query with several parameter sets
+type RunNQEQueryBatchArgs struct {
+	NetworkID     string                   `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	QueryID       string                   `json:"query_id" jsonschema:"required,description=Parameterized query ID to run once per parameter set"`
+	ParameterSets []map[string]interface{} `json:"parameter_sets" jsonschema:"required,description=Parameter maps to run the query with; one run per map (max: 50)"`
+	SnapshotID    string                   `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to query or a reference such as latest-1 / yesterday / 2024-05-01 (optional)"`
+	Options       *NQEQueryOptions         `json:"options,omitempty" jsonschema:"description=Query options such as limit and filters applied to every run"`
+	Pretty        *bool                    `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+	NoCache       bool                     `json:"no_cache,omitempty" jsonschema:"description=Bypass the semantic cache for this call: always run the query live and do not store the result"`
+}
+
+// CompareNetworksArgs represents arguments for comparing the device inventories of two networks
+type CompareNetworksArgs struct {
+	NetworkA  string `json:"network_a" jsonschema:"required,description=First network ID (e.g. staging)"`
+	NetworkB  string `json:"network_b" jsonschema:"required,description=Second network ID (e.g. production)"`
+	SnapshotA string `json:"snapshot_a,omitempty" jsonschema:"description=Snapshot ID for the first network (defaults to latest)"`
+	SnapshotB string `json:"snapshot_b,omitempty" jsonschema:"description=Snapshot ID for the second network (defaults to latest)"`
+}
+
+// DiffDeviceInventoryArgs represents arguments for diffing a network's device inventory between two snapshots
+type DiffDeviceInventoryArgs struct {
+	NetworkID      string `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	BeforeSnapshot string `json:"before_snapshot" jsonschema:"required,description=Earlier snapshot ID (or a reference such as latest-1 or 2024-05-01)"`
+	AfterSnapshot  string `json:"after_snapshot" jsonschema:"required,description=Later snapshot ID (or a reference such as latest or yesterday)"`
+}
+
+// DetectMissingDevicesArgs represents arguments for finding baseline devices absent from the latest snapshot
+type DetectMissingDevicesArgs struct {
+	NetworkID        string `json:"network_id" jsonschema:"description=Network ID (use list_networks to find, or set default with set_default_network)"`
+	BaselineSnapshot string `json:"baseline_snapshot" jsonschema:"required,description=Known-good snapshot ID (or a reference such as latest-1 or 2024-05-01) to compare the latest snapshot against"`
+	Pretty           *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type GetDeviceUtilitiesArgs struct {
 	NetworkID  string           `json:"network_id" jsonschema:"required,description=ID of the network"`
-	SnapshotID string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to query (optional)"`
+	SnapshotID string           `json:"snapshot_id,omitempty" jsonschema:"description=Specific snapshot ID to query or a reference such as latest-1 / yesterday / 2024-05-01 (optional)"`
 	Options    *NQEQueryOptions `json:"options,omitempty" jsonschema:"description=Query options including limit, offset, sorting, and filtering"`
 }
 
@@ -175,27 +425,74 @@ type NetworkContextArgs struct {
 
 // Default Settings Management argument structures
 type GetDefaultSettingsArgs struct {
-	// No parameters needed to view current defaults
+	Pretty *bool `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type SetDefaultNetworkArgs struct {
 	NetworkIdentifier string `json:"network_identifier"`
 }
 
+type SetDefaultSettingsArgs struct {
+	ResponseDetail   string  `json:"response_detail,omitempty" jsonschema:"description=Tool output detail level: 'full' returns complete JSON and 'summary' returns counts and key identifiers only,enum=full,enum=summary"`
+	IncludeNQESchema *bool   `json:"include_nqe_schema,omitempty" jsonschema:"description=Show the inferred column names and types at the top of NQE query results (default: true)"`
+	NormalizeUnits   *bool   `json:"normalize_units,omitempty" jsonschema:"description=Add a readable <column>_display value next to NQE columns with units such as *_bytes, *_percent, and *Millis; raw values are kept (default: false)"`
+	DefaultFormat    *string `json:"default_format,omitempty" jsonschema:"description=Output format for NQE calls that do not set options.format; a format set on a call still wins. An empty string restores the API default."`
+}
+
+// Session profile arguments
+type SaveProfileArgs struct {
+	Name string `json:"name" jsonschema:"required,description=Name to save the current session defaults under; an existing profile of the same name is replaced"`
+}
+
+type LoadProfileArgs struct {
+	Name string `json:"name" jsonschema:"required,description=Name of the saved profile whose defaults replace the current ones"`
+}
+
+type ListProfilesArgs struct {
+	// Empty struct - lists the profiles of the active instance
+}
+
+// Forward instance arguments
+type ListInstancesArgs struct {
+	// Empty struct - lists every configured instance
+}
+
+type SetActiveInstanceArgs struct {
+	Name string `json:"name" jsonschema:"required,description=Instance name from list_instances ('default' is the instance set through FORWARD_API_BASE_URL)"`
+}
+
 // Semantic Cache and AI Enhancement Args
 type GetCacheStatsArgs struct {
-	// No parameters needed for cache stats
+	Pretty *bool `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 type SuggestSimilarQueriesArgs struct {
 	Query string `json:"query" jsonschema:"required,description=Query text to find similar queries for"`
 	Limit int    `json:"limit,omitempty" jsonschema:"description=Maximum number of suggestions to return (default: 5)"`
+	// MinSimilarity overrides the server's suggestion floor for this call
+	MinSimilarity float64 `json:"min_similarity,omitempty" jsonschema:"description=Only suggest queries more similar than this (0-1; default: the server's suggestion floor of 0.5). Raise it for fewer but closer suggestions."`
 }
 
 type ClearCacheArgs struct {
 	ClearAll bool `json:"clear_all,omitempty" jsonschema:"description=Clear all cache entries instead of just expired ones"`
 }
 
+// GetMetricsArgs represents arguments for the metrics tool
+type GetMetricsArgs struct {
+	// No parameters needed for metrics
+}
+
+// GetStartedArgs represents arguments for the guided tool recommendations
+type GetStartedArgs struct {
+	Goal string `json:"goal,omitempty" jsonschema:"description=Only show this goal (default: all goals),enum=audit inventory,enum=verify connectivity,enum=investigate a change"`
+}
+
+// DescribeToolsArgs represents arguments for describing the registered tools
+type DescribeToolsArgs struct {
+	Tool   string `json:"tool,omitempty" jsonschema:"description=Describe only this tool (default: all registered tools)"`
+	Pretty *bool  `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
+}
+
 // AI-Powered Query Discovery Tools
 
 // SearchNQEQueriesArgs represents arguments for intelligent query search
@@ -211,6 +508,17 @@ type SearchNQEQueriesArgs struct {
 type InitializeQueryIndexArgs struct {
 	RebuildIndex       bool `json:"rebuild_index" jsonschema:"description=Force rebuild of the query index from spec file (default: false). Only needed if spec file has been updated."`
 	GenerateEmbeddings bool `json:"generate_embeddings" jsonschema:"description=Generate new AI embeddings for semantic search (default: false). Requires OpenAI API key and takes several minutes. Creates offline cache for fast searches."`
+	Background         bool `json:"background,omitempty" jsonschema:"description=Run the rebuild in the background and return a build token immediately (default: false). Poll progress with get_index_build_status."`
+}
+
+// GetIndexBuildStatusArgs represents arguments for polling a background index build
+type GetIndexBuildStatusArgs struct {
+	Token string `json:"token,omitempty" jsonschema:"description=Build token returned by initialize_query_index (optional; defaults to the most recent build)"`
+}
+
+// ReloadQueryIndexArgs represents arguments for reloading the query index from the spec file
+type ReloadQueryIndexArgs struct {
+	// No parameters needed; the index reloads from the spec file it was created with
 }
 
 // GetQueryIndexStatsArgs represents arguments for query index statistics
@@ -218,11 +526,51 @@ type GetQueryIndexStatsArgs struct {
 	Detailed bool `json:"detailed"`
 }
 
+// GetEmbeddingCoverageArgs represents arguments for reporting embedding coverage per category
+type GetEmbeddingCoverageArgs struct {
+	Threshold float64 `json:"threshold,omitempty" jsonschema:"description=Coverage (0-1) below which a category or subcategory is flagged (default: 0.8)"`
+}
+
+// EstimateQueryCostArgs represents arguments for estimating an NQE query's cost
+type EstimateQueryCostArgs struct {
+	QueryID string `json:"query_id" jsonschema:"required,description=Query ID to estimate (e.g. FQ_...)"`
+}
+
+// LookupQueryArgs represents arguments for finding queries by Query ID or path
+type LookupQueryArgs struct {
+	Term  string `json:"term" jsonschema:"required,description=A Query ID (e.g. 'FQ_ac651cb2901b067fe7dbfb511613ab44776d8029'), a Query ID prefix, or part of a query path (e.g. 'bgp neighbor')"`
+	Limit int    `json:"limit,omitempty" jsonschema:"description=Maximum number of candidates to return (default: 10)"`
+}
+
+// ListNQEDirectoriesArgs represents arguments for listing the NQE library's directory tree
+type ListNQEDirectoriesArgs struct {
+	Directory string `json:"directory,omitempty" jsonschema:"description=Only show the tree under this directory (e.g. '/L3/' or 'L3/BGP'). Leave empty to start at the top."`
+	Depth     int    `json:"depth,omitempty" jsonschema:"description=Number of directory levels to show (default: 2)"`
+}
+
+// BrowseNQELibraryArgs represents arguments for browsing the NQE library categories
+type BrowseNQELibraryArgs struct {
+	Category string `json:"category,omitempty" jsonschema:"description=Only show this category (e.g., 'L3', 'Security'). Leave empty to see every category."`
+	Examples int    `json:"examples,omitempty" jsonschema:"description=Number of example query paths to show per category (default: 3, max: 10)"`
+}
+
 // FindExecutableQueryArgs represents the arguments for finding executable queries
 type FindExecutableQueryArgs struct {
-	Query          string `json:"query" jsonschema:"required,description=Natural language description of what you want to analyze or accomplish. Be specific about the network analysis goal. Examples: 'show me all network devices', 'check device CPU and memory usage', 'find BGP neighbor information', 'compare configuration changes'."`
-	Limit          int    `json:"limit" jsonschema:"description=Maximum number of executable query recommendations to return (default: 5, max: 10). Each result includes a real Forward Networks Query ID you can execute."`
-	IncludeRelated bool   `json:"include_related" jsonschema:"description=Include the semantic search matches that led to these executable recommendations (default: false). Useful for understanding why these queries were suggested."`
+	Query          string  `json:"query" jsonschema:"required,description=Natural language description of what you want to analyze or accomplish. Be specific about the network analysis goal. Examples: 'show me all network devices', 'check device CPU and memory usage', 'find BGP neighbor information', 'compare configuration changes'."`
+	Limit          int     `json:"limit" jsonschema:"description=Maximum number of executable query recommendations to return (default: 5, max: 10). Each result includes a real Forward Networks Query ID you can execute."`
+	IncludeRelated bool    `json:"include_related" jsonschema:"description=Include the semantic search matches that led to these executable recommendations (default: false). Useful for understanding why these queries were suggested."`
+	MinConfidence  float64 `json:"min_confidence,omitempty" jsonschema:"description=Minimum mapping confidence (0-1) for an executable recommendation (default: 0.6). Lower-confidence mappings are dropped and the raw semantic matches are shown instead."`
+	IncludeHistory bool    `json:"include_history,omitempty" jsonschema:"description=Annotate each recommendation with when it last ran and its average runtime on this server (default: false)."`
+}
+
+// AskNetworkArgs represents the arguments for answering a natural-language question with a query run
+type AskNetworkArgs struct {
+	Question      string  `json:"question" jsonschema:"required,description=Natural-language question about the network; e.g. 'which devices are in the network?' or 'what hardware is end of life?'"`
+	NetworkID     string  `json:"network_id,omitempty" jsonschema:"description=Network to run the chosen query against (default: the session default network)"`
+	SnapshotID    string  `json:"snapshot_id,omitempty" jsonschema:"description=Snapshot ID or a reference such as latest-1 / yesterday / 2024-05-01 (default: latest)"`
+	MinConfidence float64 `json:"min_confidence,omitempty" jsonschema:"description=Mapping confidence (0-1) needed to run a query without asking (default: 0.75). Below it the candidates are returned instead."`
+	Limit         int     `json:"limit,omitempty" jsonschema:"description=Maximum number of result rows (default: the server's default query limit)"`
+	Pretty        *bool   `json:"pretty,omitempty" jsonschema:"description=Pretty-print JSON output for this call (true) or compact it to save tokens (false); defaults to the server setting"`
 }
 
 // Smart Query Workflow Arguments